@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
@@ -9,14 +10,16 @@ import (
 type (
 	// Config -.
 	Config struct {
-		App   `yaml:"app"`
-		HTTP  `yaml:"http"`
-		Log   `yaml:"logger"`
-		PG    `yaml:"postgres"`
-		JWT   `yaml:"jwt"`
-		Redis `yaml:"redis"`
-		Gmail `yaml:"gmail"`
-		MinIO `yaml:"minio"`
+		App    `yaml:"app"`
+		HTTP   `yaml:"http"`
+		Log    `yaml:"logger"`
+		PG     `yaml:"postgres"`
+		JWT    `yaml:"jwt"`
+		Redis  `yaml:"redis"`
+		Gmail  `yaml:"gmail"`
+		MinIO  `yaml:"minio"`
+		CBU    `yaml:"cbu"`
+		Import `yaml:"import"`
 	}
 
 	// App -.
@@ -63,6 +66,18 @@ type (
 		MinioUser      string `env-required:"true" yaml:"miniouser" env:"MINIOUSER"`
 		MinIOSecredKey string `env-required:"true" yaml:"miniosecredkey" env:"MINIOSECREDKEY"`
 		MinIOBucketName string `env-required:"true" yaml:"minibucketname" env:"MINIOBUCKETNAME"`
+		// printed documents are kept apart from the public bucket and only served through the API
+		MinIODocumentBucket string `env-default:"documents" yaml:"minio_document_bucket" env:"MINIO_DOCUMENT_BUCKET"`
+	}
+
+	// CBU -. Exchange rates are imported from RatesURL, an http(s) URL or a file.
+	CBU struct {
+		RatesURL string `env-default:"https://cbu.uz/uz/arkhiv-kursov-valyut/json/" yaml:"rates_url" env:"CBU_RATES_URL"`
+	}
+
+	// Import -. The worker looks for queued import jobs every PollInterval.
+	Import struct {
+		PollInterval time.Duration `env-default:"5s" yaml:"poll_interval" env:"IMPORT_POLL_INTERVAL"`
 	}
 )
 
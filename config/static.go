@@ -3,20 +3,28 @@ package config
 import "time"
 
 var (
-	ErrorInvalidRequest = "INVALID_REQUEST"
-	ErrorInvalidToken   = "INVALID_TOKEN"
-	ErrorInvalidUser    = "INVALID_USER"
-	ErrorInvalidPass    = "INVALID_PASS"
-	ErrorInvalidEmail   = "INVALID_EMAIL"
-	ErrorInvalidPhone   = "INVALID_PHONE"
-	ErrorSessionExpired = "SESSION_EXPIRED"
-	ErrorInternalServer = "INTERNAL_SERVER"
-	ErrorNotFound       = "NOT_FOUND"
-	ErrorUnauthorized   = "UNAUTHORIZED"
-	ErrorForbidden      = "FORBIDDEN"
-	ErrorConflict       = "CONFLICT"
-	ErrorBadRequest     = "BAD_REQUEST"
-	ErrorDuplicateKey   = "DUPLICATE_KEY"
+	ErrorInvalidRequest    = "INVALID_REQUEST"
+	ErrorInvalidToken      = "INVALID_TOKEN"
+	ErrorInvalidUser       = "INVALID_USER"
+	ErrorInvalidPass       = "INVALID_PASS"
+	ErrorInvalidEmail      = "INVALID_EMAIL"
+	ErrorInvalidPhone      = "INVALID_PHONE"
+	ErrorSessionExpired    = "SESSION_EXPIRED"
+	ErrorInternalServer    = "INTERNAL_SERVER"
+	ErrorNotFound          = "NOT_FOUND"
+	ErrorUnauthorized      = "UNAUTHORIZED"
+	ErrorForbidden         = "FORBIDDEN"
+	ErrorConflict          = "CONFLICT"
+	ErrorBadRequest        = "BAD_REQUEST"
+	ErrorDuplicateKey      = "DUPLICATE_KEY"
+	ErrorCategoryCycle     = "CATEGORY_CYCLE"
+	ErrorInsufficientStock = "INSUFFICIENT_STOCK"
+	ErrorInvalidStatus     = "INVALID_STATUS"
+	ErrorReturnExceeds     = "RETURN_EXCEEDS_SHIPPED"
+	ErrorPaymentExceeds    = "PAYMENT_EXCEEDS_DEBT"
+	ErrorSerialInStock     = "SERIAL_IN_STOCK"
+	ErrorSerialUnavailable = "SERIAL_NOT_AVAILABLE"
+	ErrorDayClosed         = "DAY_CLOSED"
 )
 
 var (
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/signal"
@@ -31,6 +32,11 @@ func Run(cfg *config.Config) {
 	// Use case
 	useCase := usecase.New(pg, cfg, l)
 
+	// Import worker
+	workerCtx, stopWorker := context.WithCancel(context.Background())
+	defer stopWorker()
+	go useCase.RunImports(workerCtx)
+
 	// redis
 	redis, err := rediscache.New(&rediscache.Config{
 		RedisHost: cfg.Redis.RedisHost,
@@ -65,6 +71,8 @@ func Run(cfg *config.Config) {
 	}
 
 	// Shutdown
+	stopWorker()
+
 	err = httpServer.Shutdown()
 	if err != nil {
 		l.Error(fmt.Errorf("app - Run - httpServer.Shutdown: %w", err))
@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateAssembly godoc
+// @Router /assembly [post]
+// @Summary Create an assembly document
+// @Description Draft assembling kits from their components or disassembling them back. The lines are the components of the kit as it is made up now
+// @Security BearerAuth
+// @Tags assembly
+// @Accept  json
+// @Produce  json
+// @Param body body entity.Assembly true "Type, warehouse, bin, kit and quantity"
+// @Success 201 {object} entity.Assembly
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) CreateAssembly(ctx *gin.Context) {
+	var (
+		body entity.Assembly
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if body.Type != entity.AssemblyTypeAssemble && body.Type != entity.AssemblyTypeDisassemble {
+		h.ReturnError(ctx, config.ErrorBadRequest, "type must be assemble or disassemble", 400)
+		return
+	}
+
+	if body.WarehouseID == "" || body.BinID == "" || body.ProductID == "" || body.Quantity <= 0 {
+		h.ReturnError(ctx, config.ErrorBadRequest, "warehouse_id, bin_id, product_id and a positive quantity are required", 400)
+		return
+	}
+
+	assembly, err := h.UseCase.CreateAssembly(ctx, body, ctx.GetHeader("sub"))
+	if h.HandleDbError(ctx, err, "Error creating assembly") {
+		return
+	}
+
+	ctx.JSON(201, assembly)
+}
+
+// GetAssembly godoc
+// @Router /assembly/{id} [get]
+// @Summary Get an assembly document by ID
+// @Description Get an assembly document with its component lines
+// @Security BearerAuth
+// @Tags assembly
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Assembly ID"
+// @Success 200 {object} entity.Assembly
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetAssembly(ctx *gin.Context) {
+	var (
+		req entity.Id
+	)
+
+	req.ID = ctx.Param("id")
+
+	assembly, err := h.UseCase.AssemblyRepo.GetSingle(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting assembly") {
+		return
+	}
+
+	ctx.JSON(200, assembly)
+}
+
+// GetAssemblies godoc
+// @Router /assembly/list [get]
+// @Summary Get a list of assembly documents
+// @Description Get a list of assembly documents without lines
+// @Security BearerAuth
+// @Tags assembly
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param search query string false "number"
+// @Param type query string false "assemble or disassemble"
+// @Param status query string false "draft or posted"
+// @Param product_id query string false "product_id"
+// @Param warehouse_id query string false "warehouse_id"
+// @Param format query string false "csv or xlsx, exports the whole filtered list instead of a page"
+// @Param lang query string false "uz or ru, language of the column headers of an export, uz by default"
+// @Success 200 {object} entity.AssemblyList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetAssemblies(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+	search := ctx.DefaultQuery("search", "")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+
+	if search != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "number",
+			Type:   "search",
+			Value:  search,
+		})
+	}
+
+	for _, column := range []string{"type", "status", "product_id", "warehouse_id"} {
+		if value := ctx.DefaultQuery(column, ""); value != "" {
+			req.Filters = append(req.Filters, entity.Filter{
+				Column: column,
+				Type:   "eq",
+				Value:  value,
+			})
+		}
+	}
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "created_at",
+		Order:  "desc",
+	})
+
+	if h.export(ctx, "assemblies", entity.Assembly{}, req, func(req entity.GetListFilter) error {
+		_, err := h.UseCase.AssemblyRepo.GetList(ctx, req)
+		return err
+	}) {
+		return
+	}
+
+	assemblies, err := h.UseCase.AssemblyRepo.GetList(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting assemblies") {
+		return
+	}
+
+	ctx.JSON(200, assemblies)
+}
+
+// DeleteAssembly godoc
+// @Router /assembly/{id} [delete]
+// @Summary Delete an assembly document
+// @Description Delete a draft assembly document
+// @Security BearerAuth
+// @Tags assembly
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Assembly ID"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) DeleteAssembly(ctx *gin.Context) {
+	var (
+		req entity.Id
+	)
+
+	req.ID = ctx.Param("id")
+
+	err := h.UseCase.AssemblyRepo.Delete(ctx, req)
+	if h.HandleDbError(ctx, err, "Error deleting assembly") {
+		return
+	}
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "Assembly deleted successfully",
+	})
+}
+
+// PostAssembly godoc
+// @Router /assembly/{id}/post [post]
+// @Summary Post an assembly document
+// @Description Write the document to the stock ledger. Assembling takes the components out of stock and puts the kits into the bin at their cost, disassembling breaks the kits down and shares their value among the components
+// @Security BearerAuth
+// @Tags assembly
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Assembly ID"
+// @Success 200 {object} entity.Assembly
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) PostAssembly(ctx *gin.Context) {
+	assembly, err := h.UseCase.PostAssembly(ctx, ctx.Param("id"), ctx.GetHeader("sub"))
+	if h.HandleDbError(ctx, err, "Error posting assembly") {
+		return
+	}
+
+	ctx.JSON(200, assembly)
+}
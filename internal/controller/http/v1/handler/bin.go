@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateBin godoc
+// @Router /warehouse/bin [post]
+// @Summary Create a new bin
+// @Description Create a new bin (shelf) inside a zone. The code TRANSIT is reserved for stock in transit
+// @Security BearerAuth
+// @Tags warehouse
+// @Accept  json
+// @Produce  json
+// @Param bin body entity.Bin true "Bin object"
+// @Success 201 {object} entity.Bin
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) CreateBin(ctx *gin.Context) {
+	var (
+		body entity.Bin
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if body.ZoneID == "" || body.Code == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "zone_id and code are required", 400)
+		return
+	}
+
+	bin, err := h.UseCase.BinRepo.Create(ctx, body)
+	if h.HandleDbError(ctx, err, "Error creating bin") {
+		return
+	}
+
+	ctx.JSON(201, bin)
+}
+
+// GetBin godoc
+// @Router /warehouse/bin/{id} [get]
+// @Summary Get a bin by ID
+// @Description Get a bin by ID
+// @Security BearerAuth
+// @Tags warehouse
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Bin ID"
+// @Success 200 {object} entity.Bin
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetBin(ctx *gin.Context) {
+	var (
+		req entity.Id
+	)
+
+	req.ID = ctx.Param("id")
+
+	bin, err := h.UseCase.BinRepo.GetSingle(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting bin") {
+		return
+	}
+
+	ctx.JSON(200, bin)
+}
+
+// GetBins godoc
+// @Router /warehouse/bin/list [get]
+// @Summary Get a list of bins
+// @Description Get a list of bins
+// @Security BearerAuth
+// @Tags warehouse
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param search query string false "search by code"
+// @Param warehouse_id query string false "warehouse_id"
+// @Param zone_id query string false "zone_id"
+// @Param format query string false "csv or xlsx, exports the whole filtered list instead of a page"
+// @Param lang query string false "uz or ru, language of the column headers of an export, uz by default"
+// @Success 200 {object} entity.BinList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetBins(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+	search := ctx.DefaultQuery("search", "")
+	warehouseID := ctx.DefaultQuery("warehouse_id", "")
+	zoneID := ctx.DefaultQuery("zone_id", "")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+
+	if search != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "code",
+			Type:   "search",
+			Value:  search,
+		})
+	}
+
+	if warehouseID != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "warehouse_id",
+			Type:   "eq",
+			Value:  warehouseID,
+		})
+	}
+
+	if zoneID != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "zone_id",
+			Type:   "eq",
+			Value:  zoneID,
+		})
+	}
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "code",
+		Order:  "asc",
+	})
+
+	if h.export(ctx, "bins", entity.Bin{}, req, func(req entity.GetListFilter) error {
+		_, err := h.UseCase.BinRepo.GetList(ctx, req)
+		return err
+	}) {
+		return
+	}
+
+	bins, err := h.UseCase.BinRepo.GetList(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting bins") {
+		return
+	}
+
+	ctx.JSON(200, bins)
+}
+
+// UpdateBin godoc
+// @Router /warehouse/bin [put]
+// @Summary Update a bin
+// @Description Update a bin. The code TRANSIT is reserved for stock in transit
+// @Security BearerAuth
+// @Tags warehouse
+// @Accept  json
+// @Produce  json
+// @Param bin body entity.Bin true "Bin object"
+// @Success 200 {object} entity.Bin
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) UpdateBin(ctx *gin.Context) {
+	var (
+		body entity.Bin
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if body.ID == "" || body.Code == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "id and code are required", 400)
+		return
+	}
+
+	bin, err := h.UseCase.BinRepo.Update(ctx, body)
+	if h.HandleDbError(ctx, err, "Error updating bin") {
+		return
+	}
+
+	ctx.JSON(200, bin)
+}
+
+// DeleteBin godoc
+// @Router /warehouse/bin/{id} [delete]
+// @Summary Delete a bin
+// @Description Delete a bin
+// @Security BearerAuth
+// @Tags warehouse
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Bin ID"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) DeleteBin(ctx *gin.Context) {
+	var (
+		req entity.Id
+	)
+
+	req.ID = ctx.Param("id")
+
+	err := h.UseCase.BinRepo.Delete(ctx, req)
+	if h.HandleDbError(ctx, err, "Error deleting bin") {
+		return
+	}
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "Bin deleted successfully",
+	})
+}
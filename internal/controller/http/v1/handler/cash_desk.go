@@ -0,0 +1,571 @@
+package handler
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateCashDesk godoc
+// @Router /cash-desk [post]
+// @Summary Create a cash desk
+// @Description Create a cash register, card terminal or bank account payments can be made through, kept in one currency
+// @Security BearerAuth
+// @Tags cash-desk
+// @Accept  json
+// @Produce  json
+// @Param body body entity.CashDesk true "Cash desk"
+// @Success 201 {object} entity.CashDesk
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) CreateCashDesk(ctx *gin.Context) {
+	var (
+		body entity.CashDesk
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if !h.validCashDesk(ctx, body) {
+		return
+	}
+
+	cashDesk, err := h.UseCase.CashDeskRepo.Create(ctx, body)
+	if h.HandleDbError(ctx, err, "Error creating cash desk") {
+		return
+	}
+
+	ctx.JSON(201, cashDesk)
+}
+
+// GetCashDesk godoc
+// @Router /cash-desk/{id} [get]
+// @Summary Get a cash desk by ID
+// @Description Get a cash desk with its balance
+// @Security BearerAuth
+// @Tags cash-desk
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Cash desk ID"
+// @Success 200 {object} entity.CashDesk
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetCashDesk(ctx *gin.Context) {
+	var (
+		req entity.Id
+	)
+
+	req.ID = ctx.Param("id")
+
+	cashDesk, err := h.UseCase.CashDeskRepo.GetSingle(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting cash desk") {
+		return
+	}
+
+	ctx.JSON(200, cashDesk)
+}
+
+// GetCashDesks godoc
+// @Router /cash-desk/list [get]
+// @Summary Get a list of cash desks
+// @Description Get a list of cash desks with their balances
+// @Security BearerAuth
+// @Tags cash-desk
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param search query string false "name"
+// @Param type query string false "cash, card or bank"
+// @Param currency query string false "currency"
+// @Param is_active query bool false "is_active"
+// @Param format query string false "csv or xlsx, exports the whole filtered list instead of a page"
+// @Param lang query string false "uz or ru, language of the column headers of an export, uz by default"
+// @Success 200 {object} entity.CashDeskList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetCashDesks(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+	search := ctx.DefaultQuery("search", "")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+
+	if search != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "name",
+			Type:   "search",
+			Value:  search,
+		})
+	}
+
+	for _, column := range []string{"type", "currency", "is_active"} {
+		if value := ctx.DefaultQuery(column, ""); value != "" {
+			req.Filters = append(req.Filters, entity.Filter{
+				Column: column,
+				Type:   "eq",
+				Value:  value,
+			})
+		}
+	}
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "name",
+		Order:  "asc",
+	})
+
+	if h.export(ctx, "cash-desks", entity.CashDesk{}, req, func(req entity.GetListFilter) error {
+		_, err := h.UseCase.CashDeskRepo.GetList(ctx, req)
+		return err
+	}) {
+		return
+	}
+
+	cashDesks, err := h.UseCase.CashDeskRepo.GetList(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting cash desks") {
+		return
+	}
+
+	ctx.JSON(200, cashDesks)
+}
+
+// UpdateCashDesk godoc
+// @Router /cash-desk [put]
+// @Summary Update a cash desk
+// @Description Update a cash desk, its currency can't be changed
+// @Security BearerAuth
+// @Tags cash-desk
+// @Accept  json
+// @Produce  json
+// @Param body body entity.CashDesk true "Cash desk"
+// @Success 200 {object} entity.CashDesk
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) UpdateCashDesk(ctx *gin.Context) {
+	var (
+		body entity.CashDesk
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if body.ID == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "id is required", 400)
+		return
+	}
+
+	if !h.validCashDesk(ctx, body) {
+		return
+	}
+
+	cashDesk, err := h.UseCase.CashDeskRepo.Update(ctx, body)
+	if h.HandleDbError(ctx, err, "Error updating cash desk") {
+		return
+	}
+
+	ctx.JSON(200, cashDesk)
+}
+
+// DeleteCashDesk godoc
+// @Router /cash-desk/{id} [delete]
+// @Summary Delete a cash desk
+// @Description Delete a cash desk nothing went through yet, deactivate it otherwise
+// @Security BearerAuth
+// @Tags cash-desk
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Cash desk ID"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) DeleteCashDesk(ctx *gin.Context) {
+	var (
+		req entity.Id
+	)
+
+	req.ID = ctx.Param("id")
+
+	err := h.UseCase.CashDeskRepo.Delete(ctx, req)
+	if h.HandleDbError(ctx, err, "Error deleting cash desk") {
+		return
+	}
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "Cash desk deleted successfully",
+	})
+}
+
+// GetCashLedger godoc
+// @Router /cash-desk/{id}/ledger [get]
+// @Summary Get the ledger of a cash desk
+// @Description List every payment and cash operation of the desk with the balance running after each of them
+// @Security BearerAuth
+// @Tags cash-desk
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Cash desk ID"
+// @Param from query string false "from date, YYYY-MM-DD"
+// @Param to query string false "to date, YYYY-MM-DD"
+// @Success 200 {object} entity.CashLedger
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetCashLedger(ctx *gin.Context) {
+	from := ctx.DefaultQuery("from", "")
+	to := ctx.DefaultQuery("to", "")
+
+	ledger, err := h.UseCase.CashDeskRepo.GetLedger(ctx, ctx.Param("id"), from, to)
+	if h.HandleDbError(ctx, err, "Error getting cash ledger") {
+		return
+	}
+
+	ctx.JSON(200, ledger)
+}
+
+// CreateCashOperation godoc
+// @Router /cash-desk/operation [post]
+// @Summary Put money into or take it out of a cash desk
+// @Description Record a cash in or cash out outside of payments, on a day not closed yet
+// @Security BearerAuth
+// @Tags cash-desk
+// @Accept  json
+// @Produce  json
+// @Param body body entity.CashOperation true "Cash operation"
+// @Success 201 {object} entity.CashOperation
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) CreateCashOperation(ctx *gin.Context) {
+	var (
+		body entity.CashOperation
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if body.CashDeskID == "" || body.Amount <= 0 {
+		h.ReturnError(ctx, config.ErrorBadRequest, "cash_desk_id and a positive amount are required", 400)
+		return
+	}
+
+	if body.Type != entity.CashOperationIn && body.Type != entity.CashOperationOut {
+		h.ReturnError(ctx, config.ErrorBadRequest, "type must be in or out", 400)
+		return
+	}
+
+	if !h.validCashDate(ctx, body.OperationDate) {
+		return
+	}
+
+	body.DayCloseID = ""
+	body.CreatedBy = ctx.GetHeader("sub")
+
+	operation, err := h.UseCase.CashDeskRepo.CreateOperation(ctx, body)
+	if h.HandleDbError(ctx, err, "Error creating cash operation") {
+		return
+	}
+
+	ctx.JSON(201, operation)
+}
+
+// GetCashOperations godoc
+// @Router /cash-desk/operation/list [get]
+// @Summary Get a list of cash operations
+// @Description Get cash ins and outs and the over and short of day closes, newest first
+// @Security BearerAuth
+// @Tags cash-desk
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param cash_desk_id query string false "cash_desk_id"
+// @Param type query string false "in, out, over or short"
+// @Param from query string false "operation date from, YYYY-MM-DD"
+// @Param to query string false "operation date to, YYYY-MM-DD"
+// @Param format query string false "csv or xlsx, exports the whole filtered list instead of a page"
+// @Param lang query string false "uz or ru, language of the column headers of an export, uz by default"
+// @Success 200 {object} entity.CashOperationList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetCashOperations(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+	from := ctx.DefaultQuery("from", "")
+	to := ctx.DefaultQuery("to", "")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+
+	for _, column := range []string{"cash_desk_id", "type"} {
+		if value := ctx.DefaultQuery(column, ""); value != "" {
+			req.Filters = append(req.Filters, entity.Filter{
+				Column: column,
+				Type:   "eq",
+				Value:  value,
+			})
+		}
+	}
+
+	if from != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "operation_date",
+			Type:   "gte",
+			Value:  from,
+		})
+	}
+
+	if to != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "operation_date",
+			Type:   "lte",
+			Value:  to,
+		})
+	}
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "created_at",
+		Order:  "desc",
+	})
+
+	if h.export(ctx, "cash-operations", entity.CashOperation{}, req, func(req entity.GetListFilter) error {
+		_, err := h.UseCase.CashDeskRepo.GetOperations(ctx, req)
+		return err
+	}) {
+		return
+	}
+
+	operations, err := h.UseCase.CashDeskRepo.GetOperations(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting cash operations") {
+		return
+	}
+
+	ctx.JSON(200, operations)
+}
+
+// DeleteCashOperation godoc
+// @Router /cash-desk/operation/{id} [delete]
+// @Summary Delete a cash operation
+// @Description Delete a cash in or out on a day not closed yet
+// @Security BearerAuth
+// @Tags cash-desk
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Cash operation ID"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) DeleteCashOperation(ctx *gin.Context) {
+	var (
+		req entity.Id
+	)
+
+	req.ID = ctx.Param("id")
+
+	err := h.UseCase.CashDeskRepo.DeleteOperation(ctx, req)
+	if h.HandleDbError(ctx, err, "Error deleting cash operation") {
+		return
+	}
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "Cash operation deleted successfully",
+	})
+}
+
+// CloseCashDay godoc
+// @Router /cash-desk/close [post]
+// @Summary Close the day of a cash desk
+// @Description Reconcile the money counted in a desk with its balance at the end of the day. The difference is written to the desk as over or short, and nothing dated on or before the day can be booked to the desk afterwards
+// @Security BearerAuth
+// @Tags cash-desk
+// @Accept  json
+// @Produce  json
+// @Param body body entity.CashDayClose true "Day close with the counted amount"
+// @Success 201 {object} entity.CashDayClose
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) CloseCashDay(ctx *gin.Context) {
+	var (
+		body entity.CashDayClose
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if body.CashDeskID == "" || body.Counted < 0 {
+		h.ReturnError(ctx, config.ErrorBadRequest, "cash_desk_id and a counted amount not below zero are required", 400)
+		return
+	}
+
+	if !h.validCashDate(ctx, body.CloseDate) {
+		return
+	}
+
+	if body.CloseDate > time.Now().Format("2006-01-02") {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Days can't be closed in advance", 400)
+		return
+	}
+
+	body.ClosedBy = ctx.GetHeader("sub")
+
+	dayClose, err := h.UseCase.CashDeskRepo.CloseDay(ctx, body)
+	if h.HandleDbError(ctx, err, "Error closing cash day") {
+		return
+	}
+
+	ctx.JSON(201, dayClose)
+}
+
+// GetCashDayCloses godoc
+// @Router /cash-desk/close/list [get]
+// @Summary Get a list of day closes
+// @Description Get day closes with their expected and counted amounts, the latest first
+// @Security BearerAuth
+// @Tags cash-desk
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param cash_desk_id query string false "cash_desk_id"
+// @Param from query string false "close date from, YYYY-MM-DD"
+// @Param to query string false "close date to, YYYY-MM-DD"
+// @Param format query string false "csv or xlsx, exports the whole filtered list instead of a page"
+// @Param lang query string false "uz or ru, language of the column headers of an export, uz by default"
+// @Success 200 {object} entity.CashDayCloseList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetCashDayCloses(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+	from := ctx.DefaultQuery("from", "")
+	to := ctx.DefaultQuery("to", "")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+
+	if value := ctx.DefaultQuery("cash_desk_id", ""); value != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "cash_desk_id",
+			Type:   "eq",
+			Value:  value,
+		})
+	}
+
+	if from != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "close_date",
+			Type:   "gte",
+			Value:  from,
+		})
+	}
+
+	if to != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "close_date",
+			Type:   "lte",
+			Value:  to,
+		})
+	}
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "close_date",
+		Order:  "desc",
+	})
+
+	if h.export(ctx, "cash-day-closes", entity.CashDayClose{}, req, func(req entity.GetListFilter) error {
+		_, err := h.UseCase.CashDeskRepo.GetDayCloses(ctx, req)
+		return err
+	}) {
+		return
+	}
+
+	closes, err := h.UseCase.CashDeskRepo.GetDayCloses(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting day closes") {
+		return
+	}
+
+	ctx.JSON(200, closes)
+}
+
+// DeleteCashDayClose godoc
+// @Router /cash-desk/close/{id} [delete]
+// @Summary Reopen a closed day
+// @Description Delete the last day close of a cash desk together with its over or short
+// @Security BearerAuth
+// @Tags cash-desk
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Day close ID"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) DeleteCashDayClose(ctx *gin.Context) {
+	var (
+		req entity.Id
+	)
+
+	req.ID = ctx.Param("id")
+
+	err := h.UseCase.CashDeskRepo.DeleteDayClose(ctx, req)
+	if h.HandleDbError(ctx, err, "Error deleting day close") {
+		return
+	}
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "Day close deleted successfully",
+	})
+}
+
+func (h *Handler) validCashDesk(ctx *gin.Context, body entity.CashDesk) bool {
+	if body.Name == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "name is required", 400)
+		return false
+	}
+
+	switch body.Type {
+	case entity.CashDeskTypeCash, entity.CashDeskTypeCard, entity.CashDeskTypeBank:
+	default:
+		h.ReturnError(ctx, config.ErrorBadRequest, "type must be cash, card or bank", 400)
+		return false
+	}
+
+	return true
+}
+
+// validPaymentMethod accepts an empty method, which is taken from the cash desk type.
+func (h *Handler) validPaymentMethod(ctx *gin.Context, method string) bool {
+	switch method {
+	case "", entity.PaymentMethodCash, entity.PaymentMethodCard, entity.PaymentMethodBankTransfer:
+		return true
+	}
+
+	h.ReturnError(ctx, config.ErrorBadRequest, "payment_method must be cash, card or bank_transfer", 400)
+	return false
+}
+
+// validCashDate checks an optional YYYY-MM-DD date of a cash document.
+func (h *Handler) validCashDate(ctx *gin.Context, date string) bool {
+	if date == "" {
+		return true
+	}
+
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Dates must be in YYYY-MM-DD format", 400)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,287 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateCategory godoc
+// @Router /category [post]
+// @Summary Create a new category
+// @Description Create a new category, parent_id is optional
+// @Security BearerAuth
+// @Tags category
+// @Accept  json
+// @Produce  json
+// @Param category body entity.Category true "Category object"
+// @Success 201 {object} entity.Category
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) CreateCategory(ctx *gin.Context) {
+	var (
+		body entity.Category
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if body.Name == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "name is required", 400)
+		return
+	}
+
+	category, err := h.UseCase.CategoryRepo.Create(ctx, body)
+	if h.HandleDbError(ctx, err, "Error creating category") {
+		return
+	}
+
+	ctx.JSON(201, category)
+}
+
+// GetCategory godoc
+// @Router /category/{id} [get]
+// @Summary Get a category by ID
+// @Description Get a category by ID
+// @Security BearerAuth
+// @Tags category
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Category ID"
+// @Success 200 {object} entity.Category
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetCategory(ctx *gin.Context) {
+	var (
+		req entity.Id
+	)
+
+	req.ID = ctx.Param("id")
+
+	category, err := h.UseCase.CategoryRepo.GetSingle(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting category") {
+		return
+	}
+
+	ctx.JSON(200, category)
+}
+
+// GetCategories godoc
+// @Router /category/list [get]
+// @Summary Get a flat list of categories
+// @Description Get a flat list of categories
+// @Security BearerAuth
+// @Tags category
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param search query string false "search"
+// @Param parent_id query string false "parent_id"
+// @Param format query string false "csv or xlsx, exports the whole filtered list instead of a page"
+// @Param lang query string false "uz or ru, language of the column headers of an export, uz by default"
+// @Success 200 {object} entity.CategoryList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetCategories(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+	search := ctx.DefaultQuery("search", "")
+	parentID := ctx.DefaultQuery("parent_id", "")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+
+	if search != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "name",
+			Type:   "search",
+			Value:  search,
+		})
+	}
+
+	if parentID != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "parent_id",
+			Type:   "eq",
+			Value:  parentID,
+		})
+	}
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "name",
+		Order:  "asc",
+	})
+
+	if h.export(ctx, "categories", entity.Category{}, req, func(req entity.GetListFilter) error {
+		_, err := h.UseCase.CategoryRepo.GetList(ctx, req)
+		return err
+	}) {
+		return
+	}
+
+	categories, err := h.UseCase.CategoryRepo.GetList(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting categories") {
+		return
+	}
+
+	ctx.JSON(200, categories)
+}
+
+// GetCategoryTree godoc
+// @Router /category/tree [get]
+// @Summary Get the full category tree
+// @Description Get all categories nested under their parents
+// @Security BearerAuth
+// @Tags category
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} entity.Category
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetCategoryTree(ctx *gin.Context) {
+	tree, err := h.UseCase.CategoryTree(ctx, "")
+	if h.HandleDbError(ctx, err, "Error getting category tree") {
+		return
+	}
+
+	ctx.JSON(200, tree)
+}
+
+// GetCategorySubtree godoc
+// @Router /category/{id}/tree [get]
+// @Summary Get a category subtree
+// @Description Get a category with all of its descendants nested
+// @Security BearerAuth
+// @Tags category
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Category ID"
+// @Success 200 {object} entity.Category
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetCategorySubtree(ctx *gin.Context) {
+	tree, err := h.UseCase.CategoryTree(ctx, ctx.Param("id"))
+	if h.HandleDbError(ctx, err, "Error getting category subtree") {
+		return
+	}
+
+	ctx.JSON(200, tree[0])
+}
+
+// GetCategoryProductCounts godoc
+// @Router /category/product-count [get]
+// @Summary Count products per category
+// @Description Count products per category, total_count includes products of all descendants
+// @Security BearerAuth
+// @Tags category
+// @Accept  json
+// @Produce  json
+// @Param id query string false "limit to the subtree of this category"
+// @Success 200 {array} entity.CategoryProductCount
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetCategoryProductCounts(ctx *gin.Context) {
+	counts, err := h.UseCase.CategoryRepo.GetProductCounts(ctx, ctx.DefaultQuery("id", ""))
+	if h.HandleDbError(ctx, err, "Error counting category products") {
+		return
+	}
+
+	ctx.JSON(200, counts)
+}
+
+// UpdateCategory godoc
+// @Router /category [put]
+// @Summary Rename a category
+// @Description Rename a category, use /category/move to change its parent
+// @Security BearerAuth
+// @Tags category
+// @Accept  json
+// @Produce  json
+// @Param category body entity.Category true "Category object"
+// @Success 200 {object} entity.Category
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) UpdateCategory(ctx *gin.Context) {
+	var (
+		body entity.Category
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if body.ID == "" || body.Name == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "id and name are required", 400)
+		return
+	}
+
+	category, err := h.UseCase.CategoryRepo.Update(ctx, body)
+	if h.HandleDbError(ctx, err, "Error updating category") {
+		return
+	}
+
+	ctx.JSON(200, category)
+}
+
+// MoveCategory godoc
+// @Router /category/move [put]
+// @Summary Move a category under a new parent
+// @Description Move a category under a new parent, an empty parent_id makes it a root category
+// @Security BearerAuth
+// @Tags category
+// @Accept  json
+// @Produce  json
+// @Param body body entity.CategoryMoveRequest true "Move request"
+// @Success 200 {object} entity.Category
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) MoveCategory(ctx *gin.Context) {
+	var (
+		body entity.CategoryMoveRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil || body.ID == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	category, err := h.UseCase.CategoryRepo.Move(ctx, body)
+	if h.HandleDbError(ctx, err, "Error moving category") {
+		return
+	}
+
+	ctx.JSON(200, category)
+}
+
+// DeleteCategory godoc
+// @Router /category/{id} [delete]
+// @Summary Delete a category
+// @Description Delete a category, categories with children can't be deleted
+// @Security BearerAuth
+// @Tags category
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Category ID"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) DeleteCategory(ctx *gin.Context) {
+	var (
+		req entity.Id
+	)
+
+	req.ID = ctx.Param("id")
+
+	err := h.UseCase.CategoryRepo.Delete(ctx, req)
+	if h.HandleDbError(ctx, err, "Error deleting category") {
+		return
+	}
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "Category deleted successfully",
+	})
+}
@@ -0,0 +1,289 @@
+package handler
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/gin-gonic/gin"
+)
+
+// GetStockValuation godoc
+// @Router /stock/valuation [get]
+// @Summary Get the stock valuation
+// @Description Get the quantity and value of stock per product and warehouse at the end of a date, valued with the company costing method
+// @Security BearerAuth
+// @Tags stock
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param as_of query string false "YYYY-MM-DD, today by default"
+// @Param product_id query string false "product_id"
+// @Param warehouse_id query string false "warehouse_id"
+// @Param unit query string false "show quantities in this unit where the product defines it"
+// @Param currency query string false "currency of the values at its rate on as_of, the base currency by default"
+// @Param format query string false "csv or xlsx, exports the whole filtered list instead of a page"
+// @Param lang query string false "uz or ru, language of the column headers of an export, uz by default"
+// @Success 200 {object} entity.StockValuationReport
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetStockValuation(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+	asOf := ctx.DefaultQuery("as_of", time.Now().Format("2006-01-02"))
+
+	if _, err := time.Parse("2006-01-02", asOf); err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "as_of must be a date in YYYY-MM-DD format", 400)
+		return
+	}
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+
+	for _, column := range []string{"product_id", "warehouse_id"} {
+		if value := ctx.DefaultQuery(column, ""); value != "" {
+			req.Filters = append(req.Filters, entity.Filter{
+				Column: column,
+				Type:   "eq",
+				Value:  value,
+			})
+		}
+	}
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "value",
+		Order:  "desc",
+	})
+
+	currency, rate, ok := h.reportCurrency(ctx, asOf)
+	if !ok {
+		return
+	}
+
+	if h.export(ctx, "stock-valuation", entity.StockValuation{}, req, func(req entity.GetListFilter) error {
+		units, err := h.exportUnits(ctx)
+		if err != nil {
+			return err
+		}
+
+		_, err = h.UseCase.CostingRepo.GetValuation(ctx, exportEach(req, func(item entity.StockValuation) entity.StockValuation {
+			return valuationIn(item, units, rate)
+		}), asOf)
+		return err
+	}) {
+		return
+	}
+
+	report, err := h.UseCase.CostingRepo.GetValuation(ctx, req, asOf)
+	if h.HandleDbError(ctx, err, "Error getting stock valuation") {
+		return
+	}
+
+	ids := make([]string, 0, len(report.Items))
+	for _, item := range report.Items {
+		ids = append(ids, item.ProductID)
+	}
+
+	units, ok := h.reportUnits(ctx, ids)
+	if !ok {
+		return
+	}
+
+	report.Currency = currency
+	report.TotalValue = inCurrency(report.TotalValue, rate)
+
+	for i, item := range report.Items {
+		report.Items[i] = valuationIn(item, units, rate)
+	}
+
+	ctx.JSON(200, report)
+}
+
+// valuationIn shows a row of the valuation in the unit of its product in units
+// and in a currency worth rate.
+func valuationIn(item entity.StockValuation, units map[string]entity.ProductUnit, rate float64) entity.StockValuation {
+	unitCost := item.UnitCost
+
+	item.Value = inCurrency(item.Value, rate)
+	item.UnitCost = math.Round(unitCost/rate*10000) / 10000
+
+	unit, found := units[item.ProductID]
+	if !found {
+		return item
+	}
+
+	item.Quantity = inUnit(item.Quantity, unit)
+	item.UnitCost = math.Round(unitCost/rate*unit.Factor*10000) / 10000
+	item.Unit = unit.Unit
+
+	return item
+}
+
+// GetCostOfGoodsSold godoc
+// @Router /stock/cogs [get]
+// @Summary Get the cost of goods sold
+// @Description Get the cost of sales net of returns per product for a period
+// @Security BearerAuth
+// @Tags stock
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param from query string false "YYYY-MM-DD, first day of the month by default"
+// @Param to query string false "YYYY-MM-DD, today by default"
+// @Param product_id query string false "product_id"
+// @Param warehouse_id query string false "warehouse_id"
+// @Param unit query string false "show quantities in this unit where the product defines it"
+// @Param format query string false "csv or xlsx, exports the whole filtered list instead of a page"
+// @Param lang query string false "uz or ru, language of the column headers of an export, uz by default"
+// @Success 200 {object} entity.CostOfGoodsSoldReport
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetCostOfGoodsSold(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+		now = time.Now()
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+	from := ctx.DefaultQuery("from", now.AddDate(0, 0, 1-now.Day()).Format("2006-01-02"))
+	to := ctx.DefaultQuery("to", now.Format("2006-01-02"))
+
+	for _, date := range []string{from, to} {
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			h.ReturnError(ctx, config.ErrorBadRequest, "from and to must be dates in YYYY-MM-DD format", 400)
+			return
+		}
+	}
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+
+	for _, column := range []string{"product_id", "warehouse_id"} {
+		if value := ctx.DefaultQuery(column, ""); value != "" {
+			req.Filters = append(req.Filters, entity.Filter{
+				Column: column,
+				Type:   "eq",
+				Value:  value,
+			})
+		}
+	}
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "cost",
+		Order:  "desc",
+	})
+
+	if h.export(ctx, "cost-of-goods-sold", entity.CostOfGoodsSold{}, req, func(req entity.GetListFilter) error {
+		units, err := h.exportUnits(ctx)
+		if err != nil {
+			return err
+		}
+
+		_, err = h.UseCase.CostingRepo.GetCostOfGoodsSold(ctx, exportEach(req, func(item entity.CostOfGoodsSold) entity.CostOfGoodsSold {
+			return costOfGoodsSoldInUnit(item, units)
+		}), from, to)
+		return err
+	}) {
+		return
+	}
+
+	report, err := h.UseCase.CostingRepo.GetCostOfGoodsSold(ctx, req, from, to)
+	if h.HandleDbError(ctx, err, "Error getting cost of goods sold") {
+		return
+	}
+
+	ids := make([]string, 0, len(report.Items))
+	for _, item := range report.Items {
+		ids = append(ids, item.ProductID)
+	}
+
+	units, ok := h.reportUnits(ctx, ids)
+	if !ok {
+		return
+	}
+
+	for i, item := range report.Items {
+		report.Items[i] = costOfGoodsSoldInUnit(item, units)
+	}
+
+	ctx.JSON(200, report)
+}
+
+// GetSettings godoc
+// @Router /settings/ [get]
+// @Summary Get company settings
+// @Description Get the company wide settings
+// @Security BearerAuth
+// @Tags settings
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} entity.CompanySettings
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetSettings(ctx *gin.Context) {
+	settings, err := h.UseCase.SettingsRepo.GetSingle(ctx)
+	if h.HandleDbError(ctx, err, "Error getting settings") {
+		return
+	}
+
+	ctx.JSON(200, settings)
+}
+
+// UpdateSettings godoc
+// @Router /settings/ [put]
+// @Summary Update company settings
+// @Description Update the company wide settings. Switching to the weighted average merges the FIFO layers of a product on its next movement
+// @Security BearerAuth
+// @Tags settings
+// @Accept  json
+// @Produce  json
+// @Param body body entity.CompanySettings true "Settings"
+// @Success 200 {object} entity.CompanySettings
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) UpdateSettings(ctx *gin.Context) {
+	var (
+		body entity.CompanySettings
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if body.CostingMethod != entity.CostingMethodFIFO && body.CostingMethod != entity.CostingMethodAverage {
+		h.ReturnError(ctx, config.ErrorBadRequest, "costing_method must be fifo or average", 400)
+		return
+	}
+
+	if body.ReservationHours < 0 {
+		h.ReturnError(ctx, config.ErrorBadRequest, "reservation_hours can't be negative", 400)
+		return
+	}
+
+	settings, err := h.UseCase.SettingsRepo.Update(ctx, body)
+	if h.HandleDbError(ctx, err, "Error updating settings") {
+		return
+	}
+
+	ctx.JSON(200, settings)
+}
+
+// costOfGoodsSoldInUnit shows a row of the report in the unit of its product in units.
+func costOfGoodsSoldInUnit(item entity.CostOfGoodsSold, units map[string]entity.ProductUnit) entity.CostOfGoodsSold {
+	unit, found := units[item.ProductID]
+	if !found {
+		return item
+	}
+
+	item.Quantity = inUnit(item.Quantity, unit)
+	item.Unit = unit.Unit
+
+	return item
+}
@@ -0,0 +1,411 @@
+package handler
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/gin-gonic/gin"
+)
+
+var currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// CreateCurrency godoc
+// @Router /currency [post]
+// @Summary Create a currency
+// @Description Define a currency documents may be kept in, rates are set for it separately
+// @Security BearerAuth
+// @Tags currency
+// @Accept  json
+// @Produce  json
+// @Param body body entity.Currency true "Currency"
+// @Success 201 {object} entity.Currency
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) CreateCurrency(ctx *gin.Context) {
+	var (
+		body entity.Currency
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if !h.validCurrency(ctx, body) {
+		return
+	}
+
+	currency, err := h.UseCase.CurrencyRepo.Create(ctx, body)
+	if h.HandleDbError(ctx, err, "Error creating currency") {
+		return
+	}
+
+	ctx.JSON(201, currency)
+}
+
+// GetCurrency godoc
+// @Router /currency/{code} [get]
+// @Summary Get a currency by code
+// @Description Get a currency by code
+// @Security BearerAuth
+// @Tags currency
+// @Accept  json
+// @Produce  json
+// @Param code path string true "Currency code"
+// @Success 200 {object} entity.Currency
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetCurrency(ctx *gin.Context) {
+	currency, err := h.UseCase.CurrencyRepo.GetSingle(ctx, ctx.Param("code"))
+	if h.HandleDbError(ctx, err, "Error getting currency") {
+		return
+	}
+
+	ctx.JSON(200, currency)
+}
+
+// GetCurrencies godoc
+// @Router /currency/list [get]
+// @Summary Get a list of currencies
+// @Description Get a list of currencies
+// @Security BearerAuth
+// @Tags currency
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param search query string false "name"
+// @Param format query string false "csv or xlsx, exports the whole filtered list instead of a page"
+// @Param lang query string false "uz or ru, language of the column headers of an export, uz by default"
+// @Success 200 {object} entity.CurrencyList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetCurrencies(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+	search := ctx.DefaultQuery("search", "")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+
+	if search != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "name",
+			Type:   "search",
+			Value:  search,
+		})
+	}
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "code",
+		Order:  "asc",
+	})
+
+	if h.export(ctx, "currencies", entity.Currency{}, req, func(req entity.GetListFilter) error {
+		_, err := h.UseCase.CurrencyRepo.GetList(ctx, req)
+		return err
+	}) {
+		return
+	}
+
+	currencies, err := h.UseCase.CurrencyRepo.GetList(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting currencies") {
+		return
+	}
+
+	ctx.JSON(200, currencies)
+}
+
+// UpdateCurrency godoc
+// @Router /currency [put]
+// @Summary Update a currency
+// @Description Rename a currency, the code and the base currency can't be changed
+// @Security BearerAuth
+// @Tags currency
+// @Accept  json
+// @Produce  json
+// @Param body body entity.Currency true "Currency"
+// @Success 200 {object} entity.Currency
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) UpdateCurrency(ctx *gin.Context) {
+	var (
+		body entity.Currency
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if !h.validCurrency(ctx, body) {
+		return
+	}
+
+	currency, err := h.UseCase.CurrencyRepo.Update(ctx, body)
+	if h.HandleDbError(ctx, err, "Error updating currency") {
+		return
+	}
+
+	ctx.JSON(200, currency)
+}
+
+// DeleteCurrency godoc
+// @Router /currency/{code} [delete]
+// @Summary Delete a currency
+// @Description Delete a currency no document is kept in, together with its rates. The base currency can't be deleted
+// @Security BearerAuth
+// @Tags currency
+// @Accept  json
+// @Produce  json
+// @Param code path string true "Currency code"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) DeleteCurrency(ctx *gin.Context) {
+	err := h.UseCase.CurrencyRepo.Delete(ctx, ctx.Param("code"))
+	if h.HandleDbError(ctx, err, "Error deleting currency") {
+		return
+	}
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "Currency deleted successfully",
+	})
+}
+
+// SetExchangeRates godoc
+// @Router /currency/rate [post]
+// @Summary Set exchange rates
+// @Description Set the rates of currencies on dates, a rate already set for the currency and date is replaced. Rates are in base currency units per unit
+// @Security BearerAuth
+// @Tags currency
+// @Accept  json
+// @Produce  json
+// @Param body body []entity.ExchangeRate true "Rates"
+// @Success 200 {object} []entity.ExchangeRate
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) SetExchangeRates(ctx *gin.Context) {
+	var (
+		body []entity.ExchangeRate
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	for i, rate := range body {
+		if rate.Currency == "" || rate.Rate <= 0 {
+			h.ReturnError(ctx, config.ErrorBadRequest, "Each rate needs a currency and a positive rate", 400)
+			return
+		}
+
+		if rate.RateDate != "" {
+			if _, err := time.Parse("2006-01-02", rate.RateDate); err != nil {
+				h.ReturnError(ctx, config.ErrorBadRequest, "rate_date must be a date in YYYY-MM-DD format", 400)
+				return
+			}
+		}
+
+		body[i].Source = entity.RateSourceManual
+	}
+
+	rates, err := h.UseCase.CurrencyRepo.SetRates(ctx, body)
+	if h.HandleDbError(ctx, err, "Error setting exchange rates") {
+		return
+	}
+
+	ctx.JSON(200, rates)
+}
+
+// GetExchangeRates godoc
+// @Router /currency/rate/list [get]
+// @Summary Get a list of exchange rates
+// @Description Get exchange rates, the latest first
+// @Security BearerAuth
+// @Tags currency
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param currency query string false "currency"
+// @Param source query string false "manual or cbu"
+// @Param from query string false "from date, YYYY-MM-DD"
+// @Param to query string false "to date, YYYY-MM-DD"
+// @Param format query string false "csv or xlsx, exports the whole filtered list instead of a page"
+// @Param lang query string false "uz or ru, language of the column headers of an export, uz by default"
+// @Success 200 {object} entity.ExchangeRateList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetExchangeRates(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+	from := ctx.DefaultQuery("from", "")
+	to := ctx.DefaultQuery("to", "")
+
+	for _, date := range []string{from, to} {
+		if _, err := time.Parse("2006-01-02", date); date != "" && err != nil {
+			h.ReturnError(ctx, config.ErrorBadRequest, "from and to must be dates in YYYY-MM-DD format", 400)
+			return
+		}
+	}
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+
+	for _, column := range []string{"currency", "source"} {
+		if value := ctx.DefaultQuery(column, ""); value != "" {
+			req.Filters = append(req.Filters, entity.Filter{
+				Column: column,
+				Type:   "eq",
+				Value:  value,
+			})
+		}
+	}
+
+	if from != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "rate_date",
+			Type:   "gte",
+			Value:  from,
+		})
+	}
+
+	if to != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "rate_date",
+			Type:   "lte",
+			Value:  to,
+		})
+	}
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "rate_date",
+		Order:  "desc",
+	}, entity.OrderBy{
+		Column: "currency",
+		Order:  "asc",
+	})
+
+	if h.export(ctx, "exchange-rates", entity.ExchangeRate{}, req, func(req entity.GetListFilter) error {
+		_, err := h.UseCase.CurrencyRepo.GetRates(ctx, req)
+		return err
+	}) {
+		return
+	}
+
+	rates, err := h.UseCase.CurrencyRepo.GetRates(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting exchange rates") {
+		return
+	}
+
+	ctx.JSON(200, rates)
+}
+
+// DeleteExchangeRate godoc
+// @Router /currency/rate/{code}/{date} [delete]
+// @Summary Delete an exchange rate
+// @Description Delete the rate of a currency on a date, documents already made keep the rate they were made at
+// @Security BearerAuth
+// @Tags currency
+// @Accept  json
+// @Produce  json
+// @Param code path string true "Currency code"
+// @Param date path string true "YYYY-MM-DD"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) DeleteExchangeRate(ctx *gin.Context) {
+	date := ctx.Param("date")
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "date must be in YYYY-MM-DD format", 400)
+		return
+	}
+
+	err := h.UseCase.CurrencyRepo.DeleteRate(ctx, ctx.Param("code"), date)
+	if h.HandleDbError(ctx, err, "Error deleting exchange rate") {
+		return
+	}
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "Exchange rate deleted successfully",
+	})
+}
+
+// ImportExchangeRates godoc
+// @Router /currency/rate/import [post]
+// @Summary Import the official exchange rates
+// @Description Import the rates of the Central Bank of Uzbekistan for every currency defined, a rate already set for the currency and date is replaced
+// @Security BearerAuth
+// @Tags currency
+// @Accept  json
+// @Produce  json
+// @Param date query string false "YYYY-MM-DD, the latest published by default"
+// @Success 200 {object} []entity.ExchangeRate
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) ImportExchangeRates(ctx *gin.Context) {
+	date := ctx.DefaultQuery("date", "")
+	if date != "" {
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			h.ReturnError(ctx, config.ErrorBadRequest, "date must be in YYYY-MM-DD format", 400)
+			return
+		}
+	}
+
+	rates, err := h.UseCase.ImportRates(ctx, date)
+	if h.HandleDbError(ctx, err, "Error importing exchange rates") {
+		return
+	}
+
+	ctx.JSON(200, rates)
+}
+
+func (h *Handler) validCurrency(ctx *gin.Context, body entity.Currency) bool {
+	if !currencyCode.MatchString(body.Code) {
+		h.ReturnError(ctx, config.ErrorBadRequest, "code must be a three letter ISO 4217 code in capitals", 400)
+		return false
+	}
+
+	if body.Name == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "name is required", 400)
+		return false
+	}
+
+	return true
+}
+
+// reportCurrency looks up the rate on date of the currency asked for with the
+// currency query parameter, the base currency with a rate of 1 by default. It
+// returns false when the response has already been written.
+func (h *Handler) reportCurrency(ctx *gin.Context, date string) (string, float64, bool) {
+	currency, rate, err := h.UseCase.CurrencyRepo.GetRate(ctx, ctx.DefaultQuery("currency", ""), date)
+	if h.HandleDbError(ctx, err, "Error getting report currency") {
+		return "", 0, false
+	}
+
+	return currency, rate, true
+}
+
+// agingInCurrency converts the buckets of a debt aging row to a currency worth rate.
+func agingInCurrency(days0To30, days31To60, days61To90, days90Plus, total *float64, rate float64) {
+	for _, amount := range []*float64{days0To30, days31To60, days61To90, days90Plus, total} {
+		*amount = inCurrency(*amount, rate)
+	}
+}
+
+// inCurrency converts a base currency amount to a currency worth rate.
+func inCurrency(amount, rate float64) float64 {
+	if rate <= 0 {
+		return amount
+	}
+
+	return math.Round(amount/rate*100) / 100
+}
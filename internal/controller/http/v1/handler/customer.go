@@ -0,0 +1,442 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateCustomer godoc
+// @Router /customer [post]
+// @Summary Create a new customer
+// @Description Create a new customer
+// @Security BearerAuth
+// @Tags customer
+// @Accept  json
+// @Produce  json
+// @Param customer body entity.Customer true "Customer object"
+// @Success 201 {object} entity.Customer
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) CreateCustomer(ctx *gin.Context) {
+	var (
+		body entity.Customer
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if body.Name == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "name is required", 400)
+		return
+	}
+
+	customer, err := h.UseCase.CustomerRepo.Create(ctx, body)
+	if h.HandleDbError(ctx, err, "Error creating customer") {
+		return
+	}
+
+	ctx.JSON(201, customer)
+}
+
+// GetCustomer godoc
+// @Router /customer/{id} [get]
+// @Summary Get a customer by ID
+// @Description Get a customer by ID
+// @Security BearerAuth
+// @Tags customer
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Customer ID"
+// @Success 200 {object} entity.Customer
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetCustomer(ctx *gin.Context) {
+	var (
+		req entity.Id
+	)
+
+	req.ID = ctx.Param("id")
+
+	customer, err := h.UseCase.CustomerRepo.GetSingle(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting customer") {
+		return
+	}
+
+	ctx.JSON(200, customer)
+}
+
+// GetCustomers godoc
+// @Router /customer/list [get]
+// @Summary Get a list of customers
+// @Description Get a list of customers
+// @Security BearerAuth
+// @Tags customer
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param search query string false "search by name, phone or tax id"
+// @Param is_active query bool false "is_active"
+// @Param price_list_id query string false "price_list_id"
+// @Param format query string false "csv or xlsx, exports the whole filtered list instead of a page"
+// @Param lang query string false "uz or ru, language of the column headers of an export, uz by default"
+// @Success 200 {object} entity.CustomerList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetCustomers(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+	search := ctx.DefaultQuery("search", "")
+	isActive := ctx.DefaultQuery("is_active", "")
+	priceListID := ctx.DefaultQuery("price_list_id", "")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+
+	if search != "" {
+		req.Filters = append(req.Filters,
+			entity.Filter{
+				Column: "name",
+				Type:   "search",
+				Value:  search,
+			},
+			entity.Filter{
+				Column: "phone",
+				Type:   "search",
+				Value:  search,
+			},
+			entity.Filter{
+				Column: "tax_id",
+				Type:   "search",
+				Value:  search,
+			},
+		)
+	}
+
+	if isActive != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "is_active",
+			Type:   "eq",
+			Value:  isActive,
+		})
+	}
+
+	if priceListID != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "price_list_id",
+			Type:   "eq",
+			Value:  priceListID,
+		})
+	}
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "name",
+		Order:  "asc",
+	})
+
+	if h.export(ctx, "customers", entity.Customer{}, req, func(req entity.GetListFilter) error {
+		_, err := h.UseCase.CustomerRepo.GetList(ctx, req)
+		return err
+	}) {
+		return
+	}
+
+	customers, err := h.UseCase.CustomerRepo.GetList(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting customers") {
+		return
+	}
+
+	ctx.JSON(200, customers)
+}
+
+// UpdateCustomer godoc
+// @Router /customer [put]
+// @Summary Update a customer
+// @Description Update a customer
+// @Security BearerAuth
+// @Tags customer
+// @Accept  json
+// @Produce  json
+// @Param customer body entity.Customer true "Customer object"
+// @Success 200 {object} entity.Customer
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) UpdateCustomer(ctx *gin.Context) {
+	var (
+		body entity.Customer
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if body.ID == "" || body.Name == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "id and name are required", 400)
+		return
+	}
+
+	customer, err := h.UseCase.CustomerRepo.Update(ctx, body)
+	if h.HandleDbError(ctx, err, "Error updating customer") {
+		return
+	}
+
+	ctx.JSON(200, customer)
+}
+
+// DeleteCustomer godoc
+// @Router /customer/{id} [delete]
+// @Summary Delete a customer
+// @Description Delete a customer, customers with orders can't be deleted
+// @Security BearerAuth
+// @Tags customer
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Customer ID"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) DeleteCustomer(ctx *gin.Context) {
+	var (
+		req entity.Id
+	)
+
+	req.ID = ctx.Param("id")
+
+	err := h.UseCase.CustomerRepo.Delete(ctx, req)
+	if h.HandleDbError(ctx, err, "Error deleting customer") {
+		return
+	}
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "Customer deleted successfully",
+	})
+}
+
+// CreateCustomerPayment godoc
+// @Router /customer/payment [post]
+// @Summary Pay for a sales order
+// @Description Record a full or partial payment against an order through a cash desk kept in the order currency, paying more than the unpaid amount is rejected
+// @Security BearerAuth
+// @Tags customer
+// @Accept  json
+// @Produce  json
+// @Param payment body entity.CustomerPayment true "Customer payment object"
+// @Success 201 {object} entity.CustomerPayment
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) CreateCustomerPayment(ctx *gin.Context) {
+	var (
+		body entity.CustomerPayment
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if body.OrderID == "" || body.CashDeskID == "" || body.Amount <= 0 {
+		h.ReturnError(ctx, config.ErrorBadRequest, "order_id, cash_desk_id and a positive amount are required", 400)
+		return
+	}
+
+	if !h.validPaymentMethod(ctx, body.Method) {
+		return
+	}
+
+	body.CreatedBy = ctx.GetHeader("sub")
+
+	payment, err := h.UseCase.CustomerPaymentRepo.Create(ctx, body)
+	if h.HandleDbError(ctx, err, "Error creating customer payment") {
+		return
+	}
+
+	ctx.JSON(201, payment)
+}
+
+// GetCustomerPayments godoc
+// @Router /customer/payment/list [get]
+// @Summary Get a list of customer payments
+// @Description Get a list of customer payments, newest first
+// @Security BearerAuth
+// @Tags customer
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param customer_id query string false "customer_id"
+// @Param order_id query string false "order_id"
+// @Param cash_desk_id query string false "cash_desk_id"
+// @Param payment_method query string false "cash, card or bank_transfer"
+// @Param from query string false "paid date from, YYYY-MM-DD"
+// @Param to query string false "paid date to, YYYY-MM-DD"
+// @Param format query string false "csv or xlsx, exports the whole filtered list instead of a page"
+// @Param lang query string false "uz or ru, language of the column headers of an export, uz by default"
+// @Success 200 {object} entity.CustomerPaymentList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetCustomerPayments(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+	from := ctx.DefaultQuery("from", "")
+	to := ctx.DefaultQuery("to", "")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+
+	for _, column := range []string{"customer_id", "order_id", "cash_desk_id", "payment_method"} {
+		if value := ctx.DefaultQuery(column, ""); value != "" {
+			req.Filters = append(req.Filters, entity.Filter{
+				Column: column,
+				Type:   "eq",
+				Value:  value,
+			})
+		}
+	}
+
+	if from != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "paid_date",
+			Type:   "gte",
+			Value:  from,
+		})
+	}
+
+	if to != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "paid_date",
+			Type:   "lte",
+			Value:  to,
+		})
+	}
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "created_at",
+		Order:  "desc",
+	})
+
+	if h.export(ctx, "customer-payments", entity.CustomerPayment{}, req, func(req entity.GetListFilter) error {
+		_, err := h.UseCase.CustomerPaymentRepo.GetList(ctx, req)
+		return err
+	}) {
+		return
+	}
+
+	payments, err := h.UseCase.CustomerPaymentRepo.GetList(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting customer payments") {
+		return
+	}
+
+	ctx.JSON(200, payments)
+}
+
+// DeleteCustomerPayment godoc
+// @Router /customer/payment/{id} [delete]
+// @Summary Delete a customer payment
+// @Description Delete a payment recorded by mistake, the debt of its order grows back
+// @Security BearerAuth
+// @Tags customer
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Customer payment ID"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) DeleteCustomerPayment(ctx *gin.Context) {
+	var (
+		req entity.Id
+	)
+
+	req.ID = ctx.Param("id")
+
+	err := h.UseCase.CustomerPaymentRepo.Delete(ctx, req)
+	if h.HandleDbError(ctx, err, "Error deleting customer payment") {
+		return
+	}
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "Customer payment deleted successfully",
+	})
+}
+
+// GetCustomerDebtAging godoc
+// @Router /customer/aging [get]
+// @Summary Get the customer debt aging report
+// @Description Bucket the unpaid part of completed orders per customer into 0-30, 31-60, 61-90 and 90+ days since completion. Debts are aged in the base currency at the rate of their document, then converted to the report currency at the rate of today
+// @Security BearerAuth
+// @Tags customer
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param customer_id query string false "customer_id"
+// @Param warehouse_id query string false "warehouse_id"
+// @Param currency query string false "currency of the amounts, the base currency by default"
+// @Param format query string false "csv or xlsx, exports the whole filtered list instead of a page"
+// @Param lang query string false "uz or ru, language of the column headers of an export, uz by default"
+// @Success 200 {object} entity.DebtAgingReport
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetCustomerDebtAging(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+
+	for _, column := range []string{"customer_id", "warehouse_id"} {
+		if value := ctx.DefaultQuery(column, ""); value != "" {
+			req.Filters = append(req.Filters, entity.Filter{
+				Column: column,
+				Type:   "eq",
+				Value:  value,
+			})
+		}
+	}
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "total",
+		Order:  "desc",
+	})
+
+	currency, rate, ok := h.reportCurrency(ctx, "")
+	if !ok {
+		return
+	}
+
+	if h.export(ctx, "customer-debt-aging", entity.DebtAging{}, req, func(req entity.GetListFilter) error {
+		_, err := h.UseCase.CustomerRepo.GetDebtAging(ctx, exportEach(req, func(item entity.DebtAging) entity.DebtAging {
+			agingInCurrency(&item.Days0To30, &item.Days31To60, &item.Days61To90, &item.Days90Plus, &item.Total, rate)
+			return item
+		}))
+		return err
+	}) {
+		return
+	}
+
+	report, err := h.UseCase.CustomerRepo.GetDebtAging(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting customer debt aging") {
+		return
+	}
+
+	report.Currency = currency
+	for i := range report.Items {
+		agingInCurrency(&report.Items[i].Days0To30, &report.Items[i].Days31To60, &report.Items[i].Days61To90,
+			&report.Items[i].Days90Plus, &report.Items[i].Total, rate)
+	}
+	agingInCurrency(&report.Totals.Days0To30, &report.Totals.Days31To60, &report.Totals.Days61To90,
+		&report.Totals.Days90Plus, &report.Totals.Total, rate)
+
+	ctx.JSON(200, report)
+}
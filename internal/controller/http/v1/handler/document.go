@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/gin-gonic/gin"
+)
+
+// PrintDocument godoc
+// @Router /document/{type}/{id}/pdf [get]
+// @Summary Print a document
+// @Description Print a sales order, goods receipt or transfer as an invoice or waybill. Printed files are kept in private storage and served from there through this endpoint until the document, the company details or the template change
+// @Security BearerAuth
+// @Tags document
+// @Produce  application/pdf
+// @Param type path string true "sales-order, goods-receipt or transfer"
+// @Param id path string true "Document ID"
+// @Param form query string false "invoice or waybill, invoice for sales orders and goods receipts and waybill for transfers by default"
+// @Param lang query string false "uz or ru, uz by default"
+// @Param regenerate query bool false "print again even when a stored file is found"
+// @Success 200 {file} file
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+func (h *Handler) PrintDocument(ctx *gin.Context) {
+	req := entity.PrintRequest{
+		Type:       ctx.Param("type"),
+		ID:         ctx.Param("id"),
+		Form:       ctx.DefaultQuery("form", ""),
+		Lang:       ctx.DefaultQuery("lang", ""),
+		Regenerate: ctx.DefaultQuery("regenerate", "false") == "true",
+	}
+
+	if req.Type != entity.DocumentSalesOrder && req.Type != entity.DocumentGoodsReceipt && req.Type != entity.DocumentTransfer {
+		h.ReturnError(ctx, config.ErrorBadRequest, "type must be sales-order, goods-receipt or transfer", 400)
+		return
+	}
+
+	if req.Form != "" && !validDocumentForm(req.Form) {
+		h.ReturnError(ctx, config.ErrorBadRequest, "form must be invoice or waybill", 400)
+		return
+	}
+
+	if req.Lang != "" && req.Lang != entity.LangUzbek && req.Lang != entity.LangRussian {
+		h.ReturnError(ctx, config.ErrorBadRequest, "lang must be uz or ru", 400)
+		return
+	}
+
+	doc, err := h.UseCase.PrepareDocument(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting document") {
+		return
+	}
+
+	var content []byte
+	if !req.Regenerate {
+		// a missing file is printed again
+		content, err = h.MinIO.DownloadDocument(doc.Object)
+	}
+
+	if req.Regenerate || err != nil || len(content) == 0 {
+		content, err = h.UseCase.RenderDocument(doc)
+		if h.HandleDbError(ctx, err, "Error printing document") {
+			return
+		}
+
+		err = h.MinIO.UploadDocument(doc.Object, content)
+		if err != nil {
+			// the document is still served, it is printed again next time
+			h.Logger.Error(err, "Error storing printed document")
+		}
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf(`inline; filename="%s-%s.pdf"`, doc.Form, doc.Number))
+	ctx.Data(200, "application/pdf", content)
+}
+
+// GetDocumentTemplates godoc
+// @Router /document/template/list [get]
+// @Summary Get document templates
+// @Description Get the layout of every printed form
+// @Security BearerAuth
+// @Tags document
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} entity.DocumentTemplateList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetDocumentTemplates(ctx *gin.Context) {
+	templates, err := h.UseCase.DocumentTemplateRepo.GetList(ctx)
+	if h.HandleDbError(ctx, err, "Error getting document templates") {
+		return
+	}
+
+	ctx.JSON(200, templates)
+}
+
+// GetDocumentTemplate godoc
+// @Router /document/template/{form} [get]
+// @Summary Get a document template
+// @Description Get the layout of a printed form
+// @Security BearerAuth
+// @Tags document
+// @Accept  json
+// @Produce  json
+// @Param form path string true "invoice or waybill"
+// @Success 200 {object} entity.DocumentTemplate
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+func (h *Handler) GetDocumentTemplate(ctx *gin.Context) {
+	template, err := h.UseCase.DocumentTemplateRepo.GetSingle(ctx, ctx.Param("form"))
+	if h.HandleDbError(ctx, err, "Error getting document template") {
+		return
+	}
+
+	ctx.JSON(200, template)
+}
+
+// UpdateDocumentTemplate godoc
+// @Router /document/template [put]
+// @Summary Update a document template
+// @Description Change the layout of a printed form. Empty title and signatures fall back to the defaults of the language a document is printed in. Documents are printed again on their next download
+// @Security BearerAuth
+// @Tags document
+// @Accept  json
+// @Produce  json
+// @Param body body entity.DocumentTemplate true "Template"
+// @Success 200 {object} entity.DocumentTemplate
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+func (h *Handler) UpdateDocumentTemplate(ctx *gin.Context) {
+	var (
+		body entity.DocumentTemplate
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if !h.validDocumentTemplate(ctx, &body) {
+		return
+	}
+
+	template, err := h.UseCase.DocumentTemplateRepo.Update(ctx, body)
+	if h.HandleDbError(ctx, err, "Error updating document template") {
+		return
+	}
+
+	ctx.JSON(200, template)
+}
+
+func validDocumentForm(form string) bool {
+	return form == entity.FormInvoice || form == entity.FormWaybill
+}
+
+func (h *Handler) validDocumentTemplate(ctx *gin.Context, body *entity.DocumentTemplate) bool {
+	if !validDocumentForm(body.Form) {
+		h.ReturnError(ctx, config.ErrorBadRequest, "form must be invoice or waybill", 400)
+		return false
+	}
+
+	if body.FontSize == 0 {
+		body.FontSize = 9
+	}
+
+	if body.FontSize < 6 || body.FontSize > 14 {
+		h.ReturnError(ctx, config.ErrorBadRequest, "font_size must be between 6 and 14", 400)
+		return false
+	}
+
+	seen := map[string]bool{}
+	for _, column := range body.Columns {
+		known := false
+		for _, c := range entity.DocumentColumns {
+			known = known || c == column
+		}
+
+		if !known || seen[column] {
+			h.ReturnError(ctx, config.ErrorBadRequest, "columns must be distinct, one of no, sku, name, unit, quantity, price and amount", 400)
+			return false
+		}
+		seen[column] = true
+	}
+
+	for _, signature := range body.Signatures {
+		if signature == "" {
+			h.ReturnError(ctx, config.ErrorBadRequest, "signatures can't be empty", 400)
+			return false
+		}
+	}
+
+	return true
+}
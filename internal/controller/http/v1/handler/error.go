@@ -20,6 +20,15 @@ func (h Handler) HandleDbError(c *gin.Context, err error, message string) bool {
 	var errorResponse entity.ErrorResponse
 	statusCode := http.StatusInternalServerError
 
+	var appErr *entity.Error
+	if errors.As(err, &appErr) {
+		c.JSON(http.StatusBadRequest, entity.ErrorResponse{
+			Message: appErr.Message,
+			Code:    appErr.Code,
+		})
+		return true
+	}
+
 	if err == pgx.ErrNoRows {
 		errorResponse = entity.ErrorResponse{
 			Message: "The requested resource was not found.",
@@ -0,0 +1,553 @@
+package handler
+
+import (
+	"encoding/csv"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/xlsx"
+	"github.com/gin-gonic/gin"
+)
+
+// Formats lists are exported in.
+const (
+	exportCSV  = "csv"
+	exportXLSX = "xlsx"
+)
+
+// exportLabels are the headers of the columns of exports by their json name.
+// Columns without a label are headed by their json name.
+var exportLabels = map[string]map[string]string{
+	entity.LangUzbek: {
+		"id":                    "ID",
+		"number":                "Raqami",
+		"name":                  "Nomi",
+		"code":                  "Kodi",
+		"type":                  "Turi",
+		"kind":                  "Turi",
+		"status":                "Holati",
+		"note":                  "Izoh",
+		"description":           "Tavsif",
+		"address":               "Manzil",
+		"phone":                 "Telefon",
+		"email":                 "Email",
+		"tax_id":                "STIR",
+		"contact_person":        "Aloqa uchun shaxs",
+		"is_active":             "Faol",
+		"is_base":               "Asosiy",
+		"is_virtual":            "Virtual",
+		"is_serialized":         "Seriyali",
+		"is_expired":            "Muddati o'tgan",
+		"created_at":            "Yaratilgan",
+		"updated_at":            "Yangilangan",
+		"created_by":            "Yaratgan",
+		"user_id":               "Foydalanuvchi",
+		"full_name":             "F.I.Sh.",
+		"username":              "Login",
+		"user_type":             "Foydalanuvchi turi",
+		"user_role":             "Roli",
+		"gender":                "Jinsi",
+		"bio":                   "Bio",
+		"profile_picture":       "Rasm",
+		"ip_address":            "IP manzil",
+		"user_agent":            "Qurilma",
+		"platform":              "Platforma",
+		"expires_at":            "Amal qilish muddati",
+		"last_active_at":        "Oxirgi faollik",
+		"product_id":            "Tovar",
+		"product_name":          "Tovar nomi",
+		"sku":                   "Artikul",
+		"barcodes":              "Shtrix kodlar",
+		"barcode_unit":          "Shtrix kod birligi",
+		"base_unit":             "O'lchov birligi",
+		"unit":                  "O'lchov birligi",
+		"sale_price":            "Sotish narxi",
+		"category_id":           "Kategoriya",
+		"parent_id":             "Yuqori kategoriya",
+		"depth":                 "Daraja",
+		"preferred_supplier_id": "Asosiy yetkazib beruvchi",
+		"warehouse_id":          "Ombor",
+		"source_warehouse_id":   "Jo'natuvchi ombor",
+		"dest_warehouse_id":     "Qabul qiluvchi ombor",
+		"zone_id":               "Zona",
+		"bin_id":                "Yacheyka",
+		"bin_code":              "Yacheyka kodi",
+		"transit_bin_id":        "Tranzit yacheyka",
+		"lot_id":                "Partiya",
+		"lot_number":            "Partiya raqami",
+		"expiry_date":           "Yaroqlilik muddati",
+		"days_left":             "Qolgan kunlar",
+		"serial_number":         "Seriya raqami",
+		"serials":               "Seriya raqamlari",
+		"quantity":              "Miqdori",
+		"min_quantity":          "Minimal miqdor",
+		"max_quantity":          "Maksimal miqdor",
+		"reorder_point":         "Buyurtma nuqtasi",
+		"on_hand":               "Mavjud",
+		"reserved":              "Band qilingan",
+		"available":             "Bo'sh",
+		"unit_cost":             "Tannarx",
+		"cost":                  "Tannarx summasi",
+		"value":                 "Qiymati",
+		"reason":                "Sabab",
+		"document_type":         "Hujjat turi",
+		"document_id":           "Hujjat",
+		"supplier_id":           "Yetkazib beruvchi",
+		"customer_id":           "Xaridor",
+		"customer_name":         "Xaridor nomi",
+		"order_id":              "Buyurtma",
+		"order_number":          "Buyurtma raqami",
+		"line_id":               "Buyurtma qatori",
+		"purchase_order_id":     "Xarid buyurtmasi",
+		"goods_receipt_id":      "Kirim hujjati",
+		"credit_id":             "Qarz hujjati",
+		"order_date":            "Buyurtma sanasi",
+		"expected_date":         "Kutilgan sana",
+		"receipt_date":          "Kirim sanasi",
+		"credit_date":           "Sana",
+		"due_date":              "To'lov muddati",
+		"paid_date":             "To'lov sanasi",
+		"sent_at":               "Yuborilgan",
+		"posted_at":             "O'tkazilgan",
+		"closed_at":             "Yopilgan",
+		"cancelled_at":          "Bekor qilingan",
+		"completed_at":          "Yakunlangan",
+		"shipped_at":            "Jo'natilgan",
+		"approved_at":           "Tasdiqlangan",
+		"approved_by":           "Tasdiqlagan",
+		"dispatched_at":         "Jo'natilgan",
+		"dispatched_by":         "Jo'natgan",
+		"received_at":           "Qabul qilingan",
+		"received_by":           "Qabul qilgan",
+		"currency":              "Valyuta",
+		"exchange_rate":         "Valyuta kursi",
+		"rate":                  "Kurs",
+		"rate_date":             "Kurs sanasi",
+		"source":                "Manba",
+		"amount":                "Summa",
+		"total_amount":          "Jami summa",
+		"paid_amount":           "To'langan",
+		"returned_amount":       "Qaytarilgan",
+		"debt":                  "Qarz",
+		"remaining_debt":        "Qolgan qarz",
+		"match_status":          "Solishtirish holati",
+		"price_list_id":         "Narxlar ro'yxati",
+		"price_list_name":       "Narxlar ro'yxati nomi",
+		"valid_from":            "Amal qilish boshlanishi",
+		"valid_to":              "Amal qilish tugashi",
+		"cash_desk_id":          "Kassa",
+		"payment_method":        "To'lov usuli",
+		"operation_date":        "Operatsiya sanasi",
+		"day_close_id":          "Kun yopilishi",
+		"balance":               "Qoldiq",
+		"last_close":            "Oxirgi yopilish",
+		"close_date":            "Yopilish sanasi",
+		"expected":              "Kutilgan",
+		"counted":               "Sanalgan",
+		"difference":            "Farq",
+		"closed_by":             "Yopgan",
+		"carrier":               "Tashuvchi",
+		"tracking_number":       "Kuzatuv raqami",
+		"total_weight":          "Umumiy og'irlik",
+		"days_0_30":             "0-30 kun",
+		"days_31_60":            "31-60 kun",
+		"days_61_90":            "61-90 kun",
+		"days_90_plus":          "90 kundan ortiq",
+		"total":                 "Jami",
+		"file_name":             "Fayl nomi",
+		"columns":               "Ustunlar",
+		"dry_run":               "Sinov",
+		"total_rows":            "Jami qatorlar",
+		"processed":             "Ishlangan",
+		"failed_rows":           "Xato qatorlar",
+		"created":               "Yaratildi",
+		"updated":               "Yangilandi",
+		"error":                 "Xato",
+		"started_at":            "Boshlangan",
+		"finished_at":           "Tugagan",
+		"job_id":                "Import",
+		"row_number":            "Qator raqami",
+		"cells":                 "Kataklar",
+		"record_id":             "Yozuv",
+	},
+	entity.LangRussian: {
+		"id":                    "ID",
+		"number":                "Номер",
+		"name":                  "Наименование",
+		"code":                  "Код",
+		"type":                  "Тип",
+		"kind":                  "Тип",
+		"status":                "Статус",
+		"note":                  "Примечание",
+		"description":           "Описание",
+		"address":               "Адрес",
+		"phone":                 "Телефон",
+		"email":                 "Email",
+		"tax_id":                "ИНН",
+		"contact_person":        "Контактное лицо",
+		"is_active":             "Активен",
+		"is_base":               "Базовая",
+		"is_virtual":            "Виртуальная",
+		"is_serialized":         "Серийный",
+		"is_expired":            "Истёк",
+		"created_at":            "Создан",
+		"updated_at":            "Изменён",
+		"created_by":            "Автор",
+		"user_id":               "Пользователь",
+		"full_name":             "ФИО",
+		"username":              "Логин",
+		"user_type":             "Тип пользователя",
+		"user_role":             "Роль",
+		"gender":                "Пол",
+		"bio":                   "О себе",
+		"profile_picture":       "Фото",
+		"ip_address":            "IP-адрес",
+		"user_agent":            "Устройство",
+		"platform":              "Платформа",
+		"expires_at":            "Действует до",
+		"last_active_at":        "Последняя активность",
+		"product_id":            "Товар",
+		"product_name":          "Наименование товара",
+		"sku":                   "Артикул",
+		"barcodes":              "Штрихкоды",
+		"barcode_unit":          "Единица штрихкода",
+		"base_unit":             "Ед. изм.",
+		"unit":                  "Ед. изм.",
+		"sale_price":            "Цена продажи",
+		"category_id":           "Категория",
+		"parent_id":             "Родительская категория",
+		"depth":                 "Уровень",
+		"preferred_supplier_id": "Основной поставщик",
+		"warehouse_id":          "Склад",
+		"source_warehouse_id":   "Склад-отправитель",
+		"dest_warehouse_id":     "Склад-получатель",
+		"zone_id":               "Зона",
+		"bin_id":                "Ячейка",
+		"bin_code":              "Код ячейки",
+		"transit_bin_id":        "Транзитная ячейка",
+		"lot_id":                "Партия",
+		"lot_number":            "Номер партии",
+		"expiry_date":           "Срок годности",
+		"days_left":             "Осталось дней",
+		"serial_number":         "Серийный номер",
+		"serials":               "Серийные номера",
+		"quantity":              "Количество",
+		"min_quantity":          "Мин. количество",
+		"max_quantity":          "Макс. количество",
+		"reorder_point":         "Точка заказа",
+		"on_hand":               "В наличии",
+		"reserved":              "Зарезервировано",
+		"available":             "Доступно",
+		"unit_cost":             "Себестоимость",
+		"cost":                  "Сумма себестоимости",
+		"value":                 "Стоимость",
+		"reason":                "Причина",
+		"document_type":         "Тип документа",
+		"document_id":           "Документ",
+		"supplier_id":           "Поставщик",
+		"customer_id":           "Покупатель",
+		"customer_name":         "Наименование покупателя",
+		"order_id":              "Заказ",
+		"order_number":          "Номер заказа",
+		"line_id":               "Строка заказа",
+		"purchase_order_id":     "Заказ поставщику",
+		"goods_receipt_id":      "Приходная накладная",
+		"credit_id":             "Долговой документ",
+		"order_date":            "Дата заказа",
+		"expected_date":         "Ожидаемая дата",
+		"receipt_date":          "Дата прихода",
+		"credit_date":           "Дата",
+		"due_date":              "Срок оплаты",
+		"paid_date":             "Дата оплаты",
+		"sent_at":               "Отправлен",
+		"posted_at":             "Проведён",
+		"closed_at":             "Закрыт",
+		"cancelled_at":          "Отменён",
+		"completed_at":          "Завершён",
+		"shipped_at":            "Отгружен",
+		"approved_at":           "Утверждён",
+		"approved_by":           "Утвердил",
+		"dispatched_at":         "Отправлен",
+		"dispatched_by":         "Отправил",
+		"received_at":           "Получен",
+		"received_by":           "Получил",
+		"currency":              "Валюта",
+		"exchange_rate":         "Курс валюты",
+		"rate":                  "Курс",
+		"rate_date":             "Дата курса",
+		"source":                "Источник",
+		"amount":                "Сумма",
+		"total_amount":          "Итоговая сумма",
+		"paid_amount":           "Оплачено",
+		"returned_amount":       "Возвращено",
+		"debt":                  "Долг",
+		"remaining_debt":        "Остаток долга",
+		"match_status":          "Статус сверки",
+		"price_list_id":         "Прайс-лист",
+		"price_list_name":       "Наименование прайс-листа",
+		"valid_from":            "Действует с",
+		"valid_to":              "Действует по",
+		"cash_desk_id":          "Касса",
+		"payment_method":        "Способ оплаты",
+		"operation_date":        "Дата операции",
+		"day_close_id":          "Закрытие дня",
+		"balance":               "Остаток",
+		"last_close":            "Последнее закрытие",
+		"close_date":            "Дата закрытия",
+		"expected":              "Ожидалось",
+		"counted":               "Посчитано",
+		"difference":            "Разница",
+		"closed_by":             "Закрыл",
+		"carrier":               "Перевозчик",
+		"tracking_number":       "Трек-номер",
+		"total_weight":          "Общий вес",
+		"days_0_30":             "0-30 дней",
+		"days_31_60":            "31-60 дней",
+		"days_61_90":            "61-90 дней",
+		"days_90_plus":          "Более 90 дней",
+		"total":                 "Итого",
+		"file_name":             "Имя файла",
+		"columns":               "Столбцы",
+		"dry_run":               "Проверка",
+		"total_rows":            "Всего строк",
+		"processed":             "Обработано",
+		"failed_rows":           "Строк с ошибками",
+		"created":               "Создано",
+		"updated":               "Обновлено",
+		"error":                 "Ошибка",
+		"started_at":            "Начат",
+		"finished_at":           "Завершён",
+		"job_id":                "Импорт",
+		"row_number":            "Номер строки",
+		"cells":                 "Ячейки",
+		"record_id":             "Запись",
+	},
+}
+
+// exportColumn is a field of a list item written as a column of an export.
+type exportColumn struct {
+	name  string
+	index []int
+}
+
+// exportWriter writes the rows of an export in one of the formats.
+type exportWriter interface {
+	Write(values []interface{}) error
+	Close() error
+}
+
+// csvExport writes an export as CSV with a byte order mark, so that Excel
+// opens it as UTF-8.
+type csvExport struct {
+	writer *csv.Writer
+	record []string
+}
+
+func newCSVExport(w io.Writer, header []string) (*csvExport, error) {
+	if _, err := io.WriteString(w, "\xef\xbb\xbf"); err != nil {
+		return nil, err
+	}
+
+	e := &csvExport{writer: csv.NewWriter(w), record: make([]string, len(header))}
+
+	return e, e.writer.Write(header)
+}
+
+func (e *csvExport) Write(values []interface{}) error {
+	for i, value := range values {
+		switch v := value.(type) {
+		case string:
+			// text that looks like a formula is opened as text, not run
+			if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+				v = "'" + v
+			}
+			e.record[i] = v
+		case bool:
+			e.record[i] = strconv.FormatBool(v)
+		case int64:
+			e.record[i] = strconv.FormatInt(v, 10)
+		case float64:
+			e.record[i] = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			e.record[i] = ""
+		}
+	}
+
+	return e.writer.Write(e.record)
+}
+
+func (e *csvExport) Close() error {
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+// export streams the whole filtered list of a list endpoint as a CSV or XLSX
+// file when the format query parameter asks for one. list gets the request
+// with the export set and has to read the list with it. It returns false when
+// no export is asked for, else the response has been written.
+func (h *Handler) export(ctx *gin.Context, name string, item interface{}, req entity.GetListFilter, list func(req entity.GetListFilter) error) bool {
+	format := ctx.DefaultQuery("format", "")
+	if format == "" {
+		return false
+	}
+
+	if format != exportCSV && format != exportXLSX {
+		h.ReturnError(ctx, config.ErrorBadRequest, "format must be csv or xlsx", 400)
+		return true
+	}
+
+	lang := ctx.DefaultQuery("lang", entity.LangUzbek)
+	if lang != entity.LangUzbek && lang != entity.LangRussian {
+		h.ReturnError(ctx, config.ErrorBadRequest, "lang must be uz or ru", 400)
+		return true
+	}
+
+	columns := exportColumns(reflect.TypeOf(item), nil)
+
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = exportLabel(lang, column.name)
+	}
+
+	var (
+		writer exportWriter
+		values = make([]interface{}, len(columns))
+	)
+
+	// the file is started with the first row, so that an error reading the
+	// list before it is still answered with an error
+	start := func() (err error) {
+		fileName := name + "-" + time.Now().Format("20060102-150405") + "." + format
+
+		ctx.Header("Content-Disposition", `attachment; filename="`+fileName+`"`)
+		if format == exportCSV {
+			ctx.Header("Content-Type", "text/csv; charset=utf-8")
+			writer, err = newCSVExport(ctx.Writer, header)
+		} else {
+			ctx.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+			writer, err = xlsx.NewWriter(ctx.Writer, header)
+		}
+
+		return err
+	}
+
+	req.Export = func(item interface{}) error {
+		if writer == nil {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+
+		row := reflect.ValueOf(item)
+		for i, column := range columns {
+			values[i] = exportValue(row.FieldByIndex(column.index))
+		}
+
+		return writer.Write(values)
+	}
+
+	err := list(req)
+	if err == nil && writer == nil {
+		err = start()
+	}
+	if err == nil {
+		err = writer.Close()
+	}
+
+	if err != nil && !ctx.Writer.Written() {
+		ctx.Writer.Header().Del("Content-Disposition")
+		ctx.Writer.Header().Del("Content-Type")
+		h.HandleDbError(ctx, err, "Error exporting "+name)
+	} else if err != nil {
+		// the file is cut off, there is no way left to tell the client
+		h.Logger.Error(err, "Error exporting "+name)
+	}
+
+	return true
+}
+
+// exportEach passes every row of an export through convert before it is
+// written, e.g. to show it in the unit or currency asked for.
+func exportEach[T any](req entity.GetListFilter, convert func(item T) T) entity.GetListFilter {
+	write := req.Export
+	req.Export = func(item interface{}) error {
+		return write(convert(item.(T)))
+	}
+
+	return req
+}
+
+// exportColumns lists the fields of a list item that are written to exports:
+// text, numbers, booleans and lists of text. Nested documents like the lines
+// of an order and fields tagged export:"-" are left out.
+func exportColumns(t reflect.Type, index []int) []exportColumn {
+	var columns []exportColumn
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldIndex := append(append([]int{}, index...), i)
+
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			columns = append(columns, exportColumns(field.Type, fieldIndex)...)
+			continue
+		}
+
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if !field.IsExported() || name == "-" || field.Tag.Get("export") == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		switch field.Type.Kind() {
+		case reflect.String, reflect.Bool, reflect.Int, reflect.Int32, reflect.Int64, reflect.Float32, reflect.Float64:
+		case reflect.Slice:
+			if field.Type.Elem().Kind() != reflect.String {
+				continue
+			}
+		default:
+			continue
+		}
+
+		columns = append(columns, exportColumn{name: name, index: fieldIndex})
+	}
+
+	return columns
+}
+
+// exportValue converts a field to a cell value of an export.
+func exportValue(v reflect.Value) interface{} {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	case reflect.Slice:
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = v.Index(i).String()
+		}
+
+		return strings.Join(items, "; ")
+	}
+
+	return nil
+}
+
+func exportLabel(lang, name string) string {
+	if label, ok := exportLabels[lang][name]; ok {
+		return label
+	}
+
+	return name
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestCSVExport(t *testing.T) {
+	var buf bytes.Buffer
+
+	e, err := newCSVExport(&buf, []string{"sku", "name", "quantity", "active"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, row := range [][]interface{}{
+		{"AB-1", "Choy, qora", 12.5, true},
+		{"=HYPERLINK(\"x\")", "+998 90", int64(-7), nil},
+		{"-5", "@SUM(A1)", 0.001, false},
+		{"\tAB-2", "say \"hi\"", 3.0, false},
+	} {
+		if err = e.Write(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = e.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "\xef\xbb\xbfsku,name,quantity,active\n" +
+		"AB-1,\"Choy, qora\",12.5,true\n" +
+		"\"'=HYPERLINK(\"\"x\"\")\",'+998 90,-7,\n" +
+		"'-5,'@SUM(A1),0.001,false\n" +
+		"'\tAB-2,\"say \"\"hi\"\"\",3,false\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+type exportBase struct {
+	ID        string `json:"id"`
+	CreatedAt string `json:"created_at"`
+}
+
+type exportLine struct {
+	SKU string `json:"sku"`
+}
+
+type exportItem struct {
+	exportBase
+	Name     string       `json:"name"`
+	Quantity float64      `json:"quantity"`
+	Count    int          `json:"count"`
+	Active   bool         `json:"active"`
+	Barcodes []string     `json:"barcodes"`
+	Lines    []exportLine `json:"lines"`
+	Password string       `json:"password" export:"-"`
+	Internal string       `json:"-"`
+	Untagged string
+	hidden   string
+}
+
+func TestExportColumns(t *testing.T) {
+	columns := exportColumns(reflect.TypeOf(exportItem{}), nil)
+
+	var names []string
+	for _, column := range columns {
+		names = append(names, column.name)
+	}
+
+	want := []string{"id", "created_at", "name", "quantity", "count", "active", "barcodes", "Untagged"}
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("got columns %v, want %v", names, want)
+	}
+
+	item := reflect.ValueOf(exportItem{
+		exportBase: exportBase{ID: "p-1"},
+		Name:       "Choy",
+		Quantity:   2.5,
+		Count:      3,
+		Active:     true,
+		Barcodes:   []string{"4780001", "4780002"},
+		hidden:     "x",
+	})
+
+	var values []interface{}
+	for _, column := range columns {
+		values = append(values, exportValue(item.FieldByIndex(column.index)))
+	}
+
+	wantValues := []interface{}{"p-1", "", "Choy", 2.5, int64(3), true, "4780001; 4780002", ""}
+	if !reflect.DeepEqual(values, wantValues) {
+		t.Errorf("got values %v, want %v", values, wantValues)
+	}
+}
+
+func TestExportLabel(t *testing.T) {
+	for _, tc := range []struct{ lang, name, want string }{
+		{"ru", "is_virtual", "Виртуальная"},
+		{"uz", "is_virtual", "Virtual"},
+		{"ru", "no_such_column", "no_such_column"},
+		{"en", "is_virtual", "is_virtual"},
+	} {
+		if got := exportLabel(tc.lang, tc.name); got != tc.want {
+			t.Errorf("exportLabel(%q, %q) = %q, want %q", tc.lang, tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,296 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateGoodsReceipt godoc
+// @Router /goods-receipt [post]
+// @Summary Create a goods receipt
+// @Description Create a draft goods receipt, stock is not changed until it is posted. Lines may be entered in any unit of their product, quantities are kept in the base unit
+// @Security BearerAuth
+// @Tags goods-receipt
+// @Accept  json
+// @Produce  json
+// @Param receipt body entity.GoodsReceipt true "Goods receipt object"
+// @Success 201 {object} entity.GoodsReceipt
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) CreateGoodsReceipt(ctx *gin.Context) {
+	var (
+		body entity.GoodsReceipt
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if !h.validGoodsReceipt(ctx, body) {
+		return
+	}
+
+	body.CreatedBy = ctx.GetHeader("sub")
+
+	receipt, err := h.UseCase.GoodsReceiptRepo.Create(ctx, body)
+	if h.HandleDbError(ctx, err, "Error creating goods receipt") {
+		return
+	}
+
+	ctx.JSON(201, receipt)
+}
+
+// GetGoodsReceipt godoc
+// @Router /goods-receipt/{id} [get]
+// @Summary Get a goods receipt by ID
+// @Description Get a goods receipt with its lines
+// @Security BearerAuth
+// @Tags goods-receipt
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Goods receipt ID"
+// @Success 200 {object} entity.GoodsReceipt
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetGoodsReceipt(ctx *gin.Context) {
+	var (
+		req entity.Id
+	)
+
+	req.ID = ctx.Param("id")
+
+	receipt, err := h.UseCase.GoodsReceiptRepo.GetSingle(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting goods receipt") {
+		return
+	}
+
+	ctx.JSON(200, receipt)
+}
+
+// GetGoodsReceipts godoc
+// @Router /goods-receipt/list [get]
+// @Summary Get a list of goods receipts
+// @Description Get a list of goods receipts without lines
+// @Security BearerAuth
+// @Tags goods-receipt
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param search query string false "number"
+// @Param status query string false "draft, posted or cancelled"
+// @Param supplier_id query string false "supplier_id"
+// @Param purchase_order_id query string false "purchase_order_id"
+// @Param warehouse_id query string false "warehouse_id"
+// @Param from query string false "receipt date from, YYYY-MM-DD"
+// @Param to query string false "receipt date to, YYYY-MM-DD"
+// @Param format query string false "csv or xlsx, exports the whole filtered list instead of a page"
+// @Param lang query string false "uz or ru, language of the column headers of an export, uz by default"
+// @Success 200 {object} entity.GoodsReceiptList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetGoodsReceipts(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+	search := ctx.DefaultQuery("search", "")
+	from := ctx.DefaultQuery("from", "")
+	to := ctx.DefaultQuery("to", "")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+
+	if search != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "number",
+			Type:   "search",
+			Value:  search,
+		})
+	}
+
+	for _, column := range []string{"status", "supplier_id", "purchase_order_id", "warehouse_id"} {
+		if value := ctx.DefaultQuery(column, ""); value != "" {
+			req.Filters = append(req.Filters, entity.Filter{
+				Column: column,
+				Type:   "eq",
+				Value:  value,
+			})
+		}
+	}
+
+	if from != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "receipt_date",
+			Type:   "gte",
+			Value:  from,
+		})
+	}
+
+	if to != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "receipt_date",
+			Type:   "lte",
+			Value:  to,
+		})
+	}
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "created_at",
+		Order:  "desc",
+	})
+
+	if h.export(ctx, "goods-receipts", entity.GoodsReceipt{}, req, func(req entity.GetListFilter) error {
+		_, err := h.UseCase.GoodsReceiptRepo.GetList(ctx, req)
+		return err
+	}) {
+		return
+	}
+
+	receipts, err := h.UseCase.GoodsReceiptRepo.GetList(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting goods receipts") {
+		return
+	}
+
+	ctx.JSON(200, receipts)
+}
+
+// UpdateGoodsReceipt godoc
+// @Router /goods-receipt [put]
+// @Summary Update a draft goods receipt
+// @Description Update the header and replace the lines of a draft goods receipt
+// @Security BearerAuth
+// @Tags goods-receipt
+// @Accept  json
+// @Produce  json
+// @Param receipt body entity.GoodsReceipt true "Goods receipt object"
+// @Success 200 {object} entity.GoodsReceipt
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) UpdateGoodsReceipt(ctx *gin.Context) {
+	var (
+		body entity.GoodsReceipt
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if body.ID == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "id is required", 400)
+		return
+	}
+
+	if !h.validGoodsReceipt(ctx, body) {
+		return
+	}
+
+	receipt, err := h.UseCase.GoodsReceiptRepo.Update(ctx, body)
+	if h.HandleDbError(ctx, err, "Error updating goods receipt") {
+		return
+	}
+
+	ctx.JSON(200, receipt)
+}
+
+// DeleteGoodsReceipt godoc
+// @Router /goods-receipt/{id} [delete]
+// @Summary Delete a draft goods receipt
+// @Description Delete a draft goods receipt, posted receipts have to be cancelled
+// @Security BearerAuth
+// @Tags goods-receipt
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Goods receipt ID"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) DeleteGoodsReceipt(ctx *gin.Context) {
+	var (
+		req entity.Id
+	)
+
+	req.ID = ctx.Param("id")
+
+	err := h.UseCase.GoodsReceiptRepo.Delete(ctx, req)
+	if h.HandleDbError(ctx, err, "Error deleting goods receipt") {
+		return
+	}
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "Goods receipt deleted successfully",
+	})
+}
+
+// PostGoodsReceipt godoc
+// @Router /goods-receipt/{id}/post [post]
+// @Summary Post a goods receipt
+// @Description Write the receipt lines to the stock ledger, all lines or none
+// @Security BearerAuth
+// @Tags goods-receipt
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Goods receipt ID"
+// @Success 200 {object} entity.GoodsReceipt
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) PostGoodsReceipt(ctx *gin.Context) {
+	receipt, err := h.UseCase.PostGoodsReceipt(ctx, ctx.Param("id"), ctx.GetHeader("sub"))
+	if h.HandleDbError(ctx, err, "Error posting goods receipt") {
+		return
+	}
+
+	ctx.JSON(200, receipt)
+}
+
+// CancelGoodsReceipt godoc
+// @Router /goods-receipt/{id}/cancel [post]
+// @Summary Cancel a goods receipt
+// @Description Cancel a goods receipt, a posted receipt is reversed in the stock ledger
+// @Security BearerAuth
+// @Tags goods-receipt
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Goods receipt ID"
+// @Success 200 {object} entity.GoodsReceipt
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) CancelGoodsReceipt(ctx *gin.Context) {
+	receipt, err := h.UseCase.CancelGoodsReceipt(ctx, ctx.Param("id"), ctx.GetHeader("sub"))
+	if h.HandleDbError(ctx, err, "Error cancelling goods receipt") {
+		return
+	}
+
+	ctx.JSON(200, receipt)
+}
+
+func (h *Handler) validGoodsReceipt(ctx *gin.Context, body entity.GoodsReceipt) bool {
+	if body.WarehouseID == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "warehouse_id is required", 400)
+		return false
+	}
+
+	serials := make([][]string, 0, len(body.Lines))
+	for _, line := range body.Lines {
+		if line.ProductID == "" || line.BinID == "" || !validQuantity(line.Quantity, line.UnitQuantity) || line.UnitCost < 0 {
+			h.ReturnError(ctx, config.ErrorBadRequest, "Each line needs product_id, bin_id, a positive quantity or unit_quantity and a non-negative unit_cost", 400)
+			return false
+		}
+
+		if line.PurchaseOrderLineID != "" && body.PurchaseOrderID == "" {
+			h.ReturnError(ctx, config.ErrorBadRequest, "purchase_order_line_id needs the purchase_order_id of the receipt", 400)
+			return false
+		}
+
+		if line.ExpiryDate != "" && line.LotNumber == "" {
+			h.ReturnError(ctx, config.ErrorBadRequest, "expiry_date needs a lot_number", 400)
+			return false
+		}
+
+		serials = append(serials, line.Serials)
+	}
+
+	return h.validSerials(ctx, serials...)
+}
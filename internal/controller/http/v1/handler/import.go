@@ -0,0 +1,285 @@
+package handler
+
+import (
+	"io"
+	"strconv"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportSize is the largest file accepted for import, 20 MB.
+const maxImportSize = 20 << 20
+
+// CreateImport godoc
+// @Router /import [post]
+// @Summary Upload a file to import
+// @Description Upload a CSV or XLSX file of products or opening balances. The first row is the header, the mapping of the columns to the fields of the import is guessed from it and returned with the job. Nothing is written until the job is run
+// @Security BearerAuth
+// @Tags import
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "CSV or XLSX file"
+// @Param kind formData string true "products or opening_balances"
+// @Success 201 {object} entity.ImportJob
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) CreateImport(ctx *gin.Context) {
+	var (
+		req entity.ImportJob
+	)
+
+	req.Kind = ctx.PostForm("kind")
+	if req.Kind != entity.ImportKindProducts && req.Kind != entity.ImportKindOpeningBalances {
+		h.ReturnError(ctx, config.ErrorBadRequest, "kind must be products or opening_balances", 400)
+		return
+	}
+
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "file is required", 400)
+		return
+	}
+
+	if file.Size > maxImportSize {
+		h.ReturnError(ctx, config.ErrorBadRequest, "file must not be larger than 20 MB", 400)
+		return
+	}
+
+	reader, err := file.Open()
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "file could not be read", 400)
+		return
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "file could not be read", 400)
+		return
+	}
+
+	req.FileName = file.Filename
+	req.CreatedBy = ctx.GetHeader("sub")
+
+	job, err := h.UseCase.CreateImport(ctx, req, data)
+	if h.HandleDbError(ctx, err, "Error creating import") {
+		return
+	}
+
+	job.Fields = entity.ImportFields[job.Kind]
+
+	ctx.JSON(201, job)
+}
+
+// GetImport godoc
+// @Router /import/{id} [get]
+// @Summary Get an import
+// @Description Get an import job with its progress, the columns of the file and the fields they can be mapped to. Poll it while the job is queued or running
+// @Security BearerAuth
+// @Tags import
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Import ID"
+// @Success 200 {object} entity.ImportJob
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+func (h *Handler) GetImport(ctx *gin.Context) {
+	var (
+		req entity.Id
+	)
+
+	req.ID = ctx.Param("id")
+
+	job, err := h.UseCase.ImportRepo.GetSingle(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting import") {
+		return
+	}
+
+	job.Fields = entity.ImportFields[job.Kind]
+
+	ctx.JSON(200, job)
+}
+
+// GetImports godoc
+// @Router /import/list [get]
+// @Summary Get a list of imports
+// @Description Get a list of import jobs, newest first
+// @Security BearerAuth
+// @Tags import
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param kind query string false "products or opening_balances"
+// @Param status query string false "uploaded, queued, running, validated, completed or failed"
+// @Param format query string false "csv or xlsx, exports the whole filtered list instead of a page"
+// @Param lang query string false "uz or ru, language of the column headers of an export, uz by default"
+// @Success 200 {object} entity.ImportJobList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetImports(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+
+	for _, column := range []string{"kind", "status"} {
+		if value := ctx.DefaultQuery(column, ""); value != "" {
+			req.Filters = append(req.Filters, entity.Filter{
+				Column: column,
+				Type:   "eq",
+				Value:  value,
+			})
+		}
+	}
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "created_at",
+		Order:  "desc",
+	})
+
+	if h.export(ctx, "imports", entity.ImportJob{}, req, func(req entity.GetListFilter) error {
+		_, err := h.UseCase.ImportRepo.GetList(ctx, req)
+		return err
+	}) {
+		return
+	}
+
+	jobs, err := h.UseCase.ImportRepo.GetList(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting imports") {
+		return
+	}
+
+	ctx.JSON(200, jobs)
+}
+
+// GetImportRows godoc
+// @Router /import/{id}/rows [get]
+// @Summary Get the rows of an import
+// @Description Get the rows of an import file with the outcome of the last run, filter by status error to get what has to be fixed
+// @Security BearerAuth
+// @Tags import
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Import ID"
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param status query string false "pending, valid, imported or error"
+// @Param format query string false "csv or xlsx, exports the whole filtered list instead of a page"
+// @Param lang query string false "uz or ru, language of the column headers of an export, uz by default"
+// @Success 200 {object} entity.ImportRowList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetImportRows(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+	status := ctx.DefaultQuery("status", "")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+
+	req.Filters = append(req.Filters, entity.Filter{
+		Column: "job_id",
+		Type:   "eq",
+		Value:  ctx.Param("id"),
+	})
+
+	if status != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "status",
+			Type:   "eq",
+			Value:  status,
+		})
+	}
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "row_number",
+		Order:  "asc",
+	})
+
+	if h.export(ctx, "import-rows", entity.ImportRow{}, req, func(req entity.GetListFilter) error {
+		_, err := h.UseCase.ImportRepo.GetRows(ctx, req)
+		return err
+	}) {
+		return
+	}
+
+	rows, err := h.UseCase.ImportRepo.GetRows(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting import rows") {
+		return
+	}
+
+	ctx.JSON(200, rows)
+}
+
+// RunImport godoc
+// @Router /import/{id}/run [post]
+// @Summary Run an import
+// @Description Queue an import with the given mapping of fields to columns, the mapping of the job is used when it is empty. A dry run only validates the rows, the errors are reported on them. Rows imported by an earlier run are skipped, so a job can be run again after fixing the failed rows. Poll the job for its progress
+// @Security BearerAuth
+// @Tags import
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Import ID"
+// @Param body body entity.ImportRunRequest true "Mapping"
+// @Success 202 {object} entity.ImportJob
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+func (h *Handler) RunImport(ctx *gin.Context) {
+	var (
+		body entity.ImportRunRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	body.JobID = ctx.Param("id")
+
+	job, err := h.UseCase.RunImport(ctx, body)
+	if h.HandleDbError(ctx, err, "Error running import") {
+		return
+	}
+
+	job.Fields = entity.ImportFields[job.Kind]
+
+	ctx.JSON(202, job)
+}
+
+// DeleteImport godoc
+// @Router /import/{id} [delete]
+// @Summary Delete an import
+// @Description Delete an import job and its rows unless it is queued or running. Products and balances it imported stay
+// @Security BearerAuth
+// @Tags import
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Import ID"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) DeleteImport(ctx *gin.Context) {
+	var (
+		req entity.Id
+	)
+
+	req.ID = ctx.Param("id")
+
+	err := h.UseCase.ImportRepo.Delete(ctx, req)
+	if h.HandleDbError(ctx, err, "Error deleting import") {
+		return
+	}
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "Import deleted successfully",
+	})
+}
@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/gin-gonic/gin"
+)
+
+// GetLots godoc
+// @Router /stock/lot/list [get]
+// @Summary Get a list of lots
+// @Description Get a list of product lots, soonest expiry first
+// @Security BearerAuth
+// @Tags stock
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param product_id query string false "product_id"
+// @Param search query string false "lot number"
+// @Param format query string false "csv or xlsx, exports the whole filtered list instead of a page"
+// @Param lang query string false "uz or ru, language of the column headers of an export, uz by default"
+// @Success 200 {object} entity.LotList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetLots(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+	productID := ctx.DefaultQuery("product_id", "")
+	search := ctx.DefaultQuery("search", "")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+
+	if productID != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "product_id",
+			Type:   "eq",
+			Value:  productID,
+		})
+	}
+
+	if search != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "lot_number",
+			Type:   "search",
+			Value:  search,
+		})
+	}
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "expiry_date",
+		Order:  "asc NULLS LAST",
+	})
+
+	if h.export(ctx, "lots", entity.Lot{}, req, func(req entity.GetListFilter) error {
+		_, err := h.UseCase.LotRepo.GetList(ctx, req)
+		return err
+	}) {
+		return
+	}
+
+	lots, err := h.UseCase.LotRepo.GetList(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting lots") {
+		return
+	}
+
+	ctx.JSON(200, lots)
+}
+
+// GetLotPicks godoc
+// @Router /stock/fefo [get]
+// @Summary Suggest lots to pick
+// @Description Suggest bins and lots to pick a quantity of a product from, first expired first out. Expired lots are skipped
+// @Security BearerAuth
+// @Tags stock
+// @Accept  json
+// @Produce  json
+// @Param product_id query string true "product_id"
+// @Param warehouse_id query string true "warehouse_id"
+// @Param quantity query number true "quantity to pick"
+// @Success 200 {object} entity.LotPickList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetLotPicks(ctx *gin.Context) {
+	productID := ctx.DefaultQuery("product_id", "")
+	warehouseID := ctx.DefaultQuery("warehouse_id", "")
+
+	quantity, err := strconv.ParseFloat(ctx.DefaultQuery("quantity", ""), 64)
+	if err != nil || quantity <= 0 || productID == "" || warehouseID == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "product_id, warehouse_id and a positive quantity are required", 400)
+		return
+	}
+
+	picks, err := h.UseCase.SuggestLotPicks(ctx, productID, warehouseID, quantity)
+	if h.HandleDbError(ctx, err, "Error suggesting lots") {
+		return
+	}
+
+	ctx.JSON(200, picks)
+}
+
+// GetExpiringStock godoc
+// @Router /stock/expiring [get]
+// @Summary Get stock that expires soon
+// @Description Get lot balances expiring within the given number of days, already expired lots included
+// @Security BearerAuth
+// @Tags stock
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param days query number false "days from today, 30 by default"
+// @Param warehouse_id query string false "warehouse_id"
+// @Param product_id query string false "product_id"
+// @Param unit query string false "show quantities in this unit where the product defines it"
+// @Param format query string false "csv or xlsx, exports the whole filtered list instead of a page"
+// @Param lang query string false "uz or ru, language of the column headers of an export, uz by default"
+// @Success 200 {object} entity.ExpiringLotList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetExpiringStock(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+
+	days, err := strconv.Atoi(ctx.DefaultQuery("days", "30"))
+	if err != nil || days < 0 {
+		h.ReturnError(ctx, config.ErrorBadRequest, "days must be a non-negative number", 400)
+		return
+	}
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+
+	for _, column := range []string{"warehouse_id", "product_id"} {
+		if value := ctx.DefaultQuery(column, ""); value != "" {
+			req.Filters = append(req.Filters, entity.Filter{
+				Column: "stock_balances." + column,
+				Type:   "eq",
+				Value:  value,
+			})
+		}
+	}
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "lots.expiry_date",
+		Order:  "asc",
+	})
+
+	if h.export(ctx, "expiring-stock", entity.ExpiringLot{}, req, func(req entity.GetListFilter) error {
+		units, err := h.exportUnits(ctx)
+		if err != nil {
+			return err
+		}
+
+		_, err = h.UseCase.StockRepo.GetExpiring(ctx, exportEach(req, func(item entity.ExpiringLot) entity.ExpiringLot {
+			return expiringLotInUnit(item, units)
+		}), days)
+		return err
+	}) {
+		return
+	}
+
+	lots, err := h.UseCase.StockRepo.GetExpiring(ctx, req, days)
+	if h.HandleDbError(ctx, err, "Error getting expiring stock") {
+		return
+	}
+
+	ids := make([]string, 0, len(lots.Items))
+	for _, item := range lots.Items {
+		ids = append(ids, item.ProductID)
+	}
+
+	units, ok := h.reportUnits(ctx, ids)
+	if !ok {
+		return
+	}
+
+	for i, item := range lots.Items {
+		lots.Items[i] = expiringLotInUnit(item, units)
+	}
+
+	ctx.JSON(200, lots)
+}
+
+// expiringLotInUnit shows a row of the report in the unit of its product in units.
+func expiringLotInUnit(item entity.ExpiringLot, units map[string]entity.ProductUnit) entity.ExpiringLot {
+	unit, found := units[item.ProductID]
+	if !found {
+		return item
+	}
+
+	item.Quantity = inUnit(item.Quantity, unit)
+	item.Unit = unit.Unit
+
+	return item
+}
@@ -0,0 +1,249 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/gin-gonic/gin"
+)
+
+// CreatePickList godoc
+// @Router /pick-list [post]
+// @Summary Generate a pick list
+// @Description Generate the pick list of a processing sales order, sorted by bin path, and move the order to picking. Lines without a lot are split over lots first expired first out
+// @Security BearerAuth
+// @Tags pick-list
+// @Accept  json
+// @Produce  json
+// @Param body body entity.PickListCreateRequest true "Sales order ID"
+// @Success 201 {object} entity.PickList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) CreatePickList(ctx *gin.Context) {
+	var (
+		body entity.PickListCreateRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil || body.OrderID == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	pickList, err := h.UseCase.CreatePickList(ctx, body, ctx.GetHeader("sub"))
+	if h.HandleDbError(ctx, err, "Error creating pick list") {
+		return
+	}
+
+	ctx.JSON(201, pickList)
+}
+
+// GetPickList godoc
+// @Router /pick-list/{id} [get]
+// @Summary Get a pick list by ID
+// @Description Get a pick list with its lines in bin path order
+// @Security BearerAuth
+// @Tags pick-list
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Pick list ID"
+// @Success 200 {object} entity.PickList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetPickList(ctx *gin.Context) {
+	var (
+		req entity.Id
+	)
+
+	req.ID = ctx.Param("id")
+
+	pickList, err := h.UseCase.PickListRepo.GetSingle(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting pick list") {
+		return
+	}
+
+	ctx.JSON(200, pickList)
+}
+
+// GetPickLists godoc
+// @Router /pick-list/list [get]
+// @Summary Get a list of pick lists
+// @Description Get a list of pick lists without lines
+// @Security BearerAuth
+// @Tags pick-list
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param search query string false "number"
+// @Param status query string false "open, completed or cancelled"
+// @Param order_id query string false "order_id"
+// @Param warehouse_id query string false "warehouse_id"
+// @Param format query string false "csv or xlsx, exports the whole filtered list instead of a page"
+// @Param lang query string false "uz or ru, language of the column headers of an export, uz by default"
+// @Success 200 {object} entity.PickListList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetPickLists(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+	search := ctx.DefaultQuery("search", "")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+
+	if search != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "number",
+			Type:   "search",
+			Value:  search,
+		})
+	}
+
+	for _, column := range []string{"status", "order_id", "warehouse_id"} {
+		if value := ctx.DefaultQuery(column, ""); value != "" {
+			req.Filters = append(req.Filters, entity.Filter{
+				Column: column,
+				Type:   "eq",
+				Value:  value,
+			})
+		}
+	}
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "created_at",
+		Order:  "desc",
+	})
+
+	if h.export(ctx, "pick-lists", entity.PickList{}, req, func(req entity.GetListFilter) error {
+		_, err := h.UseCase.PickListRepo.GetList(ctx, req)
+		return err
+	}) {
+		return
+	}
+
+	pickLists, err := h.UseCase.PickListRepo.GetList(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting pick lists") {
+		return
+	}
+
+	ctx.JSON(200, pickLists)
+}
+
+// ScanPick godoc
+// @Router /pick-list/{id}/scan [post]
+// @Summary Confirm a pick by scanning
+// @Description Confirm picked goods by their barcode, serialized products by their serial number. Picking more than is left on the line is rejected
+// @Security BearerAuth
+// @Tags pick-list
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Pick list ID"
+// @Param body body entity.PickScanRequest true "Scanned barcode or serial number"
+// @Success 200 {object} entity.PickList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) ScanPick(ctx *gin.Context) {
+	var (
+		body entity.PickScanRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if (body.Barcode == "" && body.Serial == "") || body.Quantity < 0 {
+		h.ReturnError(ctx, config.ErrorBadRequest, "barcode or serial is required and quantity can't be negative", 400)
+		return
+	}
+
+	body.PickListID = ctx.Param("id")
+
+	pickList, err := h.UseCase.ScanPick(ctx, body)
+	if h.HandleDbError(ctx, err, "Error confirming pick") {
+		return
+	}
+
+	ctx.JSON(200, pickList)
+}
+
+// ShortPick godoc
+// @Router /pick-list/{id}/short [post]
+// @Summary Report a short pick
+// @Description Close a pick list line with what has been picked so far, the rest is reported short with a reason
+// @Security BearerAuth
+// @Tags pick-list
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Pick list ID"
+// @Param body body entity.PickShortRequest true "Line and reason"
+// @Success 200 {object} entity.PickList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) ShortPick(ctx *gin.Context) {
+	var (
+		body entity.PickShortRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if body.LineID == "" || body.Reason == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "line_id and reason are required", 400)
+		return
+	}
+
+	body.PickListID = ctx.Param("id")
+
+	pickList, err := h.UseCase.ShortPick(ctx, body)
+	if h.HandleDbError(ctx, err, "Error reporting short pick") {
+		return
+	}
+
+	ctx.JSON(200, pickList)
+}
+
+// CompletePickList godoc
+// @Router /pick-list/{id}/complete [post]
+// @Summary Complete a pick list
+// @Description Close a pick list whose lines are all picked or reported short and move the order to picked. Short quantities are neither shipped nor billed
+// @Security BearerAuth
+// @Tags pick-list
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Pick list ID"
+// @Success 200 {object} entity.PickList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) CompletePickList(ctx *gin.Context) {
+	pickList, err := h.UseCase.CompletePickList(ctx, ctx.Param("id"))
+	if h.HandleDbError(ctx, err, "Error completing pick list") {
+		return
+	}
+
+	ctx.JSON(200, pickList)
+}
+
+// CancelPickList godoc
+// @Router /pick-list/{id}/cancel [post]
+// @Summary Cancel a pick list
+// @Description Cancel an open pick list and send the order back to processing
+// @Security BearerAuth
+// @Tags pick-list
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Pick list ID"
+// @Success 200 {object} entity.PickList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) CancelPickList(ctx *gin.Context) {
+	pickList, err := h.UseCase.CancelPickList(ctx, ctx.Param("id"))
+	if h.HandleDbError(ctx, err, "Error cancelling pick list") {
+		return
+	}
+
+	ctx.JSON(200, pickList)
+}
@@ -0,0 +1,258 @@
+package handler
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/gin-gonic/gin"
+)
+
+// CreatePriceList godoc
+// @Router /price-list [post]
+// @Summary Create a price list
+// @Description Create a retail, wholesale or dealer price list. Items are prices per base unit from a quantity on, the largest quantity break not above the line quantity applies
+// @Security BearerAuth
+// @Tags price-list
+// @Accept  json
+// @Produce  json
+// @Param body body entity.PriceList true "Price list with its items"
+// @Success 201 {object} entity.PriceList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) CreatePriceList(ctx *gin.Context) {
+	var (
+		body entity.PriceList
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if !h.validPriceList(ctx, body) {
+		return
+	}
+
+	priceList, err := h.UseCase.PriceListRepo.Create(ctx, body)
+	if h.HandleDbError(ctx, err, "Error creating price list") {
+		return
+	}
+
+	ctx.JSON(201, priceList)
+}
+
+// GetPriceList godoc
+// @Router /price-list/{id} [get]
+// @Summary Get a price list by ID
+// @Description Get a price list with its items
+// @Security BearerAuth
+// @Tags price-list
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Price list ID"
+// @Success 200 {object} entity.PriceList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetPriceList(ctx *gin.Context) {
+	var (
+		req entity.Id
+	)
+
+	req.ID = ctx.Param("id")
+
+	priceList, err := h.UseCase.PriceListRepo.GetSingle(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting price list") {
+		return
+	}
+
+	ctx.JSON(200, priceList)
+}
+
+// GetPriceLists godoc
+// @Router /price-list/list [get]
+// @Summary Get a list of price lists
+// @Description Get a list of price lists without items
+// @Security BearerAuth
+// @Tags price-list
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param search query string false "name"
+// @Param type query string false "retail, wholesale or dealer"
+// @Param is_active query bool false "is_active"
+// @Param format query string false "csv or xlsx, exports the whole filtered list instead of a page"
+// @Param lang query string false "uz or ru, language of the column headers of an export, uz by default"
+// @Success 200 {object} entity.PriceListList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetPriceLists(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+	search := ctx.DefaultQuery("search", "")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+
+	if search != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "name",
+			Type:   "search",
+			Value:  search,
+		})
+	}
+
+	for _, column := range []string{"type", "is_active"} {
+		if value := ctx.DefaultQuery(column, ""); value != "" {
+			req.Filters = append(req.Filters, entity.Filter{
+				Column: column,
+				Type:   "eq",
+				Value:  value,
+			})
+		}
+	}
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "name",
+		Order:  "asc",
+	})
+
+	if h.export(ctx, "price-lists", entity.PriceList{}, req, func(req entity.GetListFilter) error {
+		_, err := h.UseCase.PriceListRepo.GetList(ctx, req)
+		return err
+	}) {
+		return
+	}
+
+	priceLists, err := h.UseCase.PriceListRepo.GetList(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting price lists") {
+		return
+	}
+
+	ctx.JSON(200, priceLists)
+}
+
+// UpdatePriceList godoc
+// @Router /price-list [put]
+// @Summary Update a price list
+// @Description Update a price list, its items are replaced with the given list
+// @Security BearerAuth
+// @Tags price-list
+// @Accept  json
+// @Produce  json
+// @Param body body entity.PriceList true "Price list with its items"
+// @Success 200 {object} entity.PriceList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) UpdatePriceList(ctx *gin.Context) {
+	var (
+		body entity.PriceList
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if body.ID == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "id is required", 400)
+		return
+	}
+
+	if !h.validPriceList(ctx, body) {
+		return
+	}
+
+	priceList, err := h.UseCase.PriceListRepo.Update(ctx, body)
+	if h.HandleDbError(ctx, err, "Error updating price list") {
+		return
+	}
+
+	ctx.JSON(200, priceList)
+}
+
+// DeletePriceList godoc
+// @Router /price-list/{id} [delete]
+// @Summary Delete a price list
+// @Description Delete a price list, its customers buy at sale prices again
+// @Security BearerAuth
+// @Tags price-list
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Price list ID"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) DeletePriceList(ctx *gin.Context) {
+	var (
+		req entity.Id
+	)
+
+	req.ID = ctx.Param("id")
+
+	err := h.UseCase.PriceListRepo.Delete(ctx, req)
+	if h.HandleDbError(ctx, err, "Error deleting price list") {
+		return
+	}
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "Price list deleted successfully",
+	})
+}
+
+func (h *Handler) validPriceList(ctx *gin.Context, body entity.PriceList) bool {
+	if body.Name == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "name is required", 400)
+		return false
+	}
+
+	switch body.Type {
+	case entity.PriceListTypeRetail, entity.PriceListTypeWholesale, entity.PriceListTypeDealer:
+	default:
+		h.ReturnError(ctx, config.ErrorBadRequest, "type must be retail, wholesale or dealer", 400)
+		return false
+	}
+
+	if !h.validDateRange(ctx, body.ValidFrom, body.ValidTo) {
+		return false
+	}
+
+	breaks := map[string]map[float64]bool{}
+	for _, item := range body.Items {
+		if item.ProductID == "" || item.MinQuantity < 0 || item.Price < 0 || breaks[item.ProductID][item.MinQuantity] {
+			h.ReturnError(ctx, config.ErrorBadRequest, "Each item needs product_id, a non-negative price and a quantity break not given twice for the product", 400)
+			return false
+		}
+
+		if breaks[item.ProductID] == nil {
+			breaks[item.ProductID] = map[float64]bool{}
+		}
+		breaks[item.ProductID][item.MinQuantity] = true
+	}
+
+	return true
+}
+
+// validDateRange checks optional YYYY-MM-DD bounds and that the range does not end before it starts.
+func (h *Handler) validDateRange(ctx *gin.Context, from, to string) bool {
+	for _, date := range []string{from, to} {
+		if date == "" {
+			continue
+		}
+
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			h.ReturnError(ctx, config.ErrorBadRequest, "valid_from and valid_to must be dates in YYYY-MM-DD format", 400)
+			return false
+		}
+	}
+
+	if from != "" && to != "" && to < from {
+		h.ReturnError(ctx, config.ErrorBadRequest, "valid_to can't be before valid_from", 400)
+		return false
+	}
+
+	return true
+}
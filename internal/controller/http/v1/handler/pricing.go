@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/gin-gonic/gin"
+)
+
+// QuotePrices godoc
+// @Router /pricing/quote [post]
+// @Summary Quote line prices
+// @Description Price order lines for a customer on a date the way a new sales order would get them, without creating it. price_rules of each line tells how its price was arrived at
+// @Security BearerAuth
+// @Tags pricing
+// @Accept  json
+// @Produce  json
+// @Param body body entity.PriceQuoteRequest true "Customer, date and lines"
+// @Success 200 {object} entity.PriceQuote
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) QuotePrices(ctx *gin.Context) {
+	var (
+		body entity.PriceQuoteRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if body.Date != "" {
+		if _, err := time.Parse("2006-01-02", body.Date); err != nil {
+			h.ReturnError(ctx, config.ErrorBadRequest, "date must be in YYYY-MM-DD format", 400)
+			return
+		}
+	}
+
+	for _, line := range body.Lines {
+		if line.ProductID == "" || !validQuantity(line.Quantity, line.UnitQuantity) || line.Price < 0 {
+			h.ReturnError(ctx, config.ErrorBadRequest, "Each line needs product_id, a positive quantity or unit_quantity and a non-negative price", 400)
+			return
+		}
+	}
+
+	quote, err := h.UseCase.QuotePrices(ctx, body)
+	if h.HandleDbError(ctx, err, "Error quoting prices") {
+		return
+	}
+
+	ctx.JSON(200, quote)
+}
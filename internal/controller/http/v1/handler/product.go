@@ -0,0 +1,314 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateProduct godoc
+// @Router /product [post]
+// @Summary Create a new product
+// @Description Create a new product
+// @Security BearerAuth
+// @Tags product
+// @Accept  json
+// @Produce  json
+// @Param product body entity.Product true "Product object"
+// @Success 201 {object} entity.Product
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) CreateProduct(ctx *gin.Context) {
+	var (
+		body entity.Product
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if body.SKU == "" || body.Name == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "sku and name are required", 400)
+		return
+	}
+
+	if !h.validUnits(ctx, body) {
+		return
+	}
+
+	product, err := h.UseCase.ProductRepo.Create(ctx, body)
+	if h.HandleDbError(ctx, err, "Error creating product") {
+		return
+	}
+
+	ctx.JSON(201, product)
+}
+
+// GetProduct godoc
+// @Router /product/{id} [get]
+// @Summary Get a product by ID
+// @Description Get a product by ID
+// @Security BearerAuth
+// @Tags product
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Product ID"
+// @Success 200 {object} entity.Product
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetProduct(ctx *gin.Context) {
+	var (
+		req entity.ProductSingleRequest
+	)
+
+	req.ID = ctx.Param("id")
+
+	product, err := h.UseCase.ProductRepo.GetSingle(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting product") {
+		return
+	}
+
+	ctx.JSON(200, product)
+}
+
+// GetProductByBarcode godoc
+// @Router /product/barcode/{barcode} [get]
+// @Summary Get a product by barcode
+// @Description Get a product by one of its barcodes, barcode_unit is set when the barcode belongs to an alternate unit
+// @Security BearerAuth
+// @Tags product
+// @Accept  json
+// @Produce  json
+// @Param barcode path string true "Barcode"
+// @Success 200 {object} entity.Product
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetProductByBarcode(ctx *gin.Context) {
+	var (
+		req entity.ProductSingleRequest
+	)
+
+	req.Barcode = ctx.Param("barcode")
+
+	product, err := h.UseCase.ProductRepo.GetSingle(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting product") {
+		return
+	}
+
+	ctx.JSON(200, product)
+}
+
+// GetProducts godoc
+// @Router /product/list [get]
+// @Summary Get a list of products
+// @Description Get a list of products
+// @Security BearerAuth
+// @Tags product
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param search query string false "search by name or sku"
+// @Param category_id query string false "category_id"
+// @Param is_active query bool false "is_active"
+// @Param kind query string false "simple, bundle or kit"
+// @Param format query string false "csv or xlsx, exports the whole filtered list instead of a page"
+// @Param lang query string false "uz or ru, language of the column headers of an export, uz by default"
+// @Success 200 {object} entity.ProductList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetProducts(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+	search := ctx.DefaultQuery("search", "")
+	categoryID := ctx.DefaultQuery("category_id", "")
+	isActive := ctx.DefaultQuery("is_active", "")
+	kind := ctx.DefaultQuery("kind", "")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+
+	if search != "" {
+		req.Filters = append(req.Filters,
+			entity.Filter{
+				Column: "name",
+				Type:   "search",
+				Value:  search,
+			},
+			entity.Filter{
+				Column: "sku",
+				Type:   "search",
+				Value:  search,
+			},
+		)
+	}
+
+	if categoryID != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "category_id",
+			Type:   "eq",
+			Value:  categoryID,
+		})
+	}
+
+	if isActive != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "is_active",
+			Type:   "eq",
+			Value:  isActive,
+		})
+	}
+
+	if kind != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "kind",
+			Type:   "eq",
+			Value:  kind,
+		})
+	}
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "created_at",
+		Order:  "desc",
+	})
+
+	if h.export(ctx, "products", entity.Product{}, req, func(req entity.GetListFilter) error {
+		_, err := h.UseCase.ProductRepo.GetList(ctx, req)
+		return err
+	}) {
+		return
+	}
+
+	products, err := h.UseCase.ProductRepo.GetList(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting products") {
+		return
+	}
+
+	ctx.JSON(200, products)
+}
+
+// UpdateProduct godoc
+// @Router /product [put]
+// @Summary Update a product
+// @Description Update a product, barcodes and units are replaced with the given lists
+// @Security BearerAuth
+// @Tags product
+// @Accept  json
+// @Produce  json
+// @Param product body entity.Product true "Product object"
+// @Success 200 {object} entity.Product
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) UpdateProduct(ctx *gin.Context) {
+	var (
+		body entity.Product
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if body.ID == "" || body.SKU == "" || body.Name == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "id, sku and name are required", 400)
+		return
+	}
+
+	if !h.validUnits(ctx, body) {
+		return
+	}
+
+	product, err := h.UseCase.ProductRepo.Update(ctx, body)
+	if h.HandleDbError(ctx, err, "Error updating product") {
+		return
+	}
+
+	ctx.JSON(200, product)
+}
+
+// SetProductComponents godoc
+// @Router /product/components [put]
+// @Summary Set the kind and components of a product
+// @Description Make a product a bundle or a kit of the given components, or simple again. Bundles hold no stock and are sold out of their components, kits are assembled ahead. Components have to be simple products kept without serial numbers
+// @Security BearerAuth
+// @Tags product
+// @Accept  json
+// @Produce  json
+// @Param body body entity.ProductComponentsRequest true "Kind and components"
+// @Success 200 {object} entity.Product
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) SetProductComponents(ctx *gin.Context) {
+	var (
+		body entity.ProductComponentsRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil || body.ProductID == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	switch body.Kind {
+	case entity.ProductKindSimple:
+		if len(body.Components) > 0 {
+			h.ReturnError(ctx, config.ErrorBadRequest, "simple products have no components", 400)
+			return
+		}
+	case entity.ProductKindBundle, entity.ProductKindKit:
+		if len(body.Components) == 0 {
+			h.ReturnError(ctx, config.ErrorBadRequest, "bundles and kits need components", 400)
+			return
+		}
+	default:
+		h.ReturnError(ctx, config.ErrorBadRequest, "kind must be simple, bundle or kit", 400)
+		return
+	}
+
+	seen := map[string]bool{}
+	for _, component := range body.Components {
+		if component.ComponentID == "" || component.ComponentID == body.ProductID || seen[component.ComponentID] || component.Quantity <= 0 {
+			h.ReturnError(ctx, config.ErrorBadRequest, "components must be other products, each listed once with a positive quantity", 400)
+			return
+		}
+		seen[component.ComponentID] = true
+	}
+
+	product, err := h.UseCase.ProductRepo.SetComponents(ctx, body)
+	if h.HandleDbError(ctx, err, "Error setting product components") {
+		return
+	}
+
+	ctx.JSON(200, product)
+}
+
+// DeleteProduct godoc
+// @Router /product/{id} [delete]
+// @Summary Delete a product
+// @Description Delete a product
+// @Security BearerAuth
+// @Tags product
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Product ID"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) DeleteProduct(ctx *gin.Context) {
+	var (
+		req entity.Id
+	)
+
+	req.ID = ctx.Param("id")
+
+	err := h.UseCase.ProductRepo.Delete(ctx, req)
+	if h.HandleDbError(ctx, err, "Error deleting product") {
+		return
+	}
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "Product deleted successfully",
+	})
+}
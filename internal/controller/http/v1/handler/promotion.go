@@ -0,0 +1,226 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/gin-gonic/gin"
+)
+
+// CreatePromotion godoc
+// @Router /promotion [post]
+// @Summary Create a promotion
+// @Description Create a percent or fixed discount on a product, or on every product of a category and its subcategories. When several promotions apply to a line the largest one is taken
+// @Security BearerAuth
+// @Tags promotion
+// @Accept  json
+// @Produce  json
+// @Param body body entity.Promotion true "Promotion object"
+// @Success 201 {object} entity.Promotion
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) CreatePromotion(ctx *gin.Context) {
+	var (
+		body entity.Promotion
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if !h.validPromotion(ctx, body) {
+		return
+	}
+
+	promotion, err := h.UseCase.PromotionRepo.Create(ctx, body)
+	if h.HandleDbError(ctx, err, "Error creating promotion") {
+		return
+	}
+
+	ctx.JSON(201, promotion)
+}
+
+// GetPromotion godoc
+// @Router /promotion/{id} [get]
+// @Summary Get a promotion by ID
+// @Description Get a promotion by ID
+// @Security BearerAuth
+// @Tags promotion
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Promotion ID"
+// @Success 200 {object} entity.Promotion
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetPromotion(ctx *gin.Context) {
+	var (
+		req entity.Id
+	)
+
+	req.ID = ctx.Param("id")
+
+	promotion, err := h.UseCase.PromotionRepo.GetSingle(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting promotion") {
+		return
+	}
+
+	ctx.JSON(200, promotion)
+}
+
+// GetPromotions godoc
+// @Router /promotion/list [get]
+// @Summary Get a list of promotions
+// @Description Get a list of promotions
+// @Security BearerAuth
+// @Tags promotion
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param search query string false "name"
+// @Param type query string false "percent or fixed"
+// @Param product_id query string false "product_id"
+// @Param category_id query string false "category_id"
+// @Param is_active query bool false "is_active"
+// @Param format query string false "csv or xlsx, exports the whole filtered list instead of a page"
+// @Param lang query string false "uz or ru, language of the column headers of an export, uz by default"
+// @Success 200 {object} entity.PromotionList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetPromotions(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+	search := ctx.DefaultQuery("search", "")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+
+	if search != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "name",
+			Type:   "search",
+			Value:  search,
+		})
+	}
+
+	for _, column := range []string{"type", "product_id", "category_id", "is_active"} {
+		if value := ctx.DefaultQuery(column, ""); value != "" {
+			req.Filters = append(req.Filters, entity.Filter{
+				Column: column,
+				Type:   "eq",
+				Value:  value,
+			})
+		}
+	}
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "created_at",
+		Order:  "desc",
+	})
+
+	if h.export(ctx, "promotions", entity.Promotion{}, req, func(req entity.GetListFilter) error {
+		_, err := h.UseCase.PromotionRepo.GetList(ctx, req)
+		return err
+	}) {
+		return
+	}
+
+	promotions, err := h.UseCase.PromotionRepo.GetList(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting promotions") {
+		return
+	}
+
+	ctx.JSON(200, promotions)
+}
+
+// UpdatePromotion godoc
+// @Router /promotion [put]
+// @Summary Update a promotion
+// @Description Update a promotion, orders already priced keep their prices
+// @Security BearerAuth
+// @Tags promotion
+// @Accept  json
+// @Produce  json
+// @Param body body entity.Promotion true "Promotion object"
+// @Success 200 {object} entity.Promotion
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) UpdatePromotion(ctx *gin.Context) {
+	var (
+		body entity.Promotion
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if body.ID == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "id is required", 400)
+		return
+	}
+
+	if !h.validPromotion(ctx, body) {
+		return
+	}
+
+	promotion, err := h.UseCase.PromotionRepo.Update(ctx, body)
+	if h.HandleDbError(ctx, err, "Error updating promotion") {
+		return
+	}
+
+	ctx.JSON(200, promotion)
+}
+
+// DeletePromotion godoc
+// @Router /promotion/{id} [delete]
+// @Summary Delete a promotion
+// @Description Delete a promotion
+// @Security BearerAuth
+// @Tags promotion
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Promotion ID"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) DeletePromotion(ctx *gin.Context) {
+	var (
+		req entity.Id
+	)
+
+	req.ID = ctx.Param("id")
+
+	err := h.UseCase.PromotionRepo.Delete(ctx, req)
+	if h.HandleDbError(ctx, err, "Error deleting promotion") {
+		return
+	}
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "Promotion deleted successfully",
+	})
+}
+
+func (h *Handler) validPromotion(ctx *gin.Context, body entity.Promotion) bool {
+	if body.Name == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "name is required", 400)
+		return false
+	}
+
+	switch {
+	case body.Type != entity.PromotionTypePercent && body.Type != entity.PromotionTypeFixed:
+		h.ReturnError(ctx, config.ErrorBadRequest, "type must be percent or fixed", 400)
+		return false
+	case body.Value <= 0 || (body.Type == entity.PromotionTypePercent && body.Value > 100):
+		h.ReturnError(ctx, config.ErrorBadRequest, "value must be positive, and at most 100 for percent promotions", 400)
+		return false
+	case (body.ProductID == "") == (body.CategoryID == ""):
+		h.ReturnError(ctx, config.ErrorBadRequest, "Either product_id or category_id is required", 400)
+		return false
+	}
+
+	return h.validDateRange(ctx, body.ValidFrom, body.ValidTo)
+}
@@ -0,0 +1,282 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/gin-gonic/gin"
+)
+
+// CreatePurchaseOrder godoc
+// @Router /purchase-order [post]
+// @Summary Create a purchase order
+// @Description Create a draft purchase order. Lines may be entered in any unit of their product, quantities are kept in the base unit
+// @Security BearerAuth
+// @Tags purchase-order
+// @Accept  json
+// @Produce  json
+// @Param order body entity.PurchaseOrder true "Purchase order object"
+// @Success 201 {object} entity.PurchaseOrder
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) CreatePurchaseOrder(ctx *gin.Context) {
+	var (
+		body entity.PurchaseOrder
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if !h.validPurchaseOrder(ctx, body) {
+		return
+	}
+
+	body.CreatedBy = ctx.GetHeader("sub")
+
+	order, err := h.UseCase.PurchaseOrderRepo.Create(ctx, body)
+	if h.HandleDbError(ctx, err, "Error creating purchase order") {
+		return
+	}
+
+	ctx.JSON(201, order)
+}
+
+// GetPurchaseOrder godoc
+// @Router /purchase-order/{id} [get]
+// @Summary Get a purchase order by ID
+// @Description Get a purchase order with its lines
+// @Security BearerAuth
+// @Tags purchase-order
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Purchase order ID"
+// @Success 200 {object} entity.PurchaseOrder
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetPurchaseOrder(ctx *gin.Context) {
+	var (
+		req entity.Id
+	)
+
+	req.ID = ctx.Param("id")
+
+	order, err := h.UseCase.PurchaseOrderRepo.GetSingle(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting purchase order") {
+		return
+	}
+
+	ctx.JSON(200, order)
+}
+
+// GetPurchaseOrders godoc
+// @Router /purchase-order/list [get]
+// @Summary Get a list of purchase orders
+// @Description Get a list of purchase orders without lines
+// @Security BearerAuth
+// @Tags purchase-order
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param search query string false "number"
+// @Param status query string false "draft, sent, partially_received, received or closed"
+// @Param supplier_id query string false "supplier_id"
+// @Param warehouse_id query string false "warehouse_id"
+// @Param from query string false "order date from, YYYY-MM-DD"
+// @Param to query string false "order date to, YYYY-MM-DD"
+// @Param format query string false "csv or xlsx, exports the whole filtered list instead of a page"
+// @Param lang query string false "uz or ru, language of the column headers of an export, uz by default"
+// @Success 200 {object} entity.PurchaseOrderList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetPurchaseOrders(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+	search := ctx.DefaultQuery("search", "")
+	from := ctx.DefaultQuery("from", "")
+	to := ctx.DefaultQuery("to", "")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+
+	if search != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "number",
+			Type:   "search",
+			Value:  search,
+		})
+	}
+
+	for _, column := range []string{"status", "supplier_id", "warehouse_id"} {
+		if value := ctx.DefaultQuery(column, ""); value != "" {
+			req.Filters = append(req.Filters, entity.Filter{
+				Column: column,
+				Type:   "eq",
+				Value:  value,
+			})
+		}
+	}
+
+	if from != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "order_date",
+			Type:   "gte",
+			Value:  from,
+		})
+	}
+
+	if to != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "order_date",
+			Type:   "lte",
+			Value:  to,
+		})
+	}
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "created_at",
+		Order:  "desc",
+	})
+
+	if h.export(ctx, "purchase-orders", entity.PurchaseOrder{}, req, func(req entity.GetListFilter) error {
+		_, err := h.UseCase.PurchaseOrderRepo.GetList(ctx, req)
+		return err
+	}) {
+		return
+	}
+
+	orders, err := h.UseCase.PurchaseOrderRepo.GetList(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting purchase orders") {
+		return
+	}
+
+	ctx.JSON(200, orders)
+}
+
+// UpdatePurchaseOrder godoc
+// @Router /purchase-order [put]
+// @Summary Update a draft purchase order
+// @Description Update the header and replace the lines of a draft purchase order
+// @Security BearerAuth
+// @Tags purchase-order
+// @Accept  json
+// @Produce  json
+// @Param order body entity.PurchaseOrder true "Purchase order object"
+// @Success 200 {object} entity.PurchaseOrder
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) UpdatePurchaseOrder(ctx *gin.Context) {
+	var (
+		body entity.PurchaseOrder
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if body.ID == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "id is required", 400)
+		return
+	}
+
+	if !h.validPurchaseOrder(ctx, body) {
+		return
+	}
+
+	order, err := h.UseCase.PurchaseOrderRepo.Update(ctx, body)
+	if h.HandleDbError(ctx, err, "Error updating purchase order") {
+		return
+	}
+
+	ctx.JSON(200, order)
+}
+
+// DeletePurchaseOrder godoc
+// @Router /purchase-order/{id} [delete]
+// @Summary Delete a draft purchase order
+// @Description Delete a draft purchase order
+// @Security BearerAuth
+// @Tags purchase-order
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Purchase order ID"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) DeletePurchaseOrder(ctx *gin.Context) {
+	var (
+		req entity.Id
+	)
+
+	req.ID = ctx.Param("id")
+
+	err := h.UseCase.PurchaseOrderRepo.Delete(ctx, req)
+	if h.HandleDbError(ctx, err, "Error deleting purchase order") {
+		return
+	}
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "Purchase order deleted successfully",
+	})
+}
+
+// SendPurchaseOrder godoc
+// @Router /purchase-order/{id}/send [post]
+// @Summary Send a purchase order
+// @Description Mark a draft purchase order as sent to the supplier, goods receipts can then reference its lines
+// @Security BearerAuth
+// @Tags purchase-order
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Purchase order ID"
+// @Success 200 {object} entity.PurchaseOrder
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) SendPurchaseOrder(ctx *gin.Context) {
+	order, err := h.UseCase.SendPurchaseOrder(ctx, ctx.Param("id"))
+	if h.HandleDbError(ctx, err, "Error sending purchase order") {
+		return
+	}
+
+	ctx.JSON(200, order)
+}
+
+// ClosePurchaseOrder godoc
+// @Router /purchase-order/{id}/close [post]
+// @Summary Close a purchase order
+// @Description Close a sent purchase order, quantities not received yet are no longer expected
+// @Security BearerAuth
+// @Tags purchase-order
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Purchase order ID"
+// @Success 200 {object} entity.PurchaseOrder
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) ClosePurchaseOrder(ctx *gin.Context) {
+	order, err := h.UseCase.ClosePurchaseOrder(ctx, ctx.Param("id"))
+	if h.HandleDbError(ctx, err, "Error closing purchase order") {
+		return
+	}
+
+	ctx.JSON(200, order)
+}
+
+func (h *Handler) validPurchaseOrder(ctx *gin.Context, body entity.PurchaseOrder) bool {
+	if body.SupplierID == "" || body.WarehouseID == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "supplier_id and warehouse_id are required", 400)
+		return false
+	}
+
+	for _, line := range body.Lines {
+		if line.ProductID == "" || !validQuantity(line.Quantity, line.UnitQuantity) || line.Price < 0 {
+			h.ReturnError(ctx, config.ErrorBadRequest, "Each line needs product_id, a positive quantity or unit_quantity and a non-negative price", 400)
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/gin-gonic/gin"
+)
+
+// SetStockLevel godoc
+// @Router /replenishment/level [post]
+// @Summary Set the stock level of a product in a warehouse
+// @Description Create or replace the min/max levels and the reorder point of a product in a warehouse
+// @Security BearerAuth
+// @Tags replenishment
+// @Accept  json
+// @Produce  json
+// @Param level body entity.StockLevel true "Stock level object"
+// @Success 200 {object} entity.StockLevel
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) SetStockLevel(ctx *gin.Context) {
+	var (
+		body entity.StockLevel
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if body.ProductID == "" || body.WarehouseID == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "product_id and warehouse_id are required", 400)
+		return
+	}
+
+	if body.MinQuantity < 0 || body.ReorderPoint < 0 || body.MaxQuantity < body.MinQuantity || body.MaxQuantity < body.ReorderPoint {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Quantities can't be negative and max_quantity can't be below min_quantity or reorder_point", 400)
+		return
+	}
+
+	level, err := h.UseCase.ReplenishmentRepo.SetLevel(ctx, body)
+	if h.HandleDbError(ctx, err, "Error setting stock level") {
+		return
+	}
+
+	ctx.JSON(200, level)
+}
+
+// GetStockLevels godoc
+// @Router /replenishment/level/list [get]
+// @Summary Get a list of stock levels
+// @Description Get the min/max levels and reorder points
+// @Security BearerAuth
+// @Tags replenishment
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param product_id query string false "product_id"
+// @Param warehouse_id query string false "warehouse_id"
+// @Param format query string false "csv or xlsx, exports the whole filtered list instead of a page"
+// @Param lang query string false "uz or ru, language of the column headers of an export, uz by default"
+// @Success 200 {object} entity.StockLevelList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetStockLevels(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+
+	for _, column := range []string{"product_id", "warehouse_id"} {
+		if value := ctx.DefaultQuery(column, ""); value != "" {
+			req.Filters = append(req.Filters, entity.Filter{
+				Column: column,
+				Type:   "eq",
+				Value:  value,
+			})
+		}
+	}
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "updated_at",
+		Order:  "desc",
+	})
+
+	if h.export(ctx, "stock-levels", entity.StockLevel{}, req, func(req entity.GetListFilter) error {
+		_, err := h.UseCase.ReplenishmentRepo.GetLevels(ctx, req)
+		return err
+	}) {
+		return
+	}
+
+	levels, err := h.UseCase.ReplenishmentRepo.GetLevels(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting stock levels") {
+		return
+	}
+
+	ctx.JSON(200, levels)
+}
+
+// DeleteStockLevel godoc
+// @Router /replenishment/level/{warehouse_id}/{product_id} [delete]
+// @Summary Delete a stock level
+// @Description Stop replenishing a product in a warehouse
+// @Security BearerAuth
+// @Tags replenishment
+// @Accept  json
+// @Produce  json
+// @Param warehouse_id path string true "Warehouse ID"
+// @Param product_id path string true "Product ID"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) DeleteStockLevel(ctx *gin.Context) {
+	err := h.UseCase.ReplenishmentRepo.DeleteLevel(ctx, ctx.Param("product_id"), ctx.Param("warehouse_id"))
+	if h.HandleDbError(ctx, err, "Error deleting stock level") {
+		return
+	}
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "Stock level deleted successfully",
+	})
+}
+
+// GetReplenishmentSuggestions godoc
+// @Router /replenishment/suggestions [get]
+// @Summary Get purchase suggestions
+// @Description What to order to bring products at or below their reorder point back up to max, from on-hand, in-transit, on-order and reserved quantities, grouped by preferred supplier
+// @Security BearerAuth
+// @Tags replenishment
+// @Accept  json
+// @Produce  json
+// @Param warehouse_id query string false "warehouse_id"
+// @Param supplier_id query string false "supplier_id"
+// @Param product_id query string false "product_id"
+// @Success 200 {object} entity.ReplenishmentSuggestionList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetReplenishmentSuggestions(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	for _, column := range []string{"warehouse_id", "supplier_id", "product_id"} {
+		if value := ctx.DefaultQuery(column, ""); value != "" {
+			req.Filters = append(req.Filters, entity.Filter{
+				Column: column,
+				Type:   "eq",
+				Value:  value,
+			})
+		}
+	}
+
+	suggestions, err := h.UseCase.ReplenishmentRepo.GetSuggestions(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting replenishment suggestions") {
+		return
+	}
+
+	ctx.JSON(200, suggestions)
+}
+
+// CreateReplenishmentOrders godoc
+// @Router /replenishment/order [post]
+// @Summary Create purchase orders from the suggestions
+// @Description Turn the current suggestions into draft purchase orders, one per supplier and warehouse. Products without a preferred supplier are skipped
+// @Security BearerAuth
+// @Tags replenishment
+// @Accept  json
+// @Produce  json
+// @Param request body entity.ReplenishmentOrderRequest true "Which suggestions to order"
+// @Success 201 {object} entity.ReplenishmentOrderResponse
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) CreateReplenishmentOrders(ctx *gin.Context) {
+	var (
+		body entity.ReplenishmentOrderRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	body.CreatedBy = ctx.GetHeader("sub")
+
+	response, err := h.UseCase.CreateReplenishmentOrders(ctx, body)
+	if h.HandleDbError(ctx, err, "Error creating purchase orders") {
+		return
+	}
+
+	ctx.JSON(201, response)
+}
@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/gin-gonic/gin"
+)
+
+// GetStockAvailability godoc
+// @Router /stock/availability [get]
+// @Summary Get available-to-promise quantities
+// @Description Get on-hand quantities outside virtual bins, the quantities reserved by processing sales orders and what is left to promise, per product and warehouse. Bundles are counted by how many of them their components make up
+// @Security BearerAuth
+// @Tags stock
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param product_id query string false "product_id"
+// @Param warehouse_id query string false "warehouse_id"
+// @Param unit query string false "show quantities in this unit where the product defines it"
+// @Param format query string false "csv or xlsx, exports the whole filtered list instead of a page"
+// @Param lang query string false "uz or ru, language of the column headers of an export, uz by default"
+// @Success 200 {object} entity.StockAvailabilityList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetStockAvailability(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+
+	for _, column := range []string{"product_id", "warehouse_id"} {
+		if value := ctx.DefaultQuery(column, ""); value != "" {
+			req.Filters = append(req.Filters, entity.Filter{
+				Column: column,
+				Type:   "eq",
+				Value:  value,
+			})
+		}
+	}
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "product_id",
+		Order:  "asc",
+	})
+
+	if h.export(ctx, "stock-availability", entity.StockAvailability{}, req, func(req entity.GetListFilter) error {
+		units, err := h.exportUnits(ctx)
+		if err != nil {
+			return err
+		}
+
+		_, err = h.UseCase.ReservationRepo.GetAvailability(ctx, exportEach(req, func(item entity.StockAvailability) entity.StockAvailability {
+			return availabilityInUnit(item, units)
+		}))
+		return err
+	}) {
+		return
+	}
+
+	availability, err := h.UseCase.ReservationRepo.GetAvailability(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting stock availability") {
+		return
+	}
+
+	ids := make([]string, 0, len(availability.Items))
+	for _, item := range availability.Items {
+		ids = append(ids, item.ProductID)
+	}
+
+	units, ok := h.reportUnits(ctx, ids)
+	if !ok {
+		return
+	}
+
+	for i, item := range availability.Items {
+		availability.Items[i] = availabilityInUnit(item, units)
+	}
+
+	ctx.JSON(200, availability)
+}
+
+// GetStockReservations godoc
+// @Router /stock/reservation/list [get]
+// @Summary Get stock reservations
+// @Description Get the reservations held by processing sales orders, newest first
+// @Security BearerAuth
+// @Tags stock
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param product_id query string false "product_id"
+// @Param warehouse_id query string false "warehouse_id"
+// @Param order_id query string false "order_id"
+// @Param format query string false "csv or xlsx, exports the whole filtered list instead of a page"
+// @Param lang query string false "uz or ru, language of the column headers of an export, uz by default"
+// @Success 200 {object} entity.StockReservationList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetStockReservations(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+
+	for _, column := range []string{"product_id", "warehouse_id", "order_id"} {
+		if value := ctx.DefaultQuery(column, ""); value != "" {
+			req.Filters = append(req.Filters, entity.Filter{
+				Column: column,
+				Type:   "eq",
+				Value:  value,
+			})
+		}
+	}
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "created_at",
+		Order:  "desc",
+	})
+
+	if h.export(ctx, "stock-reservations", entity.StockReservation{}, req, func(req entity.GetListFilter) error {
+		_, err := h.UseCase.ReservationRepo.GetList(ctx, req)
+		return err
+	}) {
+		return
+	}
+
+	reservations, err := h.UseCase.ReservationRepo.GetList(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting stock reservations") {
+		return
+	}
+
+	ctx.JSON(200, reservations)
+}
+
+// availabilityInUnit shows a row of the report in the unit of its product in units.
+func availabilityInUnit(item entity.StockAvailability, units map[string]entity.ProductUnit) entity.StockAvailability {
+	unit, found := units[item.ProductID]
+	if !found {
+		return item
+	}
+
+	item.OnHand = inUnit(item.OnHand, unit)
+	item.Reserved = inUnit(item.Reserved, unit)
+	item.Available = inUnit(item.Available, unit)
+	item.Unit = unit.Unit
+
+	return item
+}
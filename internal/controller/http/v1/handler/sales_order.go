@@ -0,0 +1,331 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateSalesOrder godoc
+// @Router /sales-order [post]
+// @Summary Create a sales order
+// @Description Create a pending sales order, stock is not changed until it is completed. Lines may be entered in any unit of their product, quantities are kept in the base unit. Lines are priced by the customer's price list and the promotions in effect on the order date, a different price entered on a line is kept. price_rules tells how the price was arrived at
+// @Security BearerAuth
+// @Tags sales-order
+// @Accept  json
+// @Produce  json
+// @Param order body entity.SalesOrder true "Sales order object"
+// @Success 201 {object} entity.SalesOrder
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) CreateSalesOrder(ctx *gin.Context) {
+	var (
+		body entity.SalesOrder
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if !h.validSalesOrder(ctx, body) {
+		return
+	}
+
+	body.CreatedBy = ctx.GetHeader("sub")
+
+	order, err := h.UseCase.CreateSalesOrder(ctx, body)
+	if h.HandleDbError(ctx, err, "Error creating sales order") {
+		return
+	}
+
+	ctx.JSON(201, order)
+}
+
+// GetSalesOrder godoc
+// @Router /sales-order/{id} [get]
+// @Summary Get a sales order by ID
+// @Description Get a sales order with its lines
+// @Security BearerAuth
+// @Tags sales-order
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Sales order ID"
+// @Success 200 {object} entity.SalesOrder
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetSalesOrder(ctx *gin.Context) {
+	var (
+		req entity.Id
+	)
+
+	req.ID = ctx.Param("id")
+
+	order, err := h.UseCase.SalesOrderRepo.GetSingle(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting sales order") {
+		return
+	}
+
+	ctx.JSON(200, order)
+}
+
+// GetSalesOrders godoc
+// @Router /sales-order/list [get]
+// @Summary Get a list of sales orders
+// @Description Get a list of sales orders without lines
+// @Security BearerAuth
+// @Tags sales-order
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param search query string false "number or customer name"
+// @Param status query string false "pending, processing, picking, picked, packed, completed or cancelled"
+// @Param customer_id query string false "customer_id"
+// @Param warehouse_id query string false "warehouse_id"
+// @Param from query string false "order date from, YYYY-MM-DD"
+// @Param to query string false "order date to, YYYY-MM-DD"
+// @Param format query string false "csv or xlsx, exports the whole filtered list instead of a page"
+// @Param lang query string false "uz or ru, language of the column headers of an export, uz by default"
+// @Success 200 {object} entity.SalesOrderList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetSalesOrders(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+	search := ctx.DefaultQuery("search", "")
+	from := ctx.DefaultQuery("from", "")
+	to := ctx.DefaultQuery("to", "")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+
+	if search != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "number",
+			Type:   "search",
+			Value:  search,
+		}, entity.Filter{
+			Column: "customer_name",
+			Type:   "search",
+			Value:  search,
+		})
+	}
+
+	for _, column := range []string{"status", "customer_id", "warehouse_id"} {
+		if value := ctx.DefaultQuery(column, ""); value != "" {
+			req.Filters = append(req.Filters, entity.Filter{
+				Column: column,
+				Type:   "eq",
+				Value:  value,
+			})
+		}
+	}
+
+	if from != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "order_date",
+			Type:   "gte",
+			Value:  from,
+		})
+	}
+
+	if to != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "order_date",
+			Type:   "lte",
+			Value:  to,
+		})
+	}
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "created_at",
+		Order:  "desc",
+	})
+
+	if h.export(ctx, "sales-orders", entity.SalesOrder{}, req, func(req entity.GetListFilter) error {
+		_, err := h.UseCase.SalesOrderRepo.GetList(ctx, req)
+		return err
+	}) {
+		return
+	}
+
+	orders, err := h.UseCase.SalesOrderRepo.GetList(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting sales orders") {
+		return
+	}
+
+	ctx.JSON(200, orders)
+}
+
+// UpdateSalesOrder godoc
+// @Router /sales-order [put]
+// @Summary Update a pending sales order
+// @Description Update the header and replace the lines of a pending sales order, the lines are priced again on the order date
+// @Security BearerAuth
+// @Tags sales-order
+// @Accept  json
+// @Produce  json
+// @Param order body entity.SalesOrder true "Sales order object"
+// @Success 200 {object} entity.SalesOrder
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) UpdateSalesOrder(ctx *gin.Context) {
+	var (
+		body entity.SalesOrder
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if body.ID == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "id is required", 400)
+		return
+	}
+
+	if !h.validSalesOrder(ctx, body) {
+		return
+	}
+
+	order, err := h.UseCase.UpdateSalesOrder(ctx, body)
+	if h.HandleDbError(ctx, err, "Error updating sales order") {
+		return
+	}
+
+	ctx.JSON(200, order)
+}
+
+// DeleteSalesOrder godoc
+// @Router /sales-order/{id} [delete]
+// @Summary Delete a pending sales order
+// @Description Delete a pending sales order, orders further along have to be cancelled
+// @Security BearerAuth
+// @Tags sales-order
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Sales order ID"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) DeleteSalesOrder(ctx *gin.Context) {
+	var (
+		req entity.Id
+	)
+
+	req.ID = ctx.Param("id")
+
+	err := h.UseCase.SalesOrderRepo.Delete(ctx, req)
+	if h.HandleDbError(ctx, err, "Error deleting sales order") {
+		return
+	}
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "Sales order deleted successfully",
+	})
+}
+
+// ChangeSalesOrderStatus godoc
+// @Router /sales-order/status [put]
+// @Summary Change the status of a sales order
+// @Description Move an order along pending -> processing -> completed, or cancel it. Processing reserves the order quantities, leaving processing or fulfillment releases them. Completing a processing order ships its lines out of stock. Picking, picked and packed are set by the pick list, packing and shipment endpoints
+// @Security BearerAuth
+// @Tags sales-order
+// @Accept  json
+// @Produce  json
+// @Param body body entity.SalesOrderStatusRequest true "Order ID and new status"
+// @Success 200 {object} entity.SalesOrder
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) ChangeSalesOrderStatus(ctx *gin.Context) {
+	var (
+		body entity.SalesOrderStatusRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if body.ID == "" || body.Status == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "id and status are required", 400)
+		return
+	}
+
+	order, err := h.UseCase.ChangeSalesOrderStatus(ctx, body, ctx.GetHeader("sub"))
+	if h.HandleDbError(ctx, err, "Error changing sales order status") {
+		return
+	}
+
+	ctx.JSON(200, order)
+}
+
+// ReturnSalesOrderLines godoc
+// @Router /sales-order/{id}/return [post]
+// @Summary Return goods of a completed sales order
+// @Description Record returned quantities per line and put the goods back into stock
+// @Security BearerAuth
+// @Tags sales-order
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Sales order ID"
+// @Param body body entity.SalesOrderReturnRequest true "Returned lines"
+// @Success 200 {object} entity.SalesOrder
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) ReturnSalesOrderLines(ctx *gin.Context) {
+	var (
+		body entity.SalesOrderReturnRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil || len(body.Lines) == 0 {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	serials := make([][]string, 0, len(body.Lines))
+	for _, line := range body.Lines {
+		if line.LineID == "" || line.Quantity <= 0 {
+			h.ReturnError(ctx, config.ErrorBadRequest, "Each line needs line_id and a positive quantity", 400)
+			return
+		}
+
+		serials = append(serials, line.Serials)
+	}
+
+	if !h.validSerials(ctx, serials...) {
+		return
+	}
+
+	body.OrderID = ctx.Param("id")
+
+	order, err := h.UseCase.ReturnSalesOrderLines(ctx, body, ctx.GetHeader("sub"))
+	if h.HandleDbError(ctx, err, "Error returning sales order lines") {
+		return
+	}
+
+	ctx.JSON(200, order)
+}
+
+func (h *Handler) validSalesOrder(ctx *gin.Context, body entity.SalesOrder) bool {
+	if body.WarehouseID == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "warehouse_id is required", 400)
+		return false
+	}
+
+	serials := make([][]string, 0, len(body.Lines))
+	for _, line := range body.Lines {
+		if line.ProductID == "" || line.BinID == "" || !validQuantity(line.Quantity, line.UnitQuantity) || line.Price < 0 {
+			h.ReturnError(ctx, config.ErrorBadRequest, "Each line needs product_id, bin_id, a positive quantity or unit_quantity and a non-negative price", 400)
+			return false
+		}
+
+		serials = append(serials, line.Serials)
+	}
+
+	return h.validSerials(ctx, serials...)
+}
@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/gin-gonic/gin"
+)
+
+// GetSerials godoc
+// @Router /serial/list [get]
+// @Summary Get a list of serial numbers
+// @Description Get a list of units of serialized products
+// @Security BearerAuth
+// @Tags serial
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param product_id query string false "product_id"
+// @Param bin_id query string false "bin_id"
+// @Param status query string false "in_stock, in_transit, sold, removed or missing"
+// @Param search query string false "serial number"
+// @Param format query string false "csv or xlsx, exports the whole filtered list instead of a page"
+// @Param lang query string false "uz or ru, language of the column headers of an export, uz by default"
+// @Success 200 {object} entity.SerialList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetSerials(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+	productID := ctx.DefaultQuery("product_id", "")
+	binID := ctx.DefaultQuery("bin_id", "")
+	status := ctx.DefaultQuery("status", "")
+	search := ctx.DefaultQuery("search", "")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+
+	if productID != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "product_id",
+			Type:   "eq",
+			Value:  productID,
+		})
+	}
+
+	if binID != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "bin_id",
+			Type:   "eq",
+			Value:  binID,
+		})
+	}
+
+	if status != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "status",
+			Type:   "eq",
+			Value:  status,
+		})
+	}
+
+	if search != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "serial_number",
+			Type:   "search",
+			Value:  search,
+		})
+	}
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "serial_number",
+		Order:  "asc",
+	})
+
+	if h.export(ctx, "serials", entity.Serial{}, req, func(req entity.GetListFilter) error {
+		_, err := h.UseCase.SerialRepo.GetList(ctx, req)
+		return err
+	}) {
+		return
+	}
+
+	serials, err := h.UseCase.SerialRepo.GetList(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting serial numbers") {
+		return
+	}
+
+	ctx.JSON(200, serials)
+}
+
+// GetSerialHistory godoc
+// @Router /serial/{sn} [get]
+// @Summary Get the history of a serial number
+// @Description Get a unit of a serialized product together with every stock movement that carried it, oldest first
+// @Security BearerAuth
+// @Tags serial
+// @Accept  json
+// @Produce  json
+// @Param sn path string true "serial number"
+// @Success 200 {object} entity.SerialHistory
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+func (h *Handler) GetSerialHistory(ctx *gin.Context) {
+	history, err := h.UseCase.SerialRepo.GetHistory(ctx, ctx.Param("sn"))
+	if h.HandleDbError(ctx, err, "Error getting serial number history") {
+		return
+	}
+
+	ctx.JSON(200, history)
+}
+
+// validSerials rejects empty serial numbers and serial numbers given twice in
+// one document, the lists of all its lines are passed together.
+func (h *Handler) validSerials(ctx *gin.Context, lines ...[]string) bool {
+	seen := map[string]bool{}
+	for _, serials := range lines {
+		for _, serial := range serials {
+			if serial == "" || seen[serial] {
+				h.ReturnError(ctx, config.ErrorBadRequest, "Serial numbers must be non-empty and unique in the document", 400)
+				return false
+			}
+			seen[serial] = true
+		}
+	}
+
+	return true
+}
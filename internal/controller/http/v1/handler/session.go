@@ -45,6 +45,8 @@ func (h *Handler) GetSession(ctx *gin.Context) {
 // @Param page query number true "page"
 // @Param limit query number true "limit"
 // @Param user_id query string false "user_id"
+// @Param format query string false "csv or xlsx, exports the whole filtered list instead of a page"
+// @Param lang query string false "uz or ru, language of the column headers of an export, uz by default"
 // @Success 200 {object} entity.SessionList
 // @Failure 400 {object} entity.ErrorResponse
 func (h *Handler) GetSessions(ctx *gin.Context) {
@@ -81,6 +83,13 @@ func (h *Handler) GetSessions(ctx *gin.Context) {
 		Order:  "desc",
 	})
 
+	if h.export(ctx, "sessions", entity.Session{}, req, func(req entity.GetListFilter) error {
+		_, err := h.UseCase.SessionRepo.GetList(ctx, req)
+		return err
+	}) {
+		return
+	}
+
 	// Fetch sessions from the repository
 	sessions, err := h.UseCase.SessionRepo.GetList(ctx, req)
 	if err != nil {
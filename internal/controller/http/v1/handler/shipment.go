@@ -0,0 +1,223 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/gin-gonic/gin"
+)
+
+// PackSalesOrder godoc
+// @Router /sales-order/{id}/pack [post]
+// @Summary Pack a picked sales order
+// @Description Pack a picked order into parcels and move it to packed. Packing again before shipping replaces the parcels
+// @Security BearerAuth
+// @Tags sales-order
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Sales order ID"
+// @Param body body entity.PackRequest true "Parcels with their weights"
+// @Success 200 {object} entity.ParcelList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) PackSalesOrder(ctx *gin.Context) {
+	var (
+		body entity.PackRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil || len(body.Parcels) == 0 {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	for _, parcel := range body.Parcels {
+		if parcel.Weight <= 0 {
+			h.ReturnError(ctx, config.ErrorBadRequest, "Each parcel needs a positive weight", 400)
+			return
+		}
+	}
+
+	body.OrderID = ctx.Param("id")
+
+	parcels, err := h.UseCase.PackSalesOrder(ctx, body)
+	if h.HandleDbError(ctx, err, "Error packing sales order") {
+		return
+	}
+
+	ctx.JSON(200, entity.ParcelList{
+		Items: parcels,
+		Count: len(parcels),
+	})
+}
+
+// GetSalesOrderParcels godoc
+// @Router /sales-order/{id}/parcels [get]
+// @Summary Get the parcels of a sales order
+// @Description Get the parcels a sales order is packed into
+// @Security BearerAuth
+// @Tags sales-order
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Sales order ID"
+// @Success 200 {object} entity.ParcelList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetSalesOrderParcels(ctx *gin.Context) {
+	parcels, err := h.UseCase.ShipmentRepo.GetParcels(ctx, ctx.Param("id"))
+	if h.HandleDbError(ctx, err, "Error getting parcels") {
+		return
+	}
+
+	ctx.JSON(200, entity.ParcelList{
+		Items: parcels,
+		Count: len(parcels),
+	})
+}
+
+// CreateShipment godoc
+// @Router /shipment [post]
+// @Summary Ship a packed sales order
+// @Description Hand the parcels of a packed order over to a carrier. The picked goods leave stock and the order is completed
+// @Security BearerAuth
+// @Tags shipment
+// @Accept  json
+// @Produce  json
+// @Param shipment body entity.Shipment true "Order, carrier and tracking number"
+// @Success 201 {object} entity.Shipment
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) CreateShipment(ctx *gin.Context) {
+	var (
+		body entity.Shipment
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if body.OrderID == "" || body.Carrier == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "order_id and carrier are required", 400)
+		return
+	}
+
+	shipment, err := h.UseCase.CreateShipment(ctx, body, ctx.GetHeader("sub"))
+	if h.HandleDbError(ctx, err, "Error creating shipment") {
+		return
+	}
+
+	ctx.JSON(201, shipment)
+}
+
+// GetShipment godoc
+// @Router /shipment/{id} [get]
+// @Summary Get a shipment by ID
+// @Description Get a shipment with its parcels
+// @Security BearerAuth
+// @Tags shipment
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Shipment ID"
+// @Success 200 {object} entity.Shipment
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetShipment(ctx *gin.Context) {
+	var (
+		req entity.Id
+	)
+
+	req.ID = ctx.Param("id")
+
+	shipment, err := h.UseCase.ShipmentRepo.GetSingle(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting shipment") {
+		return
+	}
+
+	ctx.JSON(200, shipment)
+}
+
+// GetShipments godoc
+// @Router /shipment/list [get]
+// @Summary Get a list of shipments
+// @Description Get a list of shipments without parcels
+// @Security BearerAuth
+// @Tags shipment
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param search query string false "tracking number"
+// @Param carrier query string false "carrier"
+// @Param order_id query string false "order_id"
+// @Param from query string false "shipped at or after, RFC3339"
+// @Param to query string false "shipped before, RFC3339"
+// @Param format query string false "csv or xlsx, exports the whole filtered list instead of a page"
+// @Param lang query string false "uz or ru, language of the column headers of an export, uz by default"
+// @Success 200 {object} entity.ShipmentList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetShipments(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+	search := ctx.DefaultQuery("search", "")
+	from := ctx.DefaultQuery("from", "")
+	to := ctx.DefaultQuery("to", "")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+
+	if search != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "tracking_number",
+			Type:   "search",
+			Value:  search,
+		})
+	}
+
+	for _, column := range []string{"carrier", "order_id"} {
+		if value := ctx.DefaultQuery(column, ""); value != "" {
+			req.Filters = append(req.Filters, entity.Filter{
+				Column: column,
+				Type:   "eq",
+				Value:  value,
+			})
+		}
+	}
+
+	if from != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "shipped_at",
+			Type:   "gte",
+			Value:  from,
+		})
+	}
+
+	if to != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "shipped_at",
+			Type:   "lt",
+			Value:  to,
+		})
+	}
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "shipped_at",
+		Order:  "desc",
+	})
+
+	if h.export(ctx, "shipments", entity.Shipment{}, req, func(req entity.GetListFilter) error {
+		_, err := h.UseCase.ShipmentRepo.GetList(ctx, req)
+		return err
+	}) {
+		return
+	}
+
+	shipments, err := h.UseCase.ShipmentRepo.GetList(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting shipments") {
+		return
+	}
+
+	ctx.JSON(200, shipments)
+}
@@ -0,0 +1,243 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/gin-gonic/gin"
+)
+
+// GetStockBalances godoc
+// @Router /stock/balance [get]
+// @Summary Get on-hand balances
+// @Description Get on-hand quantities per product, bin and lot, derived from the stock ledger
+// @Security BearerAuth
+// @Tags stock
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param product_id query string false "product_id"
+// @Param warehouse_id query string false "warehouse_id"
+// @Param bin_id query string false "bin_id"
+// @Param lot_id query string false "lot_id"
+// @Param include_zero query bool false "include emptied bins"
+// @Param unit query string false "show quantities in this unit where the product defines it"
+// @Param format query string false "csv or xlsx, exports the whole filtered list instead of a page"
+// @Param lang query string false "uz or ru, language of the column headers of an export, uz by default"
+// @Success 200 {object} entity.StockBalanceList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetStockBalances(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+	includeZero := ctx.DefaultQuery("include_zero", "false")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+
+	for _, column := range []string{"product_id", "warehouse_id", "bin_id", "lot_id"} {
+		if value := ctx.DefaultQuery(column, ""); value != "" {
+			req.Filters = append(req.Filters, entity.Filter{
+				Column: column,
+				Type:   "eq",
+				Value:  value,
+			})
+		}
+	}
+
+	if includeZero != "true" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "quantity",
+			Type:   "gt",
+			Value:  "0",
+		})
+	}
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "updated_at",
+		Order:  "desc",
+	})
+
+	if h.export(ctx, "stock-balances", entity.StockBalance{}, req, func(req entity.GetListFilter) error {
+		units, err := h.exportUnits(ctx)
+		if err != nil {
+			return err
+		}
+
+		_, err = h.UseCase.StockRepo.GetBalances(ctx, exportEach(req, func(item entity.StockBalance) entity.StockBalance {
+			return balanceInUnit(item, units)
+		}))
+		return err
+	}) {
+		return
+	}
+
+	balances, err := h.UseCase.StockRepo.GetBalances(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting stock balances") {
+		return
+	}
+
+	ids := make([]string, 0, len(balances.Items))
+	for _, item := range balances.Items {
+		ids = append(ids, item.ProductID)
+	}
+
+	units, ok := h.reportUnits(ctx, ids)
+	if !ok {
+		return
+	}
+
+	for i, item := range balances.Items {
+		balances.Items[i] = balanceInUnit(item, units)
+	}
+
+	ctx.JSON(200, balances)
+}
+
+// GetStockMovements godoc
+// @Router /stock/movements [get]
+// @Summary Get stock ledger movements
+// @Description Get stock ledger movements, newest first
+// @Security BearerAuth
+// @Tags stock
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param product_id query string false "product_id"
+// @Param warehouse_id query string false "warehouse_id"
+// @Param bin_id query string false "bin_id"
+// @Param lot_id query string false "lot_id"
+// @Param reason query string false "reason"
+// @Param document_id query string false "document_id"
+// @Param from query string false "created at or after, RFC3339"
+// @Param to query string false "created before, RFC3339"
+// @Param format query string false "csv or xlsx, exports the whole filtered list instead of a page"
+// @Param lang query string false "uz or ru, language of the column headers of an export, uz by default"
+// @Success 200 {object} entity.StockMovementList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetStockMovements(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+	from := ctx.DefaultQuery("from", "")
+	to := ctx.DefaultQuery("to", "")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+
+	for _, column := range []string{"product_id", "warehouse_id", "bin_id", "lot_id", "reason", "document_id"} {
+		if value := ctx.DefaultQuery(column, ""); value != "" {
+			req.Filters = append(req.Filters, entity.Filter{
+				Column: column,
+				Type:   "eq",
+				Value:  value,
+			})
+		}
+	}
+
+	if from != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "created_at",
+			Type:   "gte",
+			Value:  from,
+		})
+	}
+
+	if to != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "created_at",
+			Type:   "lt",
+			Value:  to,
+		})
+	}
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "created_at",
+		Order:  "desc",
+	})
+
+	if h.export(ctx, "stock-movements", entity.StockMovement{}, req, func(req entity.GetListFilter) error {
+		_, err := h.UseCase.StockRepo.GetMovements(ctx, req)
+		return err
+	}) {
+		return
+	}
+
+	movements, err := h.UseCase.StockRepo.GetMovements(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting stock movements") {
+		return
+	}
+
+	ctx.JSON(200, movements)
+}
+
+// CreateStockAdjustment godoc
+// @Router /stock/adjustment [post]
+// @Summary Post a manual stock adjustment
+// @Description Post signed quantity corrections to the stock ledger. Lines of serialized products carry one serial number per unit added or written off
+// @Security BearerAuth
+// @Tags stock
+// @Accept  json
+// @Produce  json
+// @Param body body entity.StockAdjustmentRequest true "Adjustment lines"
+// @Success 201 {array} entity.StockMovement
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) CreateStockAdjustment(ctx *gin.Context) {
+	var (
+		body entity.StockAdjustmentRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil || len(body.Lines) == 0 {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	movements := make([]entity.StockMovement, 0, len(body.Lines))
+	for _, line := range body.Lines {
+		if line.ProductID == "" || line.BinID == "" || line.Quantity == 0 {
+			h.ReturnError(ctx, config.ErrorBadRequest, "product_id, bin_id and a non-zero quantity are required", 400)
+			return
+		}
+
+		movements = append(movements, entity.StockMovement{
+			ProductID: line.ProductID,
+			BinID:     line.BinID,
+			LotID:     line.LotID,
+			Quantity:  line.Quantity,
+			Serials:   line.Serials,
+			Reason:    entity.MovementReasonAdjustment,
+			Note:      body.Note,
+			UserID:    ctx.GetHeader("sub"),
+		})
+	}
+
+	movements, err = h.UseCase.CreateStockAdjustment(ctx, movements)
+	if h.HandleDbError(ctx, err, "Error posting stock adjustment") {
+		return
+	}
+
+	ctx.JSON(201, movements)
+}
+
+// balanceInUnit shows a row of the report in the unit of its product in units.
+func balanceInUnit(item entity.StockBalance, units map[string]entity.ProductUnit) entity.StockBalance {
+	unit, found := units[item.ProductID]
+	if !found {
+		return item
+	}
+
+	item.Quantity = inUnit(item.Quantity, unit)
+	item.Unit = unit.Unit
+
+	return item
+}
@@ -0,0 +1,277 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateStocktake godoc
+// @Router /stocktake [post]
+// @Summary Open a stocktake
+// @Description Open a count session for a warehouse, optionally narrowed to a zone and/or a category, and snapshot the expected quantities
+// @Security BearerAuth
+// @Tags stocktake
+// @Accept  json
+// @Produce  json
+// @Param stocktake body entity.Stocktake true "Stocktake object"
+// @Success 201 {object} entity.Stocktake
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) CreateStocktake(ctx *gin.Context) {
+	var (
+		body entity.Stocktake
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if body.WarehouseID == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "warehouse_id is required", 400)
+		return
+	}
+
+	body.CreatedBy = ctx.GetHeader("sub")
+
+	stocktake, err := h.UseCase.StocktakeRepo.Create(ctx, body)
+	if h.HandleDbError(ctx, err, "Error creating stocktake") {
+		return
+	}
+
+	ctx.JSON(201, stocktake)
+}
+
+// GetStocktake godoc
+// @Router /stocktake/{id} [get]
+// @Summary Get a stocktake by ID
+// @Description Get a stocktake with expected and counted quantities and the variance of every line
+// @Security BearerAuth
+// @Tags stocktake
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Stocktake ID"
+// @Success 200 {object} entity.Stocktake
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetStocktake(ctx *gin.Context) {
+	var (
+		req entity.Id
+	)
+
+	req.ID = ctx.Param("id")
+
+	stocktake, err := h.UseCase.StocktakeRepo.GetSingle(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting stocktake") {
+		return
+	}
+
+	ctx.JSON(200, stocktake)
+}
+
+// GetStocktakes godoc
+// @Router /stocktake/list [get]
+// @Summary Get a list of stocktakes
+// @Description Get a list of stocktakes without lines
+// @Security BearerAuth
+// @Tags stocktake
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param search query string false "number"
+// @Param status query string false "open, approved or cancelled"
+// @Param warehouse_id query string false "warehouse_id"
+// @Param format query string false "csv or xlsx, exports the whole filtered list instead of a page"
+// @Param lang query string false "uz or ru, language of the column headers of an export, uz by default"
+// @Success 200 {object} entity.StocktakeList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetStocktakes(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+	search := ctx.DefaultQuery("search", "")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+
+	if search != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "number",
+			Type:   "search",
+			Value:  search,
+		})
+	}
+
+	for _, column := range []string{"status", "warehouse_id"} {
+		if value := ctx.DefaultQuery(column, ""); value != "" {
+			req.Filters = append(req.Filters, entity.Filter{
+				Column: column,
+				Type:   "eq",
+				Value:  value,
+			})
+		}
+	}
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "created_at",
+		Order:  "desc",
+	})
+
+	if h.export(ctx, "stocktakes", entity.Stocktake{}, req, func(req entity.GetListFilter) error {
+		_, err := h.UseCase.StocktakeRepo.GetList(ctx, req)
+		return err
+	}) {
+		return
+	}
+
+	stocktakes, err := h.UseCase.StocktakeRepo.GetList(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting stocktakes") {
+		return
+	}
+
+	ctx.JSON(200, stocktakes)
+}
+
+// GetStocktakeSheet godoc
+// @Router /stocktake/{id}/sheet [get]
+// @Summary Get the count sheet of a stocktake
+// @Description Get the bins and products to count, without expected quantities
+// @Security BearerAuth
+// @Tags stocktake
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Stocktake ID"
+// @Success 200 {object} entity.StocktakeSheet
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetStocktakeSheet(ctx *gin.Context) {
+	var (
+		req entity.Id
+	)
+
+	req.ID = ctx.Param("id")
+
+	sheet, err := h.UseCase.StocktakeRepo.GetSheet(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting stocktake sheet") {
+		return
+	}
+
+	ctx.JSON(200, sheet)
+}
+
+// CreateStocktakeCounts godoc
+// @Router /stocktake/{id}/count [post]
+// @Summary Submit counted quantities
+// @Description Record counts for an open stocktake. A bin may be counted again, the latest count wins. Counts may be entered in any unit of their product, quantities are kept in the base unit
+// @Security BearerAuth
+// @Tags stocktake
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Stocktake ID"
+// @Param body body entity.StocktakeCountRequest true "Counts"
+// @Success 200 {object} entity.StocktakeSheet
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) CreateStocktakeCounts(ctx *gin.Context) {
+	var (
+		body entity.StocktakeCountRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil || len(body.Counts) == 0 {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	for _, count := range body.Counts {
+		if count.ProductID == "" || count.BinID == "" || count.Quantity < 0 || count.UnitQuantity < 0 {
+			h.ReturnError(ctx, config.ErrorBadRequest, "Each count needs product_id, bin_id and a non-negative quantity", 400)
+			return
+		}
+	}
+
+	body.StocktakeID = ctx.Param("id")
+
+	err = h.UseCase.StocktakeRepo.CreateCounts(ctx, body, ctx.GetHeader("sub"))
+	if h.HandleDbError(ctx, err, "Error saving stocktake counts") {
+		return
+	}
+
+	// counters get the sheet back, it has no expected quantities
+	sheet, err := h.UseCase.StocktakeRepo.GetSheet(ctx, entity.Id{ID: body.StocktakeID})
+	if h.HandleDbError(ctx, err, "Error getting stocktake sheet") {
+		return
+	}
+
+	ctx.JSON(200, sheet)
+}
+
+// GetStocktakeCounts godoc
+// @Router /stocktake/{id}/counts [get]
+// @Summary Get the counts of a stocktake
+// @Description Get every count submitted for a stocktake, newest first
+// @Security BearerAuth
+// @Tags stocktake
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Stocktake ID"
+// @Success 200 {array} entity.StocktakeCount
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetStocktakeCounts(ctx *gin.Context) {
+	var (
+		req entity.Id
+	)
+
+	req.ID = ctx.Param("id")
+
+	counts, err := h.UseCase.StocktakeRepo.GetCounts(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting stocktake counts") {
+		return
+	}
+
+	ctx.JSON(200, counts)
+}
+
+// ApproveStocktake godoc
+// @Router /stocktake/{id}/approve [post]
+// @Summary Approve a stocktake
+// @Description Close an open stocktake and adjust every counted line to its count. The adjustment is taken against the balance at the time of the latest count, so stock moved after the count is kept. Serialized products that are off have to be adjusted by their serial numbers first
+// @Security BearerAuth
+// @Tags stocktake
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Stocktake ID"
+// @Success 200 {object} entity.Stocktake
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) ApproveStocktake(ctx *gin.Context) {
+	stocktake, err := h.UseCase.ApproveStocktake(ctx, ctx.Param("id"), ctx.GetHeader("sub"))
+	if h.HandleDbError(ctx, err, "Error approving stocktake") {
+		return
+	}
+
+	ctx.JSON(200, stocktake)
+}
+
+// CancelStocktake godoc
+// @Router /stocktake/{id}/cancel [post]
+// @Summary Cancel a stocktake
+// @Description Cancel an open stocktake without changing stock
+// @Security BearerAuth
+// @Tags stocktake
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Stocktake ID"
+// @Success 200 {object} entity.Stocktake
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) CancelStocktake(ctx *gin.Context) {
+	stocktake, err := h.UseCase.CancelStocktake(ctx, ctx.Param("id"))
+	if h.HandleDbError(ctx, err, "Error cancelling stocktake") {
+		return
+	}
+
+	ctx.JSON(200, stocktake)
+}
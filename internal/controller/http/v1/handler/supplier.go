@@ -0,0 +1,306 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateSupplier godoc
+// @Router /supplier [post]
+// @Summary Create a new supplier
+// @Description Create a new supplier
+// @Security BearerAuth
+// @Tags supplier
+// @Accept  json
+// @Produce  json
+// @Param supplier body entity.Supplier true "Supplier object"
+// @Success 201 {object} entity.Supplier
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) CreateSupplier(ctx *gin.Context) {
+	var (
+		body entity.Supplier
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if body.Name == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "name is required", 400)
+		return
+	}
+
+	supplier, err := h.UseCase.SupplierRepo.Create(ctx, body)
+	if h.HandleDbError(ctx, err, "Error creating supplier") {
+		return
+	}
+
+	ctx.JSON(201, supplier)
+}
+
+// GetSupplier godoc
+// @Router /supplier/{id} [get]
+// @Summary Get a supplier by ID
+// @Description Get a supplier by ID
+// @Security BearerAuth
+// @Tags supplier
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Supplier ID"
+// @Success 200 {object} entity.Supplier
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetSupplier(ctx *gin.Context) {
+	var (
+		req entity.Id
+	)
+
+	req.ID = ctx.Param("id")
+
+	supplier, err := h.UseCase.SupplierRepo.GetSingle(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting supplier") {
+		return
+	}
+
+	ctx.JSON(200, supplier)
+}
+
+// GetSuppliers godoc
+// @Router /supplier/list [get]
+// @Summary Get a list of suppliers
+// @Description Get a list of suppliers
+// @Security BearerAuth
+// @Tags supplier
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param search query string false "search by name, phone or tax id"
+// @Param is_active query bool false "is_active"
+// @Param format query string false "csv or xlsx, exports the whole filtered list instead of a page"
+// @Param lang query string false "uz or ru, language of the column headers of an export, uz by default"
+// @Success 200 {object} entity.SupplierList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetSuppliers(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+	search := ctx.DefaultQuery("search", "")
+	isActive := ctx.DefaultQuery("is_active", "")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+
+	if search != "" {
+		req.Filters = append(req.Filters,
+			entity.Filter{
+				Column: "name",
+				Type:   "search",
+				Value:  search,
+			},
+			entity.Filter{
+				Column: "phone",
+				Type:   "search",
+				Value:  search,
+			},
+			entity.Filter{
+				Column: "tax_id",
+				Type:   "search",
+				Value:  search,
+			},
+		)
+	}
+
+	if isActive != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "is_active",
+			Type:   "eq",
+			Value:  isActive,
+		})
+	}
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "name",
+		Order:  "asc",
+	})
+
+	if h.export(ctx, "suppliers", entity.Supplier{}, req, func(req entity.GetListFilter) error {
+		_, err := h.UseCase.SupplierRepo.GetList(ctx, req)
+		return err
+	}) {
+		return
+	}
+
+	suppliers, err := h.UseCase.SupplierRepo.GetList(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting suppliers") {
+		return
+	}
+
+	ctx.JSON(200, suppliers)
+}
+
+// UpdateSupplier godoc
+// @Router /supplier [put]
+// @Summary Update a supplier
+// @Description Update a supplier
+// @Security BearerAuth
+// @Tags supplier
+// @Accept  json
+// @Produce  json
+// @Param supplier body entity.Supplier true "Supplier object"
+// @Success 200 {object} entity.Supplier
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) UpdateSupplier(ctx *gin.Context) {
+	var (
+		body entity.Supplier
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if body.ID == "" || body.Name == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "id and name are required", 400)
+		return
+	}
+
+	supplier, err := h.UseCase.SupplierRepo.Update(ctx, body)
+	if h.HandleDbError(ctx, err, "Error updating supplier") {
+		return
+	}
+
+	ctx.JSON(200, supplier)
+}
+
+// DeleteSupplier godoc
+// @Router /supplier/{id} [delete]
+// @Summary Delete a supplier
+// @Description Delete a supplier, suppliers with credits or receipts can't be deleted
+// @Security BearerAuth
+// @Tags supplier
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Supplier ID"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) DeleteSupplier(ctx *gin.Context) {
+	var (
+		req entity.Id
+	)
+
+	req.ID = ctx.Param("id")
+
+	err := h.UseCase.SupplierRepo.Delete(ctx, req)
+	if h.HandleDbError(ctx, err, "Error deleting supplier") {
+		return
+	}
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "Supplier deleted successfully",
+	})
+}
+
+// GetSupplierDebtAging godoc
+// @Router /supplier/aging [get]
+// @Summary Get the supplier debt aging report
+// @Description Bucket the unpaid part of credits per supplier into 0-30, 31-60, 61-90 and 90+ days since the credit date. Debts are aged in the base currency at the rate of their document, then converted to the report currency at the rate of today
+// @Security BearerAuth
+// @Tags supplier
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param supplier_id query string false "supplier_id"
+// @Param currency query string false "currency of the amounts, the base currency by default"
+// @Param format query string false "csv or xlsx, exports the whole filtered list instead of a page"
+// @Param lang query string false "uz or ru, language of the column headers of an export, uz by default"
+// @Success 200 {object} entity.SupplierDebtAgingReport
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetSupplierDebtAging(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+
+	if value := ctx.DefaultQuery("supplier_id", ""); value != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "supplier_id",
+			Type:   "eq",
+			Value:  value,
+		})
+	}
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "total",
+		Order:  "desc",
+	})
+
+	currency, rate, ok := h.reportCurrency(ctx, "")
+	if !ok {
+		return
+	}
+
+	if h.export(ctx, "supplier-debt-aging", entity.SupplierDebtAging{}, req, func(req entity.GetListFilter) error {
+		_, err := h.UseCase.SupplierRepo.GetDebtAging(ctx, exportEach(req, func(item entity.SupplierDebtAging) entity.SupplierDebtAging {
+			agingInCurrency(&item.Days0To30, &item.Days31To60, &item.Days61To90, &item.Days90Plus, &item.Total, rate)
+			return item
+		}))
+		return err
+	}) {
+		return
+	}
+
+	report, err := h.UseCase.SupplierRepo.GetDebtAging(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting supplier debt aging") {
+		return
+	}
+
+	report.Currency = currency
+	for i := range report.Items {
+		agingInCurrency(&report.Items[i].Days0To30, &report.Items[i].Days31To60, &report.Items[i].Days61To90,
+			&report.Items[i].Days90Plus, &report.Items[i].Total, rate)
+	}
+	agingInCurrency(&report.Totals.Days0To30, &report.Totals.Days31To60, &report.Totals.Days61To90,
+		&report.Totals.Days90Plus, &report.Totals.Total, rate)
+
+	ctx.JSON(200, report)
+}
+
+// GetSupplierStatement godoc
+// @Router /supplier/{id}/statement [get]
+// @Summary Get a supplier statement
+// @Description List every credit and payment of the supplier with the debt running after each of them
+// @Security BearerAuth
+// @Tags supplier
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Supplier ID"
+// @Param from query string false "from date, YYYY-MM-DD"
+// @Param to query string false "to date, YYYY-MM-DD"
+// @Success 200 {object} entity.SupplierStatement
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetSupplierStatement(ctx *gin.Context) {
+	from := ctx.DefaultQuery("from", "")
+	to := ctx.DefaultQuery("to", "")
+
+	statement, err := h.UseCase.SupplierRepo.GetStatement(ctx, ctx.Param("id"), from, to)
+	if h.HandleDbError(ctx, err, "Error getting supplier statement") {
+		return
+	}
+
+	ctx.JSON(200, statement)
+}
@@ -0,0 +1,410 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateSupplierCredit godoc
+// @Router /supplier/credit [post]
+// @Summary Create a supplier credit
+// @Description Create a purchase made on credit, the debt is the sum of its lines. Lines may be entered in any unit of their product, quantities are kept in the base unit
+// @Security BearerAuth
+// @Tags supplier
+// @Accept  json
+// @Produce  json
+// @Param credit body entity.SupplierCredit true "Supplier credit object"
+// @Success 201 {object} entity.SupplierCredit
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) CreateSupplierCredit(ctx *gin.Context) {
+	var (
+		body entity.SupplierCredit
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if !h.validSupplierCredit(ctx, body) {
+		return
+	}
+
+	body.CreatedBy = ctx.GetHeader("sub")
+
+	credit, err := h.UseCase.CreateSupplierCredit(ctx, body)
+	if h.HandleDbError(ctx, err, "Error creating supplier credit") {
+		return
+	}
+
+	ctx.JSON(201, credit)
+}
+
+// GetSupplierCredit godoc
+// @Router /supplier/credit/{id} [get]
+// @Summary Get a supplier credit by ID
+// @Description Get a supplier credit with its lines and remaining debt
+// @Security BearerAuth
+// @Tags supplier
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Supplier credit ID"
+// @Success 200 {object} entity.SupplierCredit
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetSupplierCredit(ctx *gin.Context) {
+	var (
+		req entity.Id
+	)
+
+	req.ID = ctx.Param("id")
+
+	credit, err := h.UseCase.SupplierCreditRepo.GetSingle(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting supplier credit") {
+		return
+	}
+
+	ctx.JSON(200, credit)
+}
+
+// GetSupplierCredits godoc
+// @Router /supplier/credit/list [get]
+// @Summary Get a list of supplier credits
+// @Description Get a list of supplier credits without lines
+// @Security BearerAuth
+// @Tags supplier
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param search query string false "number"
+// @Param supplier_id query string false "supplier_id"
+// @Param goods_receipt_id query string false "goods_receipt_id"
+// @Param match_status query string false "none, matched or mismatch"
+// @Param from query string false "credit date from, YYYY-MM-DD"
+// @Param to query string false "credit date to, YYYY-MM-DD"
+// @Param format query string false "csv or xlsx, exports the whole filtered list instead of a page"
+// @Param lang query string false "uz or ru, language of the column headers of an export, uz by default"
+// @Success 200 {object} entity.SupplierCreditList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetSupplierCredits(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+	search := ctx.DefaultQuery("search", "")
+	from := ctx.DefaultQuery("from", "")
+	to := ctx.DefaultQuery("to", "")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+
+	if search != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "number",
+			Type:   "search",
+			Value:  search,
+		})
+	}
+
+	for _, column := range []string{"supplier_id", "goods_receipt_id", "match_status"} {
+		if value := ctx.DefaultQuery(column, ""); value != "" {
+			req.Filters = append(req.Filters, entity.Filter{
+				Column: column,
+				Type:   "eq",
+				Value:  value,
+			})
+		}
+	}
+
+	if from != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "credit_date",
+			Type:   "gte",
+			Value:  from,
+		})
+	}
+
+	if to != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "credit_date",
+			Type:   "lte",
+			Value:  to,
+		})
+	}
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "created_at",
+		Order:  "desc",
+	})
+
+	if h.export(ctx, "supplier-credits", entity.SupplierCredit{}, req, func(req entity.GetListFilter) error {
+		_, err := h.UseCase.SupplierCreditRepo.GetList(ctx, req)
+		return err
+	}) {
+		return
+	}
+
+	credits, err := h.UseCase.SupplierCreditRepo.GetList(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting supplier credits") {
+		return
+	}
+
+	ctx.JSON(200, credits)
+}
+
+// UpdateSupplierCredit godoc
+// @Router /supplier/credit [put]
+// @Summary Update a supplier credit
+// @Description Update the header and replace the lines of a credit that has no payments yet
+// @Security BearerAuth
+// @Tags supplier
+// @Accept  json
+// @Produce  json
+// @Param credit body entity.SupplierCredit true "Supplier credit object"
+// @Success 200 {object} entity.SupplierCredit
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) UpdateSupplierCredit(ctx *gin.Context) {
+	var (
+		body entity.SupplierCredit
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if body.ID == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "id is required", 400)
+		return
+	}
+
+	if !h.validSupplierCredit(ctx, body) {
+		return
+	}
+
+	credit, err := h.UseCase.UpdateSupplierCredit(ctx, body)
+	if h.HandleDbError(ctx, err, "Error updating supplier credit") {
+		return
+	}
+
+	ctx.JSON(200, credit)
+}
+
+// GetSupplierCreditMatch godoc
+// @Router /supplier/credit/{id}/match [get]
+// @Summary Match a supplier credit with its receipt and purchase order
+// @Description Compare the credit per product with the goods receipt it was recorded against and the purchase order of the receipt, flagging quantity and price mismatches
+// @Security BearerAuth
+// @Tags supplier
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Supplier credit ID"
+// @Success 200 {object} entity.ThreeWayMatch
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetSupplierCreditMatch(ctx *gin.Context) {
+	match, err := h.UseCase.GetSupplierCreditMatch(ctx, ctx.Param("id"))
+	if h.HandleDbError(ctx, err, "Error matching supplier credit") {
+		return
+	}
+
+	ctx.JSON(200, match)
+}
+
+// DeleteSupplierCredit godoc
+// @Router /supplier/credit/{id} [delete]
+// @Summary Delete a supplier credit
+// @Description Delete a supplier credit that has no payments yet
+// @Security BearerAuth
+// @Tags supplier
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Supplier credit ID"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) DeleteSupplierCredit(ctx *gin.Context) {
+	var (
+		req entity.Id
+	)
+
+	req.ID = ctx.Param("id")
+
+	err := h.UseCase.SupplierCreditRepo.Delete(ctx, req)
+	if h.HandleDbError(ctx, err, "Error deleting supplier credit") {
+		return
+	}
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "Supplier credit deleted successfully",
+	})
+}
+
+// CreateSupplierPayment godoc
+// @Router /supplier/payment [post]
+// @Summary Pay a supplier credit
+// @Description Record a full or partial payment against a credit through a cash desk kept in the credit currency, paying more than the remaining debt is rejected
+// @Security BearerAuth
+// @Tags supplier
+// @Accept  json
+// @Produce  json
+// @Param payment body entity.SupplierPayment true "Supplier payment object"
+// @Success 201 {object} entity.SupplierPayment
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) CreateSupplierPayment(ctx *gin.Context) {
+	var (
+		body entity.SupplierPayment
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if body.CreditID == "" || body.CashDeskID == "" || body.Amount <= 0 {
+		h.ReturnError(ctx, config.ErrorBadRequest, "credit_id, cash_desk_id and a positive amount are required", 400)
+		return
+	}
+
+	if !h.validPaymentMethod(ctx, body.Method) {
+		return
+	}
+
+	body.CreatedBy = ctx.GetHeader("sub")
+
+	payment, err := h.UseCase.SupplierPaymentRepo.Create(ctx, body)
+	if h.HandleDbError(ctx, err, "Error creating supplier payment") {
+		return
+	}
+
+	ctx.JSON(201, payment)
+}
+
+// GetSupplierPayments godoc
+// @Router /supplier/payment/list [get]
+// @Summary Get a list of supplier payments
+// @Description Get a list of supplier payments, newest first
+// @Security BearerAuth
+// @Tags supplier
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param supplier_id query string false "supplier_id"
+// @Param credit_id query string false "credit_id"
+// @Param cash_desk_id query string false "cash_desk_id"
+// @Param payment_method query string false "cash, card or bank_transfer"
+// @Param from query string false "paid date from, YYYY-MM-DD"
+// @Param to query string false "paid date to, YYYY-MM-DD"
+// @Param format query string false "csv or xlsx, exports the whole filtered list instead of a page"
+// @Param lang query string false "uz or ru, language of the column headers of an export, uz by default"
+// @Success 200 {object} entity.SupplierPaymentList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetSupplierPayments(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+	from := ctx.DefaultQuery("from", "")
+	to := ctx.DefaultQuery("to", "")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+
+	for _, column := range []string{"supplier_id", "credit_id", "cash_desk_id", "payment_method"} {
+		if value := ctx.DefaultQuery(column, ""); value != "" {
+			req.Filters = append(req.Filters, entity.Filter{
+				Column: column,
+				Type:   "eq",
+				Value:  value,
+			})
+		}
+	}
+
+	if from != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "paid_date",
+			Type:   "gte",
+			Value:  from,
+		})
+	}
+
+	if to != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "paid_date",
+			Type:   "lte",
+			Value:  to,
+		})
+	}
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "created_at",
+		Order:  "desc",
+	})
+
+	if h.export(ctx, "supplier-payments", entity.SupplierPayment{}, req, func(req entity.GetListFilter) error {
+		_, err := h.UseCase.SupplierPaymentRepo.GetList(ctx, req)
+		return err
+	}) {
+		return
+	}
+
+	payments, err := h.UseCase.SupplierPaymentRepo.GetList(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting supplier payments") {
+		return
+	}
+
+	ctx.JSON(200, payments)
+}
+
+// DeleteSupplierPayment godoc
+// @Router /supplier/payment/{id} [delete]
+// @Summary Delete a supplier payment
+// @Description Delete a payment recorded by mistake, the debt of its credit grows back
+// @Security BearerAuth
+// @Tags supplier
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Supplier payment ID"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) DeleteSupplierPayment(ctx *gin.Context) {
+	var (
+		req entity.Id
+	)
+
+	req.ID = ctx.Param("id")
+
+	err := h.UseCase.SupplierPaymentRepo.Delete(ctx, req)
+	if h.HandleDbError(ctx, err, "Error deleting supplier payment") {
+		return
+	}
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "Supplier payment deleted successfully",
+	})
+}
+
+func (h *Handler) validSupplierCredit(ctx *gin.Context, body entity.SupplierCredit) bool {
+	if body.SupplierID == "" || len(body.Lines) == 0 {
+		h.ReturnError(ctx, config.ErrorBadRequest, "supplier_id and at least one line are required", 400)
+		return false
+	}
+
+	for _, line := range body.Lines {
+		if line.ProductID == "" || !validQuantity(line.Quantity, line.UnitQuantity) || line.Price < 0 {
+			h.ReturnError(ctx, config.ErrorBadRequest, "Each line needs product_id, a positive quantity or unit_quantity and a non-negative price", 400)
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,314 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateTransfer godoc
+// @Router /transfer [post]
+// @Summary Create a transfer
+// @Description Create a draft transfer between warehouses, stock is not changed until it is dispatched. Lines may be entered in any unit of their product, quantities are kept in the base unit
+// @Security BearerAuth
+// @Tags transfer
+// @Accept  json
+// @Produce  json
+// @Param transfer body entity.Transfer true "Transfer object"
+// @Success 201 {object} entity.Transfer
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) CreateTransfer(ctx *gin.Context) {
+	var (
+		body entity.Transfer
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if !h.validTransfer(ctx, body) {
+		return
+	}
+
+	body.CreatedBy = ctx.GetHeader("sub")
+
+	transfer, err := h.UseCase.TransferRepo.Create(ctx, body)
+	if h.HandleDbError(ctx, err, "Error creating transfer") {
+		return
+	}
+
+	ctx.JSON(201, transfer)
+}
+
+// GetTransfer godoc
+// @Router /transfer/{id} [get]
+// @Summary Get a transfer by ID
+// @Description Get a transfer with its lines and discrepancies
+// @Security BearerAuth
+// @Tags transfer
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Transfer ID"
+// @Success 200 {object} entity.Transfer
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetTransfer(ctx *gin.Context) {
+	var (
+		req entity.Id
+	)
+
+	req.ID = ctx.Param("id")
+
+	transfer, err := h.UseCase.TransferRepo.GetSingle(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting transfer") {
+		return
+	}
+
+	ctx.JSON(200, transfer)
+}
+
+// GetTransfers godoc
+// @Router /transfer/list [get]
+// @Summary Get a list of transfers
+// @Description Get a list of transfers without lines
+// @Security BearerAuth
+// @Tags transfer
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param search query string false "number"
+// @Param status query string false "draft, in_transit, received or cancelled"
+// @Param source_warehouse_id query string false "source_warehouse_id"
+// @Param dest_warehouse_id query string false "dest_warehouse_id"
+// @Param format query string false "csv or xlsx, exports the whole filtered list instead of a page"
+// @Param lang query string false "uz or ru, language of the column headers of an export, uz by default"
+// @Success 200 {object} entity.TransferList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetTransfers(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+	search := ctx.DefaultQuery("search", "")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+
+	if search != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "number",
+			Type:   "search",
+			Value:  search,
+		})
+	}
+
+	for _, column := range []string{"status", "source_warehouse_id", "dest_warehouse_id"} {
+		if value := ctx.DefaultQuery(column, ""); value != "" {
+			req.Filters = append(req.Filters, entity.Filter{
+				Column: column,
+				Type:   "eq",
+				Value:  value,
+			})
+		}
+	}
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "created_at",
+		Order:  "desc",
+	})
+
+	if h.export(ctx, "transfers", entity.Transfer{}, req, func(req entity.GetListFilter) error {
+		_, err := h.UseCase.TransferRepo.GetList(ctx, req)
+		return err
+	}) {
+		return
+	}
+
+	transfers, err := h.UseCase.TransferRepo.GetList(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting transfers") {
+		return
+	}
+
+	ctx.JSON(200, transfers)
+}
+
+// UpdateTransfer godoc
+// @Router /transfer [put]
+// @Summary Update a draft transfer
+// @Description Update the header and replace the lines of a draft transfer
+// @Security BearerAuth
+// @Tags transfer
+// @Accept  json
+// @Produce  json
+// @Param transfer body entity.Transfer true "Transfer object"
+// @Success 200 {object} entity.Transfer
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) UpdateTransfer(ctx *gin.Context) {
+	var (
+		body entity.Transfer
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if body.ID == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "id is required", 400)
+		return
+	}
+
+	if !h.validTransfer(ctx, body) {
+		return
+	}
+
+	transfer, err := h.UseCase.TransferRepo.Update(ctx, body)
+	if h.HandleDbError(ctx, err, "Error updating transfer") {
+		return
+	}
+
+	ctx.JSON(200, transfer)
+}
+
+// DeleteTransfer godoc
+// @Router /transfer/{id} [delete]
+// @Summary Delete a draft transfer
+// @Description Delete a draft transfer, dispatched transfers have to be cancelled
+// @Security BearerAuth
+// @Tags transfer
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Transfer ID"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) DeleteTransfer(ctx *gin.Context) {
+	var (
+		req entity.Id
+	)
+
+	req.ID = ctx.Param("id")
+
+	err := h.UseCase.TransferRepo.Delete(ctx, req)
+	if h.HandleDbError(ctx, err, "Error deleting transfer") {
+		return
+	}
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "Transfer deleted successfully",
+	})
+}
+
+// DispatchTransfer godoc
+// @Router /transfer/{id}/dispatch [post]
+// @Summary Dispatch a draft transfer
+// @Description Take the goods out of the source bins and put them in transit to the destination warehouse
+// @Security BearerAuth
+// @Tags transfer
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Transfer ID"
+// @Success 200 {object} entity.Transfer
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) DispatchTransfer(ctx *gin.Context) {
+	transfer, err := h.UseCase.DispatchTransfer(ctx, ctx.Param("id"), ctx.GetHeader("sub"))
+	if h.HandleDbError(ctx, err, "Error dispatching transfer") {
+		return
+	}
+
+	ctx.JSON(200, transfer)
+}
+
+// ReceiveTransfer godoc
+// @Router /transfer/{id}/receive [post]
+// @Summary Receive a transfer in transit
+// @Description Put the goods into the destination bins. Lines left out of the body are received in full, a different quantity is recorded as a discrepancy
+// @Security BearerAuth
+// @Tags transfer
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Transfer ID"
+// @Param body body entity.TransferReceiveRequest false "Received quantities"
+// @Success 200 {object} entity.Transfer
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) ReceiveTransfer(ctx *gin.Context) {
+	var (
+		body entity.TransferReceiveRequest
+	)
+
+	if ctx.Request.ContentLength != 0 {
+		err := ctx.ShouldBindJSON(&body)
+		if err != nil {
+			h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+			return
+		}
+	}
+
+	serials := make([][]string, 0, len(body.Lines))
+	for _, line := range body.Lines {
+		if line.LineID == "" || line.Quantity < 0 {
+			h.ReturnError(ctx, config.ErrorBadRequest, "Each line needs line_id and a non-negative quantity", 400)
+			return
+		}
+
+		serials = append(serials, line.Serials)
+	}
+
+	if !h.validSerials(ctx, serials...) {
+		return
+	}
+
+	body.TransferID = ctx.Param("id")
+
+	transfer, err := h.UseCase.ReceiveTransfer(ctx, body, ctx.GetHeader("sub"))
+	if h.HandleDbError(ctx, err, "Error receiving transfer") {
+		return
+	}
+
+	ctx.JSON(200, transfer)
+}
+
+// CancelTransfer godoc
+// @Router /transfer/{id}/cancel [post]
+// @Summary Cancel a transfer
+// @Description Cancel a draft transfer, or return the goods of a transfer in transit to their source bins
+// @Security BearerAuth
+// @Tags transfer
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Transfer ID"
+// @Success 200 {object} entity.Transfer
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) CancelTransfer(ctx *gin.Context) {
+	transfer, err := h.UseCase.CancelTransfer(ctx, ctx.Param("id"), ctx.GetHeader("sub"))
+	if h.HandleDbError(ctx, err, "Error cancelling transfer") {
+		return
+	}
+
+	ctx.JSON(200, transfer)
+}
+
+func (h *Handler) validTransfer(ctx *gin.Context, body entity.Transfer) bool {
+	if body.SourceWarehouseID == "" || body.DestWarehouseID == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "source_warehouse_id and dest_warehouse_id are required", 400)
+		return false
+	}
+
+	serials := make([][]string, 0, len(body.Lines))
+	for _, line := range body.Lines {
+		if line.ProductID == "" || line.SourceBinID == "" || line.DestBinID == "" || !validQuantity(line.Quantity, line.UnitQuantity) {
+			h.ReturnError(ctx, config.ErrorBadRequest, "Each line needs product_id, source_bin_id, dest_bin_id and a positive quantity or unit_quantity", 400)
+			return false
+		}
+
+		serials = append(serials, line.Serials)
+	}
+
+	return h.validSerials(ctx, serials...)
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"math"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/gin-gonic/gin"
+)
+
+// validQuantity tells whether a document line has a positive quantity, either
+// in its unit or in the base unit.
+func validQuantity(quantity, unitQuantity float64) bool {
+	return unitQuantity > 0 || (unitQuantity == 0 && quantity > 0)
+}
+
+// validUnits rejects alternate units without a name or a positive factor,
+// units given twice or named like the base unit, and barcodes used twice.
+func (h *Handler) validUnits(ctx *gin.Context, product entity.Product) bool {
+	var (
+		units    = map[string]bool{product.BaseUnit: true}
+		barcodes = map[string]bool{}
+	)
+
+	if product.BaseUnit == "" {
+		units["pcs"] = true
+	}
+
+	for _, barcode := range product.Barcodes {
+		barcodes[barcode] = true
+	}
+
+	for _, unit := range product.Units {
+		if unit.Unit == "" || unit.Factor <= 0 || units[unit.Unit] {
+			h.ReturnError(ctx, config.ErrorBadRequest, "Each unit needs a unique name other than the base unit and a positive factor", 400)
+			return false
+		}
+		units[unit.Unit] = true
+
+		if unit.Barcode == "" {
+			continue
+		}
+
+		if barcodes[unit.Barcode] {
+			h.ReturnError(ctx, config.ErrorBadRequest, "A barcode can only be used once", 400)
+			return false
+		}
+		barcodes[unit.Barcode] = true
+	}
+
+	return true
+}
+
+// reportUnits looks up the unit asked for with the unit query parameter for
+// the products of a report. It returns nil when no unit is asked for, false
+// when the response has already been written.
+func (h *Handler) reportUnits(ctx *gin.Context, productIDs []string) (map[string]entity.ProductUnit, bool) {
+	unit := ctx.DefaultQuery("unit", "")
+	if unit == "" || len(productIDs) == 0 {
+		return nil, true
+	}
+
+	units, err := h.UseCase.ProductRepo.GetReportUnits(ctx, unit, productIDs)
+	if h.HandleDbError(ctx, err, "Error getting report units") {
+		return nil, false
+	}
+
+	return units, true
+}
+
+// exportUnits looks up the unit asked for with the unit query parameter for
+// every product, as an export doesn't know its products before reading them.
+func (h *Handler) exportUnits(ctx *gin.Context) (map[string]entity.ProductUnit, error) {
+	unit := ctx.DefaultQuery("unit", "")
+	if unit == "" {
+		return nil, nil
+	}
+
+	return h.UseCase.ProductRepo.GetReportUnits(ctx, unit, nil)
+}
+
+// inUnit converts a base unit quantity to unit.
+func inUnit(quantity float64, unit entity.ProductUnit) float64 {
+	if unit.Factor <= 0 {
+		return quantity
+	}
+
+	return math.Round(quantity/unit.Factor*1000) / 1000
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+func TestInUnit(t *testing.T) {
+	for _, tc := range []struct {
+		quantity float64
+		unit     entity.ProductUnit
+		want     float64
+	}{
+		{48, entity.ProductUnit{Unit: "box", Factor: 24}, 2},
+		{30, entity.ProductUnit{Unit: "box", Factor: 24}, 1.25},
+		{10, entity.ProductUnit{Unit: "box", Factor: 3}, 3.333},
+		{1, entity.ProductUnit{Unit: "g", Factor: 0.001}, 1000},
+		{-12, entity.ProductUnit{Unit: "box", Factor: 24}, -0.5},
+		{7, entity.ProductUnit{Unit: "pcs"}, 7},
+	} {
+		if got := inUnit(tc.quantity, tc.unit); got != tc.want {
+			t.Errorf("inUnit(%v, %v) = %v, want %v", tc.quantity, tc.unit.Factor, got, tc.want)
+		}
+	}
+}
+
+func TestValidQuantity(t *testing.T) {
+	for _, tc := range []struct {
+		quantity, unitQuantity float64
+		want                   bool
+	}{
+		{5, 0, true},
+		{0, 2, true},
+		{5, 2, true},
+		{0, 0, false},
+		{-5, 0, false},
+		{5, -2, false},
+	} {
+		if got := validQuantity(tc.quantity, tc.unitQuantity); got != tc.want {
+			t.Errorf("validQuantity(%v, %v) = %v, want %v", tc.quantity, tc.unitQuantity, got, tc.want)
+		}
+	}
+}
+
+func TestValidUnits(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := &Handler{Logger: logger.New("error")}
+
+	for _, tc := range []struct {
+		name    string
+		product entity.Product
+		want    bool
+	}{
+		{
+			name: "box and pallet",
+			product: entity.Product{BaseUnit: "pcs", Barcodes: []string{"4780001"}, Units: []entity.ProductUnit{
+				{Unit: "box", Factor: 24, Barcode: "4780024"},
+				{Unit: "pallet", Factor: 960},
+			}},
+			want: true,
+		},
+		{
+			name:    "no name",
+			product: entity.Product{BaseUnit: "pcs", Units: []entity.ProductUnit{{Factor: 24}}},
+		},
+		{
+			name:    "no factor",
+			product: entity.Product{BaseUnit: "pcs", Units: []entity.ProductUnit{{Unit: "box"}}},
+		},
+		{
+			name:    "negative factor",
+			product: entity.Product{BaseUnit: "pcs", Units: []entity.ProductUnit{{Unit: "box", Factor: -24}}},
+		},
+		{
+			name:    "named like the base unit",
+			product: entity.Product{BaseUnit: "kg", Units: []entity.ProductUnit{{Unit: "kg", Factor: 1}}},
+		},
+		{
+			name:    "named like the default base unit",
+			product: entity.Product{Units: []entity.ProductUnit{{Unit: "pcs", Factor: 1}}},
+		},
+		{
+			name: "given twice",
+			product: entity.Product{BaseUnit: "pcs", Units: []entity.ProductUnit{
+				{Unit: "box", Factor: 24},
+				{Unit: "box", Factor: 12},
+			}},
+		},
+		{
+			name: "barcode of the product",
+			product: entity.Product{BaseUnit: "pcs", Barcodes: []string{"4780001"}, Units: []entity.ProductUnit{
+				{Unit: "box", Factor: 24, Barcode: "4780001"},
+			}},
+		},
+		{
+			name: "barcode of another unit",
+			product: entity.Product{BaseUnit: "pcs", Units: []entity.ProductUnit{
+				{Unit: "box", Factor: 24, Barcode: "4780024"},
+				{Unit: "pallet", Factor: 960, Barcode: "4780024"},
+			}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+
+			if got := h.validUnits(ctx, tc.product); got != tc.want {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+
+			if !tc.want && recorder.Code != 400 {
+				t.Errorf("got status %d, want 400", recorder.Code)
+			}
+		})
+	}
+}
+
+func TestBalanceInUnit(t *testing.T) {
+	units := map[string]entity.ProductUnit{"juice": {Unit: "box", Factor: 12}}
+
+	got := balanceInUnit(entity.StockBalance{ProductID: "juice", Quantity: 30, Unit: "pcs"}, units)
+	if got.Quantity != 2.5 || got.Unit != "box" {
+		t.Errorf("got %v %s, want 2.5 box", got.Quantity, got.Unit)
+	}
+
+	// products without the unit stay in their base unit
+	got = balanceInUnit(entity.StockBalance{ProductID: "tea", Quantity: 30, Unit: "kg"}, units)
+	if got.Quantity != 30 || got.Unit != "kg" {
+		t.Errorf("got %v %s, want 30 kg", got.Quantity, got.Unit)
+	}
+}
@@ -84,6 +84,8 @@ func (h *Handler) GetUser(ctx *gin.Context) {
 // @Param page query number true "page"
 // @Param limit query number true "limit"
 // @Param search query string false "search"
+// @Param format query string false "csv or xlsx, exports the whole filtered list instead of a page"
+// @Param lang query string false "uz or ru, language of the column headers of an export, uz by default"
 // @Success 200 {object} entity.UserList
 // @Failure 400 {object} entity.ErrorResponse
 func (h *Handler) GetUsers(ctx *gin.Context) {
@@ -120,6 +122,13 @@ func (h *Handler) GetUsers(ctx *gin.Context) {
 		Order:  "desc",
 	})
 
+	if h.export(ctx, "users", entity.User{}, req, func(req entity.GetListFilter) error {
+		_, err := h.UseCase.UserRepo.GetList(ctx, req)
+		return err
+	}) {
+		return
+	}
+
 	users, err := h.UseCase.UserRepo.GetList(ctx, req)
 	if h.HandleDbError(ctx, err, "Error getting users") {
 		return
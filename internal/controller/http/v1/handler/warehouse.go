@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateWarehouse godoc
+// @Router /warehouse [post]
+// @Summary Create a new warehouse
+// @Description Create a new warehouse
+// @Security BearerAuth
+// @Tags warehouse
+// @Accept  json
+// @Produce  json
+// @Param warehouse body entity.Warehouse true "Warehouse object"
+// @Success 201 {object} entity.Warehouse
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) CreateWarehouse(ctx *gin.Context) {
+	var (
+		body entity.Warehouse
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if body.Code == "" || body.Name == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "code and name are required", 400)
+		return
+	}
+
+	warehouse, err := h.UseCase.WarehouseRepo.Create(ctx, body)
+	if h.HandleDbError(ctx, err, "Error creating warehouse") {
+		return
+	}
+
+	ctx.JSON(201, warehouse)
+}
+
+// GetWarehouse godoc
+// @Router /warehouse/{id} [get]
+// @Summary Get a warehouse by ID
+// @Description Get a warehouse by ID
+// @Security BearerAuth
+// @Tags warehouse
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Warehouse ID"
+// @Success 200 {object} entity.Warehouse
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetWarehouse(ctx *gin.Context) {
+	var (
+		req entity.Id
+	)
+
+	req.ID = ctx.Param("id")
+
+	warehouse, err := h.UseCase.WarehouseRepo.GetSingle(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting warehouse") {
+		return
+	}
+
+	ctx.JSON(200, warehouse)
+}
+
+// GetWarehouses godoc
+// @Router /warehouse/list [get]
+// @Summary Get a list of warehouses
+// @Description Get a list of warehouses
+// @Security BearerAuth
+// @Tags warehouse
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param search query string false "search by code or name"
+// @Param format query string false "csv or xlsx, exports the whole filtered list instead of a page"
+// @Param lang query string false "uz or ru, language of the column headers of an export, uz by default"
+// @Success 200 {object} entity.WarehouseList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetWarehouses(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+	search := ctx.DefaultQuery("search", "")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+
+	if search != "" {
+		req.Filters = append(req.Filters,
+			entity.Filter{
+				Column: "code",
+				Type:   "search",
+				Value:  search,
+			},
+			entity.Filter{
+				Column: "name",
+				Type:   "search",
+				Value:  search,
+			},
+		)
+	}
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "code",
+		Order:  "asc",
+	})
+
+	if h.export(ctx, "warehouses", entity.Warehouse{}, req, func(req entity.GetListFilter) error {
+		_, err := h.UseCase.WarehouseRepo.GetList(ctx, req)
+		return err
+	}) {
+		return
+	}
+
+	warehouses, err := h.UseCase.WarehouseRepo.GetList(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting warehouses") {
+		return
+	}
+
+	ctx.JSON(200, warehouses)
+}
+
+// UpdateWarehouse godoc
+// @Router /warehouse [put]
+// @Summary Update a warehouse
+// @Description Update a warehouse
+// @Security BearerAuth
+// @Tags warehouse
+// @Accept  json
+// @Produce  json
+// @Param warehouse body entity.Warehouse true "Warehouse object"
+// @Success 200 {object} entity.Warehouse
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) UpdateWarehouse(ctx *gin.Context) {
+	var (
+		body entity.Warehouse
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if body.ID == "" || body.Code == "" || body.Name == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "id, code and name are required", 400)
+		return
+	}
+
+	warehouse, err := h.UseCase.WarehouseRepo.Update(ctx, body)
+	if h.HandleDbError(ctx, err, "Error updating warehouse") {
+		return
+	}
+
+	ctx.JSON(200, warehouse)
+}
+
+// DeleteWarehouse godoc
+// @Router /warehouse/{id} [delete]
+// @Summary Delete a warehouse
+// @Description Delete a warehouse, warehouses that still have zones can't be deleted
+// @Security BearerAuth
+// @Tags warehouse
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Warehouse ID"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) DeleteWarehouse(ctx *gin.Context) {
+	var (
+		req entity.Id
+	)
+
+	req.ID = ctx.Param("id")
+
+	err := h.UseCase.WarehouseRepo.Delete(ctx, req)
+	if h.HandleDbError(ctx, err, "Error deleting warehouse") {
+		return
+	}
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "Warehouse deleted successfully",
+	})
+}
@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateZone godoc
+// @Router /warehouse/zone [post]
+// @Summary Create a new zone
+// @Description Create a new zone inside a warehouse. The code TRANSIT is reserved for stock in transit
+// @Security BearerAuth
+// @Tags warehouse
+// @Accept  json
+// @Produce  json
+// @Param zone body entity.Zone true "Zone object"
+// @Success 201 {object} entity.Zone
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) CreateZone(ctx *gin.Context) {
+	var (
+		body entity.Zone
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if body.WarehouseID == "" || body.Code == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "warehouse_id and code are required", 400)
+		return
+	}
+
+	zone, err := h.UseCase.ZoneRepo.Create(ctx, body)
+	if h.HandleDbError(ctx, err, "Error creating zone") {
+		return
+	}
+
+	ctx.JSON(201, zone)
+}
+
+// GetZone godoc
+// @Router /warehouse/zone/{id} [get]
+// @Summary Get a zone by ID
+// @Description Get a zone by ID
+// @Security BearerAuth
+// @Tags warehouse
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Zone ID"
+// @Success 200 {object} entity.Zone
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetZone(ctx *gin.Context) {
+	var (
+		req entity.Id
+	)
+
+	req.ID = ctx.Param("id")
+
+	zone, err := h.UseCase.ZoneRepo.GetSingle(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting zone") {
+		return
+	}
+
+	ctx.JSON(200, zone)
+}
+
+// GetZones godoc
+// @Router /warehouse/zone/list [get]
+// @Summary Get a list of zones
+// @Description Get a list of zones
+// @Security BearerAuth
+// @Tags warehouse
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param warehouse_id query string false "warehouse_id"
+// @Param format query string false "csv or xlsx, exports the whole filtered list instead of a page"
+// @Param lang query string false "uz or ru, language of the column headers of an export, uz by default"
+// @Success 200 {object} entity.ZoneList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetZones(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+	warehouseID := ctx.DefaultQuery("warehouse_id", "")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+
+	if warehouseID != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "warehouse_id",
+			Type:   "eq",
+			Value:  warehouseID,
+		})
+	}
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "code",
+		Order:  "asc",
+	})
+
+	if h.export(ctx, "zones", entity.Zone{}, req, func(req entity.GetListFilter) error {
+		_, err := h.UseCase.ZoneRepo.GetList(ctx, req)
+		return err
+	}) {
+		return
+	}
+
+	zones, err := h.UseCase.ZoneRepo.GetList(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting zones") {
+		return
+	}
+
+	ctx.JSON(200, zones)
+}
+
+// UpdateZone godoc
+// @Router /warehouse/zone [put]
+// @Summary Update a zone
+// @Description Update a zone. The code TRANSIT is reserved for stock in transit
+// @Security BearerAuth
+// @Tags warehouse
+// @Accept  json
+// @Produce  json
+// @Param zone body entity.Zone true "Zone object"
+// @Success 200 {object} entity.Zone
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) UpdateZone(ctx *gin.Context) {
+	var (
+		body entity.Zone
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if body.ID == "" || body.Code == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "id and code are required", 400)
+		return
+	}
+
+	zone, err := h.UseCase.ZoneRepo.Update(ctx, body)
+	if h.HandleDbError(ctx, err, "Error updating zone") {
+		return
+	}
+
+	ctx.JSON(200, zone)
+}
+
+// DeleteZone godoc
+// @Router /warehouse/zone/{id} [delete]
+// @Summary Delete a zone
+// @Description Delete a zone, zones that still have bins can't be deleted
+// @Security BearerAuth
+// @Tags warehouse
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Zone ID"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) DeleteZone(ctx *gin.Context) {
+	var (
+		req entity.Id
+	)
+
+	req.ID = ctx.Param("id")
+
+	err := h.UseCase.ZoneRepo.Delete(ctx, req)
+	if h.HandleDbError(ctx, err, "Error deleting zone") {
+		return
+	}
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "Zone deleted successfully",
+	})
+}
@@ -66,6 +66,278 @@ func NewRouter(engine *gin.Engine, l *logger.Logger, config *config.Config, useC
 		session.DELETE("/:id", handlerV1.DeleteSession)
 	}
 
+	product := v1.Group("/product")
+	{
+		product.POST("/", handlerV1.CreateProduct)
+		product.GET("/list", handlerV1.GetProducts)
+		product.GET("/barcode/:barcode", handlerV1.GetProductByBarcode)
+		product.GET("/:id", handlerV1.GetProduct)
+		product.PUT("/", handlerV1.UpdateProduct)
+		product.PUT("/components", handlerV1.SetProductComponents)
+		product.DELETE("/:id", handlerV1.DeleteProduct)
+	}
+
+	category := v1.Group("/category")
+	{
+		category.POST("/", handlerV1.CreateCategory)
+		category.GET("/list", handlerV1.GetCategories)
+		category.GET("/tree", handlerV1.GetCategoryTree)
+		category.GET("/product-count", handlerV1.GetCategoryProductCounts)
+		category.GET("/:id", handlerV1.GetCategory)
+		category.GET("/:id/tree", handlerV1.GetCategorySubtree)
+		category.PUT("/", handlerV1.UpdateCategory)
+		category.PUT("/move", handlerV1.MoveCategory)
+		category.DELETE("/:id", handlerV1.DeleteCategory)
+	}
+
+	warehouse := v1.Group("/warehouse")
+	{
+		warehouse.POST("/", handlerV1.CreateWarehouse)
+		warehouse.GET("/list", handlerV1.GetWarehouses)
+		warehouse.GET("/:id", handlerV1.GetWarehouse)
+		warehouse.PUT("/", handlerV1.UpdateWarehouse)
+		warehouse.DELETE("/:id", handlerV1.DeleteWarehouse)
+
+		warehouse.POST("/zone", handlerV1.CreateZone)
+		warehouse.GET("/zone/list", handlerV1.GetZones)
+		warehouse.GET("/zone/:id", handlerV1.GetZone)
+		warehouse.PUT("/zone", handlerV1.UpdateZone)
+		warehouse.DELETE("/zone/:id", handlerV1.DeleteZone)
+
+		warehouse.POST("/bin", handlerV1.CreateBin)
+		warehouse.GET("/bin/list", handlerV1.GetBins)
+		warehouse.GET("/bin/:id", handlerV1.GetBin)
+		warehouse.PUT("/bin", handlerV1.UpdateBin)
+		warehouse.DELETE("/bin/:id", handlerV1.DeleteBin)
+	}
+
+	stock := v1.Group("/stock")
+	{
+		stock.GET("/balance", handlerV1.GetStockBalances)
+		stock.GET("/movements", handlerV1.GetStockMovements)
+		stock.POST("/adjustment", handlerV1.CreateStockAdjustment)
+		stock.GET("/lot/list", handlerV1.GetLots)
+		stock.GET("/fefo", handlerV1.GetLotPicks)
+		stock.GET("/expiring", handlerV1.GetExpiringStock)
+		stock.GET("/valuation", handlerV1.GetStockValuation)
+		stock.GET("/cogs", handlerV1.GetCostOfGoodsSold)
+		stock.GET("/availability", handlerV1.GetStockAvailability)
+		stock.GET("/reservation/list", handlerV1.GetStockReservations)
+	}
+
+	goodsReceipt := v1.Group("/goods-receipt")
+	{
+		goodsReceipt.POST("/", handlerV1.CreateGoodsReceipt)
+		goodsReceipt.GET("/list", handlerV1.GetGoodsReceipts)
+		goodsReceipt.GET("/:id", handlerV1.GetGoodsReceipt)
+		goodsReceipt.PUT("/", handlerV1.UpdateGoodsReceipt)
+		goodsReceipt.DELETE("/:id", handlerV1.DeleteGoodsReceipt)
+		goodsReceipt.POST("/:id/post", handlerV1.PostGoodsReceipt)
+		goodsReceipt.POST("/:id/cancel", handlerV1.CancelGoodsReceipt)
+	}
+
+	salesOrder := v1.Group("/sales-order")
+	{
+		salesOrder.POST("/", handlerV1.CreateSalesOrder)
+		salesOrder.GET("/list", handlerV1.GetSalesOrders)
+		salesOrder.GET("/:id", handlerV1.GetSalesOrder)
+		salesOrder.PUT("/", handlerV1.UpdateSalesOrder)
+		salesOrder.PUT("/status", handlerV1.ChangeSalesOrderStatus)
+		salesOrder.DELETE("/:id", handlerV1.DeleteSalesOrder)
+		salesOrder.POST("/:id/return", handlerV1.ReturnSalesOrderLines)
+		salesOrder.POST("/:id/pack", handlerV1.PackSalesOrder)
+		salesOrder.GET("/:id/parcels", handlerV1.GetSalesOrderParcels)
+	}
+
+	supplier := v1.Group("/supplier")
+	{
+		supplier.POST("/", handlerV1.CreateSupplier)
+		supplier.GET("/list", handlerV1.GetSuppliers)
+		supplier.GET("/aging", handlerV1.GetSupplierDebtAging)
+		supplier.GET("/:id", handlerV1.GetSupplier)
+		supplier.GET("/:id/statement", handlerV1.GetSupplierStatement)
+		supplier.PUT("/", handlerV1.UpdateSupplier)
+		supplier.DELETE("/:id", handlerV1.DeleteSupplier)
+
+		supplier.POST("/credit", handlerV1.CreateSupplierCredit)
+		supplier.GET("/credit/list", handlerV1.GetSupplierCredits)
+		supplier.GET("/credit/:id", handlerV1.GetSupplierCredit)
+		supplier.GET("/credit/:id/match", handlerV1.GetSupplierCreditMatch)
+		supplier.PUT("/credit", handlerV1.UpdateSupplierCredit)
+		supplier.DELETE("/credit/:id", handlerV1.DeleteSupplierCredit)
+
+		supplier.POST("/payment", handlerV1.CreateSupplierPayment)
+		supplier.GET("/payment/list", handlerV1.GetSupplierPayments)
+		supplier.DELETE("/payment/:id", handlerV1.DeleteSupplierPayment)
+	}
+
+	customer := v1.Group("/customer")
+	{
+		customer.POST("/", handlerV1.CreateCustomer)
+		customer.GET("/list", handlerV1.GetCustomers)
+		customer.GET("/aging", handlerV1.GetCustomerDebtAging)
+		customer.GET("/:id", handlerV1.GetCustomer)
+		customer.PUT("/", handlerV1.UpdateCustomer)
+		customer.DELETE("/:id", handlerV1.DeleteCustomer)
+
+		customer.POST("/payment", handlerV1.CreateCustomerPayment)
+		customer.GET("/payment/list", handlerV1.GetCustomerPayments)
+		customer.DELETE("/payment/:id", handlerV1.DeleteCustomerPayment)
+	}
+
+	transfer := v1.Group("/transfer")
+	{
+		transfer.POST("/", handlerV1.CreateTransfer)
+		transfer.GET("/list", handlerV1.GetTransfers)
+		transfer.GET("/:id", handlerV1.GetTransfer)
+		transfer.PUT("/", handlerV1.UpdateTransfer)
+		transfer.DELETE("/:id", handlerV1.DeleteTransfer)
+		transfer.POST("/:id/dispatch", handlerV1.DispatchTransfer)
+		transfer.POST("/:id/receive", handlerV1.ReceiveTransfer)
+		transfer.POST("/:id/cancel", handlerV1.CancelTransfer)
+	}
+
+	stocktake := v1.Group("/stocktake")
+	{
+		stocktake.POST("/", handlerV1.CreateStocktake)
+		stocktake.GET("/list", handlerV1.GetStocktakes)
+		stocktake.GET("/:id", handlerV1.GetStocktake)
+		stocktake.GET("/:id/sheet", handlerV1.GetStocktakeSheet)
+		stocktake.POST("/:id/count", handlerV1.CreateStocktakeCounts)
+		stocktake.GET("/:id/counts", handlerV1.GetStocktakeCounts)
+		stocktake.POST("/:id/approve", handlerV1.ApproveStocktake)
+		stocktake.POST("/:id/cancel", handlerV1.CancelStocktake)
+	}
+
+	settings := v1.Group("/settings")
+	{
+		settings.GET("/", handlerV1.GetSettings)
+		settings.PUT("/", handlerV1.UpdateSettings)
+	}
+
+	serial := v1.Group("/serial")
+	{
+		serial.GET("/list", handlerV1.GetSerials)
+		serial.GET("/:sn", handlerV1.GetSerialHistory)
+	}
+
+	replenishment := v1.Group("/replenishment")
+	{
+		replenishment.POST("/level", handlerV1.SetStockLevel)
+		replenishment.GET("/level/list", handlerV1.GetStockLevels)
+		replenishment.DELETE("/level/:warehouse_id/:product_id", handlerV1.DeleteStockLevel)
+		replenishment.GET("/suggestions", handlerV1.GetReplenishmentSuggestions)
+		replenishment.POST("/order", handlerV1.CreateReplenishmentOrders)
+	}
+
+	purchaseOrder := v1.Group("/purchase-order")
+	{
+		purchaseOrder.POST("/", handlerV1.CreatePurchaseOrder)
+		purchaseOrder.GET("/list", handlerV1.GetPurchaseOrders)
+		purchaseOrder.GET("/:id", handlerV1.GetPurchaseOrder)
+		purchaseOrder.PUT("/", handlerV1.UpdatePurchaseOrder)
+		purchaseOrder.DELETE("/:id", handlerV1.DeletePurchaseOrder)
+		purchaseOrder.POST("/:id/send", handlerV1.SendPurchaseOrder)
+		purchaseOrder.POST("/:id/close", handlerV1.ClosePurchaseOrder)
+	}
+
+	pickList := v1.Group("/pick-list")
+	{
+		pickList.POST("/", handlerV1.CreatePickList)
+		pickList.GET("/list", handlerV1.GetPickLists)
+		pickList.GET("/:id", handlerV1.GetPickList)
+		pickList.POST("/:id/scan", handlerV1.ScanPick)
+		pickList.POST("/:id/short", handlerV1.ShortPick)
+		pickList.POST("/:id/complete", handlerV1.CompletePickList)
+		pickList.POST("/:id/cancel", handlerV1.CancelPickList)
+	}
+
+	shipment := v1.Group("/shipment")
+	{
+		shipment.POST("/", handlerV1.CreateShipment)
+		shipment.GET("/list", handlerV1.GetShipments)
+		shipment.GET("/:id", handlerV1.GetShipment)
+	}
+
+	assembly := v1.Group("/assembly")
+	{
+		assembly.POST("/", handlerV1.CreateAssembly)
+		assembly.GET("/list", handlerV1.GetAssemblies)
+		assembly.GET("/:id", handlerV1.GetAssembly)
+		assembly.DELETE("/:id", handlerV1.DeleteAssembly)
+		assembly.POST("/:id/post", handlerV1.PostAssembly)
+	}
+
+	priceList := v1.Group("/price-list")
+	{
+		priceList.POST("/", handlerV1.CreatePriceList)
+		priceList.GET("/list", handlerV1.GetPriceLists)
+		priceList.GET("/:id", handlerV1.GetPriceList)
+		priceList.PUT("/", handlerV1.UpdatePriceList)
+		priceList.DELETE("/:id", handlerV1.DeletePriceList)
+	}
+
+	promotion := v1.Group("/promotion")
+	{
+		promotion.POST("/", handlerV1.CreatePromotion)
+		promotion.GET("/list", handlerV1.GetPromotions)
+		promotion.GET("/:id", handlerV1.GetPromotion)
+		promotion.PUT("/", handlerV1.UpdatePromotion)
+		promotion.DELETE("/:id", handlerV1.DeletePromotion)
+	}
+
+	pricing := v1.Group("/pricing")
+	{
+		pricing.POST("/quote", handlerV1.QuotePrices)
+	}
+
+	currency := v1.Group("/currency")
+	{
+		currency.POST("/", handlerV1.CreateCurrency)
+		currency.GET("/list", handlerV1.GetCurrencies)
+		currency.GET("/:code", handlerV1.GetCurrency)
+		currency.PUT("/", handlerV1.UpdateCurrency)
+		currency.DELETE("/:code", handlerV1.DeleteCurrency)
+		currency.POST("/rate", handlerV1.SetExchangeRates)
+		currency.GET("/rate/list", handlerV1.GetExchangeRates)
+		currency.DELETE("/rate/:code/:date", handlerV1.DeleteExchangeRate)
+		currency.POST("/rate/import", handlerV1.ImportExchangeRates)
+	}
+
+	cashDesk := v1.Group("/cash-desk")
+	{
+		cashDesk.POST("/", handlerV1.CreateCashDesk)
+		cashDesk.GET("/list", handlerV1.GetCashDesks)
+		cashDesk.GET("/:id", handlerV1.GetCashDesk)
+		cashDesk.GET("/:id/ledger", handlerV1.GetCashLedger)
+		cashDesk.PUT("/", handlerV1.UpdateCashDesk)
+		cashDesk.DELETE("/:id", handlerV1.DeleteCashDesk)
+		cashDesk.POST("/operation", handlerV1.CreateCashOperation)
+		cashDesk.GET("/operation/list", handlerV1.GetCashOperations)
+		cashDesk.DELETE("/operation/:id", handlerV1.DeleteCashOperation)
+		cashDesk.POST("/close", handlerV1.CloseCashDay)
+		cashDesk.GET("/close/list", handlerV1.GetCashDayCloses)
+		cashDesk.DELETE("/close/:id", handlerV1.DeleteCashDayClose)
+	}
+
+	document := v1.Group("/document")
+	{
+		document.GET("/:type/:id/pdf", handlerV1.PrintDocument)
+		document.GET("/template/list", handlerV1.GetDocumentTemplates)
+		document.GET("/template/:form", handlerV1.GetDocumentTemplate)
+		document.PUT("/template", handlerV1.UpdateDocumentTemplate)
+	}
+
+	imports := v1.Group("/import")
+	{
+		imports.POST("/", handlerV1.CreateImport)
+		imports.GET("/list", handlerV1.GetImports)
+		imports.GET("/:id", handlerV1.GetImport)
+		imports.GET("/:id/rows", handlerV1.GetImportRows)
+		imports.POST("/:id/run", handlerV1.RunImport)
+		imports.DELETE("/:id", handlerV1.DeleteImport)
+	}
+
 	auth := v1.Group("/auth")
 	{
 		auth.POST("/logout", handlerV1.Logout)
@@ -0,0 +1,44 @@
+package entity
+
+// Assembly document types.
+const (
+	AssemblyTypeAssemble    = "assemble"
+	AssemblyTypeDisassemble = "disassemble"
+)
+
+// Assembly statuses.
+const (
+	AssemblyStatusDraft  = "draft"
+	AssemblyStatusPosted = "posted"
+)
+
+// Assembly turns components into kits in a bin, or breaks kits back down into
+// their components. Its lines are the components of Quantity kits at the time
+// the document is created.
+type Assembly struct {
+	ID          string         `json:"id"`
+	Number      string         `json:"number"`
+	Type        string         `json:"type"` // assemble or disassemble
+	WarehouseID string         `json:"warehouse_id"`
+	BinID       string         `json:"bin_id"` // where kits are built or broken down
+	ProductID   string         `json:"product_id"`
+	Quantity    float64        `json:"quantity"`
+	Status      string         `json:"status"`
+	Note        string         `json:"note"`
+	CreatedBy   string         `json:"created_by"`
+	PostedAt    string         `json:"posted_at"`
+	Lines       []AssemblyLine `json:"lines,omitempty"`
+	CreatedAt   string         `json:"created_at"`
+	UpdatedAt   string         `json:"updated_at"`
+}
+
+type AssemblyLine struct {
+	ID          string  `json:"id"`
+	ComponentID string  `json:"component_id"`
+	Quantity    float64 `json:"quantity"`
+}
+
+type AssemblyList struct {
+	Items []Assembly `json:"assemblies"`
+	Count int        `json:"count"`
+}
@@ -0,0 +1,108 @@
+package entity
+
+// Cash desk types.
+const (
+	CashDeskTypeCash = "cash"
+	CashDeskTypeCard = "card" // card terminal
+	CashDeskTypeBank = "bank" // bank account
+)
+
+// Payment methods.
+const (
+	PaymentMethodCash         = "cash"
+	PaymentMethodCard         = "card"
+	PaymentMethodBankTransfer = "bank_transfer"
+)
+
+// Cash operation types. Over and short are written by the day close.
+const (
+	CashOperationIn    = "in"
+	CashOperationOut   = "out"
+	CashOperationOver  = "over"
+	CashOperationShort = "short"
+)
+
+// Cash ledger entry types.
+const (
+	CashEntryCustomerPayment = "customer_payment"
+	CashEntrySupplierPayment = "supplier_payment"
+)
+
+// CashDesk is a cash register, card terminal or bank account payments are made
+// through. Its balance is kept in its currency, only payments in that currency
+// can go through it.
+type CashDesk struct {
+	ID        string  `json:"id"`
+	Name      string  `json:"name"`
+	Type      string  `json:"type"`     // cash, card or bank
+	Currency  string  `json:"currency"` // base currency when empty
+	IsActive  bool    `json:"is_active"`
+	Note      string  `json:"note"`
+	Balance   float64 `json:"balance"`    // computed from payments and operations
+	LastClose string  `json:"last_close"` // date of the last day close, YYYY-MM-DD
+	CreatedAt string  `json:"created_at"`
+	UpdatedAt string  `json:"updated_at"`
+}
+
+type CashDeskList struct {
+	Items []CashDesk `json:"cash_desks"`
+	Count int        `json:"count"`
+}
+
+// CashOperation puts money into or takes it out of a desk outside of payments.
+type CashOperation struct {
+	ID            string  `json:"id"`
+	CashDeskID    string  `json:"cash_desk_id"`
+	Type          string  `json:"type"`           // in or out, over and short come from day closes
+	Amount        float64 `json:"amount"`         // in the currency of the desk
+	OperationDate string  `json:"operation_date"` // YYYY-MM-DD, today when empty
+	DayCloseID    string  `json:"day_close_id"`   // set on over and short
+	Note          string  `json:"note"`
+	CreatedBy     string  `json:"created_by"`
+	CreatedAt     string  `json:"created_at"`
+}
+
+type CashOperationList struct {
+	Items []CashOperation `json:"operations"`
+	Count int             `json:"count"`
+}
+
+// CashDayClose reconciles the money counted in a desk at the end of a day with
+// the balance computed for it. A difference is written to the desk as over or short.
+type CashDayClose struct {
+	ID         string  `json:"id"`
+	CashDeskID string  `json:"cash_desk_id"`
+	CloseDate  string  `json:"close_date"` // YYYY-MM-DD, today when empty
+	Expected   float64 `json:"expected"`   // computed balance at the end of the day, set by the server
+	Counted    float64 `json:"counted"`
+	Difference float64 `json:"difference"` // counted - expected, over when positive, short when negative
+	Note       string  `json:"note"`
+	ClosedBy   string  `json:"closed_by"`
+	CreatedAt  string  `json:"created_at"`
+}
+
+type CashDayCloseList struct {
+	Items []CashDayClose `json:"closes"`
+	Count int            `json:"count"`
+}
+
+type CashLedgerEntry struct {
+	Date       string  `json:"date"`
+	Type       string  `json:"type"` // customer_payment, supplier_payment or an operation type
+	DocumentID string  `json:"document_id"`
+	Method     string  `json:"method"` // payment method, empty for operations
+	Note       string  `json:"note"`
+	In         float64 `json:"in"`
+	Out        float64 `json:"out"`
+	Balance    float64 `json:"balance"` // running balance after this entry
+}
+
+type CashLedger struct {
+	CashDeskID     string            `json:"cash_desk_id"`
+	Currency       string            `json:"currency"`
+	From           string            `json:"from"`
+	To             string            `json:"to"`
+	OpeningBalance float64           `json:"opening_balance"`
+	ClosingBalance float64           `json:"closing_balance"`
+	Entries        []CashLedgerEntry `json:"entries"`
+}
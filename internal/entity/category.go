@@ -0,0 +1,29 @@
+package entity
+
+type Category struct {
+	ID        string     `json:"id"`
+	ParentID  string     `json:"parent_id"`
+	Name      string     `json:"name"`
+	Depth     int        `json:"depth"`
+	Children  []Category `json:"children,omitempty"`
+	CreatedAt string     `json:"created_at"`
+	UpdatedAt string     `json:"updated_at"`
+}
+
+type CategoryList struct {
+	Items []Category `json:"categories"`
+	Count int        `json:"count"`
+}
+
+type CategoryMoveRequest struct {
+	ID       string `json:"id"`
+	ParentID string `json:"parent_id"` // empty moves the category to the root
+}
+
+type CategoryProductCount struct {
+	CategoryID  string `json:"category_id"`
+	ParentID    string `json:"parent_id"`
+	Name        string `json:"name"`
+	DirectCount int    `json:"direct_count"`
+	TotalCount  int    `json:"total_count"` // including descendants
+}
@@ -0,0 +1,76 @@
+package entity
+
+// Costing methods.
+const (
+	CostingMethodFIFO    = "fifo"
+	CostingMethodAverage = "average" // moving weighted average
+)
+
+// CompanySettings are the company wide settings, there is a single row of them.
+type CompanySettings struct {
+	CostingMethod    string         `json:"costing_method"`
+	ReservationHours int            `json:"reservation_hours"` // sales order reservations lapse after it, 0 never
+	Company          CompanyDetails `json:"company"`           // printed on documents
+	UpdatedAt        string         `json:"updated_at"`
+}
+
+type CompanyDetails struct {
+	Name        string `json:"name"`
+	Address     string `json:"address"`
+	TaxID       string `json:"tax_id"` // INN
+	Phone       string `json:"phone"`
+	BankName    string `json:"bank_name"`
+	BankAccount string `json:"bank_account"`
+	BankCode    string `json:"bank_code"` // MFO
+	Director    string `json:"director"`
+	Accountant  string `json:"accountant"`
+}
+
+// CostLayer is a quantity of a product in a warehouse that is still in stock
+// at a known unit cost.
+type CostLayer struct {
+	ID          string  `json:"id"`
+	ProductID   string  `json:"product_id"`
+	WarehouseID string  `json:"warehouse_id"`
+	MovementID  string  `json:"movement_id"` // inbound movement that opened the layer
+	Quantity    float64 `json:"quantity"`
+	Remaining   float64 `json:"remaining"`
+	UnitCost    float64 `json:"unit_cost"`
+	CreatedAt   string  `json:"created_at"`
+}
+
+// StockValuation is the quantity and value of a product in a warehouse.
+type StockValuation struct {
+	ProductID   string  `json:"product_id"`
+	ProductName string  `json:"product_name"`
+	WarehouseID string  `json:"warehouse_id"`
+	Quantity    float64 `json:"quantity"`
+	Value       float64 `json:"value"`
+	UnitCost    float64 `json:"unit_cost"`      // value / quantity
+	Unit        string  `json:"unit,omitempty"` // unit of the quantities, see StockBalance
+}
+
+type StockValuationReport struct {
+	AsOf       string           `json:"as_of"`
+	Currency   string           `json:"currency"` // of the values, converted at its rate on as_of
+	Items      []StockValuation `json:"valuation"`
+	Count      int              `json:"count"`
+	TotalValue float64          `json:"total_value"`
+}
+
+// CostOfGoodsSold is the cost of what was sold of a product in a period, net of returns.
+type CostOfGoodsSold struct {
+	ProductID   string  `json:"product_id"`
+	ProductName string  `json:"product_name"`
+	Quantity    float64 `json:"quantity"`
+	Cost        float64 `json:"cost"`
+	Unit        string  `json:"unit,omitempty"` // unit of the quantities, see StockBalance
+}
+
+type CostOfGoodsSoldReport struct {
+	From      string            `json:"from"`
+	To        string            `json:"to"`
+	Items     []CostOfGoodsSold `json:"items"`
+	Count     int               `json:"count"`
+	TotalCost float64           `json:"total_cost"`
+}
@@ -0,0 +1,37 @@
+package entity
+
+// Exchange rate sources.
+const (
+	RateSourceManual = "manual"
+	RateSourceCBU    = "cbu" // Central Bank of Uzbekistan
+)
+
+// Currency documents may be kept in. Amounts in the base currency (UZS) need
+// no rate, the stock ledger and costs are always kept in it.
+type Currency struct {
+	Code      string `json:"code"` // ISO 4217, e.g. USD
+	Name      string `json:"name"`
+	IsBase    bool   `json:"is_base"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type CurrencyList struct {
+	Items []Currency `json:"currencies"`
+	Count int        `json:"count"`
+}
+
+// ExchangeRate is how many base currency units one unit of a currency is
+// worth. A rate holds from its date until the next one.
+type ExchangeRate struct {
+	Currency  string  `json:"currency"`
+	RateDate  string  `json:"rate_date"` // YYYY-MM-DD, today when empty
+	Rate      float64 `json:"rate"`
+	Source    string  `json:"source"` // manual or cbu
+	CreatedAt string  `json:"created_at"`
+}
+
+type ExchangeRateList struct {
+	Items []ExchangeRate `json:"rates"`
+	Count int            `json:"count"`
+}
@@ -0,0 +1,60 @@
+package entity
+
+type Customer struct {
+	ID            string  `json:"id"`
+	Name          string  `json:"name"`
+	Phone         string  `json:"phone"`
+	Email         string  `json:"email"`
+	Address       string  `json:"address"`
+	TaxID         string  `json:"tax_id"` // INN
+	Note          string  `json:"note"`
+	IsActive      bool    `json:"is_active"`
+	PriceListID   string  `json:"price_list_id"`   // prices the customer buys at, sale prices when empty
+	PriceListName string  `json:"price_list_name"` // taken from the price list
+	RemainingDebt float64 `json:"remaining_debt"`  // unpaid part of completed orders, in the base currency at their rates
+	CreatedAt     string  `json:"created_at"`
+	UpdatedAt     string  `json:"updated_at"`
+}
+
+type CustomerList struct {
+	Items []Customer `json:"customers"`
+	Count int        `json:"count"`
+}
+
+type CustomerPayment struct {
+	ID           string  `json:"id"`
+	CustomerID   string  `json:"customer_id"` // taken from the order
+	OrderID      string  `json:"order_id"`
+	Amount       float64 `json:"amount"`         // in the currency of the order
+	Currency     string  `json:"currency"`       // taken from the order
+	ExchangeRate float64 `json:"exchange_rate"`  // rate of currency on the paid date, set by the server
+	CashDeskID   string  `json:"cash_desk_id"`   // desk the money goes through, in the same currency
+	Method       string  `json:"payment_method"` // cash, card or bank_transfer, after the desk type when empty
+	PaidDate     string  `json:"paid_date"`      // YYYY-MM-DD, today when empty
+	Note         string  `json:"note"`
+	CreatedBy    string  `json:"created_by"`
+	CreatedAt    string  `json:"created_at"`
+}
+
+type CustomerPaymentList struct {
+	Items []CustomerPayment `json:"payments"`
+	Count int               `json:"count"`
+}
+
+// DebtAging splits the open debt of a customer by how many days ago the orders were completed.
+type DebtAging struct {
+	CustomerID   string  `json:"customer_id"`
+	CustomerName string  `json:"customer_name"`
+	Days0To30    float64 `json:"days_0_30"`
+	Days31To60   float64 `json:"days_31_60"`
+	Days61To90   float64 `json:"days_61_90"`
+	Days90Plus   float64 `json:"days_90_plus"`
+	Total        float64 `json:"total"`
+}
+
+type DebtAgingReport struct {
+	Currency string      `json:"currency"` // of the amounts, converted from the base currency at the rate of today
+	Items    []DebtAging `json:"customers"`
+	Count    int         `json:"count"`
+	Totals   DebtAging   `json:"totals"` // sums over every matching customer, not just this page
+}
@@ -0,0 +1,91 @@
+package entity
+
+// Types of documents that can be printed, as they appear in the URL.
+const (
+	DocumentSalesOrder   = "sales-order"
+	DocumentGoodsReceipt = "goods-receipt"
+	DocumentTransfer     = "transfer"
+)
+
+// Printed forms.
+const (
+	FormInvoice = "invoice" // hisob-faktura, счёт-фактура
+	FormWaybill = "waybill" // yuk xati, накладная
+)
+
+// Languages documents are printed in.
+const (
+	LangUzbek   = "uz"
+	LangRussian = "ru"
+)
+
+// Columns of the line table of a printed document.
+const (
+	ColumnNo       = "no"
+	ColumnSKU      = "sku"
+	ColumnName     = "name"
+	ColumnUnit     = "unit"
+	ColumnQuantity = "quantity"
+	ColumnPrice    = "price"
+	ColumnAmount   = "amount"
+)
+
+// DocumentColumns are the columns a template can choose from, in their default order.
+var DocumentColumns = []string{ColumnNo, ColumnSKU, ColumnName, ColumnUnit, ColumnQuantity, ColumnPrice, ColumnAmount}
+
+// DocumentTemplate is the layout of a printed form. Empty values are replaced
+// with the defaults of the language a document is printed in.
+type DocumentTemplate struct {
+	Form       string   `json:"form"`       // invoice or waybill
+	Title      string   `json:"title"`      // printed before the document number
+	Header     string   `json:"header"`     // printed under the title
+	Footer     string   `json:"footer"`     // printed at the bottom of every page
+	FontSize   float64  `json:"font_size"`  // 6 to 14 points, 9 when 0
+	Columns    []string `json:"columns"`    // line table columns in order, all when empty, transfers leave price and amount out
+	Signatures []string `json:"signatures"` // labels of the signature lines
+	UpdatedAt  string   `json:"updated_at"`
+}
+
+type DocumentTemplateList struct {
+	Items []DocumentTemplate `json:"templates"`
+	Count int                `json:"count"`
+}
+
+type PrintRequest struct {
+	Type       string `json:"type"` // sales-order, goods-receipt or transfer
+	ID         string `json:"id"`
+	Form       string `json:"form"` // invoice for sales orders and goods receipts, waybill for transfers when empty
+	Lang       string `json:"lang"` // uz or ru, uz when empty
+	Regenerate bool   `json:"regenerate"`
+}
+
+// PrintDocument is a document gathered for printing.
+type PrintDocument struct {
+	PrintRequest
+	Number   string
+	Date     string // YYYY-MM-DD
+	Currency string // empty when the document has no prices
+	Parties  []PrintParty
+	Lines    []PrintLine
+	Total    float64
+	Company  CompanyDetails
+	Template DocumentTemplate
+	Version  string // changes with the document, company details and template
+	Object   string // name the printed file is stored under
+}
+
+// PrintParty is a side of a document, e.g. the supplier or the receiver.
+type PrintParty struct {
+	Role    string // supplier, buyer, sender or receiver
+	Name    string
+	Details []string // address, tax id, bank account ...
+}
+
+type PrintLine struct {
+	SKU      string
+	Name     string
+	Unit     string
+	Quantity float64
+	Price    float64 // per unit
+	Amount   float64
+}
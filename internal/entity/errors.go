@@ -0,0 +1,18 @@
+package entity
+
+// Error is a business rule violation whose message can be returned to the client as is.
+type Error struct {
+	Code    string
+	Message string
+}
+
+func NewError(code, message string) *Error {
+	return &Error{
+		Code:    code,
+		Message: message,
+	}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
@@ -0,0 +1,40 @@
+package entity
+
+type GoodsReceipt struct {
+	ID              string             `json:"id"`
+	Number          string             `json:"number"` // generated when empty
+	SupplierID      string             `json:"supplier_id"`
+	PurchaseOrderID string             `json:"purchase_order_id"` // optional, lines may then reference its lines
+	WarehouseID     string             `json:"warehouse_id"`
+	ReceiptDate     string             `json:"receipt_date"` // YYYY-MM-DD, today when empty
+	Status          string             `json:"status"`       // draft, posted, cancelled
+	Note            string             `json:"note"`
+	Currency        string             `json:"currency"`      // base currency when empty, unit costs are in it
+	ExchangeRate    float64            `json:"exchange_rate"` // rate of currency on the receipt date, costs are posted at it
+	TotalAmount     float64            `json:"total_amount"`
+	CreatedBy       string             `json:"created_by"`
+	PostedAt        string             `json:"posted_at"`
+	CancelledAt     string             `json:"cancelled_at"`
+	Lines           []GoodsReceiptLine `json:"lines,omitempty"`
+	CreatedAt       string             `json:"created_at"`
+	UpdatedAt       string             `json:"updated_at"`
+}
+
+type GoodsReceiptLine struct {
+	ID                  string   `json:"id"`
+	ProductID           string   `json:"product_id"`
+	PurchaseOrderLineID string   `json:"purchase_order_line_id"` // line of the receipt's purchase order
+	BinID               string   `json:"bin_id"`                 // target bin, must belong to the receipt warehouse
+	LotNumber           string   `json:"lot_number"`             // the lot is created on posting when it doesn't exist yet
+	ExpiryDate          string   `json:"expiry_date"`
+	Serials             []string `json:"serials"`       // one per unit for serialized products
+	Unit                string   `json:"unit"`          // any unit of the product, the base unit when empty
+	UnitQuantity        float64  `json:"unit_quantity"` // quantity in unit, when given quantity is derived from it
+	Quantity            float64  `json:"quantity"`
+	UnitCost            float64  `json:"unit_cost"`
+}
+
+type GoodsReceiptList struct {
+	Items []GoodsReceipt `json:"goods_receipts"`
+	Count int            `json:"count"`
+}
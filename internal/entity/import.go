@@ -0,0 +1,107 @@
+package entity
+
+// Kinds of imports.
+const (
+	ImportKindProducts        = "products"         // creates products or updates them by SKU
+	ImportKindOpeningBalances = "opening_balances" // posts the stock counted when the warehouse goes live
+)
+
+// Import job statuses.
+const (
+	ImportStatusUploaded  = "uploaded"
+	ImportStatusQueued    = "queued"
+	ImportStatusRunning   = "running"
+	ImportStatusValidated = "validated" // a dry run finished
+	ImportStatusCompleted = "completed"
+	ImportStatusFailed    = "failed" // the job stopped, see its error
+)
+
+// Import row statuses.
+const (
+	ImportRowPending  = "pending"
+	ImportRowValid    = "valid" // passed a dry run
+	ImportRowImported = "imported"
+	ImportRowError    = "error"
+)
+
+// ImportField is a field rows of an import are mapped to.
+type ImportField struct {
+	Name     string `json:"name"`
+	Required bool   `json:"required"` // has to be mapped to a column
+	Note     string `json:"note"`
+}
+
+// ImportFields are the fields of every kind of import.
+var ImportFields = map[string][]ImportField{
+	ImportKindProducts: {
+		{Name: "sku", Required: true, Note: "existing products are updated, only the mapped fields that aren't empty"},
+		{Name: "name", Note: "required for new products"},
+		{Name: "description"},
+		{Name: "barcodes", Note: "separated by ; or ,"},
+		{Name: "category", Note: "category name"},
+		{Name: "base_unit", Note: "pcs when empty on new products"},
+		{Name: "sale_price"},
+		{Name: "is_serialized", Note: "yes/no, true/false or 1/0"},
+		{Name: "is_active", Note: "yes/no, true/false or 1/0, new products are active by default"},
+	},
+	ImportKindOpeningBalances: {
+		{Name: "sku", Required: true},
+		{Name: "warehouse", Required: true, Note: "warehouse code"},
+		{Name: "bin", Required: true, Note: "bin code within the warehouse"},
+		{Name: "quantity", Required: true, Note: "in the base unit of the product"},
+		{Name: "unit_cost", Note: "in the base currency"},
+		{Name: "lot_number"},
+		{Name: "expiry_date", Note: "YYYY-MM-DD, DD.MM.YYYY or an Excel date"},
+		{Name: "serials", Note: "one per unit for serialized products, separated by ; or ,"},
+	},
+}
+
+// ImportJob is an uploaded file and the progress of validating or importing it.
+type ImportJob struct {
+	ID         string            `json:"id"`
+	Kind       string            `json:"kind"` // products or opening_balances
+	FileName   string            `json:"file_name"`
+	Status     string            `json:"status"`
+	Columns    []string          `json:"columns"` // header row of the file
+	Mapping    map[string]string `json:"mapping"` // field -> column, guessed from the headers on upload
+	DryRun     bool              `json:"dry_run"` // the last run only validated the rows
+	TotalRows  int               `json:"total_rows"`
+	Processed  int               `json:"processed"`   // rows done by the current or last run
+	FailedRows int               `json:"failed_rows"` // rows with an error
+	Created    int               `json:"created"`     // products created, or balances posted
+	Updated    int               `json:"updated"`     // products updated
+	Error      string            `json:"error"`       // why the job failed
+	Fields     []ImportField     `json:"fields,omitempty"`
+	CreatedBy  string            `json:"created_by"`
+	StartedAt  string            `json:"started_at"`
+	FinishedAt string            `json:"finished_at"`
+	CreatedAt  string            `json:"created_at"`
+	UpdatedAt  string            `json:"updated_at"`
+}
+
+type ImportJobList struct {
+	Items []ImportJob `json:"imports"`
+	Count int         `json:"count"`
+}
+
+type ImportRow struct {
+	JobID     string   `json:"job_id"`
+	RowNumber int      `json:"row_number"` // line of the file, the header is line 1
+	Cells     []string `json:"cells"`
+	Status    string   `json:"status"`
+	Error     string   `json:"error"`
+	RecordID  string   `json:"record_id"` // product or movement written for the row
+}
+
+type ImportRowList struct {
+	Items []ImportRow `json:"rows"`
+	Count int         `json:"count"`
+}
+
+// ImportRunRequest queues a job. Rows imported by an earlier run are skipped,
+// a dry run writes nothing and reports the errors an import would run into.
+type ImportRunRequest struct {
+	JobID   string            `json:"-"`
+	Mapping map[string]string `json:"mapping"` // field -> column, the mapping of the job when empty
+	DryRun  bool              `json:"dry_run"`
+}
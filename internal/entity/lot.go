@@ -0,0 +1,54 @@
+package entity
+
+// Lot is a batch of a product, usually sharing one expiry date.
+type Lot struct {
+	ID         string `json:"id"`
+	ProductID  string `json:"product_id"`
+	LotNumber  string `json:"lot_number"`
+	ExpiryDate string `json:"expiry_date"` // YYYY-MM-DD, empty when the lot does not expire
+	CreatedAt  string `json:"created_at"`
+}
+
+type LotList struct {
+	Items []Lot `json:"lots"`
+	Count int   `json:"count"`
+}
+
+// LotPick is one step of a first-expired-first-out suggestion.
+type LotPick struct {
+	BinID      string  `json:"bin_id"`
+	BinCode    string  `json:"bin_code"`
+	LotID      string  `json:"lot_id"` // empty for stock kept without a lot
+	LotNumber  string  `json:"lot_number"`
+	ExpiryDate string  `json:"expiry_date"`
+	Available  float64 `json:"available"`
+	Quantity   float64 `json:"quantity"` // how much to take from this bin and lot
+}
+
+type LotPickList struct {
+	Items     []LotPick `json:"picks"`
+	Requested float64   `json:"requested"`
+	Shortage  float64   `json:"shortage"` // requested quantity that no lot can cover
+}
+
+// ExpiringLot is stock of a lot that expires within the report window. Expired
+// lots are included with a negative DaysLeft.
+type ExpiringLot struct {
+	ProductID   string  `json:"product_id"`
+	ProductName string  `json:"product_name"`
+	SKU         string  `json:"sku"`
+	WarehouseID string  `json:"warehouse_id"`
+	BinID       string  `json:"bin_id"`
+	BinCode     string  `json:"bin_code"`
+	LotID       string  `json:"lot_id"`
+	LotNumber   string  `json:"lot_number"`
+	ExpiryDate  string  `json:"expiry_date"`
+	DaysLeft    int     `json:"days_left"`
+	Quantity    float64 `json:"quantity"`
+	Unit        string  `json:"unit,omitempty"` // unit of the quantities, see StockBalance
+}
+
+type ExpiringLotList struct {
+	Items []ExpiringLot `json:"lots"`
+	Count int           `json:"count"`
+}
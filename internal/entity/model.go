@@ -16,12 +16,16 @@ type Filter struct {
 }
 
 type GetListFilter struct {
-	Page    int       `json:"offset"`
-	Limit   int       `json:"limit"`
-	Filters []Filter  `json:"filters"`
-	OrderBy []OrderBy `json:"order_by"`
+	Page    int        `json:"offset"`
+	Limit   int        `json:"limit"`
+	Filters []Filter   `json:"filters"`
+	OrderBy []OrderBy  `json:"order_by"`
+	Export  ExportFunc `json:"-"` // exports get every row of the list here as it is read, no page is returned
 }
 
+// ExportFunc writes a row of an export, item is the list item of the row.
+type ExportFunc func(item interface{}) error
+
 type UpdateFieldItem struct {
 	Column string `json:"column"`
 	Value  string `json:"value"`
@@ -44,3 +48,10 @@ type ErrorResponse struct {
 type SuccessResponse struct {
 	Message string `json:"message"`
 }
+
+// Document statuses shared by stock documents.
+const (
+	DocumentStatusDraft     = "draft"
+	DocumentStatusPosted    = "posted"
+	DocumentStatusCancelled = "cancelled"
+)
@@ -0,0 +1,69 @@
+package entity
+
+// Pick list statuses.
+const (
+	PickListStatusOpen      = "open"
+	PickListStatusCompleted = "completed"
+	PickListStatusCancelled = "cancelled"
+)
+
+// PickList tells the warehouse what to take from which bin for a sales order.
+// Lines are sorted by bin path, zone code first, so that a picker walks the
+// warehouse once.
+type PickList struct {
+	ID          string         `json:"id"`
+	Number      string         `json:"number"`
+	OrderID     string         `json:"order_id"`
+	OrderNumber string         `json:"order_number"`
+	WarehouseID string         `json:"warehouse_id"`
+	Status      string         `json:"status"`
+	CreatedBy   string         `json:"created_by"`
+	CompletedAt string         `json:"completed_at"`
+	Lines       []PickListLine `json:"lines,omitempty"`
+	CreatedAt   string         `json:"created_at"`
+	UpdatedAt   string         `json:"updated_at"`
+}
+
+type PickListLine struct {
+	ID            string   `json:"id"`
+	OrderLineID   string   `json:"order_line_id"`
+	ProductID     string   `json:"product_id"`
+	ProductName   string   `json:"product_name"`
+	SKU           string   `json:"sku"`
+	BinID         string   `json:"bin_id"`
+	BinPath       string   `json:"bin_path"` // zone code / bin code
+	LotID         string   `json:"lot_id"`
+	LotNumber     string   `json:"lot_number"`
+	Serials       []string `json:"serials"` // units to pick, for serialized products
+	Quantity      float64  `json:"quantity"`
+	Picked        float64  `json:"picked"`
+	PickedSerials []string `json:"picked_serials"`
+	Shorted       float64  `json:"shorted"`
+	ShortReason   string   `json:"short_reason"`
+}
+
+type PickListList struct {
+	Items []PickList `json:"pick_lists"`
+	Count int        `json:"count"`
+}
+
+type PickListCreateRequest struct {
+	OrderID string `json:"order_id"`
+}
+
+// PickScanRequest confirms a pick by scanning the product. Barcode may also be
+// the SKU, serialized products are confirmed by their serial number instead.
+type PickScanRequest struct {
+	PickListID string  `json:"-"`
+	Barcode    string  `json:"barcode"`
+	Serial     string  `json:"serial"`
+	BinID      string  `json:"bin_id"`   // the bin the picker stands at, optional
+	Quantity   float64 `json:"quantity"` // in the unit of the barcode, 1 when empty
+}
+
+// PickShortRequest closes a line with less than its quantity picked.
+type PickShortRequest struct {
+	PickListID string `json:"-"`
+	LineID     string `json:"line_id"`
+	Reason     string `json:"reason"`
+}
@@ -0,0 +1,80 @@
+package entity
+
+// Price list types.
+const (
+	PriceListTypeRetail    = "retail"
+	PriceListTypeWholesale = "wholesale"
+	PriceListTypeDealer    = "dealer"
+)
+
+// Promotion types. Percent takes value percent off the price, fixed takes
+// value off the price of each base unit.
+const (
+	PromotionTypePercent = "percent"
+	PromotionTypeFixed   = "fixed"
+)
+
+// PriceList holds the prices customers assigned to it buy at. Outside its
+// dates, when inactive or for products it has no price for, customers buy at
+// the sale price of the product.
+type PriceList struct {
+	ID        string          `json:"id"`
+	Name      string          `json:"name"`
+	Type      string          `json:"type"`       // retail, wholesale or dealer
+	ValidFrom string          `json:"valid_from"` // YYYY-MM-DD, open ended when empty
+	ValidTo   string          `json:"valid_to"`   // YYYY-MM-DD, open ended when empty
+	IsActive  bool            `json:"is_active"`
+	Note      string          `json:"note"`
+	Items     []PriceListItem `json:"items,omitempty"` // replaced with the given list
+	CreatedAt string          `json:"created_at"`
+	UpdatedAt string          `json:"updated_at"`
+}
+
+// PriceListItem is the price of a product from a quantity on. The item with
+// the largest min_quantity not above the line quantity applies.
+type PriceListItem struct {
+	ProductID   string  `json:"product_id"`
+	MinQuantity float64 `json:"min_quantity"` // in the base unit, 0 for the price without a quantity break
+	Price       float64 `json:"price"`        // per base unit
+}
+
+type PriceListList struct {
+	Items []PriceList `json:"price_lists"`
+	Count int         `json:"count"`
+}
+
+// Promotion is a discount on a product or on every product of a category and
+// its subcategories. When several apply to a line the largest one is taken.
+type Promotion struct {
+	ID         string  `json:"id"`
+	Name       string  `json:"name"`
+	Type       string  `json:"type"`        // percent or fixed
+	Value      float64 `json:"value"`       // percent or UZS per base unit
+	ProductID  string  `json:"product_id"`  // either product_id
+	CategoryID string  `json:"category_id"` // or category_id
+	ValidFrom  string  `json:"valid_from"`  // YYYY-MM-DD, open ended when empty
+	ValidTo    string  `json:"valid_to"`    // YYYY-MM-DD, open ended when empty
+	IsActive   bool    `json:"is_active"`
+	CreatedAt  string  `json:"created_at"`
+	UpdatedAt  string  `json:"updated_at"`
+}
+
+type PromotionList struct {
+	Items []Promotion `json:"promotions"`
+	Count int         `json:"count"`
+}
+
+// PriceQuoteRequest asks for the prices order lines would get for a customer
+// on a date, without creating the order.
+type PriceQuoteRequest struct {
+	CustomerID string           `json:"customer_id"` // sale prices apply without one
+	Currency   string           `json:"currency"`    // base currency when empty
+	Date       string           `json:"date"`        // YYYY-MM-DD, today when empty
+	Lines      []SalesOrderLine `json:"lines"`
+}
+
+type PriceQuote struct {
+	Currency    string           `json:"currency"` // prices and total are in it
+	Lines       []SalesOrderLine `json:"lines"`
+	TotalAmount float64          `json:"total_amount"`
+}
@@ -0,0 +1,64 @@
+package entity
+
+// Product kinds. Bundles hold no stock of their own, selling one takes its
+// components out of stock. Kits are assembled from their components ahead
+// and kept in stock like simple products.
+const (
+	ProductKindSimple = "simple"
+	ProductKindBundle = "bundle"
+	ProductKindKit    = "kit"
+)
+
+type Product struct {
+	ID                  string             `json:"id"`
+	SKU                 string             `json:"sku"`
+	Name                string             `json:"name"`
+	Description         string             `json:"description"`
+	Barcodes            []string           `json:"barcodes"` // barcodes of one base unit
+	CategoryID          string             `json:"category_id"`
+	PreferredSupplierID string             `json:"preferred_supplier_id"`  // replenishment suggestions are grouped by it
+	BaseUnit            string             `json:"base_unit"`              // pcs, kg, l, m ... the ledger keeps quantities in it
+	Units               []ProductUnit      `json:"units"`                  // alternate units, replaced with the given list
+	BarcodeUnit         string             `json:"barcode_unit,omitempty"` // unit of the barcode the product was looked up by
+	SalePrice           float64            `json:"sale_price"`             // UZS
+	IsSerialized        bool               `json:"is_serialized"`          // every unit needs a serial number in documents
+	IsActive            bool               `json:"is_active"`
+	Kind                string             `json:"kind"` // simple, bundle or kit, set with the components
+	Components          []ProductComponent `json:"components,omitempty"`
+	CreatedAt           string             `json:"created_at"`
+	UpdatedAt           string             `json:"updated_at"`
+}
+
+type ProductSingleRequest struct {
+	ID      string `json:"id"`
+	SKU     string `json:"sku"`
+	Barcode string `json:"barcode"`
+}
+
+type ProductList struct {
+	Items []Product `json:"products"`
+	Count int       `json:"count"`
+}
+
+// ProductUnit is an alternate unit of a product, such as a box of 24 pcs.
+// Document lines can be entered in any unit of their product, their quantity
+// and price are still kept per base unit.
+type ProductUnit struct {
+	Unit    string  `json:"unit"`
+	Factor  float64 `json:"factor"`  // base units in one of this unit
+	Barcode string  `json:"barcode"` // optional, scanned as one of this unit
+}
+
+// ProductComponent is what one unit of a bundle or kit is made of.
+type ProductComponent struct {
+	ComponentID string  `json:"component_id"`
+	Name        string  `json:"name"`
+	SKU         string  `json:"sku"`
+	Quantity    float64 `json:"quantity"`
+}
+
+type ProductComponentsRequest struct {
+	ProductID  string             `json:"product_id"`
+	Kind       string             `json:"kind"`
+	Components []ProductComponent `json:"components"`
+}
@@ -0,0 +1,46 @@
+package entity
+
+// Purchase order statuses. Partially received and received are set by posting
+// and cancelling the goods receipts of the order.
+const (
+	PurchaseOrderStatusDraft             = "draft"
+	PurchaseOrderStatusSent              = "sent"
+	PurchaseOrderStatusPartiallyReceived = "partially_received"
+	PurchaseOrderStatusReceived          = "received"
+	PurchaseOrderStatusClosed            = "closed"
+)
+
+type PurchaseOrder struct {
+	ID           string              `json:"id"`
+	Number       string              `json:"number"` // generated when empty
+	SupplierID   string              `json:"supplier_id"`
+	WarehouseID  string              `json:"warehouse_id"`
+	OrderDate    string              `json:"order_date"`    // YYYY-MM-DD, today when empty
+	ExpectedDate string              `json:"expected_date"` // YYYY-MM-DD, optional
+	Status       string              `json:"status"`
+	Note         string              `json:"note"`
+	Currency     string              `json:"currency"`      // base currency when empty, prices are in it
+	ExchangeRate float64             `json:"exchange_rate"` // rate of currency on the order date, set by the server
+	TotalAmount  float64             `json:"total_amount"`
+	CreatedBy    string              `json:"created_by"`
+	SentAt       string              `json:"sent_at"`
+	ClosedAt     string              `json:"closed_at"`
+	Lines        []PurchaseOrderLine `json:"lines,omitempty"`
+	CreatedAt    string              `json:"created_at"`
+	UpdatedAt    string              `json:"updated_at"`
+}
+
+type PurchaseOrderLine struct {
+	ID           string  `json:"id"`
+	ProductID    string  `json:"product_id"`
+	Unit         string  `json:"unit"`          // any unit of the product, the base unit when empty
+	UnitQuantity float64 `json:"unit_quantity"` // quantity in unit, when given quantity is derived from it
+	Quantity     float64 `json:"quantity"`
+	Received     float64 `json:"received"` // on posted goods receipts
+	Price        float64 `json:"price"`
+}
+
+type PurchaseOrderList struct {
+	Items []PurchaseOrder `json:"purchase_orders"`
+	Count int             `json:"count"`
+}
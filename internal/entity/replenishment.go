@@ -0,0 +1,67 @@
+package entity
+
+// StockLevel holds the replenishment settings of a product in a warehouse.
+// Stock is reordered up to MaxQuantity once the available quantity falls to
+// ReorderPoint or below.
+type StockLevel struct {
+	ProductID    string  `json:"product_id"`
+	WarehouseID  string  `json:"warehouse_id"`
+	MinQuantity  float64 `json:"min_quantity"`  // safety stock, suggestions below it are flagged
+	MaxQuantity  float64 `json:"max_quantity"`  // quantity to order up to
+	ReorderPoint float64 `json:"reorder_point"` // order when available falls to it
+	UpdatedAt    string  `json:"updated_at"`
+}
+
+type StockLevelList struct {
+	Items []StockLevel `json:"stock_levels"`
+	Count int          `json:"count"`
+}
+
+// ReplenishmentSuggestion is what to order of a product for a warehouse.
+// Available is on hand + in transit + on order - reserved.
+type ReplenishmentSuggestion struct {
+	ProductID    string  `json:"product_id"`
+	SKU          string  `json:"sku"`
+	ProductName  string  `json:"product_name"`
+	WarehouseID  string  `json:"warehouse_id"`
+	OnHand       float64 `json:"on_hand"`    // outside virtual bins
+	InTransit    float64 `json:"in_transit"` // dispatched to the warehouse, not received yet
+	OnOrder      float64 `json:"on_order"`   // not received yet on open purchase orders
+	Reserved     float64 `json:"reserved"`   // by processing sales orders
+	Available    float64 `json:"available"`
+	MinQuantity  float64 `json:"min_quantity"`
+	ReorderPoint float64 `json:"reorder_point"`
+	MaxQuantity  float64 `json:"max_quantity"`
+	BelowMin     bool    `json:"below_min"`
+	Quantity     float64 `json:"quantity"`  // suggested order quantity
+	UnitCost     float64 `json:"unit_cost"` // last known cost
+}
+
+// SupplierSuggestions groups suggestions by the preferred supplier of the
+// products. Products without one are grouped under an empty supplier_id.
+type SupplierSuggestions struct {
+	SupplierID   string                    `json:"supplier_id"`
+	SupplierName string                    `json:"supplier_name"`
+	Items        []ReplenishmentSuggestion `json:"items"`
+	TotalAmount  float64                   `json:"total_amount"` // at unit_cost
+}
+
+type ReplenishmentSuggestionList struct {
+	Suppliers []SupplierSuggestions `json:"suppliers"`
+	Count     int                   `json:"count"` // suggestions in all groups
+}
+
+// ReplenishmentOrderRequest turns the current suggestions into draft purchase
+// orders, one per supplier and warehouse.
+type ReplenishmentOrderRequest struct {
+	WarehouseID  string `json:"warehouse_id"` // every warehouse when empty
+	SupplierID   string `json:"supplier_id"`  // every supplier when empty
+	ExpectedDate string `json:"expected_date"`
+	Note         string `json:"note"`
+	CreatedBy    string `json:"-"`
+}
+
+type ReplenishmentOrderResponse struct {
+	Orders  []PurchaseOrder           `json:"purchase_orders"`
+	Skipped []ReplenishmentSuggestion `json:"skipped"` // products without a preferred supplier
+}
@@ -0,0 +1,37 @@
+package entity
+
+// StockReservation holds stock of a warehouse for a line of a sales order from
+// processing until the order is shipped, cancelled or sent back to pending.
+type StockReservation struct {
+	ID          string  `json:"id"`
+	OrderID     string  `json:"order_id"`
+	LineID      string  `json:"line_id"`
+	ProductID   string  `json:"product_id"`
+	WarehouseID string  `json:"warehouse_id"`
+	Quantity    float64 `json:"quantity"`
+	ExpiresAt   string  `json:"expires_at"` // empty when it doesn't lapse
+	IsExpired   bool    `json:"is_expired"` // lapsed reservations no longer hold stock
+	CreatedAt   string  `json:"created_at"`
+}
+
+type StockReservationList struct {
+	Items []StockReservation `json:"reservations"`
+	Count int                `json:"count"`
+}
+
+// StockAvailability is the available-to-promise quantity of a product in a
+// warehouse: on hand outside virtual bins minus what active reservations hold.
+// Bundles show the whole number of bundles their components make up.
+type StockAvailability struct {
+	ProductID   string  `json:"product_id"`
+	WarehouseID string  `json:"warehouse_id"`
+	OnHand      float64 `json:"on_hand"`
+	Reserved    float64 `json:"reserved"`
+	Available   float64 `json:"available"`
+	Unit        string  `json:"unit,omitempty"` // unit of the quantities, see StockBalance
+}
+
+type StockAvailabilityList struct {
+	Items []StockAvailability `json:"availability"`
+	Count int                 `json:"count"`
+}
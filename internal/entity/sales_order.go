@@ -0,0 +1,76 @@
+package entity
+
+// Sales order statuses. Picking, picked and packed are set by the pick list,
+// packing and shipment of the order.
+const (
+	OrderStatusPending    = "pending"
+	OrderStatusProcessing = "processing"
+	OrderStatusPicking    = "picking"
+	OrderStatusPicked     = "picked"
+	OrderStatusPacked     = "packed"
+	OrderStatusCompleted  = "completed"
+	OrderStatusCancelled  = "cancelled"
+)
+
+type SalesOrder struct {
+	ID             string           `json:"id"`
+	Number         string           `json:"number"` // generated when empty
+	CustomerID     string           `json:"customer_id"`
+	CustomerName   string           `json:"customer_name"` // taken from the customer when empty
+	WarehouseID    string           `json:"warehouse_id"`
+	OrderDate      string           `json:"order_date"` // YYYY-MM-DD, today when empty
+	Status         string           `json:"status"`
+	Note           string           `json:"note"`
+	Currency       string           `json:"currency"`        // base currency when empty, prices are in it
+	ExchangeRate   float64          `json:"exchange_rate"`   // rate of currency on the order date, set by the server
+	TotalAmount    float64          `json:"total_amount"`    // net of returns
+	ReturnedAmount float64          `json:"returned_amount"` // value of returned goods
+	PaidAmount     float64          `json:"paid_amount"`
+	Debt           float64          `json:"debt"` // total_amount - paid_amount
+	CompletedAt    string           `json:"completed_at"`
+	CreatedBy      string           `json:"created_by"`
+	Lines          []SalesOrderLine `json:"lines,omitempty"`
+	CreatedAt      string           `json:"created_at"`
+	UpdatedAt      string           `json:"updated_at"`
+}
+
+type SalesOrderLine struct {
+	ID           string   `json:"id"`
+	ProductID    string   `json:"product_id"`
+	BinID        string   `json:"bin_id"`        // bin the goods are shipped from
+	LotID        string   `json:"lot_id"`        // picked first-expired-first-out on shipping when empty
+	Serials      []string `json:"serials"`       // one per unit for serialized products
+	Unit         string   `json:"unit"`          // any unit of the product, the base unit when empty
+	UnitQuantity float64  `json:"unit_quantity"` // quantity in unit, when given quantity is derived from it
+	Quantity     float64  `json:"quantity"`
+	Shipped      float64  `json:"shipped"`
+	Shorted      float64  `json:"shorted"` // could not be picked, neither shipped nor billed
+	Returned     float64  `json:"returned"`
+	Price        float64  `json:"price"`       // per base unit, resolved by the pricing rules unless entered
+	ListPrice    float64  `json:"list_price"`  // price before promotions, set by the pricing rules
+	PriceRules   []string `json:"price_rules"` // how the price was arrived at, set by the pricing rules
+}
+
+type SalesOrderList struct {
+	Items []SalesOrder `json:"sales_orders"`
+	Count int          `json:"count"`
+}
+
+type SalesOrderStatusRequest struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+type SalesOrderReturnRequest struct {
+	OrderID string                 `json:"-"`
+	Note    string                 `json:"note"`
+	Lines   []SalesOrderReturnLine `json:"lines"`
+}
+
+type SalesOrderReturnLine struct {
+	LineID   string   `json:"line_id"`
+	BinID    string   `json:"bin_id"`  // defaults to the bin the line was shipped from
+	LotID    string   `json:"lot_id"`  // defaults to the lot set on the line
+	Serials  []string `json:"serials"` // units coming back, for serialized products
+	Quantity float64  `json:"quantity"`
+}
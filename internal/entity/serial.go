@@ -0,0 +1,48 @@
+package entity
+
+// Serial statuses.
+const (
+	SerialStatusInStock   = "in_stock"
+	SerialStatusInTransit = "in_transit"
+	SerialStatusSold      = "sold"
+	SerialStatusRemoved   = "removed" // its receipt was cancelled
+	SerialStatusMissing   = "missing" // dispatched but never received
+)
+
+// Serial is a single unit of a serialized product.
+type Serial struct {
+	ID           string `json:"id"`
+	ProductID    string `json:"product_id"`
+	SerialNumber string `json:"serial_number"`
+	Status       string `json:"status"`
+	WarehouseID  string `json:"warehouse_id"` // empty once the unit left stock
+	BinID        string `json:"bin_id"`
+	LotID        string `json:"lot_id"`
+	CreatedAt    string `json:"created_at"`
+	UpdatedAt    string `json:"updated_at"`
+}
+
+type SerialList struct {
+	Items []Serial `json:"serials"`
+	Count int      `json:"count"`
+}
+
+// SerialHistory is the movement trail of one unit, oldest first.
+type SerialHistory struct {
+	Serial    Serial          `json:"serial"`
+	Movements []StockMovement `json:"movements"`
+}
+
+// SerialMove moves units of a product that are in FromStatus to ToStatus and
+// ToBinID. When set, the units also have to be in FromBinID, of LotID, and last
+// moved by FromDocumentID.
+type SerialMove struct {
+	ProductID      string   `json:"product_id"`
+	Serials        []string `json:"serials"`
+	FromStatus     string   `json:"from_status"`
+	FromBinID      string   `json:"from_bin_id"`
+	FromDocumentID string   `json:"from_document_id"`
+	LotID          string   `json:"lot_id"`
+	ToStatus       string   `json:"to_status"`
+	ToBinID        string   `json:"to_bin_id"`
+}
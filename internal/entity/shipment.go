@@ -0,0 +1,44 @@
+package entity
+
+// Parcel is a package a picked sales order is packed into.
+type Parcel struct {
+	ID         string  `json:"id"`
+	OrderID    string  `json:"order_id"`
+	ParcelNo   int     `json:"parcel_no"`
+	Weight     float64 `json:"weight"` // kg
+	Note       string  `json:"note"`
+	ShipmentID string  `json:"shipment_id"`
+	CreatedAt  string  `json:"created_at"`
+}
+
+type ParcelList struct {
+	Items []Parcel `json:"parcels"`
+	Count int      `json:"count"`
+}
+
+type PackRequest struct {
+	OrderID string   `json:"-"`
+	Parcels []Parcel `json:"parcels"`
+}
+
+// Shipment hands the parcels of a packed sales order over to a carrier. Posting
+// it takes the picked goods out of stock.
+type Shipment struct {
+	ID             string   `json:"id"`
+	Number         string   `json:"number"`
+	OrderID        string   `json:"order_id"`
+	OrderNumber    string   `json:"order_number"`
+	Carrier        string   `json:"carrier"`
+	TrackingNumber string   `json:"tracking_number"`
+	Note           string   `json:"note"`
+	TotalWeight    float64  `json:"total_weight"`
+	CreatedBy      string   `json:"created_by"`
+	ShippedAt      string   `json:"shipped_at"`
+	Parcels        []Parcel `json:"parcels,omitempty"`
+	CreatedAt      string   `json:"created_at"`
+}
+
+type ShipmentList struct {
+	Items []Shipment `json:"shipments"`
+	Count int        `json:"count"`
+}
@@ -0,0 +1,74 @@
+package entity
+
+// Stock movement reasons.
+const (
+	MovementReasonAdjustment       = "adjustment"
+	MovementReasonReceipt          = "receipt"
+	MovementReasonReceiptCancel    = "receipt_cancel"
+	MovementReasonSale             = "sale"
+	MovementReasonSaleReturn       = "sale_return"
+	MovementReasonTransferDispatch = "transfer_dispatch"
+	MovementReasonTransferReceive  = "transfer_receive"
+	MovementReasonTransferCancel   = "transfer_cancel"
+	MovementReasonAssembly         = "assembly"
+	MovementReasonDisassembly      = "disassembly"
+	MovementReasonOpeningBalance   = "opening_balance"
+)
+
+// Documents that write to the stock ledger.
+const (
+	DocumentTypeGoodsReceipt = "goods_receipt"
+	DocumentTypeSalesOrder   = "sales_order"
+	DocumentTypeTransfer     = "transfer"
+	DocumentTypeStocktake    = "stocktake"
+	DocumentTypeAssembly     = "assembly"
+	DocumentTypeImport       = "import"
+)
+
+// StockMovement is one row of the append-only stock ledger.
+// Quantity is a signed delta: positive adds stock to the bin, negative takes it out.
+type StockMovement struct {
+	ID           string   `json:"id"`
+	ProductID    string   `json:"product_id"`
+	WarehouseID  string   `json:"warehouse_id"` // taken from the bin
+	BinID        string   `json:"bin_id"`
+	LotID        string   `json:"lot_id"` // empty for products kept without lots
+	Serials      []string `json:"serials,omitempty"`
+	Quantity     float64  `json:"quantity"`
+	UnitCost     float64  `json:"unit_cost"` // set by costing, receipts bring their own
+	Cost         float64  `json:"cost"`      // signed like quantity
+	Reason       string   `json:"reason"`
+	DocumentType string   `json:"document_type"`
+	DocumentID   string   `json:"document_id"`
+	Note         string   `json:"note"`
+	UserID       string   `json:"user_id"`
+	CreatedAt    string   `json:"created_at"`
+}
+
+type StockMovementList struct {
+	Items []StockMovement `json:"movements"`
+	Count int             `json:"count"`
+}
+
+// StockBalance is the on-hand quantity of a product lot in a bin, derived from the ledger.
+type StockBalance struct {
+	ProductID   string  `json:"product_id"`
+	WarehouseID string  `json:"warehouse_id"`
+	BinID       string  `json:"bin_id"`
+	LotID       string  `json:"lot_id"`
+	LotNumber   string  `json:"lot_number"`
+	ExpiryDate  string  `json:"expiry_date"`
+	Quantity    float64 `json:"quantity"`
+	Unit        string  `json:"unit,omitempty"` // set when the report is asked in a unit, the product base unit where it lacks that unit
+	UpdatedAt   string  `json:"updated_at"`
+}
+
+type StockBalanceList struct {
+	Items []StockBalance `json:"balances"`
+	Count int            `json:"count"`
+}
+
+type StockAdjustmentRequest struct {
+	Note  string          `json:"note"`
+	Lines []StockMovement `json:"lines"`
+}
@@ -0,0 +1,93 @@
+package entity
+
+// Stocktake statuses.
+const (
+	StocktakeStatusOpen      = "open"
+	StocktakeStatusApproved  = "approved"
+	StocktakeStatusCancelled = "cancelled"
+)
+
+// Stocktake is a physical count session of a warehouse, optionally narrowed
+// to one zone and/or one category (including its subcategories). Expected
+// quantities are snapshotted when the session is opened.
+type Stocktake struct {
+	ID          string          `json:"id"`
+	Number      string          `json:"number"` // generated when empty
+	WarehouseID string          `json:"warehouse_id"`
+	ZoneID      string          `json:"zone_id"`
+	CategoryID  string          `json:"category_id"`
+	Status      string          `json:"status"`
+	Note        string          `json:"note"`
+	CreatedBy   string          `json:"created_by"`
+	ApprovedBy  string          `json:"approved_by"`
+	ApprovedAt  string          `json:"approved_at"`
+	Lines       []StocktakeLine `json:"lines,omitempty"`
+	CreatedAt   string          `json:"created_at"`
+	UpdatedAt   string          `json:"updated_at"`
+}
+
+// StocktakeLine is the variance of one product lot in one bin. Products found in
+// a bin they were not expected in show up with a zero expected quantity.
+type StocktakeLine struct {
+	ProductID   string  `json:"product_id"`
+	ProductName string  `json:"product_name"`
+	SKU         string  `json:"sku"`
+	BinID       string  `json:"bin_id"`
+	BinCode     string  `json:"bin_code"`
+	LotID       string  `json:"lot_id"`
+	LotNumber   string  `json:"lot_number"`
+	ExpiryDate  string  `json:"expiry_date"`
+	Expected    float64 `json:"expected"`
+	Counted     float64 `json:"counted"`  // the latest count
+	Counts      int     `json:"counts"`   // 0 when the bin was not counted yet
+	Variance    float64 `json:"variance"` // against the expected quantity, approval adjusts against the balance at the count
+}
+
+type StocktakeList struct {
+	Items []Stocktake `json:"stocktakes"`
+	Count int         `json:"count"`
+}
+
+// StocktakeSheet is what counters work with, it never carries expected quantities.
+type StocktakeSheet struct {
+	ID          string               `json:"id"`
+	Number      string               `json:"number"`
+	WarehouseID string               `json:"warehouse_id"`
+	ZoneID      string               `json:"zone_id"`
+	CategoryID  string               `json:"category_id"`
+	Status      string               `json:"status"`
+	Lines       []StocktakeSheetLine `json:"lines"`
+}
+
+type StocktakeSheetLine struct {
+	ProductID   string  `json:"product_id"`
+	ProductName string  `json:"product_name"`
+	SKU         string  `json:"sku"`
+	BinID       string  `json:"bin_id"`
+	BinCode     string  `json:"bin_code"`
+	LotID       string  `json:"lot_id"`
+	LotNumber   string  `json:"lot_number"`
+	ExpiryDate  string  `json:"expiry_date"`
+	Counted     float64 `json:"counted"`
+	Counts      int     `json:"counts"`
+}
+
+// StocktakeCount is one submitted count. A bin may be counted several times,
+// the latest count of a product in a bin is the one that gets posted.
+type StocktakeCount struct {
+	ID           string  `json:"id"`
+	StocktakeID  string  `json:"stocktake_id"`
+	ProductID    string  `json:"product_id"`
+	BinID        string  `json:"bin_id"`
+	LotID        string  `json:"lot_id"`
+	Unit         string  `json:"unit"`          // any unit of the product, the base unit when empty
+	UnitQuantity float64 `json:"unit_quantity"` // quantity in unit, when given quantity is derived from it
+	Quantity     float64 `json:"quantity"`
+	CountedBy    string  `json:"counted_by"`
+	CreatedAt    string  `json:"created_at"`
+}
+
+type StocktakeCountRequest struct {
+	StocktakeID string           `json:"-"`
+	Counts      []StocktakeCount `json:"counts"`
+}
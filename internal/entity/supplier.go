@@ -0,0 +1,151 @@
+package entity
+
+type Supplier struct {
+	ID            string  `json:"id"`
+	Name          string  `json:"name"`
+	ContactPerson string  `json:"contact_person"`
+	Phone         string  `json:"phone"`
+	Email         string  `json:"email"`
+	Address       string  `json:"address"`
+	TaxID         string  `json:"tax_id"` // INN
+	Note          string  `json:"note"`
+	IsActive      bool    `json:"is_active"`
+	RemainingDebt float64 `json:"remaining_debt"` // computed from credits and payments, in the base currency at the credit rates
+	CreatedAt     string  `json:"created_at"`
+	UpdatedAt     string  `json:"updated_at"`
+}
+
+type SupplierList struct {
+	Items []Supplier `json:"suppliers"`
+	Count int        `json:"count"`
+}
+
+// SupplierCredit is a purchase made on credit. Its totals are computed by the server.
+type SupplierCredit struct {
+	ID             string               `json:"id"`
+	Number         string               `json:"number"` // generated when empty
+	SupplierID     string               `json:"supplier_id"`
+	GoodsReceiptID string               `json:"goods_receipt_id"`
+	CreditDate     string               `json:"credit_date"` // YYYY-MM-DD, today when empty
+	DueDate        string               `json:"due_date"`
+	Note           string               `json:"note"`
+	Currency       string               `json:"currency"`      // base currency when empty, prices are in it
+	ExchangeRate   float64              `json:"exchange_rate"` // rate of currency on the credit date, set by the server
+	TotalAmount    float64              `json:"total_amount"`
+	PaidAmount     float64              `json:"paid_amount"`
+	RemainingDebt  float64              `json:"remaining_debt"`
+	MatchStatus    string               `json:"match_status"` // none, matched or mismatch, see ThreeWayMatch
+	CreatedBy      string               `json:"created_by"`
+	Lines          []SupplierCreditLine `json:"lines,omitempty"`
+	CreatedAt      string               `json:"created_at"`
+	UpdatedAt      string               `json:"updated_at"`
+}
+
+type SupplierCreditLine struct {
+	ID           string  `json:"id"`
+	ProductID    string  `json:"product_id"`
+	Unit         string  `json:"unit"`          // any unit of the product, the base unit when empty
+	UnitQuantity float64 `json:"unit_quantity"` // quantity in unit, when given quantity is derived from it
+	Quantity     float64 `json:"quantity"`
+	Price        float64 `json:"price"`
+}
+
+type SupplierCreditList struct {
+	Items []SupplierCredit `json:"credits"`
+	Count int              `json:"count"`
+}
+
+// Outcomes of matching a supplier credit against its goods receipt and the
+// purchase order of the receipt.
+const (
+	MatchStatusNone     = "none" // the credit has no goods receipt
+	MatchStatusMatched  = "matched"
+	MatchStatusMismatch = "mismatch"
+)
+
+// ThreeWayMatch compares, per product, what was ordered, what was received
+// and what the supplier invoiced. Ordered figures are zero when the receipt
+// doesn't reference a purchase order.
+type ThreeWayMatch struct {
+	CreditID        string              `json:"credit_id"`
+	GoodsReceiptID  string              `json:"goods_receipt_id"`
+	PurchaseOrderID string              `json:"purchase_order_id"`
+	Status          string              `json:"status"`
+	Lines           []ThreeWayMatchLine `json:"lines"`
+}
+
+type ThreeWayMatchLine struct {
+	ProductID        string  `json:"product_id"`
+	OrderedQuantity  float64 `json:"ordered_quantity"` // on the referenced purchase order lines
+	OrderPrice       float64 `json:"order_price"`
+	ReceivedQuantity float64 `json:"received_quantity"`
+	ReceiptUnitCost  float64 `json:"receipt_unit_cost"`
+	InvoicedQuantity float64 `json:"invoiced_quantity"`
+	InvoicePrice     float64 `json:"invoice_price"`
+	QuantityMismatch bool    `json:"quantity_mismatch"` // invoiced != received, or received more than ordered
+	PriceMismatch    bool    `json:"price_mismatch"`    // invoice price or receipt cost differs from the order price
+}
+
+type SupplierPayment struct {
+	ID           string  `json:"id"`
+	SupplierID   string  `json:"supplier_id"` // taken from the credit
+	CreditID     string  `json:"credit_id"`
+	Amount       float64 `json:"amount"`         // in the currency of the credit
+	Currency     string  `json:"currency"`       // taken from the credit
+	ExchangeRate float64 `json:"exchange_rate"`  // rate of currency on the paid date, set by the server
+	CashDeskID   string  `json:"cash_desk_id"`   // desk the money goes through, in the same currency
+	Method       string  `json:"payment_method"` // cash, card or bank_transfer, after the desk type when empty
+	PaidDate     string  `json:"paid_date"`      // YYYY-MM-DD, today when empty
+	Note         string  `json:"note"`
+	CreatedBy    string  `json:"created_by"`
+	CreatedAt    string  `json:"created_at"`
+}
+
+type SupplierPaymentList struct {
+	Items []SupplierPayment `json:"payments"`
+	Count int               `json:"count"`
+}
+
+// Supplier statement entry types.
+const (
+	StatementEntryCredit  = "credit"
+	StatementEntryPayment = "payment"
+)
+
+type SupplierStatementEntry struct {
+	Date       string  `json:"date"`
+	Type       string  `json:"type"` // credit or payment
+	DocumentID string  `json:"document_id"`
+	Number     string  `json:"number"` // credit number, for payments the credit paid
+	Note       string  `json:"note"`
+	Debit      float64 `json:"debit"`   // owed to the supplier
+	Credit     float64 `json:"credit"`  // paid to the supplier
+	Balance    float64 `json:"balance"` // running debt after this entry
+}
+
+type SupplierStatement struct {
+	SupplierID     string                   `json:"supplier_id"`
+	From           string                   `json:"from"`
+	To             string                   `json:"to"`
+	OpeningBalance float64                  `json:"opening_balance"`
+	ClosingBalance float64                  `json:"closing_balance"`
+	Entries        []SupplierStatementEntry `json:"entries"`
+}
+
+// SupplierDebtAging splits the open debt to a supplier by how many days ago the credits were taken.
+type SupplierDebtAging struct {
+	SupplierID   string  `json:"supplier_id"`
+	SupplierName string  `json:"supplier_name"`
+	Days0To30    float64 `json:"days_0_30"`
+	Days31To60   float64 `json:"days_31_60"`
+	Days61To90   float64 `json:"days_61_90"`
+	Days90Plus   float64 `json:"days_90_plus"`
+	Total        float64 `json:"total"`
+}
+
+type SupplierDebtAgingReport struct {
+	Currency string              `json:"currency"` // of the amounts, converted from the base currency at the rate of today
+	Items    []SupplierDebtAging `json:"suppliers"`
+	Count    int                 `json:"count"`
+	Totals   SupplierDebtAging   `json:"totals"` // sums over every matching supplier, not just this page
+}
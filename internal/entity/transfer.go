@@ -0,0 +1,75 @@
+package entity
+
+// Transfer statuses.
+const (
+	TransferStatusDraft     = "draft"
+	TransferStatusInTransit = "in_transit"
+	TransferStatusReceived  = "received"
+	TransferStatusCancelled = "cancelled"
+)
+
+// Transfer moves stock between warehouses or bins. Dispatched goods sit in the
+// virtual in-transit bin of the destination warehouse until they are received.
+type Transfer struct {
+	ID                string                `json:"id"`
+	Number            string                `json:"number"` // generated when empty
+	SourceWarehouseID string                `json:"source_warehouse_id"`
+	DestWarehouseID   string                `json:"dest_warehouse_id"`
+	TransitBinID      string                `json:"transit_bin_id"` // set on dispatch
+	Status            string                `json:"status"`
+	Note              string                `json:"note"`
+	CreatedBy         string                `json:"created_by"`
+	DispatchedBy      string                `json:"dispatched_by"`
+	DispatchedAt      string                `json:"dispatched_at"`
+	ReceivedBy        string                `json:"received_by"`
+	ReceivedAt        string                `json:"received_at"`
+	CancelledAt       string                `json:"cancelled_at"`
+	Lines             []TransferLine        `json:"lines,omitempty"`
+	Discrepancies     []TransferDiscrepancy `json:"discrepancies,omitempty"`
+	CreatedAt         string                `json:"created_at"`
+	UpdatedAt         string                `json:"updated_at"`
+}
+
+type TransferLine struct {
+	ID               string   `json:"id"`
+	ProductID        string   `json:"product_id"`
+	SourceBinID      string   `json:"source_bin_id"`
+	DestBinID        string   `json:"dest_bin_id"`
+	LotID            string   `json:"lot_id"`            // lines without a lot are split by lot on dispatch
+	Serials          []string `json:"serials"`           // one per unit for serialized products
+	Unit             string   `json:"unit"`              // any unit of the product, the base unit when empty
+	UnitQuantity     float64  `json:"unit_quantity"`     // quantity in unit, when given quantity is derived from it
+	Quantity         float64  `json:"quantity"`          // dispatched quantity
+	ReceivedQuantity float64  `json:"received_quantity"` // set on receive
+}
+
+type TransferList struct {
+	Items []Transfer `json:"transfers"`
+	Count int        `json:"count"`
+}
+
+// TransferDiscrepancy records a line that arrived with a different quantity than was dispatched.
+type TransferDiscrepancy struct {
+	ID         string  `json:"id"`
+	TransferID string  `json:"transfer_id"`
+	LineID     string  `json:"line_id"`
+	ProductID  string  `json:"product_id"`
+	Dispatched float64 `json:"dispatched"`
+	Received   float64 `json:"received"`
+	Difference float64 `json:"difference"` // received - dispatched
+	Note       string  `json:"note"`
+	CreatedBy  string  `json:"created_by"`
+	CreatedAt  string  `json:"created_at"`
+}
+
+type TransferReceiveRequest struct {
+	TransferID string                `json:"-"`
+	Note       string                `json:"note"`
+	Lines      []TransferReceiveLine `json:"lines"` // lines left out are received in full
+}
+
+type TransferReceiveLine struct {
+	LineID   string   `json:"line_id"`
+	Quantity float64  `json:"quantity"`
+	Serials  []string `json:"serials"` // units that arrived, for serialized products
+}
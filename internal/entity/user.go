@@ -5,14 +5,14 @@ type User struct {
 	FullName    string `json:"full_name"`
 	Username    string `json:"username"`
 	Email       string `json:"email"`
-	Password    string `json:"password"`
+	Password    string `json:"password" export:"-"`
 	UserType    string `json:"user_type"`
 	UserRole    string `json:"user_role"`
 	Status      string `json:"status"`
 	Gender      string `json:"gender"`
 	Bio         string `json:"bio"`
 	AvatarId    string `json:"profile_picture"`
-	AccessToken string `json:"access_token"`
+	AccessToken string `json:"access_token" export:"-"`
 	CreatedAt   string `json:"created_at"`
 	UpdatedAt   string `json:"updated_at"`
 }
@@ -0,0 +1,47 @@
+package entity
+
+type Warehouse struct {
+	ID        string `json:"id"`
+	Code      string `json:"code"`
+	Name      string `json:"name"`
+	Address   string `json:"address"`
+	IsActive  bool   `json:"is_active"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type WarehouseList struct {
+	Items []Warehouse `json:"warehouses"`
+	Count int         `json:"count"`
+}
+
+type Zone struct {
+	ID          string `json:"id"`
+	WarehouseID string `json:"warehouse_id"`
+	Code        string `json:"code"`
+	Name        string `json:"name"`
+	CreatedAt   string `json:"created_at"`
+	UpdatedAt   string `json:"updated_at"`
+}
+
+type ZoneList struct {
+	Items []Zone `json:"zones"`
+	Count int    `json:"count"`
+}
+
+type Bin struct {
+	ID          string `json:"id"`
+	WarehouseID string `json:"warehouse_id"` // taken from the zone
+	ZoneID      string `json:"zone_id"`
+	Code        string `json:"code"` // shelf address, e.g. A-01-03
+	Description string `json:"description"`
+	IsActive    bool   `json:"is_active"`
+	IsVirtual   bool   `json:"is_virtual"` // in-transit location, managed by the server
+	CreatedAt   string `json:"created_at"`
+	UpdatedAt   string `json:"updated_at"`
+}
+
+type BinList struct {
+	Items []Bin `json:"bins"`
+	Count int   `json:"count"`
+}
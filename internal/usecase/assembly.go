@@ -0,0 +1,187 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+)
+
+// CreateAssembly drafts an assembly document for a kit. Its lines are the
+// components of the requested quantity of kits as the kit is made up now.
+func (uc *UseCase) CreateAssembly(ctx context.Context, req entity.Assembly, userID string) (entity.Assembly, error) {
+	product, err := uc.ProductRepo.GetSingle(ctx, entity.ProductSingleRequest{ID: req.ProductID})
+	if err != nil {
+		return entity.Assembly{}, err
+	}
+
+	if product.Kind != entity.ProductKindKit {
+		return entity.Assembly{}, ErrNotKit
+	}
+
+	req.Lines = make([]entity.AssemblyLine, 0, len(product.Components))
+	for _, component := range product.Components {
+		req.Lines = append(req.Lines, entity.AssemblyLine{
+			ComponentID: component.ComponentID,
+			Quantity:    roundTo(req.Quantity*component.Quantity, 3),
+		})
+	}
+
+	req.CreatedBy = userID
+
+	return uc.AssemblyRepo.Create(ctx, req)
+}
+
+// PostAssembly writes a draft assembly document to the ledger. Assembling
+// takes the components out of stock, the document bin first, and puts the
+// kits into the bin at the cost of the components. Disassembling does the
+// opposite, the value of the kits is shared among the components by their
+// last known unit cost.
+func (uc *UseCase) PostAssembly(ctx context.Context, id, userID string) (entity.Assembly, error) {
+	err := uc.Tx.WithTx(ctx, func(ctx context.Context) error {
+		status, err := uc.AssemblyRepo.LockStatus(ctx, entity.Id{ID: id})
+		if err != nil {
+			return err
+		}
+
+		if status != entity.AssemblyStatusDraft {
+			return ErrInvalidStatusTransition
+		}
+
+		assembly, err := uc.AssemblyRepo.GetSingle(ctx, entity.Id{ID: id})
+		if err != nil {
+			return err
+		}
+
+		if len(assembly.Lines) == 0 {
+			return ErrEmptyDocument
+		}
+
+		if assembly.Type == entity.AssemblyTypeAssemble {
+			err = uc.assemble(ctx, assembly, userID)
+		} else {
+			err = uc.disassemble(ctx, assembly, userID)
+		}
+		if err != nil {
+			return err
+		}
+
+		return uc.AssemblyRepo.SetStatus(ctx, entity.Id{ID: id}, entity.AssemblyStatusPosted)
+	})
+	if err != nil {
+		return entity.Assembly{}, err
+	}
+
+	return uc.AssemblyRepo.GetSingle(ctx, entity.Id{ID: id})
+}
+
+func (uc *UseCase) assemble(ctx context.Context, assembly entity.Assembly, userID string) error {
+	needed := map[string]float64{}
+	for _, line := range assembly.Lines {
+		needed[line.ComponentID] += line.Quantity
+	}
+
+	err := uc.lockAvailable(ctx, assembly.WarehouseID, needed)
+	if err != nil {
+		return err
+	}
+
+	var movements []entity.StockMovement
+	for _, line := range assembly.Lines {
+		picked, err := uc.pickWarehouse(ctx, assemblyMovement(assembly, line.ComponentID, -line.Quantity, userID), assembly.WarehouseID)
+		if err != nil {
+			return err
+		}
+
+		movements = append(movements, picked...)
+	}
+
+	movements, err = uc.postMovements(ctx, movements, assembly.WarehouseID)
+	if err != nil {
+		return err
+	}
+
+	var value float64
+	for _, movement := range movements {
+		value -= movement.Cost
+	}
+
+	kit := assemblyMovement(assembly, assembly.ProductID, assembly.Quantity, userID)
+	kit.UnitCost = value / assembly.Quantity
+
+	_, err = uc.postMovements(ctx, []entity.StockMovement{kit}, assembly.WarehouseID)
+	return err
+}
+
+func (uc *UseCase) disassemble(ctx context.Context, assembly entity.Assembly, userID string) error {
+	err := uc.lockAvailable(ctx, assembly.WarehouseID, map[string]float64{assembly.ProductID: assembly.Quantity})
+	if err != nil {
+		return err
+	}
+
+	kits, err := uc.pickLots(ctx, assemblyMovement(assembly, assembly.ProductID, -assembly.Quantity, userID), assembly.WarehouseID)
+	if err != nil {
+		return err
+	}
+
+	kits, err = uc.postMovements(ctx, kits, assembly.WarehouseID)
+	if err != nil {
+		return err
+	}
+
+	var value float64
+	for _, movement := range kits {
+		value -= movement.Cost
+	}
+
+	var (
+		weights = make([]float64, len(assembly.Lines))
+		total   float64
+	)
+
+	for i, line := range assembly.Lines {
+		unitCost, err := uc.CostingRepo.GetLastUnitCost(ctx, line.ComponentID)
+		if err != nil {
+			return err
+		}
+
+		weights[i] = line.Quantity * unitCost
+		total += weights[i]
+	}
+
+	// components never valued share the kits by quantity
+	if total <= 0 {
+		for i, line := range assembly.Lines {
+			weights[i] = line.Quantity
+			total += line.Quantity
+		}
+	}
+
+	movements := make([]entity.StockMovement, 0, len(assembly.Lines))
+	for i, line := range assembly.Lines {
+		movement := assemblyMovement(assembly, line.ComponentID, line.Quantity, userID)
+		movement.UnitCost = value * weights[i] / total / line.Quantity
+
+		movements = append(movements, movement)
+	}
+
+	_, err = uc.postMovements(ctx, movements, assembly.WarehouseID)
+	return err
+}
+
+func assemblyMovement(assembly entity.Assembly, productID string, quantity float64, userID string) entity.StockMovement {
+	reason := entity.MovementReasonAssembly
+	if assembly.Type == entity.AssemblyTypeDisassemble {
+		reason = entity.MovementReasonDisassembly
+	}
+
+	return entity.StockMovement{
+		ProductID:    productID,
+		BinID:        assembly.BinID,
+		Quantity:     quantity,
+		Reason:       reason,
+		DocumentType: entity.DocumentTypeAssembly,
+		DocumentID:   assembly.ID,
+		Note:         assembly.Number,
+		UserID:       userID,
+	}
+}
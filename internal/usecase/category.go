@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+)
+
+// CategoryTree returns the category with the given id nested with all of its
+// descendants, or the whole category forest when rootID is empty.
+func (uc *UseCase) CategoryTree(ctx context.Context, rootID string) ([]entity.Category, error) {
+	items, err := uc.CategoryRepo.GetSubtree(ctx, rootID)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildCategoryTree(items), nil
+}
+
+func buildCategoryTree(items []entity.Category) []entity.Category {
+	var (
+		known    = make(map[string]bool, len(items))
+		children = make(map[string][]entity.Category)
+		roots    []entity.Category
+	)
+
+	for _, item := range items {
+		known[item.ID] = true
+	}
+
+	for _, item := range items {
+		if known[item.ParentID] {
+			children[item.ParentID] = append(children[item.ParentID], item)
+		} else {
+			roots = append(roots, item)
+		}
+	}
+
+	var attach func(node entity.Category) entity.Category
+	attach = func(node entity.Category) entity.Category {
+		for _, child := range children[node.ID] {
+			node.Children = append(node.Children, attach(child))
+		}
+		return node
+	}
+
+	for i := range roots {
+		roots[i] = attach(roots[i])
+	}
+
+	return roots
+}
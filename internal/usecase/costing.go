@@ -0,0 +1,411 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+)
+
+// reversedReasons maps the reasons of movements that undo inbound movements
+// with a cost of their own to the reason of the movements they undo.
+var reversedReasons = map[string]string{
+	entity.MovementReasonReceiptCancel: entity.MovementReasonReceipt,
+}
+
+// costKey is what cost layers are kept per.
+type costKey struct {
+	productID   string
+	warehouseID string
+}
+
+// costMovements values posted movements with the company costing method and
+// keeps the cost layers in step with the ledger. Receipts and what assembly
+// documents build bring their own unit cost, outbound movements are valued by
+// the layers they consume, and other inbound movements take the cost their
+// document took out of stock (transfers, returns) or else the current cost of
+// the product in the warehouse. Movements undoing a receipt take its stock back
+// out of the layers it opened, at its cost.
+//
+// Movements of a product in one warehouse are netted first, so that moving
+// stock between bins of a warehouse doesn't reorder its FIFO layers.
+func (uc *UseCase) costMovements(ctx context.Context, movements []entity.StockMovement) ([]entity.StockMovement, error) {
+	settings, err := uc.SettingsRepo.GetSingle(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		keys   []costKey
+		groups = map[costKey][]int{}
+		net    = map[costKey]float64{}
+	)
+
+	for i, movement := range movements {
+		key := costKey{productID: movement.ProductID, warehouseID: movement.WarehouseID}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+
+		groups[key] = append(groups[key], i)
+		net[key] += movement.Quantity
+	}
+
+	// outbound groups go first, inbound ones of the same document take their cost
+	sort.SliceStable(keys, func(a, b int) bool {
+		if (net[keys[a]] < 0) != (net[keys[b]] < 0) {
+			return net[keys[a]] < 0
+		}
+		if keys[a].productID != keys[b].productID {
+			return keys[a].productID < keys[b].productID
+		}
+		return keys[a].warehouseID < keys[b].warehouseID
+	})
+
+	for _, key := range keys {
+		err = uc.costGroup(ctx, settings.CostingMethod, movements, groups[key])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return movements, nil
+}
+
+// costGroup values the movements at idx, which share a product and a warehouse.
+func (uc *UseCase) costGroup(ctx context.Context, method string, movements []entity.StockMovement, idx []int) error {
+	first := movements[idx[0]]
+
+	layers, err := uc.CostingRepo.LockLayers(ctx, first.ProductID, first.WarehouseID)
+	if err != nil {
+		return err
+	}
+
+	if method == entity.CostingMethodAverage {
+		layers, err = uc.mergeLayers(ctx, layers)
+		if err != nil {
+			return err
+		}
+	}
+
+	var (
+		net       float64
+		others    []int
+		reversals []int
+	)
+
+	for _, i := range idx {
+		movement := &movements[i]
+		if reversesOwnCost(*movement) {
+			reversals = append(reversals, i)
+			continue
+		}
+
+		if !bringsOwnCost(*movement) {
+			net += movement.Quantity
+			others = append(others, i)
+			continue
+		}
+
+		layers, err = uc.addLayer(ctx, method, layers, *movement, movement.Quantity, movement.UnitCost)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(reversals) > 0 {
+		err = uc.reverseLayers(ctx, method, layers, movements, reversals)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(others) > 0 {
+		var unitCost float64
+
+		switch {
+		case net < 0:
+			var value float64
+			value, err = uc.consumeLayers(ctx, layers, first.ProductID, -net)
+			unitCost = value / -net
+		case net > 0:
+			unitCost, err = uc.inboundUnitCost(ctx, movements[others[0]], layers)
+			if err == nil {
+				_, err = uc.addLayer(ctx, method, layers, movements[others[0]], net, unitCost)
+			}
+		default:
+			unitCost, err = uc.currentUnitCost(ctx, first.ProductID, layers)
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, i := range others {
+			movements[i].UnitCost = unitCost
+		}
+	}
+
+	for _, i := range idx {
+		movement := &movements[i]
+		movement.UnitCost = roundTo(movement.UnitCost, 4)
+		movement.Cost = roundTo(movement.Quantity*movement.UnitCost, 2)
+
+		err = uc.CostingRepo.SetMovementCost(ctx, movement.ID, movement.UnitCost, movement.Cost)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addLayer takes quantity into stock at unitCost. FIFO opens a new layer, the
+// weighted average folds it into the single open layer.
+func (uc *UseCase) addLayer(ctx context.Context, method string, layers []entity.CostLayer, movement entity.StockMovement, quantity, unitCost float64) ([]entity.CostLayer, error) {
+	if method == entity.CostingMethodAverage && len(layers) > 0 {
+		layer := &layers[0]
+		layer.UnitCost = roundTo((layer.Remaining*layer.UnitCost+quantity*unitCost)/(layer.Remaining+quantity), 4)
+		layer.Remaining += quantity
+
+		return layers, uc.CostingRepo.UpdateLayer(ctx, *layer)
+	}
+
+	layer, err := uc.CostingRepo.CreateLayer(ctx, entity.CostLayer{
+		ProductID:   movement.ProductID,
+		WarehouseID: movement.WarehouseID,
+		MovementID:  movement.ID,
+		Quantity:    quantity,
+		Remaining:   quantity,
+		UnitCost:    unitCost,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return append(layers, layer), nil
+}
+
+// consumeLayers takes quantity out of the layers, oldest first, and returns its
+// value. Stock that has no layer, e.g. from before costing, is valued at the
+// last known cost.
+func (uc *UseCase) consumeLayers(ctx context.Context, layers []entity.CostLayer, productID string, quantity float64) (float64, error) {
+	var value float64
+
+	for i := range layers {
+		if quantity <= 0 {
+			break
+		}
+
+		layer := &layers[i]
+		if layer.Remaining <= 0 {
+			continue
+		}
+
+		taken := math.Min(layer.Remaining, quantity)
+		layer.Remaining -= taken
+		quantity -= taken
+		value += taken * layer.UnitCost
+
+		err := uc.CostingRepo.UpdateLayer(ctx, *layer)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if quantity > 0 {
+		unitCost, err := uc.CostingRepo.GetLastUnitCost(ctx, productID)
+		if err != nil {
+			return 0, err
+		}
+
+		value += quantity * unitCost
+	}
+
+	return value, nil
+}
+
+// reverseLayers takes the stock of the movements at idx, which undo inbound
+// movements of their document, back out of the layers those movements opened
+// at their cost. Other layers are left alone, only stock from before costing
+// is taken out of the layers opened for it then. With the weighted average
+// the cost of the undone stock is taken back out of the average.
+func (uc *UseCase) reverseLayers(ctx context.Context, method string, layers []entity.CostLayer, movements []entity.StockMovement, idx []int) error {
+	var (
+		first    = movements[idx[0]]
+		quantity float64
+	)
+
+	for _, i := range idx {
+		quantity -= movements[i].Quantity
+	}
+
+	inbound, err := uc.CostingRepo.GetDocumentInbound(ctx, first.DocumentID, first.ProductID, first.WarehouseID,
+		reversedReasons[first.Reason])
+	if err != nil {
+		return err
+	}
+
+	var (
+		opened            = map[string]bool{}
+		inQuantity, value float64
+	)
+
+	for _, movement := range inbound {
+		opened[movement.ID] = true
+		inQuantity += movement.Quantity
+		value += movement.Cost
+	}
+
+	var unitCost float64
+	if inQuantity > 0 {
+		unitCost = value / inQuantity
+	} else {
+		unitCost, err = uc.currentUnitCost(ctx, first.ProductID, layers)
+		if err != nil {
+			return err
+		}
+	}
+
+	if method == entity.CostingMethodAverage {
+		value = quantity * unitCost
+
+		if len(layers) > 0 {
+			layer := &layers[0]
+			taken := math.Min(layer.Remaining, quantity)
+			left := layer.Remaining*layer.UnitCost - taken*unitCost
+
+			layer.Remaining -= taken
+			if layer.Remaining > 0 {
+				layer.UnitCost = roundTo(math.Max(left, 0)/layer.Remaining, 4)
+			}
+
+			err = uc.CostingRepo.UpdateLayer(ctx, *layer)
+			if err != nil {
+				return err
+			}
+		}
+	} else {
+		value = 0
+		left := quantity
+
+		// the layers of the movements first, then those opened for stock from
+		// before costing
+		for _, takes := range []func(layer entity.CostLayer) bool{
+			func(layer entity.CostLayer) bool { return opened[layer.MovementID] },
+			func(layer entity.CostLayer) bool { return layer.MovementID == "" },
+		} {
+			for i := range layers {
+				layer := &layers[i]
+				if left <= 0 || layer.Remaining <= 0 || !takes(*layer) {
+					continue
+				}
+
+				taken := math.Min(layer.Remaining, left)
+				layer.Remaining -= taken
+				left -= taken
+				value += taken * layer.UnitCost
+
+				err = uc.CostingRepo.UpdateLayer(ctx, *layer)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		value += left * unitCost
+	}
+
+	for _, i := range idx {
+		movements[i].UnitCost = value / quantity
+	}
+
+	return nil
+}
+
+// mergeLayers folds the open layers into the oldest one at their weighted
+// average cost. Only needed once after switching from FIFO.
+func (uc *UseCase) mergeLayers(ctx context.Context, layers []entity.CostLayer) ([]entity.CostLayer, error) {
+	if len(layers) <= 1 {
+		return layers, nil
+	}
+
+	var quantity, value float64
+	for i := range layers {
+		quantity += layers[i].Remaining
+		value += layers[i].Remaining * layers[i].UnitCost
+
+		if i > 0 {
+			layers[i].Remaining = 0
+
+			err := uc.CostingRepo.UpdateLayer(ctx, layers[i])
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	layers[0].Remaining = quantity
+	layers[0].UnitCost = roundTo(value/quantity, 4)
+
+	return layers[:1], uc.CostingRepo.UpdateLayer(ctx, layers[0])
+}
+
+// inboundUnitCost is the unit cost of stock coming in without a cost of its
+// own: what its document took out of stock, otherwise the current cost.
+func (uc *UseCase) inboundUnitCost(ctx context.Context, movement entity.StockMovement, layers []entity.CostLayer) (float64, error) {
+	if movement.DocumentID != "" {
+		quantity, cost, err := uc.CostingRepo.GetDocumentOutbound(ctx, movement.DocumentID, movement.ProductID)
+		if err != nil {
+			return 0, err
+		}
+
+		if quantity > 0 {
+			return cost / quantity, nil
+		}
+	}
+
+	return uc.currentUnitCost(ctx, movement.ProductID, layers)
+}
+
+// currentUnitCost is the average cost of the open layers, or the last known
+// cost of the product when there are none.
+func (uc *UseCase) currentUnitCost(ctx context.Context, productID string, layers []entity.CostLayer) (float64, error) {
+	var quantity, value float64
+	for _, layer := range layers {
+		quantity += layer.Remaining
+		value += layer.Remaining * layer.UnitCost
+	}
+
+	if quantity > 0 {
+		return value / quantity, nil
+	}
+
+	return uc.CostingRepo.GetLastUnitCost(ctx, productID)
+}
+
+// bringsOwnCost tells whether a movement comes with its unit cost: receipts,
+// opening balances and what assembly documents put together from the stock
+// they took out.
+func bringsOwnCost(movement entity.StockMovement) bool {
+	switch movement.Reason {
+	case entity.MovementReasonReceipt, entity.MovementReasonOpeningBalance:
+		return true
+	case entity.MovementReasonAssembly, entity.MovementReasonDisassembly:
+		return movement.Quantity > 0
+	}
+
+	return false
+}
+
+// reversesOwnCost tells whether a movement undoes inbound movements of its
+// document that came with their own cost, e.g. a cancelled receipt.
+func reversesOwnCost(movement entity.StockMovement) bool {
+	_, ok := reversedReasons[movement.Reason]
+	return ok && movement.Quantity < 0 && movement.DocumentID != ""
+}
+
+func roundTo(x float64, decimals int) float64 {
+	pow := math.Pow(10, float64(decimals))
+	return math.Round(x*pow) / pow
+}
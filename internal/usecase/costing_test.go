@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+)
+
+// costingRepo keeps the layers and the ledger of a single product in memory.
+type costingRepo struct {
+	CostingRepoI
+
+	layers    []entity.CostLayer
+	movements []entity.StockMovement
+}
+
+func (r *costingRepo) LockLayers(ctx context.Context, productID, warehouseID string) ([]entity.CostLayer, error) {
+	var response []entity.CostLayer
+	for _, layer := range r.layers {
+		if layer.Remaining > 0 {
+			response = append(response, layer)
+		}
+	}
+
+	return response, nil
+}
+
+func (r *costingRepo) CreateLayer(ctx context.Context, req entity.CostLayer) (entity.CostLayer, error) {
+	req.ID = fmt.Sprintf("layer-%d", len(r.layers)+1)
+	r.layers = append(r.layers, req)
+
+	return req, nil
+}
+
+func (r *costingRepo) UpdateLayer(ctx context.Context, req entity.CostLayer) error {
+	for i := range r.layers {
+		if r.layers[i].ID == req.ID {
+			r.layers[i] = req
+		}
+	}
+
+	return nil
+}
+
+func (r *costingRepo) SetMovementCost(ctx context.Context, movementID string, unitCost, cost float64) error {
+	for i := range r.movements {
+		if r.movements[i].ID == movementID {
+			r.movements[i].UnitCost = unitCost
+			r.movements[i].Cost = cost
+		}
+	}
+
+	return nil
+}
+
+func (r *costingRepo) GetDocumentInbound(ctx context.Context, documentID, productID, warehouseID, reason string) ([]entity.StockMovement, error) {
+	var response []entity.StockMovement
+	for _, movement := range r.movements {
+		if movement.DocumentID == documentID && movement.Reason == reason && movement.Quantity > 0 {
+			response = append(response, movement)
+		}
+	}
+
+	return response, nil
+}
+
+func (r *costingRepo) GetLastUnitCost(ctx context.Context, productID string) (float64, error) {
+	return 0, nil
+}
+
+// post costs a movement the way posting a document does.
+func (r *costingRepo) post(t *testing.T, uc *UseCase, method string, movement entity.StockMovement) entity.StockMovement {
+	t.Helper()
+
+	movement.ID = fmt.Sprintf("movement-%d", len(r.movements)+1)
+	movement.ProductID = "product"
+	movement.WarehouseID = "warehouse"
+	r.movements = append(r.movements, movement)
+
+	err := uc.costGroup(context.Background(), method, r.movements, []int{len(r.movements) - 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return r.movements[len(r.movements)-1]
+}
+
+func (r *costingRepo) openLayers() []entity.CostLayer {
+	layers, _ := r.LockLayers(context.Background(), "product", "warehouse")
+	return layers
+}
+
+func TestCancelledReceiptClosesItsLayer(t *testing.T) {
+	for _, method := range []string{entity.CostingMethodFIFO, entity.CostingMethodAverage} {
+		t.Run(method, func(t *testing.T) {
+			var (
+				repo = &costingRepo{}
+				uc   = &UseCase{CostingRepo: repo}
+			)
+
+			repo.post(t, uc, method, entity.StockMovement{Quantity: 10, UnitCost: 5, Reason: entity.MovementReasonReceipt, DocumentID: "receipt-a"})
+			repo.post(t, uc, method, entity.StockMovement{Quantity: 10, UnitCost: 8, Reason: entity.MovementReasonReceipt, DocumentID: "receipt-b"})
+			cancel := repo.post(t, uc, method, entity.StockMovement{Quantity: -10, Reason: entity.MovementReasonReceiptCancel, DocumentID: "receipt-b"})
+
+			if cancel.UnitCost != 8 || cancel.Cost != -80 {
+				t.Errorf("cancel valued at %v, cost %v, want 8 and -80", cancel.UnitCost, cancel.Cost)
+			}
+
+			layers := repo.openLayers()
+			if len(layers) != 1 || layers[0].Remaining != 10 || layers[0].UnitCost != 5 {
+				t.Fatalf("open layers %+v, want 10 at 5", layers)
+			}
+
+			sale := repo.post(t, uc, method, entity.StockMovement{Quantity: -4, Reason: entity.MovementReasonSale, DocumentID: "order"})
+			if sale.UnitCost != 5 {
+				t.Errorf("sale valued at %v, want 5", sale.UnitCost)
+			}
+		})
+	}
+}
+
+func TestCancelledReceiptLeavesOtherLayers(t *testing.T) {
+	var (
+		repo = &costingRepo{}
+		uc   = &UseCase{CostingRepo: repo}
+	)
+
+	repo.post(t, uc, entity.CostingMethodFIFO, entity.StockMovement{Quantity: 10, UnitCost: 5, Reason: entity.MovementReasonReceipt, DocumentID: "receipt-a"})
+	repo.post(t, uc, entity.CostingMethodFIFO, entity.StockMovement{Quantity: 10, UnitCost: 8, Reason: entity.MovementReasonReceipt, DocumentID: "receipt-b"})
+	repo.post(t, uc, entity.CostingMethodFIFO, entity.StockMovement{Quantity: -4, Reason: entity.MovementReasonReceiptCancel, DocumentID: "receipt-b"})
+
+	layers := repo.openLayers()
+	if len(layers) != 2 || layers[0].Remaining != 10 || layers[1].Remaining != 6 || layers[1].UnitCost != 8 {
+		t.Fatalf("open layers %+v, want 10 at 5 and 6 at 8", layers)
+	}
+}
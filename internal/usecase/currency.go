@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+)
+
+// ImportRates stores the official rates on date (YYYY-MM-DD, the latest
+// published when empty) of every currency defined besides the base one.
+// Rates published for more than one unit are brought down to a single unit.
+func (uc *UseCase) ImportRates(ctx context.Context, date string) ([]entity.ExchangeRate, error) {
+	published, err := uc.RateSource.Rates(ctx, date)
+	if err != nil {
+		return nil, err
+	}
+
+	currencies, err := uc.CurrencyRepo.GetList(ctx, entity.GetListFilter{Limit: 1000})
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]bool, len(currencies.Items))
+	for _, currency := range currencies.Items {
+		known[currency.Code] = !currency.IsBase
+	}
+
+	rates := []entity.ExchangeRate{}
+	for _, rate := range published {
+		if !known[rate.Currency] || rate.Rate <= 0 || rate.Nominal <= 0 {
+			continue
+		}
+
+		rates = append(rates, entity.ExchangeRate{
+			Currency: rate.Currency,
+			RateDate: rate.Date,
+			Rate:     roundTo(rate.Rate/rate.Nominal, 6),
+			Source:   entity.RateSourceCBU,
+		})
+	}
+
+	return uc.CurrencyRepo.SetRates(ctx, rates)
+}
@@ -0,0 +1,261 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+)
+
+// PrepareDocument gathers a document, the company details and the template of
+// the form it is printed on. The stored name of the printed file changes with
+// any of them, so a file printed before can be served as long as it is found
+// under the name.
+func (uc *UseCase) PrepareDocument(ctx context.Context, req entity.PrintRequest) (entity.PrintDocument, error) {
+	doc := entity.PrintDocument{PrintRequest: req}
+
+	if doc.Form == "" {
+		doc.Form = entity.FormInvoice
+		if doc.Type == entity.DocumentTransfer {
+			doc.Form = entity.FormWaybill
+		}
+	}
+
+	if doc.Lang == "" {
+		doc.Lang = entity.LangUzbek
+	}
+
+	settings, err := uc.SettingsRepo.GetSingle(ctx)
+	if err != nil {
+		return doc, err
+	}
+	doc.Company = settings.Company
+
+	doc.Template, err = uc.DocumentTemplateRepo.GetSingle(ctx, doc.Form)
+	if err != nil {
+		return doc, err
+	}
+
+	var updatedAt string
+	switch doc.Type {
+	case entity.DocumentSalesOrder:
+		updatedAt, err = uc.prepareSalesOrder(ctx, &doc)
+	case entity.DocumentGoodsReceipt:
+		updatedAt, err = uc.prepareGoodsReceipt(ctx, &doc)
+	case entity.DocumentTransfer:
+		updatedAt, err = uc.prepareTransfer(ctx, &doc)
+	default:
+		err = ErrUnknownDocumentType
+	}
+	if err != nil {
+		return doc, err
+	}
+
+	hash := sha1.Sum([]byte(updatedAt + "|" + settings.UpdatedAt + "|" + doc.Template.UpdatedAt))
+	doc.Version = hex.EncodeToString(hash[:6])
+	doc.Object = fmt.Sprintf("documents/%s/%s/%s-%s-%s.pdf", doc.Type, doc.ID, doc.Form, doc.Lang, doc.Version)
+
+	return doc, nil
+}
+
+// prepareSalesOrder bills the quantity that wasn't shorted or returned, the
+// company is the supplier and the customer the buyer.
+func (uc *UseCase) prepareSalesOrder(ctx context.Context, doc *entity.PrintDocument) (string, error) {
+	order, err := uc.SalesOrderRepo.GetSingle(ctx, entity.Id{ID: doc.ID})
+	if err != nil {
+		return "", err
+	}
+
+	customer, err := uc.CustomerRepo.GetSingle(ctx, entity.Id{ID: order.CustomerID})
+	if err != nil {
+		return "", err
+	}
+
+	warehouse, err := uc.WarehouseRepo.GetSingle(ctx, entity.Id{ID: order.WarehouseID})
+	if err != nil {
+		return "", err
+	}
+
+	doc.Number, doc.Date, doc.Currency = order.Number, order.OrderDate, order.Currency
+
+	company := companyParty(doc.Company, doc.Lang)
+	company.Details = append(company.Details, warehouseDetail(warehouse, doc.Lang))
+
+	buyer := entity.PrintParty{Name: order.CustomerName}
+	if buyer.Name == "" {
+		buyer.Name = customer.Name
+	}
+	buyer.Details = contactDetails(customer.Address, customer.TaxID, customer.Phone, doc.Lang)
+
+	company.Role, buyer.Role = roleSupplier, roleBuyer
+	doc.Parties = []entity.PrintParty{company, buyer}
+
+	for _, line := range order.Lines {
+		printed, err := uc.printLine(ctx, line.ProductID, line.Unit, line.UnitQuantity, line.Quantity, line.Quantity-line.Shorted-line.Returned, line.Price)
+		if err != nil {
+			return "", err
+		}
+
+		doc.Lines = append(doc.Lines, printed)
+		doc.Total += printed.Amount
+	}
+
+	return order.UpdatedAt, nil
+}
+
+// prepareGoodsReceipt prints the receipt at its unit costs, the supplier
+// delivers to the company.
+func (uc *UseCase) prepareGoodsReceipt(ctx context.Context, doc *entity.PrintDocument) (string, error) {
+	receipt, err := uc.GoodsReceiptRepo.GetSingle(ctx, entity.Id{ID: doc.ID})
+	if err != nil {
+		return "", err
+	}
+
+	supplier, err := uc.SupplierRepo.GetSingle(ctx, entity.Id{ID: receipt.SupplierID})
+	if err != nil {
+		return "", err
+	}
+
+	warehouse, err := uc.WarehouseRepo.GetSingle(ctx, entity.Id{ID: receipt.WarehouseID})
+	if err != nil {
+		return "", err
+	}
+
+	doc.Number, doc.Date, doc.Currency = receipt.Number, receipt.ReceiptDate, receipt.Currency
+
+	seller := entity.PrintParty{Role: roleSupplier, Name: supplier.Name}
+	seller.Details = contactDetails(supplier.Address, supplier.TaxID, supplier.Phone, doc.Lang)
+
+	company := companyParty(doc.Company, doc.Lang)
+	company.Role = roleReceiver
+	company.Details = append(company.Details, warehouseDetail(warehouse, doc.Lang))
+
+	doc.Parties = []entity.PrintParty{seller, company}
+
+	for _, line := range receipt.Lines {
+		printed, err := uc.printLine(ctx, line.ProductID, line.Unit, line.UnitQuantity, line.Quantity, line.Quantity, line.UnitCost)
+		if err != nil {
+			return "", err
+		}
+
+		doc.Lines = append(doc.Lines, printed)
+		doc.Total += printed.Amount
+	}
+
+	return receipt.UpdatedAt, nil
+}
+
+// prepareTransfer prints the dispatched quantities without prices, both sides
+// are warehouses of the company.
+func (uc *UseCase) prepareTransfer(ctx context.Context, doc *entity.PrintDocument) (string, error) {
+	transfer, err := uc.TransferRepo.GetSingle(ctx, entity.Id{ID: doc.ID})
+	if err != nil {
+		return "", err
+	}
+
+	parties := make([]entity.PrintParty, 0, 2)
+	for _, side := range []struct{ role, warehouseID string }{
+		{roleSender, transfer.SourceWarehouseID},
+		{roleReceiver, transfer.DestWarehouseID},
+	} {
+		warehouse, err := uc.WarehouseRepo.GetSingle(ctx, entity.Id{ID: side.warehouseID})
+		if err != nil {
+			return "", err
+		}
+
+		party := entity.PrintParty{Role: side.role, Name: doc.Company.Name}
+		party.Details = []string{warehouseDetail(warehouse, doc.Lang)}
+		if warehouse.Address != "" {
+			party.Details = append(party.Details, documentLabel(doc.Lang, "address")+": "+warehouse.Address)
+		}
+
+		parties = append(parties, party)
+	}
+
+	doc.Number, doc.Parties = transfer.Number, parties
+
+	doc.Date = transfer.CreatedAt
+	if transfer.DispatchedAt != "" {
+		doc.Date = transfer.DispatchedAt
+	}
+	if len(doc.Date) > 10 {
+		doc.Date = doc.Date[:10]
+	}
+
+	for _, line := range transfer.Lines {
+		printed, err := uc.printLine(ctx, line.ProductID, line.Unit, line.UnitQuantity, line.Quantity, line.Quantity, 0)
+		if err != nil {
+			return "", err
+		}
+
+		doc.Lines = append(doc.Lines, printed)
+	}
+
+	return transfer.UpdatedAt, nil
+}
+
+// printLine shows quantity (in the base unit) in the unit the line was entered
+// in, entered is the base quantity that was entered as unitQuantity of unit.
+// price is per base unit.
+func (uc *UseCase) printLine(ctx context.Context, productID, unit string, unitQuantity, entered, quantity, price float64) (entity.PrintLine, error) {
+	product, err := uc.ProductRepo.GetSingle(ctx, entity.ProductSingleRequest{ID: productID})
+	if err != nil {
+		return entity.PrintLine{}, err
+	}
+
+	line := entity.PrintLine{
+		SKU:      product.SKU,
+		Name:     product.Name,
+		Unit:     product.BaseUnit,
+		Quantity: quantity,
+		Price:    price,
+		Amount:   roundTo(quantity*price, 2),
+	}
+
+	if unit != "" && unit != product.BaseUnit && unitQuantity > 0 && entered > 0 {
+		factor := entered / unitQuantity
+		line.Unit = unit
+		line.Quantity = roundTo(quantity/factor, 3)
+		line.Price = roundTo(price*factor, 2)
+	}
+
+	return line, nil
+}
+
+func companyParty(company entity.CompanyDetails, lang string) entity.PrintParty {
+	party := entity.PrintParty{Name: company.Name}
+	party.Details = contactDetails(company.Address, company.TaxID, company.Phone, lang)
+
+	if company.BankAccount != "" {
+		party.Details = append(party.Details, documentLabel(lang, "account")+": "+company.BankAccount)
+	}
+	if company.BankName != "" {
+		party.Details = append(party.Details, documentLabel(lang, "bank")+": "+company.BankName)
+	}
+	if company.BankCode != "" {
+		party.Details = append(party.Details, documentLabel(lang, "bank_code")+": "+company.BankCode)
+	}
+
+	return party
+}
+
+func contactDetails(address, taxID, phone, lang string) []string {
+	var details []string
+	if address != "" {
+		details = append(details, documentLabel(lang, "address")+": "+address)
+	}
+	if taxID != "" {
+		details = append(details, documentLabel(lang, "tax_id")+": "+taxID)
+	}
+	if phone != "" {
+		details = append(details, documentLabel(lang, "phone")+": "+phone)
+	}
+
+	return details
+}
+
+func warehouseDetail(warehouse entity.Warehouse, lang string) string {
+	return documentLabel(lang, "warehouse") + ": " + warehouse.Name
+}
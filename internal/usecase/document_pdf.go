@@ -0,0 +1,529 @@
+package usecase
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/numwords"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/pdf"
+)
+
+// Sides of a printed document.
+const (
+	roleSupplier = "supplier"
+	roleBuyer    = "buyer"
+	roleSender   = "sender"
+	roleReceiver = "receiver"
+)
+
+const (
+	fontRegular = "DejaVuSans"
+	fontBold    = "DejaVuSans-Bold"
+
+	pageMargin      = 40.0
+	footerHeight    = 24.0
+	cellPadding     = 3.0
+	defaultFontSize = 9.0
+)
+
+var documentLabels = map[string]map[string]string{
+	entity.LangUzbek: {
+		entity.FormInvoice:    "HISOB-FAKTURA",
+		entity.FormWaybill:    "YUK XATI",
+		entity.ColumnNo:       "№",
+		entity.ColumnSKU:      "Artikul",
+		entity.ColumnName:     "Tovar nomi",
+		entity.ColumnUnit:     "O'lch. bir.",
+		entity.ColumnQuantity: "Miqdori",
+		entity.ColumnPrice:    "Narxi",
+		entity.ColumnAmount:   "Summasi",
+		roleSupplier:          "Yetkazib beruvchi",
+		roleBuyer:             "Xaridor",
+		roleSender:            "Jo'natuvchi",
+		roleReceiver:          "Qabul qiluvchi",
+		"date":                "Sana",
+		"currency":            "Valyuta",
+		"address":             "Manzil",
+		"tax_id":              "STIR",
+		"phone":               "Tel.",
+		"account":             "H/r",
+		"bank":                "Bank",
+		"bank_code":           "MFO",
+		"warehouse":           "Ombor",
+		"total":               "Jami",
+		"in_words":            "Summa so'z bilan",
+		"items":               "Jami nomlar soni",
+		"page":                "Sahifa",
+	},
+	entity.LangRussian: {
+		entity.FormInvoice:    "СЧЁТ-ФАКТУРА",
+		entity.FormWaybill:    "НАКЛАДНАЯ",
+		entity.ColumnNo:       "№",
+		entity.ColumnSKU:      "Артикул",
+		entity.ColumnName:     "Наименование",
+		entity.ColumnUnit:     "Ед. изм.",
+		entity.ColumnQuantity: "Кол-во",
+		entity.ColumnPrice:    "Цена",
+		entity.ColumnAmount:   "Сумма",
+		roleSupplier:          "Поставщик",
+		roleBuyer:             "Покупатель",
+		roleSender:            "Отправитель",
+		roleReceiver:          "Получатель",
+		"date":                "Дата",
+		"currency":            "Валюта",
+		"address":             "Адрес",
+		"tax_id":              "ИНН",
+		"phone":               "Тел.",
+		"account":             "Р/с",
+		"bank":                "Банк",
+		"bank_code":           "МФО",
+		"warehouse":           "Склад",
+		"total":               "Итого",
+		"in_words":            "Сумма прописью",
+		"items":               "Всего наименований",
+		"page":                "Страница",
+	},
+}
+
+// defaultSignatures are printed when a template has none, the first two
+// signatures of an invoice are signed by the director and the accountant.
+var defaultSignatures = map[string]map[string][]string{
+	entity.LangUzbek: {
+		entity.FormInvoice: {"Rahbar", "Bosh hisobchi", "Topshirdi", "Qabul qildi"},
+		entity.FormWaybill: {"Topshirdi", "Qabul qildi"},
+	},
+	entity.LangRussian: {
+		entity.FormInvoice: {"Руководитель", "Главный бухгалтер", "Отпустил", "Получил"},
+		entity.FormWaybill: {"Отпустил", "Получил"},
+	},
+}
+
+// columnWidths are the widths of the columns in points, the name takes the
+// rest of the page.
+var columnWidths = map[string]float64{
+	entity.ColumnNo:       24,
+	entity.ColumnSKU:      70,
+	entity.ColumnUnit:     52,
+	entity.ColumnQuantity: 58,
+	entity.ColumnPrice:    72,
+	entity.ColumnAmount:   82,
+}
+
+func documentLabel(lang, key string) string {
+	if label, ok := documentLabels[lang][key]; ok {
+		return label
+	}
+
+	return documentLabels[entity.LangUzbek][key]
+}
+
+// RenderDocument prints a prepared document on A4 pages.
+func (uc *UseCase) RenderDocument(doc entity.PrintDocument) ([]byte, error) {
+	l := &documentLayout{
+		doc:  doc,
+		pdf:  pdf.New(pdf.A4Width, pdf.A4Height),
+		size: doc.Template.FontSize,
+	}
+	if l.size == 0 {
+		l.size = defaultFontSize
+	}
+
+	if err := l.pdf.AddFont(fontRegular, pdf.DejaVuSans); err != nil {
+		return nil, err
+	}
+	if err := l.pdf.AddFont(fontBold, pdf.DejaVuSansBold); err != nil {
+		return nil, err
+	}
+
+	l.pdf.Title = l.title()
+	l.newPage()
+
+	l.header()
+	l.parties()
+	l.table()
+	l.totals()
+	l.signatures()
+	l.footers()
+
+	return l.pdf.Bytes()
+}
+
+type documentLayout struct {
+	doc  entity.PrintDocument
+	pdf  *pdf.Document
+	page *pdf.Page
+	size float64
+	y    float64 // top of the space left on the page
+}
+
+func (l *documentLayout) label(key string) string {
+	return documentLabel(l.doc.Lang, key)
+}
+
+func (l *documentLayout) title() string {
+	title := l.doc.Template.Title
+	if title == "" {
+		title = l.label(l.doc.Form)
+	}
+
+	return title + " № " + l.doc.Number
+}
+
+func (l *documentLayout) width() float64 {
+	return pdf.A4Width - 2*pageMargin
+}
+
+func (l *documentLayout) lineHeight(size float64) float64 {
+	return size * 1.35
+}
+
+func (l *documentLayout) newPage() {
+	l.page = l.pdf.AddPage()
+	l.page.SetLineWidth(0.5)
+	l.y = pageMargin
+}
+
+// fits starts a new page unless height fits in the space left, it reports
+// whether the page was kept.
+func (l *documentLayout) fits(height float64) bool {
+	if l.y+height <= pdf.A4Height-pageMargin-footerHeight {
+		return true
+	}
+
+	l.newPage()
+
+	return false
+}
+
+func (l *documentLayout) setFont(name string, size float64) {
+	// both fonts are added before any page, selecting them can't fail
+	_ = l.page.SetFont(name, size)
+}
+
+// paragraph prints text wrapped to width at x and moves down past it.
+func (l *documentLayout) paragraph(x, width float64, font string, size float64, text string, align int) {
+	l.setFont(font, size)
+	for _, line := range l.page.Wrap(text, width) {
+		l.fits(l.lineHeight(size))
+		l.setFont(font, size)
+
+		at := x
+		switch align {
+		case pdf.AlignCenter:
+			at = x + width/2
+		case pdf.AlignRight:
+			at = x + width
+		}
+
+		l.page.Text(at, l.y+size, line, align)
+		l.y += l.lineHeight(size)
+	}
+}
+
+func (l *documentLayout) header() {
+	if l.doc.Company.Name != "" {
+		l.paragraph(pageMargin, l.width(), fontBold, l.size+1, l.doc.Company.Name, pdf.AlignLeft)
+		l.y += l.size
+	}
+
+	l.paragraph(pageMargin, l.width(), fontBold, l.size+5, l.title(), pdf.AlignCenter)
+
+	subtitle := l.label("date") + ": " + printDate(l.doc.Date)
+	if l.doc.Currency != "" {
+		subtitle += "    " + l.label("currency") + ": " + l.doc.Currency
+	}
+	l.paragraph(pageMargin, l.width(), fontRegular, l.size, subtitle, pdf.AlignCenter)
+	l.y += l.size
+
+	if l.doc.Template.Header != "" {
+		l.paragraph(pageMargin, l.width(), fontRegular, l.size, l.doc.Template.Header, pdf.AlignLeft)
+		l.y += l.size
+	}
+}
+
+// parties prints the sides of the document next to each other.
+func (l *documentLayout) parties() {
+	if len(l.doc.Parties) == 0 {
+		return
+	}
+
+	const gap = 20.0
+	width := (l.width() - gap*float64(len(l.doc.Parties)-1)) / float64(len(l.doc.Parties))
+
+	// the sides are kept on one page
+	lines := 0
+	for _, party := range l.doc.Parties {
+		if len(party.Details)+1 > lines {
+			lines = len(party.Details) + 1
+		}
+	}
+	l.fits(float64(lines) * l.lineHeight(l.size))
+
+	top, bottom := l.y, l.y
+	for i, party := range l.doc.Parties {
+		l.y = top
+		x := pageMargin + float64(i)*(width+gap)
+
+		l.paragraph(x, width, fontBold, l.size, l.label(party.Role)+": "+party.Name, pdf.AlignLeft)
+		for _, detail := range party.Details {
+			l.paragraph(x, width, fontRegular, l.size, detail, pdf.AlignLeft)
+		}
+
+		bottom = math.Max(bottom, l.y)
+	}
+
+	l.y = bottom + l.size
+}
+
+// columns returns the columns of the line table and their widths.
+func (l *documentLayout) columns() ([]string, []float64) {
+	columns := l.doc.Template.Columns
+	if len(columns) == 0 {
+		columns = entity.DocumentColumns
+	}
+
+	var (
+		keep   []string
+		widths []float64
+		fixed  float64
+	)
+	for _, column := range columns {
+		if l.doc.Currency == "" && (column == entity.ColumnPrice || column == entity.ColumnAmount) {
+			continue
+		}
+
+		keep = append(keep, column)
+		widths = append(widths, columnWidths[column])
+		fixed += columnWidths[column]
+	}
+
+	// the name takes the space left, the other columns shrink when there is none
+	for i, column := range keep {
+		if column == entity.ColumnName {
+			widths[i] = math.Max(l.width()-fixed, 0)
+		}
+	}
+
+	total := 0.0
+	for _, width := range widths {
+		total += width
+	}
+	for i := range widths {
+		widths[i] *= l.width() / total
+	}
+
+	return keep, widths
+}
+
+func (l *documentLayout) cell(line entity.PrintLine, no int, column string) (string, int) {
+	switch column {
+	case entity.ColumnNo:
+		return strconv.Itoa(no), pdf.AlignRight
+	case entity.ColumnSKU:
+		return line.SKU, pdf.AlignLeft
+	case entity.ColumnName:
+		return line.Name, pdf.AlignLeft
+	case entity.ColumnUnit:
+		return line.Unit, pdf.AlignCenter
+	case entity.ColumnQuantity:
+		return printQuantity(line.Quantity), pdf.AlignRight
+	case entity.ColumnPrice:
+		return printAmount(line.Price), pdf.AlignRight
+	case entity.ColumnAmount:
+		return printAmount(line.Amount), pdf.AlignRight
+	}
+
+	return "", pdf.AlignLeft
+}
+
+// row prints a row of the line table, wrapping cells that don't fit, and
+// starts a new page with the table header when the row doesn't fit.
+func (l *documentLayout) row(cells []string, aligns []int, widths []float64, font string, fill bool) {
+	l.setFont(font, l.size)
+
+	wrapped := make([][]string, len(cells))
+	lines := 1
+	for i, cell := range cells {
+		wrapped[i] = l.page.Wrap(cell, widths[i]-2*cellPadding)
+		if len(wrapped[i]) > lines {
+			lines = len(wrapped[i])
+		}
+	}
+
+	height := float64(lines)*l.lineHeight(l.size) + 2*cellPadding
+	if !l.fits(height) && !fill {
+		l.tableHeader(widths)
+	}
+
+	x := pageMargin
+	for i := range cells {
+		if fill {
+			l.page.SetGray(0.9)
+			l.page.Rect(x, l.y, widths[i], height, true)
+			l.page.SetGray(0)
+		}
+		l.page.Rect(x, l.y, widths[i], height, false)
+
+		l.setFont(font, l.size)
+		for j, text := range wrapped[i] {
+			at := x + cellPadding
+			switch aligns[i] {
+			case pdf.AlignCenter:
+				at = x + widths[i]/2
+			case pdf.AlignRight:
+				at = x + widths[i] - cellPadding
+			}
+
+			l.page.Text(at, l.y+cellPadding+float64(j)*l.lineHeight(l.size)+l.size, text, aligns[i])
+		}
+
+		x += widths[i]
+	}
+
+	l.y += height
+}
+
+func (l *documentLayout) tableHeader(widths []float64) {
+	columns, _ := l.columns()
+
+	cells := make([]string, len(columns))
+	aligns := make([]int, len(columns))
+	for i, column := range columns {
+		cells[i], aligns[i] = l.label(column), pdf.AlignCenter
+	}
+
+	l.row(cells, aligns, widths, fontBold, true)
+}
+
+func (l *documentLayout) table() {
+	columns, widths := l.columns()
+	if len(columns) == 0 {
+		return
+	}
+
+	l.tableHeader(widths)
+
+	for i, line := range l.doc.Lines {
+		cells := make([]string, len(columns))
+		aligns := make([]int, len(columns))
+		for j, column := range columns {
+			cells[j], aligns[j] = l.cell(line, i+1, column)
+		}
+
+		l.row(cells, aligns, widths, fontRegular, false)
+	}
+
+	l.y += l.size
+}
+
+// totals prints the total and spells it out, documents without prices get
+// the number of lines instead.
+func (l *documentLayout) totals() {
+	if l.doc.Currency == "" {
+		count := int64(len(l.doc.Lines))
+		l.paragraph(pageMargin, l.width(), fontBold, l.size,
+			l.label("items")+": "+strconv.FormatInt(count, 10)+" ("+numwords.Spell(count, l.doc.Lang)+")", pdf.AlignLeft)
+		l.y += l.size
+		return
+	}
+
+	l.paragraph(pageMargin, l.width(), fontBold, l.size+1,
+		l.label("total")+": "+printAmount(l.doc.Total)+" "+l.doc.Currency, pdf.AlignRight)
+	l.paragraph(pageMargin, l.width(), fontRegular, l.size,
+		l.label("in_words")+": "+numwords.Amount(l.doc.Total, l.doc.Currency, l.doc.Lang), pdf.AlignLeft)
+	l.y += l.size
+}
+
+// signatures prints the signature lines two to a row.
+func (l *documentLayout) signatures() {
+	labels := l.doc.Template.Signatures
+	names := []string{}
+	if len(labels) == 0 {
+		labels = defaultSignatures[l.doc.Lang][l.doc.Form]
+		if l.doc.Form == entity.FormInvoice {
+			names = []string{l.doc.Company.Director, l.doc.Company.Accountant}
+		}
+	}
+
+	const gap = 30.0
+	width := (l.width() - gap) / 2
+	rowHeight := 3 * l.lineHeight(l.size)
+
+	for i, label := range labels {
+		if i%2 == 0 {
+			l.y += l.size
+			l.fits(rowHeight)
+		}
+
+		x := pageMargin + float64(i%2)*(width+gap)
+		l.setFont(fontRegular, l.size)
+		l.page.Text(x, l.y+l.size, label+":", pdf.AlignLeft)
+
+		lineX := x + l.page.TextWidth(label+":") + 6
+		l.page.Line(lineX, l.y+l.size+2, x+width, l.y+l.size+2)
+
+		if i < len(names) && names[i] != "" {
+			l.setFont(fontRegular, l.size-1)
+			l.page.Text((lineX+x+width)/2, l.y+2*l.size+4, names[i], pdf.AlignCenter)
+		}
+
+		if i%2 == 1 || i == len(labels)-1 {
+			l.y += rowHeight
+		}
+	}
+}
+
+// footers prints the template footer and the page number at the bottom of every page.
+func (l *documentLayout) footers() {
+	pages := l.pdf.Pages()
+	for i, page := range pages {
+		_ = page.SetFont(fontRegular, l.size-1)
+		y := pdf.A4Height - pageMargin
+
+		page.Text(pdf.A4Width-pageMargin, y, l.label("page")+" "+strconv.Itoa(i+1)+" / "+strconv.Itoa(len(pages)), pdf.AlignRight)
+
+		// the footer gets two lines left of the page number
+		footer := page.Wrap(l.doc.Template.Footer, l.width()-80)
+		if len(footer) > 2 {
+			footer = footer[:2]
+		}
+		for j, line := range footer {
+			page.Text(pageMargin, y-float64(len(footer)-1-j)*l.lineHeight(l.size-1), line, pdf.AlignLeft)
+		}
+	}
+}
+
+// printDate turns YYYY-MM-DD into DD.MM.YYYY.
+func printDate(date string) string {
+	if len(date) < 10 {
+		return date
+	}
+
+	return date[8:10] + "." + date[5:7] + "." + date[:4]
+}
+
+// printAmount formats money with two decimals and spaces between thousands.
+func printAmount(amount float64) string {
+	s := strconv.FormatFloat(math.Abs(amount), 'f', 2, 64)
+	whole, fraction := s[:len(s)-3], s[len(s)-3:]
+
+	var out strings.Builder
+	if amount < 0 {
+		out.WriteString("-")
+	}
+	for i, r := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			out.WriteString(" ")
+		}
+		out.WriteRune(r)
+	}
+	out.WriteString(fraction)
+
+	return out.String()
+}
+
+func printQuantity(quantity float64) string {
+	return strconv.FormatFloat(roundTo(quantity, 3), 'f', -1, 64)
+}
@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+)
+
+var (
+	ErrInvalidStatusTransition = entity.NewError(config.ErrorInvalidStatus, "Document status does not allow this action")
+	ErrEmptyDocument           = entity.NewError(config.ErrorBadRequest, "Document has no lines")
+	ErrBinWarehouseMismatch    = entity.NewError(config.ErrorBadRequest, "Bin does not belong to the document warehouse")
+	ErrUnknownLine             = entity.NewError(config.ErrorBadRequest, "Line does not belong to the document")
+	ErrOrderHasPayments        = entity.NewError(config.ErrorConflict, "Orders with payments can't be cancelled, delete the payments first")
+	ErrSerialCount             = entity.NewError(config.ErrorBadRequest, "Serialized products need exactly one serial number per unit")
+	ErrProductNotSerialized    = entity.NewError(config.ErrorBadRequest, "Serial numbers are only accepted for serialized products")
+	ErrUnknownSerial           = entity.NewError(config.ErrorBadRequest, "Serial number was not dispatched on the line")
+	ErrStocktakeSerialized     = entity.NewError(config.ErrorBadRequest, "Serialized products can't be adjusted by a stocktake, adjust them by their serial numbers first")
+	ErrNotAvailable            = entity.NewError(config.ErrorInsufficientStock, "Not enough stock available to promise, the rest is reserved by other orders")
+	ErrPurchaseOrderNotOpen    = entity.NewError(config.ErrorInvalidStatus, "Only sent purchase orders can be received")
+	ErrPurchaseOrderMismatch   = entity.NewError(config.ErrorBadRequest, "Receipt supplier and warehouse must match the purchase order")
+	ErrPickListNotOpen         = entity.NewError(config.ErrorInvalidStatus, "Pick list is already completed or cancelled")
+	ErrNotOnPickList           = entity.NewError(config.ErrorBadRequest, "Scanned item is not left to pick on the pick list")
+	ErrSerialRequired          = entity.NewError(config.ErrorBadRequest, "Serialized products are picked by scanning their serial number")
+	ErrPickIncomplete          = entity.NewError(config.ErrorBadRequest, "Every line has to be picked in full or reported short")
+	ErrBundleNoStock           = entity.NewError(config.ErrorBadRequest, "Bundles hold no stock of their own, adjust their components instead")
+	ErrBundleReceipt           = entity.NewError(config.ErrorBadRequest, "Bundles hold no stock of their own, receive their components instead")
+	ErrNotKit                  = entity.NewError(config.ErrorBadRequest, "Only kits can be assembled and disassembled")
+	ErrNothingPicked           = entity.NewError(config.ErrorBadRequest, "Nothing was picked, cancel the pick list or the order instead")
+	ErrUnknownUnit             = entity.NewError(config.ErrorBadRequest, "The unit is not defined for the product")
+	ErrUnknownDocumentType     = entity.NewError(config.ErrorBadRequest, "Only sales orders, goods receipts and transfers can be printed")
+	ErrImportFormat            = entity.NewError(config.ErrorBadRequest, "Only CSV and XLSX files can be imported")
+	ErrImportUnreadable        = entity.NewError(config.ErrorBadRequest, "The file could not be read, check that it is a valid CSV or XLSX file")
+	ErrImportEmpty             = entity.NewError(config.ErrorBadRequest, "The file has no rows under its header")
+)
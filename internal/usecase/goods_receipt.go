@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+)
+
+// PostGoodsReceipt writes the lines of a draft receipt to the stock ledger and
+// marks it posted. Either everything is written or nothing is.
+func (uc *UseCase) PostGoodsReceipt(ctx context.Context, id, userID string) (entity.GoodsReceipt, error) {
+	err := uc.Tx.WithTx(ctx, func(ctx context.Context) error {
+		status, err := uc.GoodsReceiptRepo.LockStatus(ctx, entity.Id{ID: id})
+		if err != nil {
+			return err
+		}
+
+		if status != entity.DocumentStatusDraft {
+			return ErrInvalidStatusTransition
+		}
+
+		receipt, err := uc.GoodsReceiptRepo.GetSingle(ctx, entity.Id{ID: id})
+		if err != nil {
+			return err
+		}
+
+		if len(receipt.Lines) == 0 {
+			return ErrEmptyDocument
+		}
+
+		err = uc.writeReceiptMovements(ctx, receipt, entity.MovementReasonReceipt, 1, userID)
+		if err != nil {
+			return err
+		}
+
+		err = uc.receivePurchaseOrder(ctx, receipt, 1)
+		if err != nil {
+			return err
+		}
+
+		err = uc.GoodsReceiptRepo.SetStatus(ctx, entity.Id{ID: id}, entity.DocumentStatusPosted)
+		if err != nil {
+			return err
+		}
+
+		return uc.rematchReceiptCredits(ctx, id)
+	})
+	if err != nil {
+		return entity.GoodsReceipt{}, err
+	}
+
+	return uc.GoodsReceiptRepo.GetSingle(ctx, entity.Id{ID: id})
+}
+
+// CancelGoodsReceipt cancels a receipt. A posted receipt is reversed with
+// opposite movements, which fails if the received stock has already left the bins.
+func (uc *UseCase) CancelGoodsReceipt(ctx context.Context, id, userID string) (entity.GoodsReceipt, error) {
+	err := uc.Tx.WithTx(ctx, func(ctx context.Context) error {
+		status, err := uc.GoodsReceiptRepo.LockStatus(ctx, entity.Id{ID: id})
+		if err != nil {
+			return err
+		}
+
+		switch status {
+		case entity.DocumentStatusDraft:
+		case entity.DocumentStatusPosted:
+			receipt, err := uc.GoodsReceiptRepo.GetSingle(ctx, entity.Id{ID: id})
+			if err != nil {
+				return err
+			}
+
+			err = uc.writeReceiptMovements(ctx, receipt, entity.MovementReasonReceiptCancel, -1, userID)
+			if err != nil {
+				return err
+			}
+
+			err = uc.receivePurchaseOrder(ctx, receipt, -1)
+			if err != nil {
+				return err
+			}
+		default:
+			return ErrInvalidStatusTransition
+		}
+
+		err = uc.GoodsReceiptRepo.SetStatus(ctx, entity.Id{ID: id}, entity.DocumentStatusCancelled)
+		if err != nil {
+			return err
+		}
+
+		return uc.rematchReceiptCredits(ctx, id)
+	})
+	if err != nil {
+		return entity.GoodsReceipt{}, err
+	}
+
+	return uc.GoodsReceiptRepo.GetSingle(ctx, entity.Id{ID: id})
+}
+
+// writeReceiptMovements writes the receipt lines to the ledger, lines with a
+// lot number go to that lot, which is created on first receipt. Units of
+// serialized products are put into their bin, or taken out again on reversal.
+func (uc *UseCase) writeReceiptMovements(ctx context.Context, receipt entity.GoodsReceipt, reason string, sign float64, userID string) error {
+	movements := make([]entity.StockMovement, 0, len(receipt.Lines))
+	for _, line := range receipt.Lines {
+		var lot entity.Lot
+		if line.LotNumber != "" {
+			var err error
+			lot, err = uc.LotRepo.GetOrCreate(ctx, line.ProductID, line.LotNumber, line.ExpiryDate)
+			if err != nil {
+				return err
+			}
+		}
+
+		serialized, err := uc.checkSerials(ctx, line.ProductID, line.Serials, line.Quantity)
+		if err != nil {
+			return err
+		}
+
+		if serialized && sign > 0 {
+			err = uc.SerialRepo.Receive(ctx, line.ProductID, line.BinID, lot.ID, line.Serials)
+		} else if serialized {
+			_, err = uc.SerialRepo.Move(ctx, entity.SerialMove{
+				ProductID:  line.ProductID,
+				Serials:    line.Serials,
+				FromStatus: entity.SerialStatusInStock,
+				FromBinID:  line.BinID,
+				LotID:      lot.ID,
+				ToStatus:   entity.SerialStatusRemoved,
+			})
+		}
+		if err != nil {
+			return err
+		}
+
+		movements = append(movements, entity.StockMovement{
+			ProductID:    line.ProductID,
+			BinID:        line.BinID,
+			LotID:        lot.ID,
+			Serials:      line.Serials,
+			Quantity:     sign * line.Quantity,
+			UnitCost:     line.UnitCost * receipt.ExchangeRate, // the ledger is kept in the base currency
+			Reason:       reason,
+			DocumentType: entity.DocumentTypeGoodsReceipt,
+			DocumentID:   receipt.ID,
+			Note:         receipt.Number,
+			UserID:       userID,
+		})
+	}
+
+	return uc.createDocumentMovements(ctx, movements, receipt.WarehouseID)
+}
@@ -0,0 +1,629 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	// importPageSize is the number of rows the worker takes at a time, the
+	// progress of a job is updated after every page.
+	importPageSize = 100
+
+	// importStaleAfter is how long a running job may go without progress
+	// before another worker takes it over.
+	importStaleAfter = 10 * time.Minute
+)
+
+// errDryRun rolls back the writes of a row that was only validated.
+var errDryRun = errors.New("dry run")
+
+// CreateImport reads an uploaded CSV or XLSX file into a job and guesses the
+// mapping of its columns from the header. Nothing is validated or imported
+// until the job is run.
+func (uc *UseCase) CreateImport(ctx context.Context, req entity.ImportJob, data []byte) (entity.ImportJob, error) {
+	columns, rows, err := parseImportFile(req.FileName, data)
+	if err != nil {
+		return entity.ImportJob{}, err
+	}
+
+	req.Columns = columns
+	req.Mapping = guessImportMapping(req.Kind, columns)
+
+	return uc.ImportRepo.Create(ctx, req, rows)
+}
+
+// RunImport checks the mapping and queues the job for the worker.
+func (uc *UseCase) RunImport(ctx context.Context, req entity.ImportRunRequest) (entity.ImportJob, error) {
+	job, err := uc.ImportRepo.GetSingle(ctx, entity.Id{ID: req.JobID})
+	if err != nil {
+		return entity.ImportJob{}, err
+	}
+
+	if len(req.Mapping) == 0 {
+		req.Mapping = job.Mapping
+	}
+
+	for field, column := range req.Mapping {
+		if column == "" {
+			delete(req.Mapping, field)
+		}
+	}
+
+	err = checkImportMapping(job, req.Mapping)
+	if err != nil {
+		return entity.ImportJob{}, err
+	}
+
+	job, err = uc.ImportRepo.Queue(ctx, req)
+	if err != nil {
+		return entity.ImportJob{}, err
+	}
+
+	// the worker is woken unless it is already busy and will look again
+	select {
+	case uc.importWake <- struct{}{}:
+	default:
+	}
+
+	return job, nil
+}
+
+// checkImportMapping makes sure mapping names known fields and columns of the
+// file and covers the required fields.
+func checkImportMapping(job entity.ImportJob, mapping map[string]string) error {
+	fields := map[string]bool{}
+	for _, field := range entity.ImportFields[job.Kind] {
+		fields[field.Name] = true
+
+		if field.Required && mapping[field.Name] == "" {
+			return entity.NewError(config.ErrorBadRequest, fmt.Sprintf("%s has to be mapped to a column", field.Name))
+		}
+	}
+
+	for field, column := range mapping {
+		if !fields[field] {
+			return entity.NewError(config.ErrorBadRequest, fmt.Sprintf("%s is not a field of %s imports", field, job.Kind))
+		}
+
+		if !contains(job.Columns, column) {
+			return entity.NewError(config.ErrorBadRequest, fmt.Sprintf("%s is not a column of the file", column))
+		}
+	}
+
+	return nil
+}
+
+// RunImports is the import worker. It runs queued jobs one after another
+// until ctx is cancelled, looking for new ones every poll interval or as soon
+// as a job is queued through RunImport. Several servers can run it side by
+// side, a job is only taken by one of them.
+func (uc *UseCase) RunImports(ctx context.Context) {
+	ticker := time.NewTicker(uc.config.Import.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		for ctx.Err() == nil {
+			job, err := uc.ImportRepo.Claim(ctx, importStaleAfter)
+			if errors.Is(err, pgx.ErrNoRows) {
+				break
+			}
+			if err != nil {
+				if ctx.Err() == nil {
+					uc.logger.Error(err, "usecase - RunImports - Claim")
+				}
+				break
+			}
+
+			uc.runImport(ctx, job)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-uc.importWake:
+		}
+	}
+}
+
+// runImport goes through the rows of a job a page at a time. A job stopped by
+// shutdown stays running and is taken over once it counts as stale.
+func (uc *UseCase) runImport(ctx context.Context, job entity.ImportJob) {
+	importer := &importer{uc: uc, job: job, seen: map[string]int{}, cache: map[string]string{}}
+
+	status := entity.ImportStatusCompleted
+	if job.DryRun {
+		status = entity.ImportStatusValidated
+	}
+
+	errText := ""
+
+	err := importer.run(ctx)
+	if ctx.Err() != nil {
+		return
+	}
+	if err != nil {
+		uc.logger.Error(err, "usecase - runImport")
+		status, errText = entity.ImportStatusFailed, err.Error()
+	}
+
+	err = uc.ImportRepo.Finish(ctx, job.ID, status, errText)
+	if err != nil {
+		uc.logger.Error(err, "usecase - runImport - Finish")
+	}
+}
+
+// importer holds what the rows of a job share while it runs.
+type importer struct {
+	uc      *UseCase
+	job     entity.ImportJob
+	columns map[string]int    // field -> index of its column
+	seen    map[string]int    // SKU -> first row it was on, products only
+	cache   map[string]string // looked up categories, warehouses and bins -> ID
+}
+
+func (im *importer) run(ctx context.Context) error {
+	im.columns = map[string]int{}
+	for field, column := range im.job.Mapping {
+		for i, name := range im.job.Columns {
+			if name == column {
+				im.columns[field] = i
+			}
+		}
+	}
+
+	for {
+		rows, err := im.uc.ImportRepo.GetPendingRows(ctx, im.job.ID, importPageSize)
+		if err != nil {
+			return err
+		}
+
+		if len(rows) == 0 {
+			return nil
+		}
+
+		var created, updated int
+		for _, row := range rows {
+			isNew, err := im.row(ctx, &row)
+			if err != nil {
+				return err
+			}
+
+			if row.Status == entity.ImportRowError {
+				continue
+			}
+			if isNew {
+				created++
+			} else {
+				updated++
+			}
+		}
+
+		err = im.uc.ImportRepo.Progress(ctx, im.job.ID, created, updated)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// row validates or imports a single row in a transaction of its own and
+// records the outcome, an imported row together with what it wrote. Errors of the row are kept on it, only errors that
+// stop the whole job are returned.
+func (im *importer) row(ctx context.Context, row *entity.ImportRow) (bool, error) {
+	var isNew bool
+
+	err := im.uc.Tx.WithTx(ctx, func(ctx context.Context) (err error) {
+		if im.job.Kind == entity.ImportKindProducts {
+			row.RecordID, isNew, err = im.product(ctx, *row)
+		} else {
+			row.RecordID, err = im.openingBalance(ctx, *row)
+			isNew = true
+		}
+		if err != nil {
+			return err
+		}
+
+		if im.job.DryRun {
+			return errDryRun
+		}
+
+		// marked in the same transaction, so that a job taken over after a
+		// stop never imports the row again
+		row.Status = entity.ImportRowImported
+		return im.uc.ImportRepo.SetRow(ctx, *row)
+	})
+
+	switch {
+	case err == nil:
+		return isNew, nil
+	case errors.Is(err, errDryRun):
+		row.Status, row.RecordID = entity.ImportRowValid, ""
+	default:
+		message, ok := importRowError(err)
+		if !ok || ctx.Err() != nil {
+			return false, err
+		}
+
+		row.Status, row.Error, row.RecordID = entity.ImportRowError, message, ""
+	}
+
+	return isNew, im.uc.ImportRepo.SetRow(ctx, *row)
+}
+
+// importRowError tells whether err is a problem of the data of a row and
+// describes it. Anything else, like a lost connection, fails the job.
+func importRowError(err error) (string, bool) {
+	var appErr *entity.Error
+	if errors.As(err, &appErr) {
+		return appErr.Message, true
+	}
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "not found", true
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && (strings.HasPrefix(pgErr.Code, "22") || strings.HasPrefix(pgErr.Code, "23")) {
+		if pgErr.Detail != "" {
+			return pgErr.Detail, true
+		}
+		return pgErr.Message, true
+	}
+
+	return "", false
+}
+
+// product creates the product of a row, or updates the one with its SKU with
+// the mapped cells that aren't empty.
+func (im *importer) product(ctx context.Context, row entity.ImportRow) (string, bool, error) {
+	cells := importCells{im.columns, row.Cells}
+
+	sku := cells.get("sku")
+	if sku == "" {
+		return "", false, importFieldError("sku", "is required")
+	}
+
+	if first, ok := im.seen[sku]; ok {
+		return "", false, importFieldError("sku", fmt.Sprintf("is already on row %d", first))
+	}
+
+	product, err := im.uc.ProductRepo.GetSingle(ctx, entity.ProductSingleRequest{SKU: sku})
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return "", false, err
+	}
+
+	isNew := err != nil
+	if isNew {
+		product = entity.Product{SKU: sku, IsActive: true}
+	}
+
+	if value := cells.get("name"); value != "" {
+		product.Name = value
+	}
+	if value := cells.get("description"); value != "" {
+		product.Description = value
+	}
+	if value := cells.get("barcodes"); value != "" {
+		product.Barcodes = splitImportList(value)
+	}
+	if value := cells.get("base_unit"); value != "" {
+		product.BaseUnit = value
+	}
+
+	if value := cells.get("category"); value != "" {
+		product.CategoryID, err = im.category(ctx, value)
+		if err != nil {
+			return "", false, err
+		}
+	}
+
+	if value := cells.get("sale_price"); value != "" {
+		product.SalePrice, err = parseImportNumber("sale_price", value)
+		if err != nil {
+			return "", false, err
+		}
+		if product.SalePrice < 0 {
+			return "", false, importFieldError("sale_price", "can't be negative")
+		}
+	}
+
+	if value := cells.get("is_serialized"); value != "" {
+		product.IsSerialized, err = parseImportBool("is_serialized", value)
+		if err != nil {
+			return "", false, err
+		}
+	}
+
+	if value := cells.get("is_active"); value != "" {
+		product.IsActive, err = parseImportBool("is_active", value)
+		if err != nil {
+			return "", false, err
+		}
+	}
+
+	if product.Name == "" {
+		return "", false, importFieldError("name", "is required for new products")
+	}
+
+	for _, unit := range product.Units {
+		if unit.Unit == product.BaseUnit {
+			return "", false, importFieldError("base_unit", "is already an alternate unit of the product")
+		}
+	}
+
+	if isNew {
+		product, err = im.uc.ProductRepo.Create(ctx, product)
+	} else {
+		product, err = im.uc.ProductRepo.Update(ctx, product)
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	im.seen[sku] = row.RowNumber
+
+	return product.ID, isNew, nil
+}
+
+// openingBalance puts the counted quantity of a row into its bin.
+func (im *importer) openingBalance(ctx context.Context, row entity.ImportRow) (string, error) {
+	cells := importCells{im.columns, row.Cells}
+
+	product, err := im.uc.ProductRepo.GetSingle(ctx, entity.ProductSingleRequest{SKU: cells.get("sku")})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", importFieldError("sku", "is not a product")
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if product.Kind == entity.ProductKindBundle {
+		return "", ErrBundleNoStock
+	}
+
+	warehouseID, err := im.warehouse(ctx, cells.get("warehouse"))
+	if err != nil {
+		return "", err
+	}
+
+	binID, err := im.bin(ctx, warehouseID, cells.get("bin"))
+	if err != nil {
+		return "", err
+	}
+
+	quantity, err := parseImportNumber("quantity", cells.get("quantity"))
+	if err != nil {
+		return "", err
+	}
+	if quantity <= 0 {
+		return "", importFieldError("quantity", "has to be positive")
+	}
+
+	var unitCost float64
+	if value := cells.get("unit_cost"); value != "" {
+		unitCost, err = parseImportNumber("unit_cost", value)
+		if err != nil {
+			return "", err
+		}
+		if unitCost < 0 {
+			return "", importFieldError("unit_cost", "can't be negative")
+		}
+	}
+
+	var lot entity.Lot
+	if lotNumber := cells.get("lot_number"); lotNumber != "" {
+		expiryDate, err := parseImportDate("expiry_date", cells.get("expiry_date"))
+		if err != nil {
+			return "", err
+		}
+
+		lot, err = im.uc.LotRepo.GetOrCreate(ctx, product.ID, lotNumber, expiryDate)
+		if err != nil {
+			return "", err
+		}
+	} else if cells.get("expiry_date") != "" {
+		return "", importFieldError("expiry_date", "needs a lot_number")
+	}
+
+	serials := splitImportList(cells.get("serials"))
+
+	serialized, err := im.uc.checkSerials(ctx, product.ID, serials, quantity)
+	if err != nil {
+		return "", err
+	}
+
+	if serialized {
+		err = im.uc.SerialRepo.Receive(ctx, product.ID, binID, lot.ID, serials)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	movements, err := im.uc.postMovements(ctx, []entity.StockMovement{{
+		ProductID:    product.ID,
+		BinID:        binID,
+		LotID:        lot.ID,
+		Serials:      serials,
+		Quantity:     quantity,
+		UnitCost:     roundTo(unitCost, 4),
+		Reason:       entity.MovementReasonOpeningBalance,
+		DocumentType: entity.DocumentTypeImport,
+		DocumentID:   im.job.ID,
+		Note:         im.job.FileName,
+		UserID:       im.job.CreatedBy,
+	}}, warehouseID)
+	if err != nil {
+		return "", err
+	}
+
+	return movements[0].ID, nil
+}
+
+// category finds a category by its name, which has to be unique.
+func (im *importer) category(ctx context.Context, name string) (string, error) {
+	key := "category:" + strings.ToLower(name)
+	if id, ok := im.cache[key]; ok {
+		return id, nil
+	}
+
+	categories, err := im.uc.CategoryRepo.GetList(ctx, entity.GetListFilter{
+		Limit:   2,
+		Filters: []entity.Filter{{Column: "name", Type: "eq", Value: name}},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	switch len(categories.Items) {
+	case 0:
+		return "", importFieldError("category", fmt.Sprintf("%q is not a category", name))
+	case 1:
+		im.cache[key] = categories.Items[0].ID
+		return categories.Items[0].ID, nil
+	}
+
+	return "", importFieldError("category", fmt.Sprintf("%q names more than one category", name))
+}
+
+// warehouse finds a warehouse by its code.
+func (im *importer) warehouse(ctx context.Context, code string) (string, error) {
+	key := "warehouse:" + code
+	if id, ok := im.cache[key]; ok {
+		return id, nil
+	}
+
+	warehouses, err := im.uc.WarehouseRepo.GetList(ctx, entity.GetListFilter{
+		Limit:   1,
+		Filters: []entity.Filter{{Column: "code", Type: "eq", Value: code}},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if len(warehouses.Items) == 0 {
+		return "", importFieldError("warehouse", fmt.Sprintf("%q is not a warehouse code", code))
+	}
+
+	im.cache[key] = warehouses.Items[0].ID
+
+	return warehouses.Items[0].ID, nil
+}
+
+// bin finds a bin of a warehouse by its code. Virtual bins hold no counted stock.
+func (im *importer) bin(ctx context.Context, warehouseID, code string) (string, error) {
+	key := "bin:" + warehouseID + ":" + code
+	if id, ok := im.cache[key]; ok {
+		return id, nil
+	}
+
+	bins, err := im.uc.BinRepo.GetList(ctx, entity.GetListFilter{
+		Limit: 1,
+		Filters: []entity.Filter{
+			{Column: "warehouse_id", Type: "eq", Value: warehouseID},
+			{Column: "code", Type: "eq", Value: code},
+			{Column: "is_virtual", Type: "eq", Value: "false"},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if len(bins.Items) == 0 {
+		return "", importFieldError("bin", fmt.Sprintf("%q is not a bin of the warehouse", code))
+	}
+
+	im.cache[key] = bins.Items[0].ID
+
+	return bins.Items[0].ID, nil
+}
+
+// importCells reads the cells of a row by the field they are mapped to.
+type importCells struct {
+	columns map[string]int
+	cells   []string
+}
+
+// get returns the cell of field, or an empty string when field isn't mapped.
+func (c importCells) get(field string) string {
+	i, ok := c.columns[field]
+	if !ok || i >= len(c.cells) {
+		return ""
+	}
+
+	return c.cells[i]
+}
+
+func importFieldError(field, message string) error {
+	return entity.NewError(config.ErrorBadRequest, field+" "+message)
+}
+
+// splitImportList splits a cell holding several values, like barcodes.
+func splitImportList(value string) []string {
+	var response []string
+	for _, item := range strings.FieldsFunc(value, func(r rune) bool { return r == ';' || r == ',' || r == '\n' }) {
+		item = strings.TrimSpace(item)
+		if item != "" && !contains(response, item) {
+			response = append(response, item)
+		}
+	}
+
+	return response
+}
+
+// parseImportNumber reads numbers written with spaces between thousands and
+// with a comma or a dot before the decimals.
+func parseImportNumber(field, value string) (float64, error) {
+	value = strings.NewReplacer(" ", "", "\u00a0", "", "\u202f", "").Replace(value)
+	if !strings.Contains(value, ".") {
+		value = strings.Replace(value, ",", ".", 1)
+	}
+
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, importFieldError(field, "is not a number")
+	}
+
+	return n, nil
+}
+
+// parseImportBool reads yes and no in the ways people write them.
+func parseImportBool(field, value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "1", "true", "yes", "y", "ha", "да", "+":
+		return true, nil
+	case "0", "false", "no", "n", "yo'q", "yoq", "нет", "-":
+		return false, nil
+	}
+
+	return false, importFieldError(field, "has to be yes or no")
+}
+
+// parseImportDate reads YYYY-MM-DD, DD.MM.YYYY and the day numbers XLSX files
+// keep dates as. It returns YYYY-MM-DD, or an empty string for an empty value.
+func parseImportDate(field, value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	for _, layout := range []string{"2006-01-02", "02.01.2006"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.Format("2006-01-02"), nil
+		}
+	}
+
+	if days, err := strconv.ParseFloat(value, 64); err == nil && days >= 1 && days < 2958466 {
+		return time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC).AddDate(0, 0, int(days)).Format("2006-01-02"), nil
+	}
+
+	return "", importFieldError(field, "is not a date")
+}
@@ -0,0 +1,182 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/xlsx"
+)
+
+// importAliases are the headers, besides the field name itself, a column is
+// mapped to a field by on upload.
+var importAliases = map[string][]string{
+	"sku":           {"artikul", "артикул", "код"},
+	"name":          {"nomi", "nomlanishi", "наименование", "название"},
+	"description":   {"tavsif", "описание"},
+	"barcodes":      {"barcode", "shtrix kod", "shtrixkod", "штрихкод", "штрих-код"},
+	"category":      {"kategoriya", "категория"},
+	"base_unit":     {"unit", "birlik", "o'lchov birligi", "единица", "ед. изм."},
+	"sale_price":    {"price", "narx", "narxi", "цена"},
+	"is_serialized": {"serialized", "серийный"},
+	"is_active":     {"active", "faol", "активен"},
+	"warehouse":     {"ombor", "склад"},
+	"bin":           {"yacheyka", "ячейка"},
+	"quantity":      {"qty", "miqdor", "miqdori", "soni", "количество", "кол-во"},
+	"unit_cost":     {"cost", "tannarx", "себестоимость"},
+	"lot_number":    {"lot", "partiya", "партия"},
+	"expiry_date":   {"expiry", "yaroqlilik muddati", "срок годности"},
+	"serials":       {"serial", "serial numbers", "seriya raqami", "серийный номер", "серийные номера"},
+}
+
+// parseImportFile reads the header and the rows of a CSV or XLSX file. The
+// first row that isn't empty is the header, empty rows are left out and the
+// cells of the rest are trimmed to the header.
+func parseImportFile(fileName string, data []byte) ([]string, []entity.ImportRow, error) {
+	var (
+		records [][]string
+		lines   []int
+		err     error
+	)
+
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".csv":
+		records, lines, err = readCSV(data)
+	case ".xlsx":
+		records, err = xlsx.Read(data)
+		for i := range records {
+			lines = append(lines, i+1)
+		}
+	default:
+		return nil, nil, ErrImportFormat
+	}
+	if err != nil {
+		return nil, nil, ErrImportUnreadable
+	}
+
+	var (
+		columns []string
+		rows    []entity.ImportRow
+	)
+
+	for i, record := range records {
+		for j := range record {
+			record[j] = strings.TrimSpace(record[j])
+		}
+
+		if strings.Join(record, "") == "" {
+			continue
+		}
+
+		if columns == nil {
+			columns = importColumns(record)
+			continue
+		}
+
+		if len(record) > len(columns) {
+			record = record[:len(columns)]
+		}
+
+		rows = append(rows, entity.ImportRow{RowNumber: lines[i], Cells: record})
+	}
+
+	if len(rows) == 0 {
+		return nil, nil, ErrImportEmpty
+	}
+
+	return columns, rows, nil
+}
+
+// readCSV reads a CSV file separated by commas, semicolons, as Excel saves
+// them in many locales, or tabs. It returns the line every record starts on.
+func readCSV(data []byte) ([][]string, []int, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	firstLine := data
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		firstLine = data[:i]
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.LazyQuotes = true
+	reader.FieldsPerRecord = -1
+	for _, comma := range []rune{';', '\t'} {
+		if bytes.Count(firstLine, []byte(string(comma))) > bytes.Count(firstLine, []byte(string(reader.Comma))) {
+			reader.Comma = comma
+		}
+	}
+
+	var (
+		records [][]string
+		lines   []int
+	)
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+		records = append(records, record)
+		lines = append(lines, line)
+	}
+
+	return records, lines, nil
+}
+
+// importColumns names the columns of a header row. Columns without a name
+// are named by their position, repeated names get a number.
+func importColumns(header []string) []string {
+	var (
+		columns = make([]string, len(header))
+		seen    = map[string]int{}
+	)
+
+	for i, name := range header {
+		if name == "" {
+			name = fmt.Sprintf("column %d", i+1)
+		}
+
+		seen[name]++
+		if seen[name] > 1 {
+			name = fmt.Sprintf("%s (%d)", name, seen[name])
+		}
+
+		columns[i] = name
+	}
+
+	return columns
+}
+
+// guessImportMapping maps the fields of an import to the columns named like
+// them or one of their aliases.
+func guessImportMapping(kind string, columns []string) map[string]string {
+	mapping := map[string]string{}
+
+	for _, field := range entity.ImportFields[kind] {
+		names := append([]string{field.Name, strings.ReplaceAll(field.Name, "_", " ")}, importAliases[field.Name]...)
+
+		for _, column := range columns {
+			header := strings.ToLower(strings.TrimSpace(column))
+			for _, name := range names {
+				if header == name {
+					mapping[field.Name] = column
+				}
+			}
+
+			if mapping[field.Name] != "" {
+				break
+			}
+		}
+	}
+
+	return mapping
+}
@@ -3,8 +3,10 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/cbu"
 )
 
 //go:generate mockgen -source=interfaces.go -destination=./mocks_test.go -package=usecase_test
@@ -28,4 +30,347 @@ type (
 		Delete(ctx context.Context, req entity.Id) error
 		UpdateField(ctx context.Context, req entity.UpdateFieldRequest) (entity.RowsEffected, error)
 	}
+
+	// ProductRepo -.
+	ProductRepoI interface {
+		Create(ctx context.Context, req entity.Product) (entity.Product, error)
+		GetSingle(ctx context.Context, req entity.ProductSingleRequest) (entity.Product, error)
+		GetList(ctx context.Context, req entity.GetListFilter) (entity.ProductList, error)
+		Update(ctx context.Context, req entity.Product) (entity.Product, error)
+		Delete(ctx context.Context, req entity.Id) error
+		GetComponents(ctx context.Context, productID string) ([]entity.ProductComponent, error)
+		SetComponents(ctx context.Context, req entity.ProductComponentsRequest) (entity.Product, error)
+		GetReportUnits(ctx context.Context, unit string, productIDs []string) (map[string]entity.ProductUnit, error)
+	}
+
+	// CategoryRepo -.
+	CategoryRepoI interface {
+		Create(ctx context.Context, req entity.Category) (entity.Category, error)
+		GetSingle(ctx context.Context, req entity.Id) (entity.Category, error)
+		GetList(ctx context.Context, req entity.GetListFilter) (entity.CategoryList, error)
+		GetSubtree(ctx context.Context, rootID string) ([]entity.Category, error)
+		Update(ctx context.Context, req entity.Category) (entity.Category, error)
+		Move(ctx context.Context, req entity.CategoryMoveRequest) (entity.Category, error)
+		Delete(ctx context.Context, req entity.Id) error
+		GetProductCounts(ctx context.Context, rootID string) ([]entity.CategoryProductCount, error)
+	}
+
+	// WarehouseRepo -.
+	WarehouseRepoI interface {
+		Create(ctx context.Context, req entity.Warehouse) (entity.Warehouse, error)
+		GetSingle(ctx context.Context, req entity.Id) (entity.Warehouse, error)
+		GetList(ctx context.Context, req entity.GetListFilter) (entity.WarehouseList, error)
+		Update(ctx context.Context, req entity.Warehouse) (entity.Warehouse, error)
+		Delete(ctx context.Context, req entity.Id) error
+	}
+
+	// ZoneRepo -.
+	ZoneRepoI interface {
+		Create(ctx context.Context, req entity.Zone) (entity.Zone, error)
+		GetSingle(ctx context.Context, req entity.Id) (entity.Zone, error)
+		GetList(ctx context.Context, req entity.GetListFilter) (entity.ZoneList, error)
+		Update(ctx context.Context, req entity.Zone) (entity.Zone, error)
+		Delete(ctx context.Context, req entity.Id) error
+	}
+
+	// BinRepo -.
+	BinRepoI interface {
+		Create(ctx context.Context, req entity.Bin) (entity.Bin, error)
+		GetSingle(ctx context.Context, req entity.Id) (entity.Bin, error)
+		GetList(ctx context.Context, req entity.GetListFilter) (entity.BinList, error)
+		Update(ctx context.Context, req entity.Bin) (entity.Bin, error)
+		Delete(ctx context.Context, req entity.Id) error
+		GetTransitBin(ctx context.Context, warehouseID string) (string, error)
+	}
+
+	// StockRepo -.
+	StockRepoI interface {
+		CreateMovements(ctx context.Context, req []entity.StockMovement) ([]entity.StockMovement, error)
+		LockBalance(ctx context.Context, productID, warehouseID, binID, lotID string) (float64, error)
+		GetMovements(ctx context.Context, req entity.GetListFilter) (entity.StockMovementList, error)
+		GetBalances(ctx context.Context, req entity.GetListFilter) (entity.StockBalanceList, error)
+		GetLotPicks(ctx context.Context, productID, warehouseID, binID string) ([]entity.LotPick, error)
+		GetExpiring(ctx context.Context, req entity.GetListFilter, days int) (entity.ExpiringLotList, error)
+	}
+
+	// GoodsReceiptRepo -.
+	GoodsReceiptRepoI interface {
+		Create(ctx context.Context, req entity.GoodsReceipt) (entity.GoodsReceipt, error)
+		GetSingle(ctx context.Context, req entity.Id) (entity.GoodsReceipt, error)
+		GetList(ctx context.Context, req entity.GetListFilter) (entity.GoodsReceiptList, error)
+		Update(ctx context.Context, req entity.GoodsReceipt) (entity.GoodsReceipt, error)
+		Delete(ctx context.Context, req entity.Id) error
+		LockStatus(ctx context.Context, req entity.Id) (string, error)
+		SetStatus(ctx context.Context, req entity.Id, status string) error
+	}
+
+	// SalesOrderRepo -.
+	SalesOrderRepoI interface {
+		Create(ctx context.Context, req entity.SalesOrder) (entity.SalesOrder, error)
+		GetSingle(ctx context.Context, req entity.Id) (entity.SalesOrder, error)
+		GetList(ctx context.Context, req entity.GetListFilter) (entity.SalesOrderList, error)
+		Update(ctx context.Context, req entity.SalesOrder) (entity.SalesOrder, error)
+		Delete(ctx context.Context, req entity.Id) error
+		LockStatus(ctx context.Context, req entity.Id) (string, error)
+		SetStatus(ctx context.Context, req entity.Id, status string) error
+		AddShipped(ctx context.Context, orderID, lineID string, quantity float64) error
+		AddShorted(ctx context.Context, orderID, lineID string, quantity float64) error
+		AddReturned(ctx context.Context, orderID, lineID string, quantity float64) (entity.SalesOrderLine, error)
+	}
+
+	// SupplierRepo -.
+	SupplierRepoI interface {
+		Create(ctx context.Context, req entity.Supplier) (entity.Supplier, error)
+		GetSingle(ctx context.Context, req entity.Id) (entity.Supplier, error)
+		GetList(ctx context.Context, req entity.GetListFilter) (entity.SupplierList, error)
+		Update(ctx context.Context, req entity.Supplier) (entity.Supplier, error)
+		Delete(ctx context.Context, req entity.Id) error
+		GetStatement(ctx context.Context, supplierID, from, to string) (entity.SupplierStatement, error)
+		GetDebtAging(ctx context.Context, req entity.GetListFilter) (entity.SupplierDebtAgingReport, error)
+	}
+
+	// SupplierCreditRepo -.
+	SupplierCreditRepoI interface {
+		Create(ctx context.Context, req entity.SupplierCredit) (entity.SupplierCredit, error)
+		GetSingle(ctx context.Context, req entity.Id) (entity.SupplierCredit, error)
+		GetList(ctx context.Context, req entity.GetListFilter) (entity.SupplierCreditList, error)
+		Update(ctx context.Context, req entity.SupplierCredit) (entity.SupplierCredit, error)
+		Delete(ctx context.Context, req entity.Id) error
+		SetMatchStatus(ctx context.Context, req entity.Id, status string) error
+		GetIDsByGoodsReceipt(ctx context.Context, goodsReceiptID string) ([]string, error)
+	}
+
+	// SupplierPaymentRepo -.
+	SupplierPaymentRepoI interface {
+		Create(ctx context.Context, req entity.SupplierPayment) (entity.SupplierPayment, error)
+		GetSingle(ctx context.Context, req entity.Id) (entity.SupplierPayment, error)
+		GetList(ctx context.Context, req entity.GetListFilter) (entity.SupplierPaymentList, error)
+		Delete(ctx context.Context, req entity.Id) error
+	}
+
+	// CustomerRepo -.
+	CustomerRepoI interface {
+		Create(ctx context.Context, req entity.Customer) (entity.Customer, error)
+		GetSingle(ctx context.Context, req entity.Id) (entity.Customer, error)
+		GetList(ctx context.Context, req entity.GetListFilter) (entity.CustomerList, error)
+		Update(ctx context.Context, req entity.Customer) (entity.Customer, error)
+		Delete(ctx context.Context, req entity.Id) error
+		GetDebtAging(ctx context.Context, req entity.GetListFilter) (entity.DebtAgingReport, error)
+	}
+
+	// CustomerPaymentRepo -.
+	CustomerPaymentRepoI interface {
+		Create(ctx context.Context, req entity.CustomerPayment) (entity.CustomerPayment, error)
+		GetSingle(ctx context.Context, req entity.Id) (entity.CustomerPayment, error)
+		GetList(ctx context.Context, req entity.GetListFilter) (entity.CustomerPaymentList, error)
+		Delete(ctx context.Context, req entity.Id) error
+	}
+
+	// TransferRepo -.
+	TransferRepoI interface {
+		Create(ctx context.Context, req entity.Transfer) (entity.Transfer, error)
+		GetSingle(ctx context.Context, req entity.Id) (entity.Transfer, error)
+		GetList(ctx context.Context, req entity.GetListFilter) (entity.TransferList, error)
+		Update(ctx context.Context, req entity.Transfer) (entity.Transfer, error)
+		Delete(ctx context.Context, req entity.Id) error
+		LockStatus(ctx context.Context, req entity.Id) (string, error)
+		SetStatus(ctx context.Context, req entity.Id, status, userID string) error
+		ReplaceLines(ctx context.Context, transferID string, lines []entity.TransferLine) error
+		SetTransitBin(ctx context.Context, req entity.Id, binID string) error
+		SetReceived(ctx context.Context, transferID, lineID string, quantity float64) error
+		CreateDiscrepancy(ctx context.Context, req entity.TransferDiscrepancy) error
+	}
+
+	// StocktakeRepo -.
+	StocktakeRepoI interface {
+		Create(ctx context.Context, req entity.Stocktake) (entity.Stocktake, error)
+		GetSingle(ctx context.Context, req entity.Id) (entity.Stocktake, error)
+		GetList(ctx context.Context, req entity.GetListFilter) (entity.StocktakeList, error)
+		GetSheet(ctx context.Context, req entity.Id) (entity.StocktakeSheet, error)
+		LockStatus(ctx context.Context, req entity.Id) (string, error)
+		SetStatus(ctx context.Context, req entity.Id, status, userID string) error
+		CreateCounts(ctx context.Context, req entity.StocktakeCountRequest, userID string) error
+		GetCounts(ctx context.Context, req entity.Id) ([]entity.StocktakeCount, error)
+		GetMovedSinceCount(ctx context.Context, stocktakeID, productID, binID, lotID string) (float64, error)
+	}
+
+	// LotRepo -.
+	LotRepoI interface {
+		GetOrCreate(ctx context.Context, productID, lotNumber, expiryDate string) (entity.Lot, error)
+		GetList(ctx context.Context, req entity.GetListFilter) (entity.LotList, error)
+	}
+
+	// SerialRepo -.
+	SerialRepoI interface {
+		Receive(ctx context.Context, productID, binID, lotID string, serials []string) error
+		Move(ctx context.Context, req entity.SerialMove) ([]entity.Serial, error)
+		GetSingle(ctx context.Context, serialNumber string) (entity.Serial, error)
+		GetList(ctx context.Context, req entity.GetListFilter) (entity.SerialList, error)
+		GetHistory(ctx context.Context, serialNumber string) (entity.SerialHistory, error)
+	}
+
+	// SettingsRepo -.
+	SettingsRepoI interface {
+		GetSingle(ctx context.Context) (entity.CompanySettings, error)
+		Update(ctx context.Context, req entity.CompanySettings) (entity.CompanySettings, error)
+	}
+
+	// CostingRepo -.
+	CostingRepoI interface {
+		LockLayers(ctx context.Context, productID, warehouseID string) ([]entity.CostLayer, error)
+		CreateLayer(ctx context.Context, req entity.CostLayer) (entity.CostLayer, error)
+		UpdateLayer(ctx context.Context, req entity.CostLayer) error
+		SetMovementCost(ctx context.Context, movementID string, unitCost, cost float64) error
+		GetDocumentOutbound(ctx context.Context, documentID, productID string) (float64, float64, error)
+		GetDocumentInbound(ctx context.Context, documentID, productID, warehouseID, reason string) ([]entity.StockMovement, error)
+		GetLastUnitCost(ctx context.Context, productID string) (float64, error)
+		GetValuation(ctx context.Context, req entity.GetListFilter, asOf string) (entity.StockValuationReport, error)
+		GetCostOfGoodsSold(ctx context.Context, req entity.GetListFilter, from, to string) (entity.CostOfGoodsSoldReport, error)
+	}
+
+	// ReplenishmentRepo -.
+	ReplenishmentRepoI interface {
+		SetLevel(ctx context.Context, req entity.StockLevel) (entity.StockLevel, error)
+		GetLevels(ctx context.Context, req entity.GetListFilter) (entity.StockLevelList, error)
+		DeleteLevel(ctx context.Context, productID, warehouseID string) error
+		GetSuggestions(ctx context.Context, req entity.GetListFilter) (entity.ReplenishmentSuggestionList, error)
+	}
+
+	// PurchaseOrderRepo -.
+	PurchaseOrderRepoI interface {
+		Create(ctx context.Context, req entity.PurchaseOrder) (entity.PurchaseOrder, error)
+		GetSingle(ctx context.Context, req entity.Id) (entity.PurchaseOrder, error)
+		GetList(ctx context.Context, req entity.GetListFilter) (entity.PurchaseOrderList, error)
+		Update(ctx context.Context, req entity.PurchaseOrder) (entity.PurchaseOrder, error)
+		Delete(ctx context.Context, req entity.Id) error
+		LockStatus(ctx context.Context, req entity.Id) (string, error)
+		SetStatus(ctx context.Context, req entity.Id, status string) error
+		AddReceived(ctx context.Context, orderID, lineID, productID string, quantity float64) error
+		RefreshStatus(ctx context.Context, req entity.Id) error
+	}
+
+	// ReservationRepo -.
+	ReservationRepoI interface {
+		LockAvailable(ctx context.Context, productID, warehouseID string) (float64, error)
+		Create(ctx context.Context, req []entity.StockReservation, hours int) error
+		Release(ctx context.Context, orderID string) error
+		GetList(ctx context.Context, req entity.GetListFilter) (entity.StockReservationList, error)
+		GetAvailability(ctx context.Context, req entity.GetListFilter) (entity.StockAvailabilityList, error)
+	}
+
+	// PickListRepo -.
+	PickListRepoI interface {
+		Create(ctx context.Context, req entity.PickList) (entity.PickList, error)
+		GetSingle(ctx context.Context, req entity.Id) (entity.PickList, error)
+		GetByOrder(ctx context.Context, orderID string) (entity.PickList, error)
+		GetList(ctx context.Context, req entity.GetListFilter) (entity.PickListList, error)
+		LockStatus(ctx context.Context, req entity.Id) (string, error)
+		SetStatus(ctx context.Context, req entity.Id, status string) error
+		CancelOpen(ctx context.Context, orderID string) error
+		AddPicked(ctx context.Context, pickListID, lineID string, quantity float64, serial string) error
+		Short(ctx context.Context, pickListID, lineID, reason string) error
+	}
+
+	// ShipmentRepo -.
+	ShipmentRepoI interface {
+		SetParcels(ctx context.Context, orderID string, parcels []entity.Parcel) ([]entity.Parcel, error)
+		GetParcels(ctx context.Context, orderID string) ([]entity.Parcel, error)
+		Create(ctx context.Context, req entity.Shipment) (entity.Shipment, error)
+		GetSingle(ctx context.Context, req entity.Id) (entity.Shipment, error)
+		GetList(ctx context.Context, req entity.GetListFilter) (entity.ShipmentList, error)
+	}
+
+	// AssemblyRepo -.
+	AssemblyRepoI interface {
+		Create(ctx context.Context, req entity.Assembly) (entity.Assembly, error)
+		GetSingle(ctx context.Context, req entity.Id) (entity.Assembly, error)
+		GetList(ctx context.Context, req entity.GetListFilter) (entity.AssemblyList, error)
+		Delete(ctx context.Context, req entity.Id) error
+		LockStatus(ctx context.Context, req entity.Id) (string, error)
+		SetStatus(ctx context.Context, req entity.Id, status string) error
+	}
+
+	// PriceListRepo -.
+	PriceListRepoI interface {
+		Create(ctx context.Context, req entity.PriceList) (entity.PriceList, error)
+		GetSingle(ctx context.Context, req entity.Id) (entity.PriceList, error)
+		GetList(ctx context.Context, req entity.GetListFilter) (entity.PriceListList, error)
+		Update(ctx context.Context, req entity.PriceList) (entity.PriceList, error)
+		Delete(ctx context.Context, req entity.Id) error
+		GetPrice(ctx context.Context, priceListID, productID string, quantity float64, date string) (entity.PriceListItem, error)
+	}
+
+	// PromotionRepo -.
+	PromotionRepoI interface {
+		Create(ctx context.Context, req entity.Promotion) (entity.Promotion, error)
+		GetSingle(ctx context.Context, req entity.Id) (entity.Promotion, error)
+		GetList(ctx context.Context, req entity.GetListFilter) (entity.PromotionList, error)
+		Update(ctx context.Context, req entity.Promotion) (entity.Promotion, error)
+		Delete(ctx context.Context, req entity.Id) error
+		GetActive(ctx context.Context, productID, date string) ([]entity.Promotion, error)
+	}
+
+	// CurrencyRepo -.
+	CurrencyRepoI interface {
+		Create(ctx context.Context, req entity.Currency) (entity.Currency, error)
+		GetSingle(ctx context.Context, code string) (entity.Currency, error)
+		GetList(ctx context.Context, req entity.GetListFilter) (entity.CurrencyList, error)
+		Update(ctx context.Context, req entity.Currency) (entity.Currency, error)
+		Delete(ctx context.Context, code string) error
+		SetRates(ctx context.Context, rates []entity.ExchangeRate) ([]entity.ExchangeRate, error)
+		GetRates(ctx context.Context, req entity.GetListFilter) (entity.ExchangeRateList, error)
+		DeleteRate(ctx context.Context, currency, date string) error
+		GetRate(ctx context.Context, currency, date string) (string, float64, error)
+	}
+
+	// CashDeskRepo -.
+	CashDeskRepoI interface {
+		Create(ctx context.Context, req entity.CashDesk) (entity.CashDesk, error)
+		GetSingle(ctx context.Context, req entity.Id) (entity.CashDesk, error)
+		GetList(ctx context.Context, req entity.GetListFilter) (entity.CashDeskList, error)
+		Update(ctx context.Context, req entity.CashDesk) (entity.CashDesk, error)
+		Delete(ctx context.Context, req entity.Id) error
+		CreateOperation(ctx context.Context, req entity.CashOperation) (entity.CashOperation, error)
+		GetOperation(ctx context.Context, req entity.Id) (entity.CashOperation, error)
+		GetOperations(ctx context.Context, req entity.GetListFilter) (entity.CashOperationList, error)
+		DeleteOperation(ctx context.Context, req entity.Id) error
+		CloseDay(ctx context.Context, req entity.CashDayClose) (entity.CashDayClose, error)
+		GetDayClose(ctx context.Context, req entity.Id) (entity.CashDayClose, error)
+		GetDayCloses(ctx context.Context, req entity.GetListFilter) (entity.CashDayCloseList, error)
+		DeleteDayClose(ctx context.Context, req entity.Id) error
+		GetLedger(ctx context.Context, cashDeskID, from, to string) (entity.CashLedger, error)
+	}
+
+	// DocumentTemplateRepo -.
+	DocumentTemplateRepoI interface {
+		GetSingle(ctx context.Context, form string) (entity.DocumentTemplate, error)
+		GetList(ctx context.Context) (entity.DocumentTemplateList, error)
+		Update(ctx context.Context, req entity.DocumentTemplate) (entity.DocumentTemplate, error)
+	}
+
+	// ImportRepo -.
+	ImportRepoI interface {
+		Create(ctx context.Context, req entity.ImportJob, rows []entity.ImportRow) (entity.ImportJob, error)
+		GetSingle(ctx context.Context, req entity.Id) (entity.ImportJob, error)
+		GetList(ctx context.Context, req entity.GetListFilter) (entity.ImportJobList, error)
+		Delete(ctx context.Context, req entity.Id) error
+		Queue(ctx context.Context, req entity.ImportRunRequest) (entity.ImportJob, error)
+		Claim(ctx context.Context, stale time.Duration) (entity.ImportJob, error)
+		GetPendingRows(ctx context.Context, jobID string, limit int) ([]entity.ImportRow, error)
+		GetRows(ctx context.Context, req entity.GetListFilter) (entity.ImportRowList, error)
+		SetRow(ctx context.Context, req entity.ImportRow) error
+		Progress(ctx context.Context, jobID string, created, updated int) error
+		Finish(ctx context.Context, jobID, status, errText string) error
+	}
+
+	// RateSource returns the official exchange rates on a date.
+	RateSourceI interface {
+		Rates(ctx context.Context, date string) ([]cbu.Rate, error)
+	}
+
+	// Transactor runs fn in a single database transaction. Repo calls made with
+	// the ctx passed to fn take part in it.
+	Transactor interface {
+		WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+	}
 )
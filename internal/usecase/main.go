@@ -3,20 +3,94 @@ package usecase
 import (
 	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
 	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/usecase/repo"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/cbu"
 	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/logger"
 	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/postgres"
 )
 
 // UseCase -.
 type UseCase struct {
-	UserRepo         UserRepoI
-	SessionRepo      SessionRepoI
+	UserRepo             UserRepoI
+	SessionRepo          SessionRepoI
+	ProductRepo          ProductRepoI
+	CategoryRepo         CategoryRepoI
+	WarehouseRepo        WarehouseRepoI
+	ZoneRepo             ZoneRepoI
+	BinRepo              BinRepoI
+	StockRepo            StockRepoI
+	GoodsReceiptRepo     GoodsReceiptRepoI
+	SalesOrderRepo       SalesOrderRepoI
+	SupplierRepo         SupplierRepoI
+	SupplierCreditRepo   SupplierCreditRepoI
+	SupplierPaymentRepo  SupplierPaymentRepoI
+	CustomerRepo         CustomerRepoI
+	CustomerPaymentRepo  CustomerPaymentRepoI
+	TransferRepo         TransferRepoI
+	StocktakeRepo        StocktakeRepoI
+	LotRepo              LotRepoI
+	SerialRepo           SerialRepoI
+	SettingsRepo         SettingsRepoI
+	CostingRepo          CostingRepoI
+	ReplenishmentRepo    ReplenishmentRepoI
+	PurchaseOrderRepo    PurchaseOrderRepoI
+	ReservationRepo      ReservationRepoI
+	PickListRepo         PickListRepoI
+	ShipmentRepo         ShipmentRepoI
+	AssemblyRepo         AssemblyRepoI
+	PriceListRepo        PriceListRepoI
+	PromotionRepo        PromotionRepoI
+	CurrencyRepo         CurrencyRepoI
+	RateSource           RateSourceI
+	CashDeskRepo         CashDeskRepoI
+	DocumentTemplateRepo DocumentTemplateRepoI
+	ImportRepo           ImportRepoI
+	Tx                   Transactor
+
+	config     *config.Config
+	logger     *logger.Logger
+	importWake chan struct{} // signals RunImports that a job was queued
 }
 
 // New -.
 func New(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *UseCase {
 	return &UseCase{
-		UserRepo:         repo.NewUserRepo(pg, config, logger),
-		SessionRepo:      repo.NewSessionRepo(pg, config, logger),
+		UserRepo:             repo.NewUserRepo(pg, config, logger),
+		SessionRepo:          repo.NewSessionRepo(pg, config, logger),
+		ProductRepo:          repo.NewProductRepo(pg, config, logger),
+		CategoryRepo:         repo.NewCategoryRepo(pg, config, logger),
+		WarehouseRepo:        repo.NewWarehouseRepo(pg, config, logger),
+		ZoneRepo:             repo.NewZoneRepo(pg, config, logger),
+		BinRepo:              repo.NewBinRepo(pg, config, logger),
+		StockRepo:            repo.NewStockRepo(pg, config, logger),
+		GoodsReceiptRepo:     repo.NewGoodsReceiptRepo(pg, config, logger),
+		SalesOrderRepo:       repo.NewSalesOrderRepo(pg, config, logger),
+		SupplierRepo:         repo.NewSupplierRepo(pg, config, logger),
+		SupplierCreditRepo:   repo.NewSupplierCreditRepo(pg, config, logger),
+		SupplierPaymentRepo:  repo.NewSupplierPaymentRepo(pg, config, logger),
+		CustomerRepo:         repo.NewCustomerRepo(pg, config, logger),
+		CustomerPaymentRepo:  repo.NewCustomerPaymentRepo(pg, config, logger),
+		TransferRepo:         repo.NewTransferRepo(pg, config, logger),
+		StocktakeRepo:        repo.NewStocktakeRepo(pg, config, logger),
+		LotRepo:              repo.NewLotRepo(pg, config, logger),
+		SerialRepo:           repo.NewSerialRepo(pg, config, logger),
+		SettingsRepo:         repo.NewSettingsRepo(pg, config, logger),
+		CostingRepo:          repo.NewCostingRepo(pg, config, logger),
+		ReplenishmentRepo:    repo.NewReplenishmentRepo(pg, config, logger),
+		PurchaseOrderRepo:    repo.NewPurchaseOrderRepo(pg, config, logger),
+		ReservationRepo:      repo.NewReservationRepo(pg, config, logger),
+		PickListRepo:         repo.NewPickListRepo(pg, config, logger),
+		ShipmentRepo:         repo.NewShipmentRepo(pg, config, logger),
+		AssemblyRepo:         repo.NewAssemblyRepo(pg, config, logger),
+		PriceListRepo:        repo.NewPriceListRepo(pg, config, logger),
+		PromotionRepo:        repo.NewPromotionRepo(pg, config, logger),
+		CurrencyRepo:         repo.NewCurrencyRepo(pg, config, logger),
+		RateSource:           cbu.NewSource(config.CBU.RatesURL),
+		CashDeskRepo:         repo.NewCashDeskRepo(pg, config, logger),
+		DocumentTemplateRepo: repo.NewDocumentTemplateRepo(pg, config, logger),
+		ImportRepo:           repo.NewImportRepo(pg, config, logger),
+		Tx:                   pg,
+		config:               config,
+		logger:               logger,
+		importWake:           make(chan struct{}, 1),
 	}
 }
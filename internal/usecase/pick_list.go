@@ -0,0 +1,384 @@
+package usecase
+
+import (
+	"context"
+	"math"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+)
+
+// CreatePickList generates the pick list of a processing order and moves the
+// order to picking. Lines without a lot are split over the lots of their bin
+// first expired first out, whatever the bin can't cover stays on the list to be
+// picked or reported short.
+func (uc *UseCase) CreatePickList(ctx context.Context, req entity.PickListCreateRequest, userID string) (entity.PickList, error) {
+	var pickListID string
+
+	err := uc.Tx.WithTx(ctx, func(ctx context.Context) error {
+		status, err := uc.SalesOrderRepo.LockStatus(ctx, entity.Id{ID: req.OrderID})
+		if err != nil {
+			return err
+		}
+
+		if status != entity.OrderStatusProcessing {
+			return ErrInvalidStatusTransition
+		}
+
+		order, err := uc.SalesOrderRepo.GetSingle(ctx, entity.Id{ID: req.OrderID})
+		if err != nil {
+			return err
+		}
+
+		lines, err := uc.pickListLines(ctx, order)
+		if err != nil {
+			return err
+		}
+
+		if len(lines) == 0 {
+			return ErrEmptyDocument
+		}
+
+		pickList, err := uc.PickListRepo.Create(ctx, entity.PickList{
+			OrderID:     order.ID,
+			WarehouseID: order.WarehouseID,
+			CreatedBy:   userID,
+			Lines:       lines,
+		})
+		if err != nil {
+			return err
+		}
+		pickListID = pickList.ID
+
+		return uc.SalesOrderRepo.SetStatus(ctx, entity.Id{ID: order.ID}, entity.OrderStatusPicking)
+	})
+	if err != nil {
+		return entity.PickList{}, err
+	}
+
+	return uc.PickListRepo.GetSingle(ctx, entity.Id{ID: pickListID})
+}
+
+func (uc *UseCase) pickListLines(ctx context.Context, order entity.SalesOrder) ([]entity.PickListLine, error) {
+	var response []entity.PickListLine
+
+	for _, line := range order.Lines {
+		remaining := roundTo(line.Quantity-line.Shipped-line.Shorted, 3)
+		if remaining <= 0 {
+			continue
+		}
+
+		item := entity.PickListLine{
+			OrderLineID: line.ID,
+			ProductID:   line.ProductID,
+			BinID:       line.BinID,
+			LotID:       line.LotID,
+			Serials:     line.Serials,
+			Quantity:    remaining,
+		}
+
+		// serialized lines name their units, lines with a lot are picked as given
+		if len(line.Serials) > 0 || line.LotID != "" {
+			response = append(response, item)
+			continue
+		}
+
+		picks, err := uc.StockRepo.GetLotPicks(ctx, line.ProductID, order.WarehouseID, line.BinID)
+		if err != nil {
+			return nil, err
+		}
+
+		unlotted := -1
+		for _, pick := range picks {
+			if remaining <= 0 {
+				break
+			}
+
+			quantity := math.Min(pick.Available, remaining)
+			remaining = roundTo(remaining-quantity, 3)
+
+			if pick.LotID == "" {
+				unlotted = len(response)
+			}
+
+			lotItem := item
+			lotItem.LotID = pick.LotID
+			lotItem.Quantity = quantity
+			response = append(response, lotItem)
+		}
+
+		if remaining <= 0 {
+			continue
+		}
+
+		if unlotted >= 0 {
+			response[unlotted].Quantity += remaining
+			continue
+		}
+
+		item.Quantity = remaining
+		response = append(response, item)
+	}
+
+	return response, nil
+}
+
+// ScanPick confirms a pick by the scanned barcode or serial number. The
+// quantity goes to the first line of the product, in the scanned bin if one is
+// given, that still has that much left to pick. Barcodes of alternate units
+// count the base units one of them holds.
+func (uc *UseCase) ScanPick(ctx context.Context, req entity.PickScanRequest) (entity.PickList, error) {
+	err := uc.Tx.WithTx(ctx, func(ctx context.Context) error {
+		status, err := uc.PickListRepo.LockStatus(ctx, entity.Id{ID: req.PickListID})
+		if err != nil {
+			return err
+		}
+
+		if status != entity.PickListStatusOpen {
+			return ErrPickListNotOpen
+		}
+
+		var (
+			productID string
+			factor    = 1.0
+		)
+
+		if req.Serial != "" {
+			serial, err := uc.SerialRepo.GetSingle(ctx, req.Serial)
+			if err != nil {
+				return err
+			}
+			productID = serial.ProductID
+		}
+
+		if req.Barcode != "" {
+			product, err := uc.ProductRepo.GetSingle(ctx, entity.ProductSingleRequest{Barcode: req.Barcode})
+			if err != nil {
+				return err
+			}
+
+			if productID != "" && productID != product.ID {
+				return ErrNotOnPickList
+			}
+			productID = product.ID
+
+			// a box barcode picks a box worth of base units
+			for _, unit := range product.Units {
+				if unit.Unit == product.BarcodeUnit {
+					factor = unit.Factor
+				}
+			}
+		}
+
+		quantity := req.Quantity
+		if quantity == 0 {
+			quantity = 1
+		}
+		quantity = roundTo(quantity*factor, 3)
+
+		if req.Serial != "" && quantity != 1 {
+			return ErrSerialCount
+		}
+
+		pickList, err := uc.PickListRepo.GetSingle(ctx, entity.Id{ID: req.PickListID})
+		if err != nil {
+			return err
+		}
+
+		line, err := findPickLine(pickList, productID, req.BinID, req.Serial, quantity)
+		if err != nil {
+			return err
+		}
+
+		return uc.PickListRepo.AddPicked(ctx, pickList.ID, line.ID, quantity, req.Serial)
+	})
+	if err != nil {
+		return entity.PickList{}, err
+	}
+
+	return uc.PickListRepo.GetSingle(ctx, entity.Id{ID: req.PickListID})
+}
+
+// findPickLine picks the line a scan goes to. When no line has quantity left,
+// the first open one is returned and recording the pick fails as an over-pick.
+func findPickLine(pickList entity.PickList, productID, binID, serial string, quantity float64) (entity.PickListLine, error) {
+	var (
+		open  entity.PickListLine
+		found bool
+	)
+
+	for _, line := range pickList.Lines {
+		left := roundTo(line.Quantity-line.Picked-line.Shorted, 3)
+		if line.ProductID != productID || left <= 0 || (binID != "" && line.BinID != binID) {
+			continue
+		}
+
+		if len(line.Serials) > 0 {
+			if serial == "" {
+				return line, ErrSerialRequired
+			}
+
+			if !contains(line.Serials, serial) || contains(line.PickedSerials, serial) {
+				continue
+			}
+		}
+
+		if left >= quantity {
+			return line, nil
+		}
+
+		if !found {
+			open, found = line, true
+		}
+	}
+
+	if !found {
+		return open, ErrNotOnPickList
+	}
+
+	return open, nil
+}
+
+// ShortPick closes a line with what has been picked so far, the rest is
+// reported short.
+func (uc *UseCase) ShortPick(ctx context.Context, req entity.PickShortRequest) (entity.PickList, error) {
+	err := uc.Tx.WithTx(ctx, func(ctx context.Context) error {
+		status, err := uc.PickListRepo.LockStatus(ctx, entity.Id{ID: req.PickListID})
+		if err != nil {
+			return err
+		}
+
+		if status != entity.PickListStatusOpen {
+			return ErrPickListNotOpen
+		}
+
+		return uc.PickListRepo.Short(ctx, req.PickListID, req.LineID, req.Reason)
+	})
+	if err != nil {
+		return entity.PickList{}, err
+	}
+
+	return uc.PickListRepo.GetSingle(ctx, entity.Id{ID: req.PickListID})
+}
+
+// CompletePickList closes a pick list whose lines are all picked or reported
+// short and moves the order to picked. Short quantities are taken off the
+// order lines, they are neither shipped nor billed.
+func (uc *UseCase) CompletePickList(ctx context.Context, id string) (entity.PickList, error) {
+	err := uc.Tx.WithTx(ctx, func(ctx context.Context) error {
+		pickList, err := uc.lockPickList(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		var (
+			picked  float64
+			shorted = map[string]float64{}
+		)
+
+		for _, line := range pickList.Lines {
+			if roundTo(line.Quantity-line.Picked-line.Shorted, 3) > 0 {
+				return ErrPickIncomplete
+			}
+
+			picked += line.Picked
+			if line.Shorted > 0 {
+				shorted[line.OrderLineID] += line.Shorted
+			}
+		}
+
+		if picked <= 0 {
+			return ErrNothingPicked
+		}
+
+		order, err := uc.SalesOrderRepo.GetSingle(ctx, entity.Id{ID: pickList.OrderID})
+		if err != nil {
+			return err
+		}
+
+		for _, line := range order.Lines {
+			if shorted[line.ID] <= 0 {
+				continue
+			}
+
+			err = uc.SalesOrderRepo.AddShorted(ctx, order.ID, line.ID, shorted[line.ID])
+			if err != nil {
+				return err
+			}
+		}
+
+		err = uc.PickListRepo.SetStatus(ctx, entity.Id{ID: id}, entity.PickListStatusCompleted)
+		if err != nil {
+			return err
+		}
+
+		return uc.SalesOrderRepo.SetStatus(ctx, entity.Id{ID: order.ID}, entity.OrderStatusPicked)
+	})
+	if err != nil {
+		return entity.PickList{}, err
+	}
+
+	return uc.PickListRepo.GetSingle(ctx, entity.Id{ID: id})
+}
+
+// CancelPickList drops an open pick list and sends the order back to
+// processing, picked goods go back to their bins.
+func (uc *UseCase) CancelPickList(ctx context.Context, id string) (entity.PickList, error) {
+	err := uc.Tx.WithTx(ctx, func(ctx context.Context) error {
+		pickList, err := uc.lockPickList(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		err = uc.PickListRepo.SetStatus(ctx, entity.Id{ID: id}, entity.PickListStatusCancelled)
+		if err != nil {
+			return err
+		}
+
+		return uc.SalesOrderRepo.SetStatus(ctx, entity.Id{ID: pickList.OrderID}, entity.OrderStatusProcessing)
+	})
+	if err != nil {
+		return entity.PickList{}, err
+	}
+
+	return uc.PickListRepo.GetSingle(ctx, entity.Id{ID: id})
+}
+
+// lockPickList locks an open pick list together with its picking order, the
+// order first as changing the order status does.
+func (uc *UseCase) lockPickList(ctx context.Context, id string) (entity.PickList, error) {
+	pickList, err := uc.PickListRepo.GetSingle(ctx, entity.Id{ID: id})
+	if err != nil {
+		return pickList, err
+	}
+
+	orderStatus, err := uc.SalesOrderRepo.LockStatus(ctx, entity.Id{ID: pickList.OrderID})
+	if err != nil {
+		return pickList, err
+	}
+
+	status, err := uc.PickListRepo.LockStatus(ctx, entity.Id{ID: id})
+	if err != nil {
+		return pickList, err
+	}
+
+	if status != entity.PickListStatusOpen {
+		return pickList, ErrPickListNotOpen
+	}
+
+	if orderStatus != entity.OrderStatusPicking {
+		return pickList, ErrInvalidStatusTransition
+	}
+
+	// read again, scans may have landed before the lock
+	return uc.PickListRepo.GetSingle(ctx, entity.Id{ID: id})
+}
+
+func contains(items []string, item string) bool {
+	for _, s := range items {
+		if s == item {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,183 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+)
+
+// PriceLines resolves the price of order lines for a customer on date
+// (YYYY-MM-DD, today when empty). A line starts from the sale price of its
+// product, or from the price of the customer's price list for the line
+// quantity when the list is in effect and has one. The largest promotion in
+// effect on the product or its categories is taken off that. Prices are kept
+// in the base currency, for an order in another currency the result is
+// converted at its rate on date. A price entered on the line that differs from
+// the result is kept as entered manually. The rules applied are written to the
+// price rules of the line.
+func (uc *UseCase) PriceLines(ctx context.Context, customerID, currency, date string, lines []entity.SalesOrderLine) error {
+	var customer entity.Customer
+
+	if date == "" {
+		date = time.Now().Format("2006-01-02")
+	}
+
+	currency, rate, err := uc.CurrencyRepo.GetRate(ctx, currency, date)
+	if err != nil {
+		return err
+	}
+
+	if customerID != "" {
+		customer, err = uc.CustomerRepo.GetSingle(ctx, entity.Id{ID: customerID})
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := range lines {
+		err := uc.priceLine(ctx, customer, currency, rate, date, &lines[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// QuotePrices prices order lines the way a new order would get them.
+func (uc *UseCase) QuotePrices(ctx context.Context, req entity.PriceQuoteRequest) (entity.PriceQuote, error) {
+	err := uc.PriceLines(ctx, req.CustomerID, req.Currency, req.Date, req.Lines)
+	if err != nil {
+		return entity.PriceQuote{}, err
+	}
+
+	quote := entity.PriceQuote{Currency: req.Currency, Lines: req.Lines}
+	for _, line := range req.Lines {
+		quote.TotalAmount += line.Quantity * line.Price
+	}
+
+	quote.TotalAmount = roundTo(quote.TotalAmount, 2)
+
+	return quote, nil
+}
+
+func (uc *UseCase) priceLine(ctx context.Context, customer entity.Customer, currency string, rate float64, date string,
+	line *entity.SalesOrderLine) error {
+	product, err := uc.ProductRepo.GetSingle(ctx, entity.ProductSingleRequest{ID: line.ProductID})
+	if err != nil {
+		return err
+	}
+
+	line.Quantity, err = baseQuantity(product, *line)
+	if err != nil {
+		return err
+	}
+
+	price := product.SalePrice
+	rules := []string{"sale price: " + formatMoney(price)}
+
+	if customer.PriceListID != "" {
+		item, err := uc.PriceListRepo.GetPrice(ctx, customer.PriceListID, line.ProductID, line.Quantity, date)
+		if err != nil {
+			return err
+		}
+
+		if item.ProductID != "" {
+			rule := "price list " + customer.PriceListName
+			if item.MinQuantity > 0 {
+				rule += " from " + formatQuantity(item.MinQuantity) + " " + product.BaseUnit
+			}
+
+			price = item.Price
+			rules = []string{rule + ": " + formatMoney(price)}
+		}
+	}
+
+	listPrice := price
+
+	promotions, err := uc.PromotionRepo.GetActive(ctx, line.ProductID, date)
+	if err != nil {
+		return err
+	}
+
+	var (
+		best         entity.Promotion
+		bestDiscount float64
+	)
+
+	for _, promotion := range promotions {
+		if discount := promotionDiscount(promotion, listPrice); discount > bestDiscount {
+			best, bestDiscount = promotion, discount
+		}
+	}
+
+	if bestDiscount > 0 {
+		price = roundTo(listPrice-bestDiscount, 2)
+
+		off := formatMoney(best.Value)
+		if best.Type == entity.PromotionTypePercent {
+			off = formatQuantity(best.Value) + "%"
+		}
+
+		rules = append(rules, fmt.Sprintf("promotion %s -%s: %s", best.Name, off, formatMoney(price)))
+	}
+
+	if rate != 1 {
+		listPrice, price = roundTo(listPrice/rate, 2), roundTo(price/rate, 2)
+		rules = append(rules, fmt.Sprintf("in %s at %s: %s", currency, formatQuantity(rate), formatMoney(price)))
+	}
+
+	if line.Price > 0 && line.Price != price {
+		price = line.Price
+		rules = append(rules, "entered manually: "+formatMoney(price))
+	}
+
+	line.ListPrice, line.Price, line.PriceRules = listPrice, price, rules
+
+	return nil
+}
+
+// baseQuantity is the quantity of a line in the base unit of its product, the
+// same way the line is stored.
+func baseQuantity(product entity.Product, line entity.SalesOrderLine) (float64, error) {
+	if line.UnitQuantity <= 0 {
+		return line.Quantity, nil
+	}
+
+	if line.Unit == "" || line.Unit == product.BaseUnit {
+		return line.UnitQuantity, nil
+	}
+
+	for _, unit := range product.Units {
+		if unit.Unit == line.Unit {
+			return roundTo(line.UnitQuantity*unit.Factor, 3), nil
+		}
+	}
+
+	return 0, ErrUnknownUnit
+}
+
+// promotionDiscount is how much a promotion takes off price, never more than the price itself.
+func promotionDiscount(promotion entity.Promotion, price float64) float64 {
+	discount := promotion.Value
+	if promotion.Type == entity.PromotionTypePercent {
+		discount = roundTo(price*promotion.Value/100, 2)
+	}
+
+	if discount > price {
+		return price
+	}
+
+	return discount
+}
+
+func formatMoney(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
+
+func formatQuantity(quantity float64) string {
+	return strconv.FormatFloat(quantity, 'f', -1, 64)
+}
@@ -0,0 +1,218 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+)
+
+type currencyRepo struct {
+	CurrencyRepoI
+
+	rates map[string]float64
+}
+
+func (r *currencyRepo) GetRate(ctx context.Context, currency, date string) (string, float64, error) {
+	if currency == "" {
+		return "UZS", 1, nil
+	}
+
+	return currency, r.rates[currency], nil
+}
+
+type customerRepo struct {
+	CustomerRepoI
+
+	customers map[string]entity.Customer
+}
+
+func (r *customerRepo) GetSingle(ctx context.Context, req entity.Id) (entity.Customer, error) {
+	return r.customers[req.ID], nil
+}
+
+// priceListRepo keeps the quantity breaks of one price list.
+type priceListRepo struct {
+	PriceListRepoI
+
+	items []entity.PriceListItem
+}
+
+func (r *priceListRepo) GetPrice(ctx context.Context, priceListID, productID string, quantity float64, date string) (entity.PriceListItem, error) {
+	var response entity.PriceListItem
+	for _, item := range r.items {
+		if item.ProductID == productID && item.MinQuantity <= quantity && item.MinQuantity >= response.MinQuantity {
+			response = item
+		}
+	}
+
+	return response, nil
+}
+
+type promotionRepo struct {
+	PromotionRepoI
+
+	promotions map[string][]entity.Promotion
+}
+
+func (r *promotionRepo) GetActive(ctx context.Context, productID, date string) ([]entity.Promotion, error) {
+	return r.promotions[productID], nil
+}
+
+func TestPriceLines(t *testing.T) {
+	uc := &UseCase{
+		ProductRepo: &productRepo{products: map[string]entity.Product{
+			"juice": {ID: "juice", BaseUnit: "pcs", SalePrice: 12500, Units: []entity.ProductUnit{{Unit: "box", Factor: 12}}},
+			"tea":   {ID: "tea", BaseUnit: "kg", SalePrice: 100},
+			"gum":   {ID: "gum", BaseUnit: "pcs", SalePrice: 10},
+		}},
+		CustomerRepo: &customerRepo{customers: map[string]entity.Customer{
+			"shop": {ID: "shop", PriceListID: "wholesale", PriceListName: "Wholesale"},
+			"cafe": {ID: "cafe"},
+		}},
+		PriceListRepo: &priceListRepo{items: []entity.PriceListItem{
+			{ProductID: "juice", Price: 11000},
+			{ProductID: "juice", MinQuantity: 24, Price: 10000},
+		}},
+		PromotionRepo: &promotionRepo{promotions: map[string][]entity.Promotion{
+			"tea": {
+				{Name: "Spring", Type: entity.PromotionTypePercent, Value: 10},
+				{Name: "Loyal", Type: entity.PromotionTypeFixed, Value: 15},
+			},
+			"gum": {{Name: "Clearance", Type: entity.PromotionTypeFixed, Value: 25}},
+		}},
+		CurrencyRepo: &currencyRepo{rates: map[string]float64{"USD": 12500}},
+	}
+
+	for _, tc := range []struct {
+		name     string
+		customer string
+		currency string
+		line     entity.SalesOrderLine
+		want     entity.SalesOrderLine
+		err      error
+	}{
+		{
+			name: "sale price",
+			line: entity.SalesOrderLine{ProductID: "juice", Quantity: 3},
+			want: entity.SalesOrderLine{ProductID: "juice", Quantity: 3, Price: 12500, ListPrice: 12500,
+				PriceRules: []string{"sale price: 12500.00"}},
+		},
+		{
+			name:     "customer without a price list",
+			customer: "cafe",
+			line:     entity.SalesOrderLine{ProductID: "juice", Quantity: 3},
+			want: entity.SalesOrderLine{ProductID: "juice", Quantity: 3, Price: 12500, ListPrice: 12500,
+				PriceRules: []string{"sale price: 12500.00"}},
+		},
+		{
+			name:     "price list",
+			customer: "shop",
+			line:     entity.SalesOrderLine{ProductID: "juice", Quantity: 3},
+			want: entity.SalesOrderLine{ProductID: "juice", Quantity: 3, Price: 11000, ListPrice: 11000,
+				PriceRules: []string{"price list Wholesale: 11000.00"}},
+		},
+		{
+			name:     "quantity break in boxes",
+			customer: "shop",
+			line:     entity.SalesOrderLine{ProductID: "juice", Unit: "box", UnitQuantity: 2},
+			want: entity.SalesOrderLine{ProductID: "juice", Unit: "box", UnitQuantity: 2, Quantity: 24, Price: 10000,
+				ListPrice: 10000, PriceRules: []string{"price list Wholesale from 24 pcs: 10000.00"}},
+		},
+		{
+			name:     "product not on the price list",
+			customer: "shop",
+			line:     entity.SalesOrderLine{ProductID: "tea", Quantity: 1},
+			want: entity.SalesOrderLine{ProductID: "tea", Quantity: 1, Price: 85, ListPrice: 100,
+				PriceRules: []string{"sale price: 100.00", "promotion Loyal -15.00: 85.00"}},
+		},
+		{
+			name: "promotion capped at the price",
+			line: entity.SalesOrderLine{ProductID: "gum", Quantity: 1},
+			want: entity.SalesOrderLine{ProductID: "gum", Quantity: 1, Price: 0, ListPrice: 10,
+				PriceRules: []string{"sale price: 10.00", "promotion Clearance -25.00: 0.00"}},
+		},
+		{
+			name:     "order currency",
+			currency: "USD",
+			line:     entity.SalesOrderLine{ProductID: "juice", Quantity: 1},
+			want: entity.SalesOrderLine{ProductID: "juice", Quantity: 1, Price: 1, ListPrice: 1,
+				PriceRules: []string{"sale price: 12500.00", "in USD at 12500: 1.00"}},
+		},
+		{
+			name: "entered manually",
+			line: entity.SalesOrderLine{ProductID: "tea", Quantity: 2, Price: 95},
+			want: entity.SalesOrderLine{ProductID: "tea", Quantity: 2, Price: 95, ListPrice: 100,
+				PriceRules: []string{"sale price: 100.00", "promotion Loyal -15.00: 85.00", "entered manually: 95.00"}},
+		},
+		{
+			name: "entered like the rules",
+			line: entity.SalesOrderLine{ProductID: "tea", Quantity: 2, Price: 85},
+			want: entity.SalesOrderLine{ProductID: "tea", Quantity: 2, Price: 85, ListPrice: 100,
+				PriceRules: []string{"sale price: 100.00", "promotion Loyal -15.00: 85.00"}},
+		},
+		{
+			name: "unknown unit",
+			line: entity.SalesOrderLine{ProductID: "tea", Unit: "box", UnitQuantity: 1},
+			err:  ErrUnknownUnit,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			lines := []entity.SalesOrderLine{tc.line}
+
+			err := uc.PriceLines(context.Background(), tc.customer, tc.currency, "2026-10-18", lines)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("got error %v, want %v", err, tc.err)
+			}
+			if err != nil {
+				return
+			}
+
+			if !reflect.DeepEqual(lines[0], tc.want) {
+				t.Errorf("got %+v, want %+v", lines[0], tc.want)
+			}
+		})
+	}
+}
+
+func TestPromotionDiscount(t *testing.T) {
+	for _, tc := range []struct {
+		promotion entity.Promotion
+		price     float64
+		want      float64
+	}{
+		{entity.Promotion{Type: entity.PromotionTypePercent, Value: 10}, 12500, 1250},
+		{entity.Promotion{Type: entity.PromotionTypePercent, Value: 12.5}, 99.99, 12.5},
+		{entity.Promotion{Type: entity.PromotionTypePercent, Value: 150}, 80, 80},
+		{entity.Promotion{Type: entity.PromotionTypeFixed, Value: 15}, 100, 15},
+		{entity.Promotion{Type: entity.PromotionTypeFixed, Value: 150}, 100, 100},
+	} {
+		if got := promotionDiscount(tc.promotion, tc.price); got != tc.want {
+			t.Errorf("%s %v off %v = %v, want %v", tc.promotion.Type, tc.promotion.Value, tc.price, got, tc.want)
+		}
+	}
+}
+
+func TestBaseQuantity(t *testing.T) {
+	product := entity.Product{BaseUnit: "pcs", Units: []entity.ProductUnit{{Unit: "box", Factor: 24}, {Unit: "pack", Factor: 0.5}}}
+
+	for _, tc := range []struct {
+		line entity.SalesOrderLine
+		want float64
+		err  error
+	}{
+		{line: entity.SalesOrderLine{Quantity: 7}, want: 7},
+		{line: entity.SalesOrderLine{Unit: "pcs", UnitQuantity: 5, Quantity: 99}, want: 5},
+		{line: entity.SalesOrderLine{UnitQuantity: 5}, want: 5},
+		{line: entity.SalesOrderLine{Unit: "box", UnitQuantity: 2.5}, want: 60},
+		{line: entity.SalesOrderLine{Unit: "pack", UnitQuantity: 0.333}, want: 0.167},
+		{line: entity.SalesOrderLine{Unit: "pallet", UnitQuantity: 1}, err: ErrUnknownUnit},
+	} {
+		got, err := baseQuantity(product, tc.line)
+		if got != tc.want || !errors.Is(err, tc.err) {
+			t.Errorf("%v %s = %v, %v, want %v, %v", tc.line.UnitQuantity, tc.line.Unit, got, err, tc.want, tc.err)
+		}
+	}
+}
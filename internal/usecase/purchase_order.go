@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+)
+
+// SendPurchaseOrder marks a draft order as sent to the supplier. From then on
+// goods receipts can reference its lines.
+func (uc *UseCase) SendPurchaseOrder(ctx context.Context, id string) (entity.PurchaseOrder, error) {
+	err := uc.Tx.WithTx(ctx, func(ctx context.Context) error {
+		status, err := uc.PurchaseOrderRepo.LockStatus(ctx, entity.Id{ID: id})
+		if err != nil {
+			return err
+		}
+
+		if status != entity.PurchaseOrderStatusDraft {
+			return ErrInvalidStatusTransition
+		}
+
+		order, err := uc.PurchaseOrderRepo.GetSingle(ctx, entity.Id{ID: id})
+		if err != nil {
+			return err
+		}
+
+		if len(order.Lines) == 0 {
+			return ErrEmptyDocument
+		}
+
+		return uc.PurchaseOrderRepo.SetStatus(ctx, entity.Id{ID: id}, entity.PurchaseOrderStatusSent)
+	})
+	if err != nil {
+		return entity.PurchaseOrder{}, err
+	}
+
+	return uc.PurchaseOrderRepo.GetSingle(ctx, entity.Id{ID: id})
+}
+
+// ClosePurchaseOrder closes a sent order, whatever is still outstanding on it
+// is no longer expected.
+func (uc *UseCase) ClosePurchaseOrder(ctx context.Context, id string) (entity.PurchaseOrder, error) {
+	err := uc.Tx.WithTx(ctx, func(ctx context.Context) error {
+		status, err := uc.PurchaseOrderRepo.LockStatus(ctx, entity.Id{ID: id})
+		if err != nil {
+			return err
+		}
+
+		switch status {
+		case entity.PurchaseOrderStatusSent, entity.PurchaseOrderStatusPartiallyReceived, entity.PurchaseOrderStatusReceived:
+		default:
+			return ErrInvalidStatusTransition
+		}
+
+		return uc.PurchaseOrderRepo.SetStatus(ctx, entity.Id{ID: id}, entity.PurchaseOrderStatusClosed)
+	})
+	if err != nil {
+		return entity.PurchaseOrder{}, err
+	}
+
+	return uc.PurchaseOrderRepo.GetSingle(ctx, entity.Id{ID: id})
+}
+
+// receivePurchaseOrder records the receipt lines on the purchase order lines
+// they reference, or takes them off again when sign is negative, and moves the
+// order between sent, partially received and received. Only sent orders take
+// new receipts, a closed order keeps its status when a receipt is reversed.
+func (uc *UseCase) receivePurchaseOrder(ctx context.Context, receipt entity.GoodsReceipt, sign float64) error {
+	if receipt.PurchaseOrderID == "" {
+		return nil
+	}
+
+	id := entity.Id{ID: receipt.PurchaseOrderID}
+
+	status, err := uc.PurchaseOrderRepo.LockStatus(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if sign > 0 {
+		switch status {
+		case entity.PurchaseOrderStatusSent, entity.PurchaseOrderStatusPartiallyReceived, entity.PurchaseOrderStatusReceived:
+		default:
+			return ErrPurchaseOrderNotOpen
+		}
+
+		order, err := uc.PurchaseOrderRepo.GetSingle(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if order.SupplierID != receipt.SupplierID || order.WarehouseID != receipt.WarehouseID {
+			return ErrPurchaseOrderMismatch
+		}
+	}
+
+	for _, line := range receipt.Lines {
+		if line.PurchaseOrderLineID == "" {
+			continue
+		}
+
+		err = uc.PurchaseOrderRepo.AddReceived(ctx, id.ID, line.PurchaseOrderLineID, line.ProductID, sign*line.Quantity)
+		if err != nil {
+			return err
+		}
+	}
+
+	return uc.PurchaseOrderRepo.RefreshStatus(ctx, id)
+}
@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+)
+
+// CreateReplenishmentOrders turns the current suggestions into draft purchase
+// orders, one per supplier and warehouse, priced at the last known cost.
+// Products without a preferred supplier are returned as skipped.
+func (uc *UseCase) CreateReplenishmentOrders(ctx context.Context, req entity.ReplenishmentOrderRequest) (entity.ReplenishmentOrderResponse, error) {
+	response := entity.ReplenishmentOrderResponse{}
+
+	var filter entity.GetListFilter
+	if req.WarehouseID != "" {
+		filter.Filters = append(filter.Filters, entity.Filter{Column: "warehouse_id", Type: "eq", Value: req.WarehouseID})
+	}
+	if req.SupplierID != "" {
+		filter.Filters = append(filter.Filters, entity.Filter{Column: "supplier_id", Type: "eq", Value: req.SupplierID})
+	}
+
+	err := uc.Tx.WithTx(ctx, func(ctx context.Context) error {
+		suggestions, err := uc.ReplenishmentRepo.GetSuggestions(ctx, filter)
+		if err != nil {
+			return err
+		}
+
+		for _, group := range suggestions.Suppliers {
+			if group.SupplierID == "" {
+				response.Skipped = append(response.Skipped, group.Items...)
+				continue
+			}
+
+			var (
+				warehouses []string
+				lines      = map[string][]entity.PurchaseOrderLine{}
+			)
+
+			for _, item := range group.Items {
+				if _, ok := lines[item.WarehouseID]; !ok {
+					warehouses = append(warehouses, item.WarehouseID)
+				}
+
+				lines[item.WarehouseID] = append(lines[item.WarehouseID], entity.PurchaseOrderLine{
+					ProductID: item.ProductID,
+					Quantity:  item.Quantity,
+					Price:     roundTo(item.UnitCost, 2),
+				})
+			}
+
+			for _, warehouseID := range warehouses {
+				order, err := uc.PurchaseOrderRepo.Create(ctx, entity.PurchaseOrder{
+					SupplierID:   group.SupplierID,
+					WarehouseID:  warehouseID,
+					ExpectedDate: req.ExpectedDate,
+					Note:         req.Note,
+					CreatedBy:    req.CreatedBy,
+					Lines:        lines[warehouseID],
+				})
+				if err != nil {
+					return err
+				}
+
+				response.Orders = append(response.Orders, order)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.ReplenishmentOrderResponse{}, err
+	}
+
+	return response, nil
+}
@@ -0,0 +1,234 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/logger"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/postgres"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+const assemblyColumns = `id, number, type, warehouse_id, bin_id, product_id, quantity, status, note, created_by, posted_at,
+	created_at, updated_at`
+
+type AssemblyRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewAssemblyRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *AssemblyRepo {
+	return &AssemblyRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+func (r *AssemblyRepo) Create(ctx context.Context, req entity.Assembly) (entity.Assembly, error) {
+	req.ID = uuid.NewString()
+
+	err := r.pg.WithTx(ctx, func(ctx context.Context) error {
+		qeury, args, err := r.pg.Builder.Insert("assemblies").
+			Columns(`id, number, type, warehouse_id, bin_id, product_id, quantity, note, created_by`).
+			Values(req.ID, squirrel.Expr("'AS-' || LPAD(nextval('assembly_number_seq')::TEXT, 6, '0')"),
+				req.Type, req.WarehouseID, req.BinID, req.ProductID, req.Quantity, req.Note, nullString(req.CreatedBy)).ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		if err != nil {
+			return err
+		}
+
+		if len(req.Lines) == 0 {
+			return nil
+		}
+
+		insert := r.pg.Builder.Insert("assembly_lines").Columns(`id, assembly_id, line_no, component_id, quantity`)
+		for i, line := range req.Lines {
+			insert = insert.Values(uuid.NewString(), req.ID, i+1, line.ComponentID, line.Quantity)
+		}
+
+		qeury, args, err = insert.ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		return err
+	})
+	if err != nil {
+		return entity.Assembly{}, err
+	}
+
+	return r.GetSingle(ctx, entity.Id{ID: req.ID})
+}
+
+func (r *AssemblyRepo) GetSingle(ctx context.Context, req entity.Id) (entity.Assembly, error) {
+	qeury, args, err := r.pg.Builder.Select(assemblyColumns).From("assemblies").Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return entity.Assembly{}, err
+	}
+
+	response, err := scanAssembly(r.pg.DB(ctx).QueryRow(ctx, qeury, args...))
+	if err != nil {
+		return entity.Assembly{}, err
+	}
+
+	qeury, args, err = r.pg.Builder.Select(`id, component_id, quantity`).
+		From("assembly_lines").Where("assembly_id = ?", req.ID).OrderBy("line_no").ToSql()
+	if err != nil {
+		return entity.Assembly{}, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return entity.Assembly{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var line entity.AssemblyLine
+
+		err = rows.Scan(&line.ID, &line.ComponentID, &line.Quantity)
+		if err != nil {
+			return entity.Assembly{}, err
+		}
+
+		response.Lines = append(response.Lines, line)
+	}
+
+	return response, rows.Err()
+}
+
+func (r *AssemblyRepo) GetList(ctx context.Context, req entity.GetListFilter) (entity.AssemblyList, error) {
+	response := entity.AssemblyList{}
+
+	qeuryBuilder := r.pg.Builder.Select(assemblyColumns).From("assemblies")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanAssembly(rows)
+		if err != nil {
+			return response, err
+		}
+
+		response.Items, err = listItem(response.Items, item, req)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return response, err
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("assemblies").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+// Delete removes a draft assembly document.
+func (r *AssemblyRepo) Delete(ctx context.Context, req entity.Id) error {
+	return r.pg.WithTx(ctx, func(ctx context.Context) error {
+		status, err := lockStatus(ctx, r.pg, "assemblies", req.ID)
+		if err != nil {
+			return err
+		}
+
+		if status != entity.AssemblyStatusDraft {
+			return ErrDocumentNotDraft
+		}
+
+		qeury, args, err := r.pg.Builder.Delete("assemblies").Where("id = ?", req.ID).ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		return err
+	})
+}
+
+// LockStatus locks the assembly document for the rest of the transaction and returns its status.
+func (r *AssemblyRepo) LockStatus(ctx context.Context, req entity.Id) (string, error) {
+	return lockStatus(ctx, r.pg, "assemblies", req.ID)
+}
+
+func (r *AssemblyRepo) SetStatus(ctx context.Context, req entity.Id, status string) error {
+	mp := map[string]interface{}{
+		"status":     status,
+		"updated_at": "now()",
+	}
+
+	if status == entity.AssemblyStatusPosted {
+		mp["posted_at"] = "now()"
+	}
+
+	qeury, args, err := r.pg.Builder.Update("assemblies").SetMap(mp).Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+func scanAssembly(row pgx.Row) (entity.Assembly, error) {
+	var (
+		item                 entity.Assembly
+		createdBy            sql.NullString
+		postedAt             sql.NullTime
+		createdAt, updatedAt time.Time
+	)
+
+	err := row.Scan(&item.ID, &item.Number, &item.Type, &item.WarehouseID, &item.BinID, &item.ProductID, &item.Quantity,
+		&item.Status, &item.Note, &createdBy, &postedAt, &createdAt, &updatedAt)
+	if err != nil {
+		return entity.Assembly{}, err
+	}
+
+	item.CreatedBy = createdBy.String
+	item.PostedAt = formatNullTime(postedAt)
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+	item.UpdatedAt = updatedAt.Format(time.RFC3339)
+
+	return item, nil
+}
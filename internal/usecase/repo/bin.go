@@ -0,0 +1,223 @@
+package repo
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/logger"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/postgres"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+// transitCode is the code of the virtual zone and bin created for in-transit stock.
+const transitCode = "TRANSIT"
+
+var ErrReservedCode = entity.NewError(config.ErrorBadRequest, "The code "+transitCode+" is reserved for stock in transit")
+
+type BinRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewBinRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *BinRepo {
+	return &BinRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+func (r *BinRepo) Create(ctx context.Context, req entity.Bin) (entity.Bin, error) {
+	if strings.EqualFold(req.Code, transitCode) {
+		return entity.Bin{}, ErrReservedCode
+	}
+
+	req.ID = uuid.NewString()
+
+	// warehouse_id is copied from the zone so bins can be filtered by warehouse directly
+	qeury, args, err := r.pg.Builder.Insert("bins").
+		Columns(`id, warehouse_id, zone_id, code, description, is_active`).
+		Select(r.pg.Builder.Select().
+			Column("?::UUID", req.ID).
+			Columns("warehouse_id", "id").
+			Column("?::TEXT", req.Code).
+			Column("?::TEXT", req.Description).
+			Column("?::BOOLEAN", req.IsActive).
+			From("zones").Where("id = ?", req.ZoneID)).ToSql()
+	if err != nil {
+		return entity.Bin{}, err
+	}
+
+	tag, err := r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil {
+		return entity.Bin{}, err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return entity.Bin{}, pgx.ErrNoRows
+	}
+
+	return r.GetSingle(ctx, entity.Id{ID: req.ID})
+}
+
+func (r *BinRepo) GetSingle(ctx context.Context, req entity.Id) (entity.Bin, error) {
+	response := entity.Bin{}
+	var createdAt, updatedAt time.Time
+
+	qeury, args, err := r.pg.Builder.
+		Select(`id, warehouse_id, zone_id, code, description, is_active, is_virtual, created_at, updated_at`).
+		From("bins").Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return entity.Bin{}, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, qeury, args...).
+		Scan(&response.ID, &response.WarehouseID, &response.ZoneID, &response.Code, &response.Description,
+			&response.IsActive, &response.IsVirtual, &createdAt, &updatedAt)
+	if err != nil {
+		return entity.Bin{}, err
+	}
+
+	response.CreatedAt = createdAt.Format(time.RFC3339)
+	response.UpdatedAt = updatedAt.Format(time.RFC3339)
+
+	return response, nil
+}
+
+func (r *BinRepo) GetList(ctx context.Context, req entity.GetListFilter) (entity.BinList, error) {
+	var (
+		response             = entity.BinList{}
+		createdAt, updatedAt time.Time
+	)
+
+	qeuryBuilder := r.pg.Builder.
+		Select(`id, warehouse_id, zone_id, code, description, is_active, is_virtual, created_at, updated_at`).
+		From("bins")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item entity.Bin
+		err = rows.Scan(&item.ID, &item.WarehouseID, &item.ZoneID, &item.Code, &item.Description,
+			&item.IsActive, &item.IsVirtual, &createdAt, &updatedAt)
+		if err != nil {
+			return response, err
+		}
+
+		item.CreatedAt = createdAt.Format(time.RFC3339)
+		item.UpdatedAt = updatedAt.Format(time.RFC3339)
+
+		response.Items, err = listItem(response.Items, item, req)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return response, err
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("bins").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+func (r *BinRepo) Update(ctx context.Context, req entity.Bin) (entity.Bin, error) {
+	mp := map[string]interface{}{
+		"code":        req.Code,
+		"description": req.Description,
+		"is_active":   req.IsActive,
+		"updated_at":  "now()",
+	}
+
+	qeuryBuilder := r.pg.Builder.Update("bins").SetMap(mp).Where("id = ?", req.ID)
+
+	reserved := strings.EqualFold(req.Code, transitCode)
+	if reserved {
+		// only the in-transit bin itself may keep the code
+		qeuryBuilder = qeuryBuilder.Where("is_virtual")
+	}
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return entity.Bin{}, err
+	}
+
+	tag, err := r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil {
+		return entity.Bin{}, err
+	}
+
+	if reserved && tag.RowsAffected() == 0 {
+		return entity.Bin{}, ErrReservedCode
+	}
+
+	return r.GetSingle(ctx, entity.Id{ID: req.ID})
+}
+
+func (r *BinRepo) Delete(ctx context.Context, req entity.Id) error {
+	qeury, args, err := r.pg.Builder.Delete("bins").Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetTransitBin returns the virtual bin that holds stock travelling to the
+// warehouse, creating it together with its zone on first use.
+func (r *BinRepo) GetTransitBin(ctx context.Context, warehouseID string) (string, error) {
+	var binID string
+
+	err := r.pg.WithTx(ctx, func(ctx context.Context) error {
+		var zoneID string
+
+		err := r.pg.DB(ctx).QueryRow(ctx, `INSERT INTO zones (id, warehouse_id, code, name) VALUES ($1, $2, $3, $4)
+			ON CONFLICT (warehouse_id, code) DO UPDATE SET code = EXCLUDED.code RETURNING id`,
+			uuid.NewString(), warehouseID, transitCode, "In transit").Scan(&zoneID)
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, `INSERT INTO bins (id, warehouse_id, zone_id, code, description, is_active, is_virtual)
+			VALUES ($1, $2, $3, $4, $5, TRUE, TRUE) ON CONFLICT (warehouse_id) WHERE is_virtual DO NOTHING`,
+			uuid.NewString(), warehouseID, zoneID, transitCode, "Stock dispatched to this warehouse and not received yet")
+		if err != nil {
+			return err
+		}
+
+		return r.pg.DB(ctx).QueryRow(ctx, `SELECT id FROM bins WHERE warehouse_id = $1 AND is_virtual`, warehouseID).Scan(&binID)
+	})
+
+	return binID, err
+}
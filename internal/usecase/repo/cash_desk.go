@@ -0,0 +1,659 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/logger"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/postgres"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+var (
+	ErrDayClosed        = entity.NewError(config.ErrorDayClosed, "The day is already closed for the cash desk")
+	ErrCashDeskInactive = entity.NewError(config.ErrorBadRequest, "The cash desk is not active")
+	ErrCashDeskCurrency = entity.NewError(config.ErrorBadRequest, "The payment currency doesn't match the currency of the cash desk")
+	ErrClosingOperation = entity.NewError(config.ErrorBadRequest, "Over and short are removed by deleting the day close")
+	ErrNotLastDayClose  = entity.NewError(config.ErrorBadRequest, "Only the last day close of a cash desk can be deleted")
+)
+
+const (
+	cashOperationColumns = `id, cash_desk_id, type, amount, operation_date, day_close_id, note, created_by, created_at`
+	cashDayCloseColumns  = `id, cash_desk_id, close_date, expected, counted, difference, note, closed_by, created_at`
+)
+
+var cashDeskColumns = `id, name, type, currency, is_active, note,
+	(SELECT COALESCE(SUM(amount_in - amount_out), 0) FROM ` + cashEntries("cash_desks.id") + `),
+	(SELECT MAX(close_date) FROM cash_day_closes WHERE cash_day_closes.cash_desk_id = cash_desks.id),
+	created_at, updated_at`
+
+// cashEntries is every payment and operation of the desk with the id desk
+// evaluates to, with the money it brought in or took out.
+func cashEntries(desk string) string {
+	return fmt.Sprintf(`(
+	SELECT paid_date AS date, 'customer_payment' AS type, id AS document_id, payment_method AS method, note,
+		amount AS amount_in, 0 AS amount_out, created_at
+	FROM customer_payments WHERE cash_desk_id = %[1]s
+	UNION ALL
+	SELECT paid_date, 'supplier_payment', id, payment_method, note, 0, amount, created_at
+	FROM supplier_payments WHERE cash_desk_id = %[1]s
+	UNION ALL
+	SELECT operation_date, type, id, '', note,
+		CASE WHEN type IN ('in', 'over') THEN amount ELSE 0 END,
+		CASE WHEN type IN ('out', 'short') THEN amount ELSE 0 END, created_at
+	FROM cash_operations WHERE cash_desk_id = %[1]s
+) entries`, desk)
+}
+
+type CashDeskRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewCashDeskRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *CashDeskRepo {
+	return &CashDeskRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+func (r *CashDeskRepo) Create(ctx context.Context, req entity.CashDesk) (entity.CashDesk, error) {
+	req.ID = uuid.NewString()
+
+	qeury, args, err := r.pg.Builder.Insert("cash_desks").
+		Columns(`id, name, type, currency, is_active, note`).
+		Values(req.ID, req.Name, req.Type,
+			squirrel.Expr("COALESCE(NULLIF(?, ''), (SELECT code FROM currencies WHERE is_base))", req.Currency),
+			req.IsActive, req.Note).ToSql()
+	if err != nil {
+		return entity.CashDesk{}, err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil {
+		return entity.CashDesk{}, err
+	}
+
+	return r.GetSingle(ctx, entity.Id{ID: req.ID})
+}
+
+func (r *CashDeskRepo) GetSingle(ctx context.Context, req entity.Id) (entity.CashDesk, error) {
+	qeury, args, err := r.pg.Builder.Select(cashDeskColumns).From("cash_desks").Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return entity.CashDesk{}, err
+	}
+
+	return scanCashDesk(r.pg.DB(ctx).QueryRow(ctx, qeury, args...))
+}
+
+func (r *CashDeskRepo) GetList(ctx context.Context, req entity.GetListFilter) (entity.CashDeskList, error) {
+	response := entity.CashDeskList{}
+
+	qeuryBuilder := r.pg.Builder.Select(cashDeskColumns).From("cash_desks")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanCashDesk(rows)
+		if err != nil {
+			return response, err
+		}
+
+		response.Items, err = listItem(response.Items, item, req)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return response, err
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("cash_desks").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+// Update changes a cash desk, its currency stays the one it was created with.
+func (r *CashDeskRepo) Update(ctx context.Context, req entity.CashDesk) (entity.CashDesk, error) {
+	mp := map[string]interface{}{
+		"name":       req.Name,
+		"type":       req.Type,
+		"is_active":  req.IsActive,
+		"note":       req.Note,
+		"updated_at": "now()",
+	}
+
+	qeury, args, err := r.pg.Builder.Update("cash_desks").SetMap(mp).Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return entity.CashDesk{}, err
+	}
+
+	tag, err := r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil {
+		return entity.CashDesk{}, err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return entity.CashDesk{}, pgx.ErrNoRows
+	}
+
+	return r.GetSingle(ctx, entity.Id{ID: req.ID})
+}
+
+// Delete removes a cash desk nothing went through yet.
+func (r *CashDeskRepo) Delete(ctx context.Context, req entity.Id) error {
+	qeury, args, err := r.pg.Builder.Delete("cash_desks").Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	return err
+}
+
+// CreateOperation puts money into or takes it out of a desk on a day not closed yet.
+func (r *CashDeskRepo) CreateOperation(ctx context.Context, req entity.CashOperation) (entity.CashOperation, error) {
+	req.ID = uuid.NewString()
+
+	err := r.pg.WithTx(ctx, func(ctx context.Context) error {
+		_, _, err := lockCashDesk(ctx, r.pg, req.CashDeskID, req.OperationDate)
+		if err != nil {
+			return err
+		}
+
+		return r.insertOperation(ctx, req)
+	})
+	if err != nil {
+		return entity.CashOperation{}, err
+	}
+
+	return r.GetOperation(ctx, entity.Id{ID: req.ID})
+}
+
+func (r *CashDeskRepo) GetOperation(ctx context.Context, req entity.Id) (entity.CashOperation, error) {
+	qeury, args, err := r.pg.Builder.Select(cashOperationColumns).From("cash_operations").Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return entity.CashOperation{}, err
+	}
+
+	return scanCashOperation(r.pg.DB(ctx).QueryRow(ctx, qeury, args...))
+}
+
+func (r *CashDeskRepo) GetOperations(ctx context.Context, req entity.GetListFilter) (entity.CashOperationList, error) {
+	response := entity.CashOperationList{}
+
+	qeuryBuilder := r.pg.Builder.Select(cashOperationColumns).From("cash_operations")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanCashOperation(rows)
+		if err != nil {
+			return response, err
+		}
+
+		response.Items, err = listItem(response.Items, item, req)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return response, err
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("cash_operations").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+// DeleteOperation removes a cash in or out on a day not closed yet.
+func (r *CashDeskRepo) DeleteOperation(ctx context.Context, req entity.Id) error {
+	return r.pg.WithTx(ctx, func(ctx context.Context) error {
+		operation, err := r.GetOperation(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		if operation.DayCloseID != "" {
+			return ErrClosingOperation
+		}
+
+		_, _, err = lockCashDesk(ctx, r.pg, operation.CashDeskID, operation.OperationDate)
+		if err != nil {
+			return err
+		}
+
+		qeury, args, err := r.pg.Builder.Delete("cash_operations").Where("id = ?", req.ID).ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		return err
+	})
+}
+
+// CloseDay reconciles the counted money of a desk with its balance at the end
+// of the close date, which has to come after the last close. A difference is
+// written to the desk as over or short so that the balance matches the count.
+func (r *CashDeskRepo) CloseDay(ctx context.Context, req entity.CashDayClose) (entity.CashDayClose, error) {
+	req.ID = uuid.NewString()
+
+	err := r.pg.WithTx(ctx, func(ctx context.Context) error {
+		_, _, err := lockCashDesk(ctx, r.pg, req.CashDeskID, req.CloseDate)
+		if err != nil {
+			return err
+		}
+
+		closeDate := squirrel.Expr("COALESCE(NULLIF(?, '')::DATE, CURRENT_DATE)", req.CloseDate)
+
+		err = r.pg.DB(ctx).QueryRow(ctx, `SELECT COALESCE(SUM(amount_in - amount_out), 0) FROM `+cashEntries("$1")+`
+			WHERE date <= COALESCE(NULLIF($2::TEXT, '')::DATE, CURRENT_DATE)`, req.CashDeskID, req.CloseDate).Scan(&req.Expected)
+		if err != nil {
+			return err
+		}
+
+		req.Difference = math.Round((req.Counted-req.Expected)*100) / 100
+
+		qeury, args, err := r.pg.Builder.Insert("cash_day_closes").
+			Columns(`id, cash_desk_id, close_date, expected, counted, difference, note, closed_by`).
+			Values(req.ID, req.CashDeskID, closeDate, req.Expected, req.Counted, req.Difference, req.Note,
+				nullString(req.ClosedBy)).ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		if err != nil {
+			return err
+		}
+
+		if req.Difference == 0 {
+			return nil
+		}
+
+		operation := entity.CashOperation{
+			ID:            uuid.NewString(),
+			CashDeskID:    req.CashDeskID,
+			Type:          entity.CashOperationOver,
+			Amount:        req.Difference,
+			OperationDate: req.CloseDate,
+			DayCloseID:    req.ID,
+			Note:          req.Note,
+			CreatedBy:     req.ClosedBy,
+		}
+
+		if req.Difference < 0 {
+			operation.Type, operation.Amount = entity.CashOperationShort, -req.Difference
+		}
+
+		return r.insertOperation(ctx, operation)
+	})
+	if err != nil {
+		return entity.CashDayClose{}, err
+	}
+
+	return r.GetDayClose(ctx, entity.Id{ID: req.ID})
+}
+
+func (r *CashDeskRepo) GetDayClose(ctx context.Context, req entity.Id) (entity.CashDayClose, error) {
+	qeury, args, err := r.pg.Builder.Select(cashDayCloseColumns).From("cash_day_closes").Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return entity.CashDayClose{}, err
+	}
+
+	return scanCashDayClose(r.pg.DB(ctx).QueryRow(ctx, qeury, args...))
+}
+
+func (r *CashDeskRepo) GetDayCloses(ctx context.Context, req entity.GetListFilter) (entity.CashDayCloseList, error) {
+	response := entity.CashDayCloseList{}
+
+	qeuryBuilder := r.pg.Builder.Select(cashDayCloseColumns).From("cash_day_closes")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanCashDayClose(rows)
+		if err != nil {
+			return response, err
+		}
+
+		response.Items, err = listItem(response.Items, item, req)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return response, err
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("cash_day_closes").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+// DeleteDayClose reopens the last closed day of a desk together with its over or short.
+func (r *CashDeskRepo) DeleteDayClose(ctx context.Context, req entity.Id) error {
+	return r.pg.WithTx(ctx, func(ctx context.Context) error {
+		dayClose, err := r.GetDayClose(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		var isLast bool
+
+		qeury, args, err := r.pg.Builder.
+			Select("NOT EXISTS (SELECT 1 FROM cash_day_closes WHERE cash_desk_id = cash_desks.id AND close_date > ?::DATE)",
+				dayClose.CloseDate).
+			From("cash_desks").Where("id = ?", dayClose.CashDeskID).Suffix("FOR UPDATE").ToSql()
+		if err != nil {
+			return err
+		}
+
+		err = r.pg.DB(ctx).QueryRow(ctx, qeury, args...).Scan(&isLast)
+		if err != nil {
+			return err
+		}
+
+		if !isLast {
+			return ErrNotLastDayClose
+		}
+
+		qeury, args, err = r.pg.Builder.Delete("cash_day_closes").Where("id = ?", req.ID).ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		return err
+	})
+}
+
+// GetLedger returns the payments and operations of a desk between from and to
+// (YYYY-MM-DD, both optional and inclusive) with the balance running after each entry.
+func (r *CashDeskRepo) GetLedger(ctx context.Context, cashDeskID, from, to string) (entity.CashLedger, error) {
+	response := entity.CashLedger{
+		CashDeskID: cashDeskID,
+		From:       from,
+		To:         to,
+		Entries:    []entity.CashLedgerEntry{},
+	}
+
+	err := r.pg.DB(ctx).QueryRow(ctx, `SELECT currency FROM cash_desks WHERE id = $1`, cashDeskID).Scan(&response.Currency)
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, `SELECT date, type, document_id, method, note, amount_in, amount_out FROM `+
+		cashEntries("$1")+` ORDER BY date, created_at`, cashDeskID)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	balance := 0.0
+	for rows.Next() {
+		var (
+			item entity.CashLedgerEntry
+			date time.Time
+		)
+
+		err = rows.Scan(&date, &item.Type, &item.DocumentID, &item.Method, &item.Note, &item.In, &item.Out)
+		if err != nil {
+			return response, err
+		}
+
+		item.Date = date.Format("2006-01-02")
+		balance = math.Round((balance+item.In-item.Out)*100) / 100
+		item.Balance = balance
+
+		switch {
+		case from != "" && item.Date < from:
+			response.OpeningBalance = balance
+		case to != "" && item.Date > to:
+			continue
+		default:
+			response.Entries = append(response.Entries, item)
+		}
+
+		response.ClosingBalance = balance
+	}
+
+	return response, rows.Err()
+}
+
+func (r *CashDeskRepo) insertOperation(ctx context.Context, req entity.CashOperation) error {
+	qeury, args, err := r.pg.Builder.Insert("cash_operations").
+		Columns(`id, cash_desk_id, type, amount, operation_date, day_close_id, note, created_by`).
+		Values(req.ID, req.CashDeskID, req.Type, req.Amount,
+			squirrel.Expr("COALESCE(NULLIF(?, '')::DATE, CURRENT_DATE)", req.OperationDate),
+			nullString(req.DayCloseID), req.Note, nullString(req.CreatedBy)).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	return err
+}
+
+// lockCashDesk locks an active desk until the end of the current transaction
+// and returns its type and currency. Nothing can be booked to the desk on date
+// (YYYY-MM-DD, today when empty) once that day is closed.
+func lockCashDesk(ctx context.Context, pg *postgres.Postgres, id, date string) (string, string, error) {
+	var (
+		deskType, currency string
+		isActive, isOpen   bool
+	)
+
+	qeury, args, err := pg.Builder.Select("type, currency, is_active").
+		Column(`NOT EXISTS (SELECT 1 FROM cash_day_closes WHERE cash_desk_id = cash_desks.id
+			AND close_date >= COALESCE(NULLIF(?, '')::DATE, CURRENT_DATE))`, date).
+		From("cash_desks").Where("id = ?", id).Suffix("FOR UPDATE").ToSql()
+	if err != nil {
+		return "", "", err
+	}
+
+	err = pg.DB(ctx).QueryRow(ctx, qeury, args...).Scan(&deskType, &currency, &isActive, &isOpen)
+	if err != nil {
+		return "", "", err
+	}
+
+	switch {
+	case !isActive:
+		return "", "", ErrCashDeskInactive
+	case !isOpen:
+		return "", "", ErrDayClosed
+	}
+
+	return deskType, currency, nil
+}
+
+// paymentDesk books a payment to a desk: the desk has to be open on the paid
+// date and kept in the payment currency. It returns the payment method, the
+// one going with the desk type when method is empty.
+func paymentDesk(ctx context.Context, pg *postgres.Postgres, id, date, currency, method string) (string, error) {
+	deskType, deskCurrency, err := lockCashDesk(ctx, pg, id, date)
+	if err != nil {
+		return "", err
+	}
+
+	if deskCurrency != currency {
+		return "", ErrCashDeskCurrency
+	}
+
+	if method != "" {
+		return method, nil
+	}
+
+	switch deskType {
+	case entity.CashDeskTypeCard:
+		return entity.PaymentMethodCard, nil
+	case entity.CashDeskTypeBank:
+		return entity.PaymentMethodBankTransfer, nil
+	}
+
+	return entity.PaymentMethodCash, nil
+}
+
+// unbookPayment checks that the day a payment was booked to its desk on is
+// still open, so that the payment may be removed.
+func unbookPayment(ctx context.Context, pg *postgres.Postgres, table, id string) error {
+	var (
+		deskID   sql.NullString
+		paidDate time.Time
+	)
+
+	qeury, args, err := pg.Builder.Select("cash_desk_id, paid_date").From(table).Where("id = ?", id).ToSql()
+	if err != nil {
+		return err
+	}
+
+	err = pg.DB(ctx).QueryRow(ctx, qeury, args...).Scan(&deskID, &paidDate)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// a missing payment is reported by the delete itself
+		return nil
+	}
+	if err != nil || !deskID.Valid {
+		return err
+	}
+
+	_, _, err = lockCashDesk(ctx, pg, deskID.String, paidDate.Format("2006-01-02"))
+	if errors.Is(err, ErrCashDeskInactive) {
+		return nil
+	}
+
+	return err
+}
+
+func scanCashDesk(row pgx.Row) (entity.CashDesk, error) {
+	var (
+		item                 entity.CashDesk
+		lastClose            sql.NullTime
+		createdAt, updatedAt time.Time
+	)
+
+	err := row.Scan(&item.ID, &item.Name, &item.Type, &item.Currency, &item.IsActive, &item.Note, &item.Balance,
+		&lastClose, &createdAt, &updatedAt)
+	if err != nil {
+		return entity.CashDesk{}, err
+	}
+
+	item.LastClose = formatNullDate(lastClose)
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+	item.UpdatedAt = updatedAt.Format(time.RFC3339)
+
+	return item, nil
+}
+
+func scanCashOperation(row pgx.Row) (entity.CashOperation, error) {
+	var (
+		item                   entity.CashOperation
+		dayCloseID, createdBy  sql.NullString
+		operationDate, created time.Time
+	)
+
+	err := row.Scan(&item.ID, &item.CashDeskID, &item.Type, &item.Amount, &operationDate, &dayCloseID, &item.Note,
+		&createdBy, &created)
+	if err != nil {
+		return entity.CashOperation{}, err
+	}
+
+	item.DayCloseID = dayCloseID.String
+	item.CreatedBy = createdBy.String
+	item.OperationDate = operationDate.Format("2006-01-02")
+	item.CreatedAt = created.Format(time.RFC3339)
+
+	return item, nil
+}
+
+func scanCashDayClose(row pgx.Row) (entity.CashDayClose, error) {
+	var (
+		item      entity.CashDayClose
+		closedBy  sql.NullString
+		closeDate time.Time
+		createdAt time.Time
+	)
+
+	err := row.Scan(&item.ID, &item.CashDeskID, &closeDate, &item.Expected, &item.Counted, &item.Difference,
+		&item.Note, &closedBy, &createdAt)
+	if err != nil {
+		return entity.CashDayClose{}, err
+	}
+
+	item.ClosedBy = closedBy.String
+	item.CloseDate = closeDate.Format("2006-01-02")
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+
+	return item, nil
+}
@@ -0,0 +1,320 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/logger"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/postgres"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+var ErrCategoryCycle = entity.NewError(config.ErrorCategoryCycle, "Category can't be moved under itself or one of its descendants")
+
+// subtreeQuery walks categories down from the roots matched by the %s condition.
+// Rows come out in depth-first order, so every parent precedes its children.
+const subtreeQuery = `WITH RECURSIVE tree AS (
+	SELECT id, parent_id, name, 0 AS depth, ARRAY[name::TEXT] AS path, created_at, updated_at
+	FROM categories WHERE %s
+	UNION ALL
+	SELECT c.id, c.parent_id, c.name, tree.depth + 1, tree.path || c.name::TEXT, c.created_at, c.updated_at
+	FROM categories c JOIN tree ON c.parent_id = tree.id
+)`
+
+type CategoryRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewCategoryRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *CategoryRepo {
+	return &CategoryRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+func (r *CategoryRepo) Create(ctx context.Context, req entity.Category) (entity.Category, error) {
+	req.ID = uuid.NewString()
+
+	qeury, args, err := r.pg.Builder.Insert("categories").
+		Columns(`id, parent_id, name`).
+		Values(req.ID, nullString(req.ParentID), req.Name).ToSql()
+	if err != nil {
+		return entity.Category{}, err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil {
+		return entity.Category{}, err
+	}
+
+	return r.GetSingle(ctx, entity.Id{ID: req.ID})
+}
+
+func (r *CategoryRepo) GetSingle(ctx context.Context, req entity.Id) (entity.Category, error) {
+	response := entity.Category{}
+	var (
+		createdAt, updatedAt time.Time
+		parentID             sql.NullString
+	)
+
+	qeury, args, err := r.pg.Builder.
+		Select(`id, parent_id, name, created_at, updated_at`).
+		From("categories").Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return entity.Category{}, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, qeury, args...).
+		Scan(&response.ID, &parentID, &response.Name, &createdAt, &updatedAt)
+	if err != nil {
+		return entity.Category{}, err
+	}
+
+	response.ParentID = parentID.String
+	response.CreatedAt = createdAt.Format(time.RFC3339)
+	response.UpdatedAt = updatedAt.Format(time.RFC3339)
+
+	return response, nil
+}
+
+func (r *CategoryRepo) GetList(ctx context.Context, req entity.GetListFilter) (entity.CategoryList, error) {
+	var (
+		response             = entity.CategoryList{}
+		createdAt, updatedAt time.Time
+		parentID             sql.NullString
+	)
+
+	qeuryBuilder := r.pg.Builder.
+		Select(`id, parent_id, name, created_at, updated_at`).
+		From("categories")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item entity.Category
+		err = rows.Scan(&item.ID, &parentID, &item.Name, &createdAt, &updatedAt)
+		if err != nil {
+			return response, err
+		}
+
+		item.ParentID = parentID.String
+		item.CreatedAt = createdAt.Format(time.RFC3339)
+		item.UpdatedAt = updatedAt.Format(time.RFC3339)
+
+		response.Items, err = listItem(response.Items, item, req)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return response, err
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("categories").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+// GetSubtree returns the category with the given id and all of its descendants,
+// or every category when rootID is empty, as a flat depth-first list.
+func (r *CategoryRepo) GetSubtree(ctx context.Context, rootID string) ([]entity.Category, error) {
+	var (
+		response             []entity.Category
+		createdAt, updatedAt time.Time
+		parentID             sql.NullString
+		args                 []interface{}
+		root                 = "parent_id IS NULL"
+	)
+
+	if rootID != "" {
+		root = "id = $1"
+		args = append(args, rootID)
+	}
+
+	qeury := fmt.Sprintf(subtreeQuery, root) + ` SELECT id, parent_id, name, depth, created_at, updated_at FROM tree ORDER BY path`
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item entity.Category
+		err = rows.Scan(&item.ID, &parentID, &item.Name, &item.Depth, &createdAt, &updatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		item.ParentID = parentID.String
+		item.CreatedAt = createdAt.Format(time.RFC3339)
+		item.UpdatedAt = updatedAt.Format(time.RFC3339)
+
+		response = append(response, item)
+	}
+
+	if rootID != "" && len(response) == 0 {
+		return nil, pgx.ErrNoRows
+	}
+
+	return response, rows.Err()
+}
+
+func (r *CategoryRepo) Update(ctx context.Context, req entity.Category) (entity.Category, error) {
+	mp := map[string]interface{}{
+		"name":       req.Name,
+		"updated_at": "now()",
+	}
+
+	qeury, args, err := r.pg.Builder.Update("categories").SetMap(mp).Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return entity.Category{}, err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil {
+		return entity.Category{}, err
+	}
+
+	return r.GetSingle(ctx, entity.Id{ID: req.ID})
+}
+
+// Move re-parents a category. The table is locked against concurrent moves,
+// otherwise two crossing moves could each pass the cycle check and build a loop.
+func (r *CategoryRepo) Move(ctx context.Context, req entity.CategoryMoveRequest) (entity.Category, error) {
+	err := r.pg.WithTx(ctx, func(ctx context.Context) error {
+		_, err := r.pg.DB(ctx).Exec(ctx, "LOCK TABLE categories IN SHARE ROW EXCLUSIVE MODE")
+		if err != nil {
+			return err
+		}
+
+		if req.ParentID != "" {
+			var isDescendant bool
+			err = r.pg.DB(ctx).QueryRow(ctx, fmt.Sprintf(subtreeQuery, "id = $1")+` SELECT EXISTS (SELECT 1 FROM tree WHERE id = $2)`,
+				req.ID, req.ParentID).Scan(&isDescendant)
+			if err != nil {
+				return err
+			}
+
+			if isDescendant {
+				return ErrCategoryCycle
+			}
+		}
+
+		qeury, args, err := r.pg.Builder.Update("categories").
+			SetMap(map[string]interface{}{
+				"parent_id":  nullString(req.ParentID),
+				"updated_at": "now()",
+			}).Where("id = ?", req.ID).ToSql()
+		if err != nil {
+			return err
+		}
+
+		tag, err := r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		if err != nil {
+			return err
+		}
+
+		if tag.RowsAffected() == 0 {
+			return pgx.ErrNoRows
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.Category{}, err
+	}
+
+	return r.GetSingle(ctx, entity.Id{ID: req.ID})
+}
+
+func (r *CategoryRepo) Delete(ctx context.Context, req entity.Id) error {
+	qeury, args, err := r.pg.Builder.Delete("categories").Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetProductCounts counts products of every category in the subtree of rootID
+// (or of all categories), both directly assigned and including descendants.
+func (r *CategoryRepo) GetProductCounts(ctx context.Context, rootID string) ([]entity.CategoryProductCount, error) {
+	var (
+		response []entity.CategoryProductCount
+		parentID sql.NullString
+		args     []interface{}
+		root     = "parent_id IS NULL"
+	)
+
+	if rootID != "" {
+		root = "id = $1"
+		args = append(args, rootID)
+	}
+
+	qeury := fmt.Sprintf(subtreeQuery, root) + `,
+	descendants AS (
+		SELECT id AS ancestor_id, id FROM tree
+		UNION ALL
+		SELECT descendants.ancestor_id, c.id FROM categories c JOIN descendants ON c.parent_id = descendants.id
+	)
+	SELECT tree.id, tree.parent_id, tree.name,
+		(SELECT COUNT(1) FROM products WHERE products.category_id = tree.id),
+		(SELECT COUNT(1) FROM products JOIN descendants ON products.category_id = descendants.id
+			WHERE descendants.ancestor_id = tree.id)
+	FROM tree ORDER BY tree.path`
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item entity.CategoryProductCount
+		err = rows.Scan(&item.CategoryID, &parentID, &item.Name, &item.DirectCount, &item.TotalCount)
+		if err != nil {
+			return nil, err
+		}
+
+		item.ParentID = parentID.String
+
+		response = append(response, item)
+	}
+
+	return response, rows.Err()
+}
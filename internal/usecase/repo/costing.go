@@ -0,0 +1,322 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/logger"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/postgres"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+)
+
+type CostingRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewCostingRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *CostingRepo {
+	return &CostingRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+// valuationQuery sums the ledger with its costs per product and warehouse up to
+// the end of the as-of date.
+func (r *CostingRepo) valuationQuery(asOf string) squirrel.SelectBuilder {
+	return r.pg.Builder.
+		Select(`stock_movements.product_id, stock_movements.warehouse_id,
+			SUM(stock_movements.quantity) AS quantity, COALESCE(SUM(movement_costs.cost), 0) AS value`).
+		From("stock_movements").
+		LeftJoin("movement_costs ON movement_costs.movement_id = stock_movements.id").
+		Where("stock_movements.created_at < ?::DATE + 1", asOf).
+		GroupBy("stock_movements.product_id", "stock_movements.warehouse_id")
+}
+
+// cogsQuery nets the cost of sales against the cost of returns per product and
+// warehouse, both signed so that sales count positive.
+func (r *CostingRepo) cogsQuery(from, to string) squirrel.SelectBuilder {
+	return r.pg.Builder.
+		Select(`stock_movements.product_id, stock_movements.warehouse_id,
+			-SUM(stock_movements.quantity) AS quantity, -COALESCE(SUM(movement_costs.cost), 0) AS cost`).
+		From("stock_movements").
+		LeftJoin("movement_costs ON movement_costs.movement_id = stock_movements.id").
+		Where(squirrel.Eq{"stock_movements.reason": []string{entity.MovementReasonSale, entity.MovementReasonSaleReturn}}).
+		Where("stock_movements.created_at >= ?::DATE AND stock_movements.created_at < ?::DATE + 1", from, to).
+		GroupBy("stock_movements.product_id", "stock_movements.warehouse_id")
+}
+
+// LockLayers returns the open layers of a product in a warehouse, oldest first,
+// locked until the end of the current transaction.
+func (r *CostingRepo) LockLayers(ctx context.Context, productID, warehouseID string) ([]entity.CostLayer, error) {
+	qeury, args, err := r.pg.Builder.
+		Select(`id, product_id, warehouse_id, movement_id, quantity, remaining, unit_cost, created_at`).
+		From("cost_layers").
+		Where("product_id = ? AND warehouse_id = ? AND remaining > 0", productID, warehouseID).
+		OrderBy("created_at", "id").Suffix("FOR UPDATE").ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var response []entity.CostLayer
+	for rows.Next() {
+		var (
+			item       entity.CostLayer
+			movementID sql.NullString
+			createdAt  time.Time
+		)
+
+		err = rows.Scan(&item.ID, &item.ProductID, &item.WarehouseID, &movementID, &item.Quantity, &item.Remaining,
+			&item.UnitCost, &createdAt)
+		if err != nil {
+			return nil, err
+		}
+
+		item.MovementID = movementID.String
+		item.CreatedAt = createdAt.Format(time.RFC3339)
+
+		response = append(response, item)
+	}
+
+	return response, rows.Err()
+}
+
+func (r *CostingRepo) CreateLayer(ctx context.Context, req entity.CostLayer) (entity.CostLayer, error) {
+	req.ID = uuid.NewString()
+
+	qeury, args, err := r.pg.Builder.Insert("cost_layers").
+		Columns(`id, product_id, warehouse_id, movement_id, quantity, remaining, unit_cost`).
+		Values(req.ID, req.ProductID, req.WarehouseID, nullString(req.MovementID), req.Quantity, req.Remaining,
+			req.UnitCost).ToSql()
+	if err != nil {
+		return req, err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	return req, err
+}
+
+// UpdateLayer stores the remaining quantity and the unit cost of a layer.
+func (r *CostingRepo) UpdateLayer(ctx context.Context, req entity.CostLayer) error {
+	qeury, args, err := r.pg.Builder.Update("cost_layers").
+		Set("remaining", req.Remaining).
+		Set("unit_cost", req.UnitCost).
+		Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	return err
+}
+
+func (r *CostingRepo) SetMovementCost(ctx context.Context, movementID string, unitCost, cost float64) error {
+	qeury, args, err := r.pg.Builder.Insert("movement_costs").
+		Columns(`movement_id, unit_cost, cost`).
+		Values(movementID, unitCost, cost).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	return err
+}
+
+// GetDocumentOutbound returns the quantity and the cost that a document took
+// out of stock for a product, both positive.
+func (r *CostingRepo) GetDocumentOutbound(ctx context.Context, documentID, productID string) (float64, float64, error) {
+	var quantity, cost float64
+
+	qeury, args, err := r.pg.Builder.
+		Select(`COALESCE(-SUM(stock_movements.quantity), 0), COALESCE(-SUM(movement_costs.cost), 0)`).
+		From("stock_movements").
+		Join("movement_costs ON movement_costs.movement_id = stock_movements.id").
+		Where("stock_movements.document_id = ? AND stock_movements.product_id = ? AND stock_movements.quantity < 0",
+			documentID, productID).ToSql()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, qeury, args...).Scan(&quantity, &cost)
+	return quantity, cost, err
+}
+
+// GetDocumentInbound returns the movements a document took a product into a
+// warehouse with for reason, with their costs.
+func (r *CostingRepo) GetDocumentInbound(ctx context.Context, documentID, productID, warehouseID, reason string) ([]entity.StockMovement, error) {
+	qeury, args, err := r.pg.Builder.
+		Select(`stock_movements.id, stock_movements.quantity, movement_costs.unit_cost, movement_costs.cost`).
+		From("stock_movements").
+		Join("movement_costs ON movement_costs.movement_id = stock_movements.id").
+		Where(`stock_movements.document_id = ? AND stock_movements.product_id = ? AND stock_movements.warehouse_id = ?
+			AND stock_movements.reason = ? AND stock_movements.quantity > 0`, documentID, productID, warehouseID, reason).
+		OrderBy("stock_movements.created_at", "stock_movements.id").ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var response []entity.StockMovement
+	for rows.Next() {
+		var item entity.StockMovement
+
+		err = rows.Scan(&item.ID, &item.Quantity, &item.UnitCost, &item.Cost)
+		if err != nil {
+			return nil, err
+		}
+
+		response = append(response, item)
+	}
+
+	return response, rows.Err()
+}
+
+// GetLastUnitCost returns the unit cost the product was last taken into stock
+// at in any warehouse, zero if it never was.
+func (r *CostingRepo) GetLastUnitCost(ctx context.Context, productID string) (float64, error) {
+	var unitCost float64
+
+	qeury, args, err := r.pg.Builder.
+		Select("COALESCE((SELECT unit_cost FROM cost_layers WHERE product_id = ? ORDER BY created_at DESC, id LIMIT 1), 0)",
+			productID).ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, qeury, args...).Scan(&unitCost)
+	return unitCost, err
+}
+
+// GetValuation values the stock per product and warehouse at the end of asOf
+// (YYYY-MM-DD). Filters may use product_id and warehouse_id.
+func (r *CostingRepo) GetValuation(ctx context.Context, req entity.GetListFilter, asOf string) (entity.StockValuationReport, error) {
+	response := entity.StockValuationReport{AsOf: asOf}
+
+	qeuryBuilder := r.pg.Builder.
+		Select(`valuation.product_id, COALESCE(products.name, ''), valuation.warehouse_id, valuation.quantity, valuation.value`).
+		FromSelect(r.valuationQuery(asOf), "valuation").
+		LeftJoin("products ON products.id = valuation.product_id").
+		Where("(valuation.quantity <> 0 OR valuation.value <> 0)")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item entity.StockValuation
+
+		err = rows.Scan(&item.ProductID, &item.ProductName, &item.WarehouseID, &item.Quantity, &item.Value)
+		if err != nil {
+			return response, err
+		}
+
+		if item.Quantity != 0 {
+			item.UnitCost = item.Value / item.Quantity
+		}
+
+		response.Items, err = listItem(response.Items, item, req)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return response, err
+	}
+
+	totalsQuery, args, err := r.pg.Builder.Select("COUNT(1), COALESCE(SUM(value), 0)").FromSelect(r.valuationQuery(asOf), "valuation").
+		Where("(valuation.quantity <> 0 OR valuation.value <> 0)").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, totalsQuery, args...).Scan(&response.Count, &response.TotalValue)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+// GetCostOfGoodsSold sums the cost of sales net of returns per product between
+// from and to (YYYY-MM-DD, inclusive). Filters may use product_id and warehouse_id.
+func (r *CostingRepo) GetCostOfGoodsSold(ctx context.Context, req entity.GetListFilter, from, to string) (entity.CostOfGoodsSoldReport, error) {
+	response := entity.CostOfGoodsSoldReport{From: from, To: to}
+
+	qeuryBuilder := r.pg.Builder.
+		Select(`cogs.product_id, COALESCE(products.name, ''), SUM(cogs.quantity) AS quantity, SUM(cogs.cost) AS cost`).
+		FromSelect(r.cogsQuery(from, to), "cogs").
+		LeftJoin("products ON products.id = cogs.product_id").
+		GroupBy("cogs.product_id", "products.name")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item entity.CostOfGoodsSold
+
+		err = rows.Scan(&item.ProductID, &item.ProductName, &item.Quantity, &item.Cost)
+		if err != nil {
+			return response, err
+		}
+
+		response.Items, err = listItem(response.Items, item, req)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return response, err
+	}
+
+	totalsQuery, args, err := r.pg.Builder.
+		Select("COUNT(DISTINCT product_id), COALESCE(SUM(cost), 0)").FromSelect(r.cogsQuery(from, to), "cogs").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, totalsQuery, args...).Scan(&response.Count, &response.TotalCost)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
@@ -0,0 +1,339 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/logger"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/postgres"
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+)
+
+var (
+	ErrUnknownCurrency = entity.NewError(config.ErrorBadRequest, "The currency is not defined")
+	ErrNoExchangeRate  = entity.NewError(config.ErrorBadRequest, "No exchange rate is set for the currency on or before the document date")
+	ErrBaseCurrency    = entity.NewError(config.ErrorBadRequest, "The base currency can't be deleted")
+)
+
+const (
+	currencyColumns     = `code, name, is_base, created_at, updated_at`
+	exchangeRateColumns = `currency, rate_date, rate, source, created_at`
+)
+
+type CurrencyRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewCurrencyRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *CurrencyRepo {
+	return &CurrencyRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+func (r *CurrencyRepo) Create(ctx context.Context, req entity.Currency) (entity.Currency, error) {
+	qeury, args, err := r.pg.Builder.Insert("currencies").Columns("code, name").Values(req.Code, req.Name).ToSql()
+	if err != nil {
+		return entity.Currency{}, err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil {
+		return entity.Currency{}, err
+	}
+
+	return r.GetSingle(ctx, req.Code)
+}
+
+func (r *CurrencyRepo) GetSingle(ctx context.Context, code string) (entity.Currency, error) {
+	qeury, args, err := r.pg.Builder.Select(currencyColumns).From("currencies").Where("code = ?", code).ToSql()
+	if err != nil {
+		return entity.Currency{}, err
+	}
+
+	return scanCurrency(r.pg.DB(ctx).QueryRow(ctx, qeury, args...))
+}
+
+func (r *CurrencyRepo) GetList(ctx context.Context, req entity.GetListFilter) (entity.CurrencyList, error) {
+	response := entity.CurrencyList{}
+
+	qeuryBuilder := r.pg.Builder.Select(currencyColumns).From("currencies")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanCurrency(rows)
+		if err != nil {
+			return response, err
+		}
+
+		response.Items, err = listItem(response.Items, item, req)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return response, err
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("currencies").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+func (r *CurrencyRepo) Update(ctx context.Context, req entity.Currency) (entity.Currency, error) {
+	qeury, args, err := r.pg.Builder.Update("currencies").
+		SetMap(map[string]interface{}{
+			"name":       req.Name,
+			"updated_at": "now()",
+		}).Where("code = ?", req.Code).ToSql()
+	if err != nil {
+		return entity.Currency{}, err
+	}
+
+	tag, err := r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil {
+		return entity.Currency{}, err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return entity.Currency{}, pgx.ErrNoRows
+	}
+
+	return r.GetSingle(ctx, req.Code)
+}
+
+// Delete removes a currency no document is kept in, with its rates.
+func (r *CurrencyRepo) Delete(ctx context.Context, code string) error {
+	currency, err := r.GetSingle(ctx, code)
+	if err != nil {
+		return err
+	}
+
+	if currency.IsBase {
+		return ErrBaseCurrency
+	}
+
+	qeury, args, err := r.pg.Builder.Delete("currencies").Where("code = ?", code).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	return err
+}
+
+// SetRates stores exchange rates, replacing those already set for the same
+// currency and date. Rates of the base currency are refused.
+func (r *CurrencyRepo) SetRates(ctx context.Context, rates []entity.ExchangeRate) ([]entity.ExchangeRate, error) {
+	if len(rates) == 0 {
+		return []entity.ExchangeRate{}, nil
+	}
+
+	response := make([]entity.ExchangeRate, 0, len(rates))
+
+	err := r.pg.WithTx(ctx, func(ctx context.Context) error {
+		for _, rate := range rates {
+			qeury, args, err := r.pg.Builder.Insert("exchange_rates").
+				Columns("currency, rate_date, rate, source").
+				Select(r.pg.Builder.Select().
+					Column("code").
+					Column("COALESCE(NULLIF(?, '')::DATE, CURRENT_DATE)", rate.RateDate).
+					Column("?::NUMERIC", rate.Rate).
+					Column("?::TEXT", rate.Source).
+					From("currencies").Where("code = ? AND NOT is_base", rate.Currency)).
+				Suffix(`ON CONFLICT (currency, rate_date) DO UPDATE
+					SET rate = EXCLUDED.rate, source = EXCLUDED.source, created_at = NOW()
+					RETURNING ` + exchangeRateColumns).ToSql()
+			if err != nil {
+				return err
+			}
+
+			item, err := scanExchangeRate(r.pg.DB(ctx).QueryRow(ctx, qeury, args...))
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrUnknownCurrency
+			}
+			if err != nil {
+				return err
+			}
+
+			response = append(response, item)
+		}
+
+		return nil
+	})
+
+	return response, err
+}
+
+// GetRates lists exchange rates. Filters may use currency, source and rate_date.
+func (r *CurrencyRepo) GetRates(ctx context.Context, req entity.GetListFilter) (entity.ExchangeRateList, error) {
+	response := entity.ExchangeRateList{}
+
+	qeuryBuilder := r.pg.Builder.Select(exchangeRateColumns).From("exchange_rates")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanExchangeRate(rows)
+		if err != nil {
+			return response, err
+		}
+
+		response.Items, err = listItem(response.Items, item, req)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return response, err
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("exchange_rates").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+func (r *CurrencyRepo) DeleteRate(ctx context.Context, currency, date string) error {
+	qeury, args, err := r.pg.Builder.Delete("exchange_rates").Where("currency = ? AND rate_date = ?::DATE", currency, date).ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// GetRate returns the rate of a currency on date (YYYY-MM-DD, today when
+// empty) and the code it resolved to, the base currency when empty.
+func (r *CurrencyRepo) GetRate(ctx context.Context, currency, date string) (string, float64, error) {
+	rate, err := exchangeRate(ctx, r.pg, &currency, squirrel.Expr("COALESCE(NULLIF(?, '')::DATE, CURRENT_DATE)", date))
+	return currency, rate, err
+}
+
+// exchangeRate resolves the currency of a document, the base currency when
+// empty, and returns its rate on the date date evaluates to, the last one set
+// on or before it. The base currency has a rate of 1.
+func exchangeRate(ctx context.Context, pg *postgres.Postgres, currency *string, date squirrel.Sqlizer) (float64, error) {
+	var (
+		isBase bool
+		rate   float64
+	)
+
+	qeury, args, err := pg.Builder.Select("code, is_base").
+		Column(squirrel.Expr(`COALESCE((SELECT rate FROM exchange_rates WHERE exchange_rates.currency = currencies.code
+			AND rate_date <= (?) ORDER BY rate_date DESC LIMIT 1), 0)`, date)).
+		From("currencies").
+		Where(squirrel.Expr("CASE WHEN ?::TEXT = '' THEN is_base ELSE code = ? END", *currency, *currency)).ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	err = pg.DB(ctx).QueryRow(ctx, qeury, args...).Scan(currency, &isBase, &rate)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrUnknownCurrency
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	switch {
+	case isBase:
+		return 1, nil
+	case rate <= 0:
+		return 0, ErrNoExchangeRate
+	}
+
+	return rate, nil
+}
+
+func scanCurrency(row pgx.Row) (entity.Currency, error) {
+	var (
+		item                 entity.Currency
+		createdAt, updatedAt time.Time
+	)
+
+	err := row.Scan(&item.Code, &item.Name, &item.IsBase, &createdAt, &updatedAt)
+	if err != nil {
+		return entity.Currency{}, err
+	}
+
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+	item.UpdatedAt = updatedAt.Format(time.RFC3339)
+
+	return item, nil
+}
+
+func scanExchangeRate(row pgx.Row) (entity.ExchangeRate, error) {
+	var (
+		item      entity.ExchangeRate
+		rateDate  time.Time
+		createdAt time.Time
+	)
+
+	err := row.Scan(&item.Currency, &rateDate, &item.Rate, &item.Source, &createdAt)
+	if err != nil {
+		return entity.ExchangeRate{}, err
+	}
+
+	item.RateDate = rateDate.Format("2006-01-02")
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+
+	return item, nil
+}
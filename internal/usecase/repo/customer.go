@@ -0,0 +1,261 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/logger"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/postgres"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	customerColumns = `id, name, phone, email, address, tax_id, note, is_active, price_list_id,
+	COALESCE((SELECT name FROM price_lists WHERE price_lists.id = customers.price_list_id), ''),
+	COALESCE((SELECT SUM((` + orderTotalExpr + ` - ` + orderPaidExpr + `) * exchange_rate) FROM sales_orders
+		WHERE sales_orders.customer_id = customers.id AND sales_orders.status = 'completed'), 0),
+	created_at, updated_at`
+
+	agingBuckets = `COALESCE(SUM(debt) FILTER (WHERE age_days <= 30), 0),
+	COALESCE(SUM(debt) FILTER (WHERE age_days BETWEEN 31 AND 60), 0),
+	COALESCE(SUM(debt) FILTER (WHERE age_days BETWEEN 61 AND 90), 0),
+	COALESCE(SUM(debt) FILTER (WHERE age_days > 90), 0),
+	COALESCE(SUM(debt), 0) AS total`
+)
+
+// debtsQuery is the unpaid part of every completed order in the base currency
+// at the rate of the order, like the debt of the customer, together with its
+// age in days.
+var debtsQuery = `(SELECT customer_id, warehouse_id,
+		CURRENT_DATE - COALESCE(completed_at::DATE, order_date) AS age_days,
+		(` + orderTotalExpr + ` - ` + orderPaidExpr + `) * exchange_rate AS debt
+	FROM sales_orders WHERE status = 'completed') debts`
+
+type CustomerRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewCustomerRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *CustomerRepo {
+	return &CustomerRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+func (r *CustomerRepo) Create(ctx context.Context, req entity.Customer) (entity.Customer, error) {
+	req.ID = uuid.NewString()
+
+	qeury, args, err := r.pg.Builder.Insert("customers").
+		Columns(`id, name, phone, email, address, tax_id, note, is_active, price_list_id`).
+		Values(req.ID, req.Name, req.Phone, req.Email, req.Address, req.TaxID, req.Note, req.IsActive,
+			nullString(req.PriceListID)).ToSql()
+	if err != nil {
+		return entity.Customer{}, err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil {
+		return entity.Customer{}, err
+	}
+
+	return r.GetSingle(ctx, entity.Id{ID: req.ID})
+}
+
+func (r *CustomerRepo) GetSingle(ctx context.Context, req entity.Id) (entity.Customer, error) {
+	qeury, args, err := r.pg.Builder.Select(customerColumns).From("customers").Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return entity.Customer{}, err
+	}
+
+	return scanCustomer(r.pg.DB(ctx).QueryRow(ctx, qeury, args...))
+}
+
+func (r *CustomerRepo) GetList(ctx context.Context, req entity.GetListFilter) (entity.CustomerList, error) {
+	response := entity.CustomerList{}
+
+	qeuryBuilder := r.pg.Builder.Select(customerColumns).From("customers")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanCustomer(rows)
+		if err != nil {
+			return response, err
+		}
+
+		response.Items, err = listItem(response.Items, item, req)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return response, err
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("customers").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+func (r *CustomerRepo) Update(ctx context.Context, req entity.Customer) (entity.Customer, error) {
+	mp := map[string]interface{}{
+		"name":          req.Name,
+		"phone":         req.Phone,
+		"email":         req.Email,
+		"address":       req.Address,
+		"tax_id":        req.TaxID,
+		"note":          req.Note,
+		"is_active":     req.IsActive,
+		"price_list_id": nullString(req.PriceListID),
+		"updated_at":    "now()",
+	}
+
+	qeury, args, err := r.pg.Builder.Update("customers").SetMap(mp).Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return entity.Customer{}, err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil {
+		return entity.Customer{}, err
+	}
+
+	return r.GetSingle(ctx, entity.Id{ID: req.ID})
+}
+
+func (r *CustomerRepo) Delete(ctx context.Context, req entity.Id) error {
+	qeury, args, err := r.pg.Builder.Delete("customers").Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetDebtAging buckets the open debt of completed orders per customer by the
+// days passed since completion, in the base currency. Filters may use
+// customer_id and warehouse_id.
+func (r *CustomerRepo) GetDebtAging(ctx context.Context, req entity.GetListFilter) (entity.DebtAgingReport, error) {
+	response := entity.DebtAgingReport{}
+
+	qeuryBuilder := r.pg.Builder.
+		Select(`debts.customer_id, COALESCE(customers.name, ''), `+agingBuckets).
+		From(debtsQuery).
+		LeftJoin("customers ON customers.id = debts.customer_id").
+		Where("debt > 0").
+		GroupBy("debts.customer_id", "customers.name")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			item       entity.DebtAging
+			customerID sql.NullString
+		)
+
+		err = rows.Scan(&customerID, &item.CustomerName, &item.Days0To30, &item.Days31To60, &item.Days61To90,
+			&item.Days90Plus, &item.Total)
+		if err != nil {
+			return response, err
+		}
+
+		item.CustomerID = customerID.String
+
+		response.Items, err = listItem(response.Items, item, req)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return response, err
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("customer_id").From(debtsQuery).
+		Where("debt > 0").Where(where).GroupBy("customer_id").ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, `SELECT COUNT(1) FROM (`+countQuery+`) grouped`, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	totalsQuery, args, err := r.pg.Builder.Select(agingBuckets).From(debtsQuery).Where("debt > 0").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, totalsQuery, args...).Scan(&response.Totals.Days0To30, &response.Totals.Days31To60,
+		&response.Totals.Days61To90, &response.Totals.Days90Plus, &response.Totals.Total)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+func scanCustomer(row pgx.Row) (entity.Customer, error) {
+	var (
+		item                 entity.Customer
+		priceListID          sql.NullString
+		createdAt, updatedAt time.Time
+	)
+
+	err := row.Scan(&item.ID, &item.Name, &item.Phone, &item.Email, &item.Address, &item.TaxID, &item.Note,
+		&item.IsActive, &priceListID, &item.PriceListName, &item.RemainingDebt, &createdAt, &updatedAt)
+	if err != nil {
+		return entity.Customer{}, err
+	}
+
+	item.PriceListID = priceListID.String
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+	item.UpdatedAt = updatedAt.Format(time.RFC3339)
+
+	return item, nil
+}
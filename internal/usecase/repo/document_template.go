@@ -0,0 +1,111 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/logger"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/postgres"
+	"github.com/jackc/pgx/v4"
+)
+
+const documentTemplateColumns = `form, title, header, footer, font_size, columns, signatures, updated_at`
+
+// DocumentTemplateRepo keeps the layout of the printed forms, there is a row
+// for every form.
+type DocumentTemplateRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewDocumentTemplateRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *DocumentTemplateRepo {
+	return &DocumentTemplateRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+func (r *DocumentTemplateRepo) GetSingle(ctx context.Context, form string) (entity.DocumentTemplate, error) {
+	qeury, args, err := r.pg.Builder.Select(documentTemplateColumns).From("document_templates").Where("form = ?", form).ToSql()
+	if err != nil {
+		return entity.DocumentTemplate{}, err
+	}
+
+	return scanDocumentTemplate(r.pg.DB(ctx).QueryRow(ctx, qeury, args...))
+}
+
+func (r *DocumentTemplateRepo) GetList(ctx context.Context) (entity.DocumentTemplateList, error) {
+	response := entity.DocumentTemplateList{}
+
+	qeury, args, err := r.pg.Builder.Select(documentTemplateColumns).From("document_templates").OrderBy("form").ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanDocumentTemplate(rows)
+		if err != nil {
+			return response, err
+		}
+
+		response.Items = append(response.Items, item)
+	}
+
+	response.Count = len(response.Items)
+
+	return response, rows.Err()
+}
+
+func (r *DocumentTemplateRepo) Update(ctx context.Context, req entity.DocumentTemplate) (entity.DocumentTemplate, error) {
+	qeury, args, err := r.pg.Builder.Update("document_templates").
+		SetMap(map[string]interface{}{
+			"title":      req.Title,
+			"header":     req.Header,
+			"footer":     req.Footer,
+			"font_size":  req.FontSize,
+			"columns":    textArray(req.Columns),
+			"signatures": textArray(req.Signatures),
+			"updated_at": "now()",
+		}).Where("form = ?", req.Form).ToSql()
+	if err != nil {
+		return entity.DocumentTemplate{}, err
+	}
+
+	tag, err := r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil {
+		return entity.DocumentTemplate{}, err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return entity.DocumentTemplate{}, pgx.ErrNoRows
+	}
+
+	return r.GetSingle(ctx, req.Form)
+}
+
+func scanDocumentTemplate(row pgx.Row) (entity.DocumentTemplate, error) {
+	var (
+		item      entity.DocumentTemplate
+		updatedAt time.Time
+	)
+
+	err := row.Scan(&item.Form, &item.Title, &item.Header, &item.Footer, &item.FontSize, &item.Columns, &item.Signatures, &updatedAt)
+	if err != nil {
+		return entity.DocumentTemplate{}, err
+	}
+
+	item.UpdatedAt = updatedAt.Format(time.RFC3339)
+
+	return item, nil
+}
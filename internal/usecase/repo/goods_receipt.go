@@ -0,0 +1,340 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/logger"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/postgres"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+var ErrDocumentNotDraft = entity.NewError(config.ErrorInvalidStatus, "Only draft documents can be changed or deleted")
+
+const goodsReceiptColumns = `id, number, supplier_id, purchase_order_id, warehouse_id, receipt_date, status, note, currency, exchange_rate,
+	created_by, posted_at, cancelled_at,
+	COALESCE((SELECT SUM(quantity * unit_cost) FROM goods_receipt_lines WHERE goods_receipt_lines.receipt_id = goods_receipts.id), 0),
+	created_at, updated_at`
+
+type GoodsReceiptRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewGoodsReceiptRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *GoodsReceiptRepo {
+	return &GoodsReceiptRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+func (r *GoodsReceiptRepo) Create(ctx context.Context, req entity.GoodsReceipt) (entity.GoodsReceipt, error) {
+	req.ID = uuid.NewString()
+
+	err := r.pg.WithTx(ctx, func(ctx context.Context) error {
+		receiptDate := squirrel.Expr("COALESCE(NULLIF(?, '')::DATE, CURRENT_DATE)", req.ReceiptDate)
+
+		rate, err := exchangeRate(ctx, r.pg, &req.Currency, receiptDate)
+		if err != nil {
+			return err
+		}
+
+		qeury, args, err := r.pg.Builder.Insert("goods_receipts").
+			Columns(`id, number, supplier_id, purchase_order_id, warehouse_id, receipt_date, note, currency, exchange_rate, created_by`).
+			Values(req.ID,
+				squirrel.Expr("COALESCE(NULLIF(?, ''), 'GR-' || LPAD(nextval('goods_receipt_number_seq')::TEXT, 6, '0'))", req.Number),
+				nullString(req.SupplierID), nullString(req.PurchaseOrderID), req.WarehouseID, receiptDate,
+				req.Note, req.Currency, rate, nullString(req.CreatedBy)).ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		if err != nil {
+			return err
+		}
+
+		return r.insertLines(ctx, req.ID, req.Lines)
+	})
+	if err != nil {
+		return entity.GoodsReceipt{}, err
+	}
+
+	return r.GetSingle(ctx, entity.Id{ID: req.ID})
+}
+
+func (r *GoodsReceiptRepo) GetSingle(ctx context.Context, req entity.Id) (entity.GoodsReceipt, error) {
+	qeury, args, err := r.pg.Builder.Select(goodsReceiptColumns).From("goods_receipts").Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return entity.GoodsReceipt{}, err
+	}
+
+	response, err := scanGoodsReceipt(r.pg.DB(ctx).QueryRow(ctx, qeury, args...))
+	if err != nil {
+		return entity.GoodsReceipt{}, err
+	}
+
+	qeury, args, err = r.pg.Builder.
+		Select(`id, product_id, purchase_order_line_id, bin_id, lot_number, expiry_date, serials, unit, unit_quantity, quantity,
+			unit_cost`).
+		From("goods_receipt_lines").Where("receipt_id = ?", req.ID).OrderBy("line_no").ToSql()
+	if err != nil {
+		return entity.GoodsReceipt{}, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return entity.GoodsReceipt{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			line                entity.GoodsReceiptLine
+			purchaseOrderLineID sql.NullString
+			expiryDate          sql.NullTime
+		)
+
+		err = rows.Scan(&line.ID, &line.ProductID, &purchaseOrderLineID, &line.BinID, &line.LotNumber, &expiryDate,
+			&line.Serials, &line.Unit, &line.UnitQuantity, &line.Quantity, &line.UnitCost)
+		if err != nil {
+			return entity.GoodsReceipt{}, err
+		}
+
+		line.PurchaseOrderLineID = purchaseOrderLineID.String
+		line.ExpiryDate = formatNullDate(expiryDate)
+
+		response.Lines = append(response.Lines, line)
+	}
+
+	return response, rows.Err()
+}
+
+func (r *GoodsReceiptRepo) GetList(ctx context.Context, req entity.GetListFilter) (entity.GoodsReceiptList, error) {
+	response := entity.GoodsReceiptList{}
+
+	qeuryBuilder := r.pg.Builder.Select(goodsReceiptColumns).From("goods_receipts")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanGoodsReceipt(rows)
+		if err != nil {
+			return response, err
+		}
+
+		response.Items, err = listItem(response.Items, item, req)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return response, err
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("goods_receipts").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+// Update replaces the header fields and the lines of a draft receipt.
+func (r *GoodsReceiptRepo) Update(ctx context.Context, req entity.GoodsReceipt) (entity.GoodsReceipt, error) {
+	err := r.pg.WithTx(ctx, func(ctx context.Context) error {
+		status, err := lockStatus(ctx, r.pg, "goods_receipts", req.ID)
+		if err != nil {
+			return err
+		}
+
+		if status != entity.DocumentStatusDraft {
+			return ErrDocumentNotDraft
+		}
+
+		rate, err := exchangeRate(ctx, r.pg, &req.Currency,
+			squirrel.Expr("COALESCE(NULLIF(?, '')::DATE, (SELECT receipt_date FROM goods_receipts WHERE id = ?))", req.ReceiptDate, req.ID))
+		if err != nil {
+			return err
+		}
+
+		mp := map[string]interface{}{
+			"supplier_id":       nullString(req.SupplierID),
+			"purchase_order_id": nullString(req.PurchaseOrderID),
+			"warehouse_id":      req.WarehouseID,
+			"receipt_date":      squirrel.Expr("COALESCE(NULLIF(?, '')::DATE, receipt_date)", req.ReceiptDate),
+			"note":              req.Note,
+			"currency":          req.Currency,
+			"exchange_rate":     rate,
+			"updated_at":        "now()",
+		}
+
+		if req.Number != "" {
+			mp["number"] = req.Number
+		}
+
+		qeury, args, err := r.pg.Builder.Update("goods_receipts").SetMap(mp).Where("id = ?", req.ID).ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		if err != nil {
+			return err
+		}
+
+		qeury, args, err = r.pg.Builder.Delete("goods_receipt_lines").Where("receipt_id = ?", req.ID).ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		if err != nil {
+			return err
+		}
+
+		return r.insertLines(ctx, req.ID, req.Lines)
+	})
+	if err != nil {
+		return entity.GoodsReceipt{}, err
+	}
+
+	return r.GetSingle(ctx, entity.Id{ID: req.ID})
+}
+
+// Delete removes a draft receipt, posted ones must be cancelled instead.
+func (r *GoodsReceiptRepo) Delete(ctx context.Context, req entity.Id) error {
+	return r.pg.WithTx(ctx, func(ctx context.Context) error {
+		status, err := lockStatus(ctx, r.pg, "goods_receipts", req.ID)
+		if err != nil {
+			return err
+		}
+
+		if status != entity.DocumentStatusDraft {
+			return ErrDocumentNotDraft
+		}
+
+		qeury, args, err := r.pg.Builder.Delete("goods_receipts").Where("id = ?", req.ID).ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		return err
+	})
+}
+
+// LockStatus locks the receipt for the rest of the transaction and returns its status.
+func (r *GoodsReceiptRepo) LockStatus(ctx context.Context, req entity.Id) (string, error) {
+	return lockStatus(ctx, r.pg, "goods_receipts", req.ID)
+}
+
+func (r *GoodsReceiptRepo) SetStatus(ctx context.Context, req entity.Id, status string) error {
+	mp := map[string]interface{}{
+		"status":     status,
+		"updated_at": "now()",
+	}
+
+	switch status {
+	case entity.DocumentStatusPosted:
+		mp["posted_at"] = "now()"
+	case entity.DocumentStatusCancelled:
+		mp["cancelled_at"] = "now()"
+	}
+
+	qeury, args, err := r.pg.Builder.Update("goods_receipts").SetMap(mp).Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *GoodsReceiptRepo) insertLines(ctx context.Context, receiptID string, lines []entity.GoodsReceiptLine) error {
+	if len(lines) == 0 {
+		return nil
+	}
+
+	insert := r.pg.Builder.Insert("goods_receipt_lines").
+		Columns(`id, receipt_id, line_no, product_id, purchase_order_line_id, bin_id, lot_number, expiry_date, serials, unit,
+			unit_quantity, quantity, unit_cost`)
+	for i, line := range lines {
+		err := toBaseUnit(ctx, r.pg, line.ProductID, &line.Unit, &line.UnitQuantity, &line.Quantity)
+		if err != nil {
+			return err
+		}
+
+		insert = insert.Values(uuid.NewString(), receiptID, i+1, line.ProductID, nullString(line.PurchaseOrderLineID), line.BinID,
+			line.LotNumber, squirrel.Expr("NULLIF(?, '')::DATE", line.ExpiryDate), textArray(line.Serials), line.Unit,
+			line.UnitQuantity, line.Quantity, line.UnitCost)
+	}
+
+	qeury, args, err := insert.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	return err
+}
+
+func scanGoodsReceipt(row pgx.Row) (entity.GoodsReceipt, error) {
+	var (
+		item                                   entity.GoodsReceipt
+		supplierID, purchaseOrderID, createdBy sql.NullString
+		postedAt, cancelledAt                  sql.NullTime
+		receiptDate                            time.Time
+		createdAt, updatedAt                   time.Time
+	)
+
+	err := row.Scan(&item.ID, &item.Number, &supplierID, &purchaseOrderID, &item.WarehouseID, &receiptDate, &item.Status,
+		&item.Note, &item.Currency, &item.ExchangeRate, &createdBy, &postedAt, &cancelledAt, &item.TotalAmount, &createdAt, &updatedAt)
+	if err != nil {
+		return entity.GoodsReceipt{}, err
+	}
+
+	item.SupplierID = supplierID.String
+	item.PurchaseOrderID = purchaseOrderID.String
+	item.CreatedBy = createdBy.String
+	item.ReceiptDate = receiptDate.Format("2006-01-02")
+	item.PostedAt = formatNullTime(postedAt)
+	item.CancelledAt = formatNullTime(cancelledAt)
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+	item.UpdatedAt = updatedAt.Format(time.RFC3339)
+
+	return item, nil
+}
@@ -1,7 +1,12 @@
 package repo
 
 import (
+	"context"
+	"database/sql"
+	"time"
+
 	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/postgres"
 	"github.com/Masterminds/squirrel"
 )
 
@@ -26,7 +31,10 @@ func PrepareFilter(filters []entity.Filter) squirrel.And {
 			or = append(or, squirrel.ILike{e.Column: "%" + e.Value + "%"})
 		}
 	}
-	where = append(where, or)
+	// an empty OR renders as (1=0) and would filter out every row
+	if len(or) > 0 {
+		where = append(where, or)
+	}
 
 	return where
 }
@@ -40,6 +48,11 @@ func PrepareGetListQuery(selectQuery squirrel.SelectBuilder, filterRequest entit
 		selectQuery = selectQuery.OrderBy(e.Column + " " + e.Order)
 	}
 
+	// exports stream the whole list
+	if filterRequest.Export != nil {
+		return selectQuery, where
+	}
+
 	if filterRequest.Limit <= 0 {
 		filterRequest.Limit = 10
 	}
@@ -52,3 +65,58 @@ func PrepareGetListQuery(selectQuery squirrel.SelectBuilder, filterRequest entit
 
 	return selectQuery, where
 }
+
+// listItem adds item to the page of a list, or writes it to the export of req
+// without keeping it.
+func listItem[T any](items []T, item T, req entity.GetListFilter) ([]T, error) {
+	if req.Export != nil {
+		return items, req.Export(item)
+	}
+
+	return append(items, item), nil
+}
+
+// nullString stores empty strings as NULL, e.g. for optional foreign keys.
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// textArray stores a nil slice as an empty array for NOT NULL array columns.
+func textArray(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+
+	return s
+}
+
+// lockStatus locks a document row until the end of the current transaction and returns its status.
+func lockStatus(ctx context.Context, pg *postgres.Postgres, table, id string) (string, error) {
+	var status string
+
+	qeury, args, err := pg.Builder.Select("status").From(table).Where("id = ?", id).Suffix("FOR UPDATE").ToSql()
+	if err != nil {
+		return "", err
+	}
+
+	err = pg.DB(ctx).QueryRow(ctx, qeury, args...).Scan(&status)
+	return status, err
+}
+
+// formatNullTime renders optional timestamps as RFC3339 or an empty string.
+func formatNullTime(t sql.NullTime) string {
+	if !t.Valid {
+		return ""
+	}
+
+	return t.Time.Format(time.RFC3339)
+}
+
+// formatNullDate renders optional dates as YYYY-MM-DD or an empty string.
+func formatNullDate(t sql.NullTime) string {
+	if !t.Valid {
+		return ""
+	}
+
+	return t.Time.Format("2006-01-02")
+}
@@ -0,0 +1,438 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/logger"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/postgres"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+var ErrImportRunning = entity.NewError(config.ErrorInvalidStatus, "Import is queued or running")
+
+// importBatchSize is the number of rows inserted by a single statement.
+const importBatchSize = 500
+
+const (
+	importJobColumns = `id, kind, file_name, status, columns, mapping, dry_run, total_rows, processed, failed_rows,
+	created, updated, error, created_by, started_at, finished_at, created_at, updated_at`
+
+	importRowColumns = `job_id, row_number, cells, status, error, record_id`
+
+	// importClaimQuery starts the oldest queued job, or one whose worker
+	// stopped reporting progress. $1 seconds a running job may go silent.
+	importClaimQuery = `UPDATE import_jobs SET status = 'running', started_at = COALESCE(started_at, NOW()), updated_at = NOW()
+	WHERE id = (
+		SELECT id FROM import_jobs
+		WHERE status = 'queued' OR (status = 'running' AND updated_at < NOW() - $1 * INTERVAL '1 second')
+		ORDER BY created_at LIMIT 1
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING ` + importJobColumns
+)
+
+type ImportRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewImportRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *ImportRepo {
+	return &ImportRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+// Create stores an uploaded file together with its rows.
+func (r *ImportRepo) Create(ctx context.Context, req entity.ImportJob, rows []entity.ImportRow) (entity.ImportJob, error) {
+	req.ID = uuid.NewString()
+
+	mapping, err := json.Marshal(req.Mapping)
+	if err != nil {
+		return entity.ImportJob{}, err
+	}
+
+	err = r.pg.WithTx(ctx, func(ctx context.Context) error {
+		qeury, args, err := r.pg.Builder.Insert("import_jobs").
+			Columns(`id, kind, file_name, columns, mapping, total_rows, created_by`).
+			Values(req.ID, req.Kind, req.FileName, textArray(req.Columns), squirrel.Expr("?::JSONB", string(mapping)),
+				len(rows), nullString(req.CreatedBy)).ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		if err != nil {
+			return err
+		}
+
+		for start := 0; start < len(rows); start += importBatchSize {
+			end := start + importBatchSize
+			if end > len(rows) {
+				end = len(rows)
+			}
+
+			qeuryBuilder := r.pg.Builder.Insert("import_rows").Columns(`job_id, row_number, cells`)
+			for _, row := range rows[start:end] {
+				qeuryBuilder = qeuryBuilder.Values(req.ID, row.RowNumber, textArray(row.Cells))
+			}
+
+			qeury, args, err := qeuryBuilder.ToSql()
+			if err != nil {
+				return err
+			}
+
+			_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.ImportJob{}, err
+	}
+
+	return r.GetSingle(ctx, entity.Id{ID: req.ID})
+}
+
+func (r *ImportRepo) GetSingle(ctx context.Context, req entity.Id) (entity.ImportJob, error) {
+	qeury, args, err := r.pg.Builder.Select(importJobColumns).From("import_jobs").Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return entity.ImportJob{}, err
+	}
+
+	return scanImportJob(r.pg.DB(ctx).QueryRow(ctx, qeury, args...))
+}
+
+func (r *ImportRepo) GetList(ctx context.Context, req entity.GetListFilter) (entity.ImportJobList, error) {
+	response := entity.ImportJobList{}
+
+	qeuryBuilder := r.pg.Builder.Select(importJobColumns).From("import_jobs")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanImportJob(rows)
+		if err != nil {
+			return response, err
+		}
+
+		response.Items, err = listItem(response.Items, item, req)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return response, err
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("import_jobs").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+// Delete removes a job and its rows unless the worker has it.
+func (r *ImportRepo) Delete(ctx context.Context, req entity.Id) error {
+	return r.pg.WithTx(ctx, func(ctx context.Context) error {
+		status, err := lockStatus(ctx, r.pg, "import_jobs", req.ID)
+		if err != nil {
+			return err
+		}
+
+		if status == entity.ImportStatusQueued || status == entity.ImportStatusRunning {
+			return ErrImportRunning
+		}
+
+		qeury, args, err := r.pg.Builder.Delete("import_jobs").Where("id = ?", req.ID).ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		return err
+	})
+}
+
+// Queue hands a job to the worker with the given mapping. Rows that aren't
+// imported yet are checked again, imported ones count as processed.
+func (r *ImportRepo) Queue(ctx context.Context, req entity.ImportRunRequest) (entity.ImportJob, error) {
+	mapping, err := json.Marshal(req.Mapping)
+	if err != nil {
+		return entity.ImportJob{}, err
+	}
+
+	err = r.pg.WithTx(ctx, func(ctx context.Context) error {
+		status, err := lockStatus(ctx, r.pg, "import_jobs", req.JobID)
+		if err != nil {
+			return err
+		}
+
+		if status == entity.ImportStatusQueued || status == entity.ImportStatusRunning {
+			return ErrImportRunning
+		}
+
+		qeury, args, err := r.pg.Builder.Update("import_rows").
+			SetMap(map[string]interface{}{
+				"status": entity.ImportRowPending,
+				"error":  "",
+			}).
+			Where("job_id = ? AND status <> ?", req.JobID, entity.ImportRowImported).ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		if err != nil {
+			return err
+		}
+
+		qeury, args, err = r.pg.Builder.Update("import_jobs").
+			SetMap(map[string]interface{}{
+				"status":  entity.ImportStatusQueued,
+				"mapping": squirrel.Expr("?::JSONB", string(mapping)),
+				"dry_run": req.DryRun,
+				"processed": squirrel.Expr("(SELECT COUNT(1) FROM import_rows WHERE job_id = import_jobs.id AND status = ?)",
+					entity.ImportRowImported),
+				"failed_rows": 0,
+				"created":     0,
+				"updated":     0,
+				"error":       "",
+				"started_at":  nil,
+				"finished_at": nil,
+				"updated_at":  "now()",
+			}).
+			Where("id = ?", req.JobID).ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		return err
+	})
+	if err != nil {
+		return entity.ImportJob{}, err
+	}
+
+	return r.GetSingle(ctx, entity.Id{ID: req.JobID})
+}
+
+// Claim starts the next job for the worker. Jobs running for longer than
+// stale without progress are taken over, their worker is assumed gone.
+// pgx.ErrNoRows is returned when there is nothing to do.
+func (r *ImportRepo) Claim(ctx context.Context, stale time.Duration) (entity.ImportJob, error) {
+	return scanImportJob(r.pg.DB(ctx).QueryRow(ctx, importClaimQuery, stale.Seconds()))
+}
+
+// GetPendingRows returns the next rows of a job the worker hasn't been through.
+func (r *ImportRepo) GetPendingRows(ctx context.Context, jobID string, limit int) ([]entity.ImportRow, error) {
+	var response []entity.ImportRow
+
+	qeury, args, err := r.pg.Builder.Select(importRowColumns).From("import_rows").
+		Where("job_id = ? AND status = ?", jobID, entity.ImportRowPending).
+		OrderBy("row_number").Limit(uint64(limit)).ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanImportRow(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		response = append(response, item)
+	}
+
+	return response, rows.Err()
+}
+
+func (r *ImportRepo) GetRows(ctx context.Context, req entity.GetListFilter) (entity.ImportRowList, error) {
+	response := entity.ImportRowList{}
+
+	qeuryBuilder := r.pg.Builder.Select(importRowColumns).From("import_rows")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanImportRow(rows)
+		if err != nil {
+			return response, err
+		}
+
+		response.Items, err = listItem(response.Items, item, req)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return response, err
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("import_rows").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+// SetRow records the outcome of a row.
+func (r *ImportRepo) SetRow(ctx context.Context, req entity.ImportRow) error {
+	qeury, args, err := r.pg.Builder.Update("import_rows").
+		SetMap(map[string]interface{}{
+			"status":    req.Status,
+			"error":     req.Error,
+			"record_id": nullString(req.RecordID),
+		}).
+		Where("job_id = ? AND row_number = ?", req.JobID, req.RowNumber).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	return err
+}
+
+// Progress counts the rows of a running job done so far and adds the products
+// or balances written since the last call. It also tells Claim the worker is
+// still there.
+func (r *ImportRepo) Progress(ctx context.Context, jobID string, created, updated int) error {
+	qeury, args, err := r.pg.Builder.Update("import_jobs").
+		SetMap(map[string]interface{}{
+			"processed": squirrel.Expr("(SELECT COUNT(1) FROM import_rows WHERE job_id = import_jobs.id AND status <> ?)",
+				entity.ImportRowPending),
+			"failed_rows": squirrel.Expr("(SELECT COUNT(1) FROM import_rows WHERE job_id = import_jobs.id AND status = ?)",
+				entity.ImportRowError),
+			"created":    squirrel.Expr("created + ?", created),
+			"updated":    squirrel.Expr("updated + ?", updated),
+			"updated_at": "now()",
+		}).
+		Where("id = ? AND status = ?", jobID, entity.ImportStatusRunning).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	return err
+}
+
+// Finish ends a running job with status, errText says why a job failed.
+func (r *ImportRepo) Finish(ctx context.Context, jobID, status, errText string) error {
+	qeury, args, err := r.pg.Builder.Update("import_jobs").
+		SetMap(map[string]interface{}{
+			"status":      status,
+			"error":       errText,
+			"finished_at": "now()",
+			"updated_at":  "now()",
+		}).
+		Where("id = ? AND status = ?", jobID, entity.ImportStatusRunning).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	return err
+}
+
+func scanImportJob(row pgx.Row) (entity.ImportJob, error) {
+	var (
+		item       entity.ImportJob
+		mapping    []byte
+		createdBy  sql.NullString
+		startedAt  sql.NullTime
+		finishedAt sql.NullTime
+		createdAt  time.Time
+		updatedAt  time.Time
+	)
+
+	err := row.Scan(&item.ID, &item.Kind, &item.FileName, &item.Status, &item.Columns, &mapping, &item.DryRun,
+		&item.TotalRows, &item.Processed, &item.FailedRows, &item.Created, &item.Updated, &item.Error, &createdBy,
+		&startedAt, &finishedAt, &createdAt, &updatedAt)
+	if err != nil {
+		return entity.ImportJob{}, err
+	}
+
+	err = json.Unmarshal(mapping, &item.Mapping)
+	if err != nil {
+		return entity.ImportJob{}, err
+	}
+
+	item.CreatedBy = createdBy.String
+	item.StartedAt = formatNullTime(startedAt)
+	item.FinishedAt = formatNullTime(finishedAt)
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+	item.UpdatedAt = updatedAt.Format(time.RFC3339)
+
+	return item, nil
+}
+
+func scanImportRow(row pgx.Row) (entity.ImportRow, error) {
+	var (
+		item     entity.ImportRow
+		recordID sql.NullString
+	)
+
+	err := row.Scan(&item.JobID, &item.RowNumber, &item.Cells, &item.Status, &item.Error, &recordID)
+	if err != nil {
+		return entity.ImportRow{}, err
+	}
+
+	item.RecordID = recordID.String
+
+	return item, nil
+}
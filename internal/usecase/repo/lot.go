@@ -0,0 +1,125 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/logger"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/postgres"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+var ErrLotExpiryMismatch = entity.NewError(config.ErrorConflict, "Lot already exists with a different expiry date")
+
+const lotColumns = `id, product_id, lot_number, expiry_date, created_at`
+
+type LotRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewLotRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *LotRepo {
+	return &LotRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+// GetOrCreate returns the lot of a product by its number and creates it when it
+// doesn't exist yet. An existing lot without an expiry date takes expiryDate,
+// a different expiry date than the stored one fails with ErrLotExpiryMismatch.
+func (r *LotRepo) GetOrCreate(ctx context.Context, productID, lotNumber, expiryDate string) (entity.Lot, error) {
+	qeury, args, err := r.pg.Builder.Insert("lots").
+		Columns(`id, product_id, lot_number, expiry_date`).
+		Values(uuid.NewString(), productID, lotNumber, squirrel.Expr("NULLIF(?, '')::DATE", expiryDate)).
+		Suffix(`ON CONFLICT (product_id, lot_number) DO UPDATE
+			SET expiry_date = COALESCE(lots.expiry_date, EXCLUDED.expiry_date)
+			RETURNING ` + lotColumns).ToSql()
+	if err != nil {
+		return entity.Lot{}, err
+	}
+
+	lot, err := scanLot(r.pg.DB(ctx).QueryRow(ctx, qeury, args...))
+	if err != nil {
+		return entity.Lot{}, err
+	}
+
+	if expiryDate != "" && lot.ExpiryDate != expiryDate {
+		return entity.Lot{}, ErrLotExpiryMismatch
+	}
+
+	return lot, nil
+}
+
+func (r *LotRepo) GetList(ctx context.Context, req entity.GetListFilter) (entity.LotList, error) {
+	response := entity.LotList{}
+
+	qeuryBuilder := r.pg.Builder.Select(lotColumns).From("lots")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanLot(rows)
+		if err != nil {
+			return response, err
+		}
+
+		response.Items, err = listItem(response.Items, item, req)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return response, err
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("lots").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+func scanLot(row pgx.Row) (entity.Lot, error) {
+	var (
+		item       entity.Lot
+		expiryDate sql.NullTime
+		createdAt  time.Time
+	)
+
+	err := row.Scan(&item.ID, &item.ProductID, &item.LotNumber, &expiryDate, &createdAt)
+	if err != nil {
+		return entity.Lot{}, err
+	}
+
+	item.ExpiryDate = formatNullDate(expiryDate)
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+
+	return item, nil
+}
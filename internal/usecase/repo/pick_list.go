@@ -0,0 +1,379 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/logger"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/postgres"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+var (
+	ErrOverPick       = entity.NewError(config.ErrorBadRequest, "Picked quantity exceeds what is left to pick on the line")
+	ErrPickListExists = entity.NewError(config.ErrorConflict, "The order already has a pick list")
+)
+
+const pickListColumns = `id, number, order_id, (SELECT number FROM sales_orders WHERE sales_orders.id = pick_lists.order_id),
+	warehouse_id, status, created_by, completed_at, created_at, updated_at`
+
+type PickListRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewPickListRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *PickListRepo {
+	return &PickListRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+// Create stores a pick list with its lines numbered in bin path order, so that
+// the list reads the way the picker walks.
+func (r *PickListRepo) Create(ctx context.Context, req entity.PickList) (entity.PickList, error) {
+	req.ID = uuid.NewString()
+
+	err := r.pg.WithTx(ctx, func(ctx context.Context) error {
+		qeury, args, err := r.pg.Builder.Insert("pick_lists").
+			Columns(`id, number, order_id, warehouse_id, created_by`).
+			Values(req.ID, squirrel.Expr("'PK-' || LPAD(nextval('pick_list_number_seq')::TEXT, 6, '0')"),
+				req.OrderID, req.WarehouseID, nullString(req.CreatedBy)).ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.ConstraintName == "uq_pick_lists_order_id" {
+				return ErrPickListExists
+			}
+			return err
+		}
+
+		return r.insertLines(ctx, req.ID, req.Lines)
+	})
+	if err != nil {
+		return entity.PickList{}, err
+	}
+
+	return r.GetSingle(ctx, entity.Id{ID: req.ID})
+}
+
+func (r *PickListRepo) GetSingle(ctx context.Context, req entity.Id) (entity.PickList, error) {
+	qeury, args, err := r.pg.Builder.Select(pickListColumns).From("pick_lists").Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return entity.PickList{}, err
+	}
+
+	response, err := scanPickList(r.pg.DB(ctx).QueryRow(ctx, qeury, args...))
+	if err != nil {
+		return entity.PickList{}, err
+	}
+
+	qeury, args, err = r.pg.Builder.
+		Select(`pick_list_lines.id, pick_list_lines.order_line_id, pick_list_lines.product_id, products.name, products.sku,
+			pick_list_lines.bin_id, zones.code || '/' || bins.code, pick_list_lines.lot_id, COALESCE(lots.lot_number, ''),
+			pick_list_lines.serials, pick_list_lines.quantity, pick_list_lines.picked, pick_list_lines.picked_serials,
+			pick_list_lines.shorted, pick_list_lines.short_reason`).
+		From("pick_list_lines").
+		Join("products ON products.id = pick_list_lines.product_id").
+		Join("bins ON bins.id = pick_list_lines.bin_id").
+		Join("zones ON zones.id = bins.zone_id").
+		LeftJoin("lots ON lots.id = pick_list_lines.lot_id").
+		Where("pick_list_lines.pick_list_id = ?", req.ID).
+		OrderBy("pick_list_lines.line_no").ToSql()
+	if err != nil {
+		return entity.PickList{}, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return entity.PickList{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			line  entity.PickListLine
+			lotID sql.NullString
+		)
+
+		err = rows.Scan(&line.ID, &line.OrderLineID, &line.ProductID, &line.ProductName, &line.SKU, &line.BinID, &line.BinPath,
+			&lotID, &line.LotNumber, &line.Serials, &line.Quantity, &line.Picked, &line.PickedSerials, &line.Shorted,
+			&line.ShortReason)
+		if err != nil {
+			return entity.PickList{}, err
+		}
+
+		line.LotID = lotID.String
+
+		response.Lines = append(response.Lines, line)
+	}
+
+	return response, rows.Err()
+}
+
+// GetByOrder returns the pick list of an order that isn't cancelled.
+func (r *PickListRepo) GetByOrder(ctx context.Context, orderID string) (entity.PickList, error) {
+	var id string
+
+	qeury, args, err := r.pg.Builder.Select("id").From("pick_lists").
+		Where("order_id = ? AND status <> ?", orderID, entity.PickListStatusCancelled).ToSql()
+	if err != nil {
+		return entity.PickList{}, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, qeury, args...).Scan(&id)
+	if err != nil {
+		return entity.PickList{}, err
+	}
+
+	return r.GetSingle(ctx, entity.Id{ID: id})
+}
+
+func (r *PickListRepo) GetList(ctx context.Context, req entity.GetListFilter) (entity.PickListList, error) {
+	response := entity.PickListList{}
+
+	qeuryBuilder := r.pg.Builder.Select(pickListColumns).From("pick_lists")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanPickList(rows)
+		if err != nil {
+			return response, err
+		}
+
+		response.Items, err = listItem(response.Items, item, req)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return response, err
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("pick_lists").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+// LockStatus locks the pick list for the rest of the transaction and returns its status.
+func (r *PickListRepo) LockStatus(ctx context.Context, req entity.Id) (string, error) {
+	return lockStatus(ctx, r.pg, "pick_lists", req.ID)
+}
+
+func (r *PickListRepo) SetStatus(ctx context.Context, req entity.Id, status string) error {
+	mp := map[string]interface{}{
+		"status":     status,
+		"updated_at": "now()",
+	}
+
+	if status == entity.PickListStatusCompleted {
+		mp["completed_at"] = "now()"
+	}
+
+	qeury, args, err := r.pg.Builder.Update("pick_lists").SetMap(mp).Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// CancelOpen cancels the open pick list of an order, if it has one.
+func (r *PickListRepo) CancelOpen(ctx context.Context, orderID string) error {
+	qeury, args, err := r.pg.Builder.Update("pick_lists").
+		Set("status", entity.PickListStatusCancelled).
+		Set("updated_at", "now()").
+		Where("order_id = ? AND status = ?", orderID, entity.PickListStatusOpen).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	return err
+}
+
+// AddPicked records quantity as picked on a line, serial being the unit picked
+// for serialized products. Picking more than is left fails with ErrOverPick.
+func (r *PickListRepo) AddPicked(ctx context.Context, pickListID, lineID string, quantity float64, serial string) error {
+	qeuryBuilder := r.pg.Builder.Update("pick_list_lines").
+		Set("picked", squirrel.Expr("picked + ?", quantity)).
+		Where("id = ? AND pick_list_id = ?", lineID, pickListID)
+
+	if serial != "" {
+		qeuryBuilder = qeuryBuilder.Set("picked_serials", squirrel.Expr("array_append(picked_serials, ?::TEXT)", serial))
+	}
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.ConstraintName == "pick_list_lines_picked_check" {
+			return ErrOverPick
+		}
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// Short closes a line with what has been picked so far, the rest is recorded
+// as short with the reason given.
+func (r *PickListRepo) Short(ctx context.Context, pickListID, lineID, reason string) error {
+	qeury, args, err := r.pg.Builder.Update("pick_list_lines").
+		Set("shorted", squirrel.Expr("quantity - picked")).
+		Set("short_reason", reason).
+		Where("id = ? AND pick_list_id = ?", lineID, pickListID).ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *PickListRepo) insertLines(ctx context.Context, pickListID string, lines []entity.PickListLine) error {
+	if len(lines) == 0 {
+		return nil
+	}
+
+	binIDs := make([]string, 0, len(lines))
+	for _, line := range lines {
+		binIDs = append(binIDs, line.BinID)
+	}
+
+	paths, err := r.binPaths(ctx, binIDs)
+	if err != nil {
+		return err
+	}
+
+	sort.SliceStable(lines, func(a, b int) bool {
+		return paths[lines[a].BinID] < paths[lines[b].BinID]
+	})
+
+	insert := r.pg.Builder.Insert("pick_list_lines").
+		Columns(`id, pick_list_id, line_no, order_line_id, product_id, bin_id, lot_id, serials, quantity`)
+	for i, line := range lines {
+		insert = insert.Values(uuid.NewString(), pickListID, i+1, line.OrderLineID, line.ProductID, line.BinID,
+			nullString(line.LotID), textArray(line.Serials), line.Quantity)
+	}
+
+	qeury, args, err := insert.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	return err
+}
+
+// binPaths returns zone code / bin code per bin ID.
+func (r *PickListRepo) binPaths(ctx context.Context, binIDs []string) (map[string]string, error) {
+	qeury, args, err := r.pg.Builder.Select("bins.id, zones.code || '/' || bins.code").
+		From("bins").
+		Join("zones ON zones.id = bins.zone_id").
+		Where("bins.id = ANY(?)", binIDs).ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	response := make(map[string]string, len(binIDs))
+	for rows.Next() {
+		var id, path string
+		if err = rows.Scan(&id, &path); err != nil {
+			return nil, err
+		}
+
+		response[id] = path
+	}
+
+	return response, rows.Err()
+}
+
+func scanPickList(row pgx.Row) (entity.PickList, error) {
+	var (
+		item                 entity.PickList
+		createdBy            sql.NullString
+		completedAt          sql.NullTime
+		createdAt, updatedAt time.Time
+	)
+
+	err := row.Scan(&item.ID, &item.Number, &item.OrderID, &item.OrderNumber, &item.WarehouseID, &item.Status, &createdBy,
+		&completedAt, &createdAt, &updatedAt)
+	if err != nil {
+		return entity.PickList{}, err
+	}
+
+	item.CreatedBy = createdBy.String
+	item.CompletedAt = formatNullTime(completedAt)
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+	item.UpdatedAt = updatedAt.Format(time.RFC3339)
+
+	return item, nil
+}
@@ -0,0 +1,269 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/logger"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/postgres"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	priceListColumns = `id, name, type, valid_from, valid_to, is_active, note, created_at, updated_at`
+
+	// validOnExpr tells whether a price list or promotion is in effect on the date passed.
+	validOnExpr = `is_active AND (valid_from IS NULL OR valid_from <= ?::DATE) AND (valid_to IS NULL OR valid_to >= ?::DATE)`
+)
+
+type PriceListRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewPriceListRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *PriceListRepo {
+	return &PriceListRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+func (r *PriceListRepo) Create(ctx context.Context, req entity.PriceList) (entity.PriceList, error) {
+	req.ID = uuid.NewString()
+
+	err := r.pg.WithTx(ctx, func(ctx context.Context) error {
+		qeury, args, err := r.pg.Builder.Insert("price_lists").
+			Columns(`id, name, type, valid_from, valid_to, is_active, note`).
+			Values(req.ID, req.Name, req.Type, squirrel.Expr("NULLIF(?, '')::DATE", req.ValidFrom),
+				squirrel.Expr("NULLIF(?, '')::DATE", req.ValidTo), req.IsActive, req.Note).ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		if err != nil {
+			return err
+		}
+
+		return r.insertItems(ctx, req.ID, req.Items)
+	})
+	if err != nil {
+		return entity.PriceList{}, err
+	}
+
+	return r.GetSingle(ctx, entity.Id{ID: req.ID})
+}
+
+// GetSingle returns a price list with its items ordered by product and quantity break.
+func (r *PriceListRepo) GetSingle(ctx context.Context, req entity.Id) (entity.PriceList, error) {
+	qeury, args, err := r.pg.Builder.Select(priceListColumns).From("price_lists").Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return entity.PriceList{}, err
+	}
+
+	response, err := scanPriceList(r.pg.DB(ctx).QueryRow(ctx, qeury, args...))
+	if err != nil {
+		return entity.PriceList{}, err
+	}
+
+	qeury, args, err = r.pg.Builder.Select("product_id, min_quantity, price").From("price_list_items").
+		Where("price_list_id = ?", req.ID).OrderBy("product_id", "min_quantity").ToSql()
+	if err != nil {
+		return entity.PriceList{}, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return entity.PriceList{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item entity.PriceListItem
+
+		err = rows.Scan(&item.ProductID, &item.MinQuantity, &item.Price)
+		if err != nil {
+			return entity.PriceList{}, err
+		}
+
+		response.Items = append(response.Items, item)
+	}
+
+	return response, rows.Err()
+}
+
+func (r *PriceListRepo) GetList(ctx context.Context, req entity.GetListFilter) (entity.PriceListList, error) {
+	response := entity.PriceListList{}
+
+	qeuryBuilder := r.pg.Builder.Select(priceListColumns).From("price_lists")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanPriceList(rows)
+		if err != nil {
+			return response, err
+		}
+
+		response.Items, err = listItem(response.Items, item, req)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return response, err
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("price_lists").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+// Update replaces the header fields and the items of a price list.
+func (r *PriceListRepo) Update(ctx context.Context, req entity.PriceList) (entity.PriceList, error) {
+	mp := map[string]interface{}{
+		"name":       req.Name,
+		"type":       req.Type,
+		"valid_from": squirrel.Expr("NULLIF(?, '')::DATE", req.ValidFrom),
+		"valid_to":   squirrel.Expr("NULLIF(?, '')::DATE", req.ValidTo),
+		"is_active":  req.IsActive,
+		"note":       req.Note,
+		"updated_at": "now()",
+	}
+
+	err := r.pg.WithTx(ctx, func(ctx context.Context) error {
+		qeury, args, err := r.pg.Builder.Update("price_lists").SetMap(mp).Where("id = ?", req.ID).ToSql()
+		if err != nil {
+			return err
+		}
+
+		tag, err := r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		if err != nil {
+			return err
+		}
+
+		if tag.RowsAffected() == 0 {
+			return pgx.ErrNoRows
+		}
+
+		qeury, args, err = r.pg.Builder.Delete("price_list_items").Where("price_list_id = ?", req.ID).ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		if err != nil {
+			return err
+		}
+
+		return r.insertItems(ctx, req.ID, req.Items)
+	})
+	if err != nil {
+		return entity.PriceList{}, err
+	}
+
+	return r.GetSingle(ctx, entity.Id{ID: req.ID})
+}
+
+// Delete removes a price list, its customers go back to sale prices.
+func (r *PriceListRepo) Delete(ctx context.Context, req entity.Id) error {
+	qeury, args, err := r.pg.Builder.Delete("price_lists").Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	return err
+}
+
+// GetPrice returns the item of a price list that applies to quantity base
+// units of a product on date, the one with the largest quantity break not
+// above quantity. The item is empty when the list is not in effect on date or
+// has no price for the product.
+func (r *PriceListRepo) GetPrice(ctx context.Context, priceListID, productID string, quantity float64, date string) (entity.PriceListItem, error) {
+	var item entity.PriceListItem
+
+	qeury, args, err := r.pg.Builder.Select("product_id, min_quantity, price").From("price_list_items").
+		Where("price_list_id = ? AND product_id = ? AND min_quantity <= ?", priceListID, productID, quantity).
+		Where("EXISTS (SELECT 1 FROM price_lists WHERE id = price_list_items.price_list_id AND "+validOnExpr+")", date, date).
+		OrderBy("min_quantity DESC").Limit(1).ToSql()
+	if err != nil {
+		return item, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, qeury, args...).Scan(&item.ProductID, &item.MinQuantity, &item.Price)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.PriceListItem{}, nil
+	}
+
+	return item, err
+}
+
+func (r *PriceListRepo) insertItems(ctx context.Context, priceListID string, items []entity.PriceListItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	insert := r.pg.Builder.Insert("price_list_items").Columns("price_list_id, product_id, min_quantity, price")
+	for _, item := range items {
+		insert = insert.Values(priceListID, item.ProductID, item.MinQuantity, item.Price)
+	}
+
+	qeury, args, err := insert.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	return err
+}
+
+func scanPriceList(row pgx.Row) (entity.PriceList, error) {
+	var (
+		item                 entity.PriceList
+		validFrom, validTo   sql.NullTime
+		createdAt, updatedAt time.Time
+	)
+
+	err := row.Scan(&item.ID, &item.Name, &item.Type, &validFrom, &validTo, &item.IsActive, &item.Note,
+		&createdAt, &updatedAt)
+	if err != nil {
+		return entity.PriceList{}, err
+	}
+
+	item.ValidFrom = formatNullDate(validFrom)
+	item.ValidTo = formatNullDate(validTo)
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+	item.UpdatedAt = updatedAt.Format(time.RFC3339)
+
+	return item, nil
+}
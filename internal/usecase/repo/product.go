@@ -0,0 +1,586 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/logger"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/postgres"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+var (
+	ErrSerializedWithStock = entity.NewError(config.ErrorConflict, "Serial tracking can't be switched while the product is in stock")
+	ErrSerializedBundle    = entity.NewError(config.ErrorBadRequest, "Bundles, kits and their components can't be serialized")
+	ErrBundleWithStock     = entity.NewError(config.ErrorConflict, "A product in stock can't become a bundle, bundles hold no stock of their own")
+	ErrNestedBundle        = entity.NewError(config.ErrorBadRequest, "Components have to be simple products, bundles and kits can't be nested")
+)
+
+const productColumns = `id, sku, name, description, category_id, preferred_supplier_id, base_unit, sale_price, is_serialized, is_active, kind,
+	COALESCE((SELECT array_agg(barcode ORDER BY barcode) FROM product_barcodes
+		WHERE product_barcodes.product_id = products.id AND product_barcodes.unit IS NULL), '{}'),
+	created_at, updated_at`
+
+type ProductRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewProductRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *ProductRepo {
+	return &ProductRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+func (r *ProductRepo) Create(ctx context.Context, req entity.Product) (entity.Product, error) {
+	req.ID = uuid.NewString()
+	if req.BaseUnit == "" {
+		req.BaseUnit = "pcs"
+	}
+
+	err := r.pg.WithTx(ctx, func(ctx context.Context) error {
+		qeury, args, err := r.pg.Builder.Insert("products").
+			Columns(`id, sku, name, description, category_id, preferred_supplier_id, base_unit, sale_price, is_serialized, is_active`).
+			Values(req.ID, req.SKU, req.Name, req.Description, nullString(req.CategoryID), nullString(req.PreferredSupplierID),
+				req.BaseUnit, req.SalePrice, req.IsSerialized, req.IsActive).ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		if err != nil {
+			return err
+		}
+
+		err = r.insertBarcodes(ctx, req.ID, req.Barcodes)
+		if err != nil {
+			return err
+		}
+
+		return r.insertUnits(ctx, req.ID, req.Units)
+	})
+	if err != nil {
+		return entity.Product{}, err
+	}
+
+	return r.GetSingle(ctx, entity.ProductSingleRequest{ID: req.ID})
+}
+
+func (r *ProductRepo) GetSingle(ctx context.Context, req entity.ProductSingleRequest) (entity.Product, error) {
+	response := entity.Product{}
+	var (
+		createdAt, updatedAt            time.Time
+		categoryID, preferredSupplierID sql.NullString
+	)
+
+	qeuryBuilder := r.pg.Builder.Select(productColumns).From("products")
+
+	switch {
+	case req.ID != "":
+		qeuryBuilder = qeuryBuilder.Where("id = ?", req.ID)
+	case req.SKU != "":
+		qeuryBuilder = qeuryBuilder.Where("sku = ?", req.SKU)
+	case req.Barcode != "":
+		qeuryBuilder = qeuryBuilder.Where("id = (SELECT product_id FROM product_barcodes WHERE barcode = ?)", req.Barcode)
+	default:
+		return entity.Product{}, fmt.Errorf("GetSingle - invalid request")
+	}
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return entity.Product{}, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, qeury, args...).
+		Scan(&response.ID, &response.SKU, &response.Name, &response.Description, &categoryID, &preferredSupplierID, &response.BaseUnit,
+			&response.SalePrice, &response.IsSerialized, &response.IsActive, &response.Kind, &response.Barcodes, &createdAt, &updatedAt)
+	if err != nil {
+		return entity.Product{}, err
+	}
+
+	response.CategoryID = categoryID.String
+	response.PreferredSupplierID = preferredSupplierID.String
+	response.CreatedAt = createdAt.Format(time.RFC3339)
+	response.UpdatedAt = updatedAt.Format(time.RFC3339)
+
+	units, err := r.getUnits(ctx, []string{response.ID})
+	if err != nil {
+		return entity.Product{}, err
+	}
+	response.Units = units[response.ID]
+
+	if req.Barcode != "" {
+		qeury, args, err = r.pg.Builder.Select("COALESCE(unit, '')").From("product_barcodes").Where("barcode = ?", req.Barcode).ToSql()
+		if err != nil {
+			return entity.Product{}, err
+		}
+
+		err = r.pg.DB(ctx).QueryRow(ctx, qeury, args...).Scan(&response.BarcodeUnit)
+		if err != nil {
+			return entity.Product{}, err
+		}
+	}
+
+	if response.Kind != entity.ProductKindSimple {
+		response.Components, err = r.GetComponents(ctx, response.ID)
+		if err != nil {
+			return entity.Product{}, err
+		}
+	}
+
+	return response, nil
+}
+
+func (r *ProductRepo) GetList(ctx context.Context, req entity.GetListFilter) (entity.ProductList, error) {
+	var (
+		response                        = entity.ProductList{}
+		createdAt, updatedAt            time.Time
+		categoryID, preferredSupplierID sql.NullString
+	)
+
+	qeuryBuilder := r.pg.Builder.Select(productColumns).From("products")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item entity.Product
+		err = rows.Scan(&item.ID, &item.SKU, &item.Name, &item.Description, &categoryID, &preferredSupplierID, &item.BaseUnit,
+			&item.SalePrice, &item.IsSerialized, &item.IsActive, &item.Kind, &item.Barcodes, &createdAt, &updatedAt)
+		if err != nil {
+			return response, err
+		}
+
+		item.CategoryID = categoryID.String
+		item.PreferredSupplierID = preferredSupplierID.String
+		item.CreatedAt = createdAt.Format(time.RFC3339)
+		item.UpdatedAt = updatedAt.Format(time.RFC3339)
+
+		response.Items, err = listItem(response.Items, item, req)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return response, err
+	}
+
+	ids := make([]string, 0, len(response.Items))
+	for _, item := range response.Items {
+		ids = append(ids, item.ID)
+	}
+
+	units, err := r.getUnits(ctx, ids)
+	if err != nil {
+		return response, err
+	}
+
+	for i := range response.Items {
+		response.Items[i].Units = units[response.Items[i].ID]
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("products").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+func (r *ProductRepo) Update(ctx context.Context, req entity.Product) (entity.Product, error) {
+	mp := map[string]interface{}{
+		"sku":                   req.SKU,
+		"name":                  req.Name,
+		"description":           req.Description,
+		"category_id":           nullString(req.CategoryID),
+		"preferred_supplier_id": nullString(req.PreferredSupplierID),
+		"base_unit":             req.BaseUnit,
+		"sale_price":            req.SalePrice,
+		"is_serialized":         req.IsSerialized,
+		"is_active":             req.IsActive,
+		"updated_at":            "now()",
+	}
+
+	err := r.pg.WithTx(ctx, func(ctx context.Context) error {
+		// existing stock has no serial numbers, or they'd be orphaned
+		var locked, composed bool
+
+		qeury, args, err := r.pg.Builder.Select().
+			Column(`is_serialized <> ? AND EXISTS (SELECT 1 FROM stock_balances
+				WHERE stock_balances.product_id = products.id AND stock_balances.quantity <> 0)`, req.IsSerialized).
+			Column(`kind <> 'simple' OR EXISTS (SELECT 1 FROM product_components WHERE component_id = products.id)`).
+			From("products").Where("id = ?", req.ID).Suffix("FOR UPDATE").ToSql()
+		if err != nil {
+			return err
+		}
+
+		err = r.pg.DB(ctx).QueryRow(ctx, qeury, args...).Scan(&locked, &composed)
+		if err != nil {
+			return err
+		}
+
+		if locked {
+			return ErrSerializedWithStock
+		}
+
+		if composed && req.IsSerialized {
+			return ErrSerializedBundle
+		}
+
+		qeury, args, err = r.pg.Builder.Update("products").SetMap(mp).Where("id = ?", req.ID).ToSql()
+		if err != nil {
+			return err
+		}
+
+		tag, err := r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		if err != nil {
+			return err
+		}
+
+		if tag.RowsAffected() == 0 {
+			return pgx.ErrNoRows
+		}
+
+		// barcodes and units are replaced as a whole
+		for _, table := range []string{"product_barcodes", "product_units"} {
+			qeury, args, err = r.pg.Builder.Delete(table).Where("product_id = ?", req.ID).ToSql()
+			if err != nil {
+				return err
+			}
+
+			_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = r.insertBarcodes(ctx, req.ID, req.Barcodes)
+		if err != nil {
+			return err
+		}
+
+		return r.insertUnits(ctx, req.ID, req.Units)
+	})
+	if err != nil {
+		return entity.Product{}, err
+	}
+
+	return r.GetSingle(ctx, entity.ProductSingleRequest{ID: req.ID})
+}
+
+func (r *ProductRepo) Delete(ctx context.Context, req entity.Id) error {
+	qeury, args, err := r.pg.Builder.Delete("products").Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetComponents returns what one unit of a bundle or kit is made of.
+func (r *ProductRepo) GetComponents(ctx context.Context, productID string) ([]entity.ProductComponent, error) {
+	qeury, args, err := r.pg.Builder.
+		Select("product_components.component_id, products.name, products.sku, product_components.quantity").
+		From("product_components").
+		Join("products ON products.id = product_components.component_id").
+		Where("product_components.product_id = ?", productID).
+		OrderBy("products.name").ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var response []entity.ProductComponent
+	for rows.Next() {
+		var item entity.ProductComponent
+		if err = rows.Scan(&item.ComponentID, &item.Name, &item.SKU, &item.Quantity); err != nil {
+			return nil, err
+		}
+
+		response = append(response, item)
+	}
+
+	return response, rows.Err()
+}
+
+// SetComponents sets the kind of a product and replaces its components. Only
+// simple, non-serialized products can be components, a product that is a
+// component itself can't have any, and a product in stock can't become a bundle.
+func (r *ProductRepo) SetComponents(ctx context.Context, req entity.ProductComponentsRequest) (entity.Product, error) {
+	err := r.pg.WithTx(ctx, func(ctx context.Context) error {
+		var serialized, component, inStock bool
+
+		qeury, args, err := r.pg.Builder.Select().
+			Column("is_serialized").
+			Column("EXISTS (SELECT 1 FROM product_components WHERE component_id = products.id)").
+			Column("EXISTS (SELECT 1 FROM stock_balances WHERE stock_balances.product_id = products.id AND stock_balances.quantity <> 0)").
+			From("products").Where("id = ?", req.ProductID).Suffix("FOR UPDATE").ToSql()
+		if err != nil {
+			return err
+		}
+
+		err = r.pg.DB(ctx).QueryRow(ctx, qeury, args...).Scan(&serialized, &component, &inStock)
+		if err != nil {
+			return err
+		}
+
+		if req.Kind != entity.ProductKindSimple {
+			switch {
+			case serialized:
+				return ErrSerializedBundle
+			case component:
+				return ErrNestedBundle
+			case inStock && req.Kind == entity.ProductKindBundle:
+				return ErrBundleWithStock
+			}
+		}
+
+		if len(req.Components) > 0 {
+			var invalid int
+
+			ids := make([]string, 0, len(req.Components))
+			for _, item := range req.Components {
+				ids = append(ids, item.ComponentID)
+			}
+
+			// components are locked too, so they can't turn into bundles meanwhile
+			qeury, args, err = r.pg.Builder.Select("COUNT(1) FILTER (WHERE kind <> 'simple' OR is_serialized)").
+				FromSelect(r.pg.Builder.Select("kind, is_serialized").From("products").
+					Where("id = ANY(?)", ids).Suffix("FOR UPDATE"), "components").ToSql()
+			if err != nil {
+				return err
+			}
+
+			err = r.pg.DB(ctx).QueryRow(ctx, qeury, args...).Scan(&invalid)
+			if err != nil {
+				return err
+			}
+
+			if invalid > 0 {
+				return ErrNestedBundle
+			}
+		}
+
+		qeury, args, err = r.pg.Builder.Update("products").
+			Set("kind", req.Kind).
+			Set("updated_at", "now()").
+			Where("id = ?", req.ProductID).ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		if err != nil {
+			return err
+		}
+
+		qeury, args, err = r.pg.Builder.Delete("product_components").Where("product_id = ?", req.ProductID).ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		if err != nil {
+			return err
+		}
+
+		if len(req.Components) == 0 {
+			return nil
+		}
+
+		insert := r.pg.Builder.Insert("product_components").Columns("product_id, component_id, quantity")
+		for _, item := range req.Components {
+			insert = insert.Values(req.ProductID, item.ComponentID, item.Quantity)
+		}
+
+		qeury, args, err = insert.ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		return err
+	})
+	if err != nil {
+		return entity.Product{}, err
+	}
+
+	return r.GetSingle(ctx, entity.ProductSingleRequest{ID: req.ProductID})
+}
+
+func (r *ProductRepo) insertBarcodes(ctx context.Context, productID string, barcodes []string) error {
+	if len(barcodes) == 0 {
+		return nil
+	}
+
+	insert := r.pg.Builder.Insert("product_barcodes").Columns("barcode, product_id")
+	for _, barcode := range barcodes {
+		insert = insert.Values(barcode, productID)
+	}
+
+	qeury, args, err := insert.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	return err
+}
+
+func (r *ProductRepo) insertUnits(ctx context.Context, productID string, units []entity.ProductUnit) error {
+	if len(units) == 0 {
+		return nil
+	}
+
+	insert := r.pg.Builder.Insert("product_units").Columns("product_id, unit, factor")
+	barcodes := r.pg.Builder.Insert("product_barcodes").Columns("barcode, product_id, unit")
+
+	var withBarcode bool
+	for _, unit := range units {
+		insert = insert.Values(productID, unit.Unit, unit.Factor)
+
+		if unit.Barcode != "" {
+			barcodes = barcodes.Values(unit.Barcode, productID, unit.Unit)
+			withBarcode = true
+		}
+	}
+
+	qeury, args, err := insert.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil || !withBarcode {
+		return err
+	}
+
+	qeury, args, err = barcodes.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	return err
+}
+
+// getUnits returns the alternate units of the products, smallest first.
+func (r *ProductRepo) getUnits(ctx context.Context, productIDs []string) (map[string][]entity.ProductUnit, error) {
+	response := make(map[string][]entity.ProductUnit, len(productIDs))
+	for _, id := range productIDs {
+		response[id] = []entity.ProductUnit{}
+	}
+
+	if len(productIDs) == 0 {
+		return response, nil
+	}
+
+	qeury, args, err := r.pg.Builder.
+		Select("product_units.product_id, product_units.unit, product_units.factor, COALESCE(product_barcodes.barcode, '')").
+		From("product_units").
+		LeftJoin("product_barcodes ON product_barcodes.product_id = product_units.product_id AND product_barcodes.unit = product_units.unit").
+		Where("product_units.product_id = ANY(?)", productIDs).
+		OrderBy("product_units.factor").ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			productID string
+			item      entity.ProductUnit
+		)
+
+		err = rows.Scan(&productID, &item.Unit, &item.Factor, &item.Barcode)
+		if err != nil {
+			return nil, err
+		}
+
+		response[productID] = append(response[productID], item)
+	}
+
+	return response, rows.Err()
+}
+
+// GetReportUnits tells per product which unit a report asked in unit shows it
+// in: unit itself where the product defines it, else the base unit. Every
+// product is looked up when productIDs is nil, e.g. for exports.
+func (r *ProductRepo) GetReportUnits(ctx context.Context, unit string, productIDs []string) (map[string]entity.ProductUnit, error) {
+	response := make(map[string]entity.ProductUnit, len(productIDs))
+
+	qeuryBuilder := r.pg.Builder.
+		Select("products.id, COALESCE(product_units.unit, products.base_unit), COALESCE(product_units.factor, 1)").
+		From("products").
+		LeftJoin("product_units ON product_units.product_id = products.id AND product_units.unit = ?", unit)
+	if productIDs != nil {
+		qeuryBuilder = qeuryBuilder.Where("products.id = ANY(?)", productIDs)
+	}
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			productID string
+			item      entity.ProductUnit
+		)
+
+		err = rows.Scan(&productID, &item.Unit, &item.Factor)
+		if err != nil {
+			return nil, err
+		}
+
+		response[productID] = item
+	}
+
+	return response, rows.Err()
+}
@@ -0,0 +1,211 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/logger"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/postgres"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	promotionColumns = `id, name, type, value, product_id, category_id, valid_from, valid_to, is_active, created_at, updated_at`
+
+	// productCategoriesQuery is the category of the product passed and every
+	// category above it.
+	productCategoriesQuery = `WITH RECURSIVE ancestors AS (
+	SELECT id, parent_id FROM categories WHERE id = (SELECT category_id FROM products WHERE id = ?)
+	UNION ALL
+	SELECT c.id, c.parent_id FROM categories c JOIN ancestors ON c.id = ancestors.parent_id
+) SELECT id FROM ancestors`
+)
+
+type PromotionRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewPromotionRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *PromotionRepo {
+	return &PromotionRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+func (r *PromotionRepo) Create(ctx context.Context, req entity.Promotion) (entity.Promotion, error) {
+	req.ID = uuid.NewString()
+
+	qeury, args, err := r.pg.Builder.Insert("promotions").
+		Columns(`id, name, type, value, product_id, category_id, valid_from, valid_to, is_active`).
+		Values(req.ID, req.Name, req.Type, req.Value, nullString(req.ProductID), nullString(req.CategoryID),
+			squirrel.Expr("NULLIF(?, '')::DATE", req.ValidFrom), squirrel.Expr("NULLIF(?, '')::DATE", req.ValidTo),
+			req.IsActive).ToSql()
+	if err != nil {
+		return entity.Promotion{}, err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil {
+		return entity.Promotion{}, err
+	}
+
+	return r.GetSingle(ctx, entity.Id{ID: req.ID})
+}
+
+func (r *PromotionRepo) GetSingle(ctx context.Context, req entity.Id) (entity.Promotion, error) {
+	qeury, args, err := r.pg.Builder.Select(promotionColumns).From("promotions").Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return entity.Promotion{}, err
+	}
+
+	return scanPromotion(r.pg.DB(ctx).QueryRow(ctx, qeury, args...))
+}
+
+func (r *PromotionRepo) GetList(ctx context.Context, req entity.GetListFilter) (entity.PromotionList, error) {
+	response := entity.PromotionList{}
+
+	qeuryBuilder := r.pg.Builder.Select(promotionColumns).From("promotions")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanPromotion(rows)
+		if err != nil {
+			return response, err
+		}
+
+		response.Items, err = listItem(response.Items, item, req)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return response, err
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("promotions").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+func (r *PromotionRepo) Update(ctx context.Context, req entity.Promotion) (entity.Promotion, error) {
+	mp := map[string]interface{}{
+		"name":        req.Name,
+		"type":        req.Type,
+		"value":       req.Value,
+		"product_id":  nullString(req.ProductID),
+		"category_id": nullString(req.CategoryID),
+		"valid_from":  squirrel.Expr("NULLIF(?, '')::DATE", req.ValidFrom),
+		"valid_to":    squirrel.Expr("NULLIF(?, '')::DATE", req.ValidTo),
+		"is_active":   req.IsActive,
+		"updated_at":  "now()",
+	}
+
+	qeury, args, err := r.pg.Builder.Update("promotions").SetMap(mp).Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return entity.Promotion{}, err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil {
+		return entity.Promotion{}, err
+	}
+
+	return r.GetSingle(ctx, entity.Id{ID: req.ID})
+}
+
+func (r *PromotionRepo) Delete(ctx context.Context, req entity.Id) error {
+	qeury, args, err := r.pg.Builder.Delete("promotions").Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	return err
+}
+
+// GetActive returns the promotions in effect on date for a product, those on
+// the product itself and those on its category or any category above it.
+func (r *PromotionRepo) GetActive(ctx context.Context, productID, date string) ([]entity.Promotion, error) {
+	var response []entity.Promotion
+
+	qeury, args, err := r.pg.Builder.Select(promotionColumns).From("promotions").
+		Where(squirrel.Or{
+			squirrel.Eq{"product_id": productID},
+			squirrel.Expr("category_id IN ("+productCategoriesQuery+")", productID),
+		}).
+		Where(validOnExpr, date, date).ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanPromotion(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		response = append(response, item)
+	}
+
+	return response, rows.Err()
+}
+
+func scanPromotion(row pgx.Row) (entity.Promotion, error) {
+	var (
+		item                  entity.Promotion
+		productID, categoryID sql.NullString
+		validFrom, validTo    sql.NullTime
+		createdAt, updatedAt  time.Time
+	)
+
+	err := row.Scan(&item.ID, &item.Name, &item.Type, &item.Value, &productID, &categoryID, &validFrom, &validTo,
+		&item.IsActive, &createdAt, &updatedAt)
+	if err != nil {
+		return entity.Promotion{}, err
+	}
+
+	item.ProductID = productID.String
+	item.CategoryID = categoryID.String
+	item.ValidFrom = formatNullDate(validFrom)
+	item.ValidTo = formatNullDate(validTo)
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+	item.UpdatedAt = updatedAt.Format(time.RFC3339)
+
+	return item, nil
+}
@@ -0,0 +1,371 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/logger"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/postgres"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+var ErrPurchaseOrderLineMismatch = entity.NewError(config.ErrorBadRequest, "Receipt line does not match a line of its purchase order")
+
+const purchaseOrderColumns = `id, number, supplier_id, warehouse_id, order_date, expected_date, status, note, currency, exchange_rate,
+	created_by, sent_at, closed_at,
+	COALESCE((SELECT SUM(quantity * price) FROM purchase_order_lines WHERE purchase_order_lines.order_id = purchase_orders.id), 0),
+	created_at, updated_at`
+
+type PurchaseOrderRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewPurchaseOrderRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *PurchaseOrderRepo {
+	return &PurchaseOrderRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+func (r *PurchaseOrderRepo) Create(ctx context.Context, req entity.PurchaseOrder) (entity.PurchaseOrder, error) {
+	req.ID = uuid.NewString()
+
+	err := r.pg.WithTx(ctx, func(ctx context.Context) error {
+		orderDate := squirrel.Expr("COALESCE(NULLIF(?, '')::DATE, CURRENT_DATE)", req.OrderDate)
+
+		rate, err := exchangeRate(ctx, r.pg, &req.Currency, orderDate)
+		if err != nil {
+			return err
+		}
+
+		qeury, args, err := r.pg.Builder.Insert("purchase_orders").
+			Columns(`id, number, supplier_id, warehouse_id, order_date, expected_date, note, currency, exchange_rate, created_by`).
+			Values(req.ID,
+				squirrel.Expr("COALESCE(NULLIF(?, ''), 'PO-' || LPAD(nextval('purchase_order_number_seq')::TEXT, 6, '0'))", req.Number),
+				req.SupplierID, req.WarehouseID, orderDate,
+				squirrel.Expr("NULLIF(?, '')::DATE", req.ExpectedDate),
+				req.Note, req.Currency, rate, nullString(req.CreatedBy)).ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		if err != nil {
+			return err
+		}
+
+		return r.insertLines(ctx, req.ID, req.Lines)
+	})
+	if err != nil {
+		return entity.PurchaseOrder{}, err
+	}
+
+	return r.GetSingle(ctx, entity.Id{ID: req.ID})
+}
+
+func (r *PurchaseOrderRepo) GetSingle(ctx context.Context, req entity.Id) (entity.PurchaseOrder, error) {
+	qeury, args, err := r.pg.Builder.Select(purchaseOrderColumns).From("purchase_orders").Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return entity.PurchaseOrder{}, err
+	}
+
+	response, err := scanPurchaseOrder(r.pg.DB(ctx).QueryRow(ctx, qeury, args...))
+	if err != nil {
+		return entity.PurchaseOrder{}, err
+	}
+
+	qeury, args, err = r.pg.Builder.Select(`id, product_id, unit, unit_quantity, quantity, received, price`).
+		From("purchase_order_lines").Where("order_id = ?", req.ID).OrderBy("line_no").ToSql()
+	if err != nil {
+		return entity.PurchaseOrder{}, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return entity.PurchaseOrder{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var line entity.PurchaseOrderLine
+
+		err = rows.Scan(&line.ID, &line.ProductID, &line.Unit, &line.UnitQuantity, &line.Quantity, &line.Received, &line.Price)
+		if err != nil {
+			return entity.PurchaseOrder{}, err
+		}
+
+		response.Lines = append(response.Lines, line)
+	}
+
+	return response, rows.Err()
+}
+
+func (r *PurchaseOrderRepo) GetList(ctx context.Context, req entity.GetListFilter) (entity.PurchaseOrderList, error) {
+	response := entity.PurchaseOrderList{}
+
+	qeuryBuilder := r.pg.Builder.Select(purchaseOrderColumns).From("purchase_orders")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanPurchaseOrder(rows)
+		if err != nil {
+			return response, err
+		}
+
+		response.Items, err = listItem(response.Items, item, req)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return response, err
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("purchase_orders").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+// Update replaces the header fields and the lines of a draft purchase order.
+func (r *PurchaseOrderRepo) Update(ctx context.Context, req entity.PurchaseOrder) (entity.PurchaseOrder, error) {
+	err := r.pg.WithTx(ctx, func(ctx context.Context) error {
+		status, err := lockStatus(ctx, r.pg, "purchase_orders", req.ID)
+		if err != nil {
+			return err
+		}
+
+		if status != entity.PurchaseOrderStatusDraft {
+			return ErrDocumentNotDraft
+		}
+
+		rate, err := exchangeRate(ctx, r.pg, &req.Currency,
+			squirrel.Expr("COALESCE(NULLIF(?, '')::DATE, (SELECT order_date FROM purchase_orders WHERE id = ?))", req.OrderDate, req.ID))
+		if err != nil {
+			return err
+		}
+
+		mp := map[string]interface{}{
+			"supplier_id":   req.SupplierID,
+			"warehouse_id":  req.WarehouseID,
+			"order_date":    squirrel.Expr("COALESCE(NULLIF(?, '')::DATE, order_date)", req.OrderDate),
+			"expected_date": squirrel.Expr("NULLIF(?, '')::DATE", req.ExpectedDate),
+			"note":          req.Note,
+			"currency":      req.Currency,
+			"exchange_rate": rate,
+			"updated_at":    "now()",
+		}
+
+		if req.Number != "" {
+			mp["number"] = req.Number
+		}
+
+		qeury, args, err := r.pg.Builder.Update("purchase_orders").SetMap(mp).Where("id = ?", req.ID).ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		if err != nil {
+			return err
+		}
+
+		qeury, args, err = r.pg.Builder.Delete("purchase_order_lines").Where("order_id = ?", req.ID).ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		if err != nil {
+			return err
+		}
+
+		return r.insertLines(ctx, req.ID, req.Lines)
+	})
+	if err != nil {
+		return entity.PurchaseOrder{}, err
+	}
+
+	return r.GetSingle(ctx, entity.Id{ID: req.ID})
+}
+
+// Delete removes a draft purchase order.
+func (r *PurchaseOrderRepo) Delete(ctx context.Context, req entity.Id) error {
+	return r.pg.WithTx(ctx, func(ctx context.Context) error {
+		status, err := lockStatus(ctx, r.pg, "purchase_orders", req.ID)
+		if err != nil {
+			return err
+		}
+
+		if status != entity.PurchaseOrderStatusDraft {
+			return ErrDocumentNotDraft
+		}
+
+		qeury, args, err := r.pg.Builder.Delete("purchase_orders").Where("id = ?", req.ID).ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		return err
+	})
+}
+
+// LockStatus locks the purchase order for the rest of the transaction and returns its status.
+func (r *PurchaseOrderRepo) LockStatus(ctx context.Context, req entity.Id) (string, error) {
+	return lockStatus(ctx, r.pg, "purchase_orders", req.ID)
+}
+
+func (r *PurchaseOrderRepo) SetStatus(ctx context.Context, req entity.Id, status string) error {
+	mp := map[string]interface{}{
+		"status":     status,
+		"updated_at": "now()",
+	}
+
+	switch status {
+	case entity.PurchaseOrderStatusSent:
+		mp["sent_at"] = "now()"
+	case entity.PurchaseOrderStatusClosed:
+		mp["closed_at"] = "now()"
+	}
+
+	qeury, args, err := r.pg.Builder.Update("purchase_orders").SetMap(mp).Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// AddReceived records quantity as received on a line of the order, negative
+// quantities take it off again. The line has to be of productID.
+func (r *PurchaseOrderRepo) AddReceived(ctx context.Context, orderID, lineID, productID string, quantity float64) error {
+	qeury, args, err := r.pg.Builder.Update("purchase_order_lines").
+		Set("received", squirrel.Expr("received + ?", quantity)).
+		Where("id = ? AND order_id = ? AND product_id = ?", lineID, orderID, productID).ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrPurchaseOrderLineMismatch
+	}
+
+	return nil
+}
+
+// RefreshStatus moves an open order to sent, partially received or received
+// from what its lines have received. Closed and draft orders are left alone.
+func (r *PurchaseOrderRepo) RefreshStatus(ctx context.Context, req entity.Id) error {
+	qeury, args, err := r.pg.Builder.Update("purchase_orders").
+		Set("status", squirrel.Expr(`CASE
+			WHEN NOT EXISTS (SELECT 1 FROM purchase_order_lines WHERE order_id = purchase_orders.id AND received > 0) THEN ?
+			WHEN NOT EXISTS (SELECT 1 FROM purchase_order_lines WHERE order_id = purchase_orders.id AND received < quantity) THEN ?
+			ELSE ? END`,
+			entity.PurchaseOrderStatusSent, entity.PurchaseOrderStatusReceived, entity.PurchaseOrderStatusPartiallyReceived)).
+		Set("updated_at", "now()").
+		Where("id = ?", req.ID).
+		Where(squirrel.Eq{"status": []string{entity.PurchaseOrderStatusSent, entity.PurchaseOrderStatusPartiallyReceived,
+			entity.PurchaseOrderStatusReceived}}).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	return err
+}
+
+func (r *PurchaseOrderRepo) insertLines(ctx context.Context, orderID string, lines []entity.PurchaseOrderLine) error {
+	if len(lines) == 0 {
+		return nil
+	}
+
+	insert := r.pg.Builder.Insert("purchase_order_lines").
+		Columns(`id, order_id, line_no, product_id, unit, unit_quantity, quantity, price`)
+	for i, line := range lines {
+		err := toBaseUnit(ctx, r.pg, line.ProductID, &line.Unit, &line.UnitQuantity, &line.Quantity)
+		if err != nil {
+			return err
+		}
+
+		insert = insert.Values(uuid.NewString(), orderID, i+1, line.ProductID, line.Unit, line.UnitQuantity, line.Quantity, line.Price)
+	}
+
+	qeury, args, err := insert.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	return err
+}
+
+func scanPurchaseOrder(row pgx.Row) (entity.PurchaseOrder, error) {
+	var (
+		item                 entity.PurchaseOrder
+		createdBy            sql.NullString
+		expectedDate         sql.NullTime
+		sentAt, closedAt     sql.NullTime
+		orderDate            time.Time
+		createdAt, updatedAt time.Time
+	)
+
+	err := row.Scan(&item.ID, &item.Number, &item.SupplierID, &item.WarehouseID, &orderDate, &expectedDate, &item.Status,
+		&item.Note, &item.Currency, &item.ExchangeRate, &createdBy, &sentAt, &closedAt, &item.TotalAmount, &createdAt, &updatedAt)
+	if err != nil {
+		return entity.PurchaseOrder{}, err
+	}
+
+	item.CreatedBy = createdBy.String
+	item.OrderDate = orderDate.Format("2006-01-02")
+	item.ExpectedDate = formatNullDate(expectedDate)
+	item.SentAt = formatNullTime(sentAt)
+	item.ClosedAt = formatNullTime(closedAt)
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+	item.UpdatedAt = updatedAt.Format(time.RFC3339)
+
+	return item, nil
+}
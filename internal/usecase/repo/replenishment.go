@@ -0,0 +1,219 @@
+package repo
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/logger"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/postgres"
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+)
+
+const stockLevelColumns = `product_id, warehouse_id, min_quantity, max_quantity, reorder_point, updated_at`
+
+type ReplenishmentRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewReplenishmentRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *ReplenishmentRepo {
+	return &ReplenishmentRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+// SetLevel creates or replaces the stock level of a product in a warehouse.
+func (r *ReplenishmentRepo) SetLevel(ctx context.Context, req entity.StockLevel) (entity.StockLevel, error) {
+	qeury, args, err := r.pg.Builder.Insert("stock_levels").
+		Columns(`product_id, warehouse_id, min_quantity, max_quantity, reorder_point`).
+		Values(req.ProductID, req.WarehouseID, req.MinQuantity, req.MaxQuantity, req.ReorderPoint).
+		Suffix(`ON CONFLICT (product_id, warehouse_id) DO UPDATE
+			SET min_quantity = EXCLUDED.min_quantity, max_quantity = EXCLUDED.max_quantity,
+				reorder_point = EXCLUDED.reorder_point, updated_at = NOW()
+			RETURNING ` + stockLevelColumns).ToSql()
+	if err != nil {
+		return entity.StockLevel{}, err
+	}
+
+	return scanStockLevel(r.pg.DB(ctx).QueryRow(ctx, qeury, args...))
+}
+
+func (r *ReplenishmentRepo) GetLevels(ctx context.Context, req entity.GetListFilter) (entity.StockLevelList, error) {
+	response := entity.StockLevelList{}
+
+	qeuryBuilder := r.pg.Builder.Select(stockLevelColumns).From("stock_levels")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanStockLevel(rows)
+		if err != nil {
+			return response, err
+		}
+
+		response.Items, err = listItem(response.Items, item, req)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return response, err
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("stock_levels").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+func (r *ReplenishmentRepo) DeleteLevel(ctx context.Context, productID, warehouseID string) error {
+	qeury, args, err := r.pg.Builder.Delete("stock_levels").
+		Where("product_id = ? AND warehouse_id = ?", productID, warehouseID).ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// levelsQuery puts the quantities that replenishment looks at next to every
+// stock level of an active product.
+func (r *ReplenishmentRepo) levelsQuery() squirrel.SelectBuilder {
+	return r.pg.Builder.
+		Select(`stock_levels.product_id, products.sku, products.name AS product_name, stock_levels.warehouse_id,
+			COALESCE(products.preferred_supplier_id::TEXT, '') AS supplier_id, COALESCE(suppliers.name, '') AS supplier_name,
+			stock_levels.min_quantity, stock_levels.reorder_point, stock_levels.max_quantity`).
+		Column(`COALESCE((SELECT SUM(stock_balances.quantity) FROM stock_balances
+			JOIN bins ON bins.id = stock_balances.bin_id
+			WHERE stock_balances.product_id = stock_levels.product_id AND stock_balances.warehouse_id = stock_levels.warehouse_id
+				AND NOT bins.is_virtual), 0) AS on_hand`).
+		Column(`COALESCE((SELECT SUM(stock_balances.quantity) FROM stock_balances
+			JOIN bins ON bins.id = stock_balances.bin_id
+			WHERE stock_balances.product_id = stock_levels.product_id AND stock_balances.warehouse_id = stock_levels.warehouse_id
+				AND bins.is_virtual), 0) AS in_transit`).
+		Column(`COALESCE((SELECT SUM(GREATEST(purchase_order_lines.quantity - purchase_order_lines.received, 0)) FROM purchase_order_lines
+			JOIN purchase_orders ON purchase_orders.id = purchase_order_lines.order_id
+			WHERE purchase_order_lines.product_id = stock_levels.product_id AND purchase_orders.warehouse_id = stock_levels.warehouse_id
+				AND purchase_orders.status IN (?, ?, ?)), 0) AS on_order`,
+			entity.PurchaseOrderStatusDraft, entity.PurchaseOrderStatusSent, entity.PurchaseOrderStatusPartiallyReceived).
+		Column(`COALESCE((SELECT SUM(reserved_rows.quantity) FROM (` + reservedRows + `) AS reserved_rows
+			WHERE reserved_rows.product_id = stock_levels.product_id AND reserved_rows.warehouse_id = stock_levels.warehouse_id), 0) AS reserved`).
+		Column(`COALESCE((SELECT unit_cost FROM cost_layers WHERE cost_layers.product_id = stock_levels.product_id
+			ORDER BY created_at DESC, id LIMIT 1), 0) AS unit_cost`).
+		From("stock_levels").
+		Join("products ON products.id = stock_levels.product_id").
+		LeftJoin("suppliers ON suppliers.id = products.preferred_supplier_id").
+		Where("products.is_active")
+}
+
+// GetSuggestions returns what to order, grouped by the preferred supplier of the
+// products. A product is suggested once its available quantity falls to the
+// reorder point, and the suggestion brings it back up to the max quantity.
+// Filters may use product_id, warehouse_id and supplier_id.
+func (r *ReplenishmentRepo) GetSuggestions(ctx context.Context, req entity.GetListFilter) (entity.ReplenishmentSuggestionList, error) {
+	response := entity.ReplenishmentSuggestionList{}
+
+	qeury, args, err := r.pg.Builder.
+		Select(`product_id, sku, product_name, warehouse_id, supplier_id, supplier_name, min_quantity, reorder_point,
+			max_quantity, on_hand, in_transit, on_order, reserved, unit_cost`).
+		FromSelect(r.levelsQuery(), "levels").
+		Where("on_hand + in_transit + on_order - reserved <= reorder_point").
+		Where("on_hand + in_transit + on_order - reserved < max_quantity").
+		Where(PrepareFilter(req.Filters)).
+		OrderBy("supplier_name", "supplier_id", "product_name", "product_id", "warehouse_id").ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			item                     entity.ReplenishmentSuggestion
+			supplierID, supplierName string
+		)
+
+		err = rows.Scan(&item.ProductID, &item.SKU, &item.ProductName, &item.WarehouseID, &supplierID, &supplierName,
+			&item.MinQuantity, &item.ReorderPoint, &item.MaxQuantity, &item.OnHand, &item.InTransit, &item.OnOrder,
+			&item.Reserved, &item.UnitCost)
+		if err != nil {
+			return response, err
+		}
+
+		item.Available = item.OnHand + item.InTransit + item.OnOrder - item.Reserved
+		item.BelowMin = item.Available < item.MinQuantity
+		item.Quantity = math.Round((item.MaxQuantity-item.Available)*1000) / 1000
+
+		groups := response.Suppliers
+		if len(groups) == 0 || groups[len(groups)-1].SupplierID != supplierID {
+			response.Suppliers = append(response.Suppliers, entity.SupplierSuggestions{
+				SupplierID:   supplierID,
+				SupplierName: supplierName,
+			})
+		}
+
+		group := &response.Suppliers[len(response.Suppliers)-1]
+		group.Items = append(group.Items, item)
+		group.TotalAmount += item.Quantity * item.UnitCost
+
+		response.Count++
+	}
+
+	return response, rows.Err()
+}
+
+func scanStockLevel(row pgx.Row) (entity.StockLevel, error) {
+	var (
+		item      entity.StockLevel
+		updatedAt time.Time
+	)
+
+	err := row.Scan(&item.ProductID, &item.WarehouseID, &item.MinQuantity, &item.MaxQuantity, &item.ReorderPoint, &updatedAt)
+	if err != nil {
+		return entity.StockLevel{}, err
+	}
+
+	item.UpdatedAt = updatedAt.Format(time.RFC3339)
+
+	return item, nil
+}
@@ -0,0 +1,279 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/logger"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/postgres"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+)
+
+// activeReservation leaves out reservations that have lapsed.
+const activeReservation = `(stock_reservations.expires_at IS NULL OR stock_reservations.expires_at > NOW())`
+
+// reservedRows lists what active reservations hold per product and warehouse.
+// Bundles hold no stock, their reservations hold their components instead.
+const reservedRows = `SELECT stock_reservations.product_id, stock_reservations.warehouse_id, stock_reservations.quantity
+		FROM stock_reservations
+		JOIN products ON products.id = stock_reservations.product_id AND products.kind <> 'bundle'
+		WHERE ` + activeReservation + `
+	UNION ALL
+	SELECT product_components.component_id, stock_reservations.warehouse_id,
+			stock_reservations.quantity * product_components.quantity
+		FROM stock_reservations
+		JOIN products ON products.id = stock_reservations.product_id AND products.kind = 'bundle'
+		JOIN product_components ON product_components.product_id = stock_reservations.product_id
+		WHERE ` + activeReservation
+
+// stockAvailability sums on-hand stock outside virtual bins and active
+// reservations per product and warehouse.
+const stockAvailability = `SELECT product_id, warehouse_id, SUM(on_hand) AS on_hand, SUM(reserved) AS reserved
+	FROM (SELECT stock_balances.product_id, stock_balances.warehouse_id, stock_balances.quantity AS on_hand, 0 AS reserved
+			FROM stock_balances JOIN bins ON bins.id = stock_balances.bin_id
+			WHERE NOT bins.is_virtual
+		UNION ALL
+		SELECT product_id, warehouse_id, 0, quantity FROM (` + reservedRows + `) AS reserved_rows) AS atp_rows
+	GROUP BY product_id, warehouse_id`
+
+// bundleAvailability derives bundles from their components: on hand is how
+// many could be put together from stock, reserved how many of those the
+// components' reservations take away. Warehouses missing a component are left out.
+const bundleAvailability = `SELECT product_components.product_id, component_atp.warehouse_id,
+		FLOOR(MIN(component_atp.on_hand / product_components.quantity)) AS on_hand,
+		FLOOR(MIN(component_atp.on_hand / product_components.quantity))
+			- FLOOR(MIN((component_atp.on_hand - component_atp.reserved) / product_components.quantity)) AS reserved
+	FROM product_components
+	JOIN products ON products.id = product_components.product_id AND products.kind = 'bundle'
+	JOIN (` + stockAvailability + `) AS component_atp ON component_atp.product_id = product_components.component_id
+	GROUP BY product_components.product_id, component_atp.warehouse_id
+	HAVING COUNT(1) = (SELECT COUNT(1) FROM product_components AS all_components
+		WHERE all_components.product_id = product_components.product_id)`
+
+const reservationColumns = `id, order_id, line_id, product_id, warehouse_id, quantity, expires_at,
+	NOT ` + activeReservation + `, created_at`
+
+type ReservationRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewReservationRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *ReservationRepo {
+	return &ReservationRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+// LockAvailable locks the balances of a product in a warehouse until the end of
+// the current transaction and returns its available-to-promise quantity.
+// Concurrent reservations of the product wait for each other here, so the
+// same stock can't be promised twice.
+func (r *ReservationRepo) LockAvailable(ctx context.Context, productID, warehouseID string) (float64, error) {
+	var onHand, reserved float64
+
+	qeury, args, err := r.pg.Builder.Select("stock_balances.quantity").From("stock_balances").
+		Join("bins ON bins.id = stock_balances.bin_id").
+		Where("stock_balances.product_id = ? AND stock_balances.warehouse_id = ? AND NOT bins.is_virtual", productID, warehouseID).
+		Suffix("FOR UPDATE OF stock_balances").ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var quantity float64
+		if err = rows.Scan(&quantity); err != nil {
+			return 0, err
+		}
+
+		onHand += quantity
+	}
+
+	if err = rows.Err(); err != nil {
+		return 0, err
+	}
+
+	qeury, args, err = r.pg.Builder.Select("COALESCE(SUM(quantity), 0)").From("("+reservedRows+") AS reserved_rows").
+		Where("product_id = ? AND warehouse_id = ?", productID, warehouseID).ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, qeury, args...).Scan(&reserved)
+	if err != nil {
+		return 0, err
+	}
+
+	return onHand - reserved, nil
+}
+
+// Create reserves the lines, they lapse after hours unless hours is zero.
+func (r *ReservationRepo) Create(ctx context.Context, req []entity.StockReservation, hours int) error {
+	if len(req) == 0 {
+		return nil
+	}
+
+	expiresAt := squirrel.Expr("NULL::TIMESTAMP")
+	if hours > 0 {
+		expiresAt = squirrel.Expr("NOW() + make_interval(hours => ?)", hours)
+	}
+
+	insert := r.pg.Builder.Insert("stock_reservations").
+		Columns(`id, order_id, line_id, product_id, warehouse_id, quantity, expires_at`)
+	for _, item := range req {
+		insert = insert.Values(uuid.NewString(), item.OrderID, item.LineID, item.ProductID, item.WarehouseID, item.Quantity,
+			expiresAt)
+	}
+
+	qeury, args, err := insert.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	return err
+}
+
+// Release drops the reservations of an order.
+func (r *ReservationRepo) Release(ctx context.Context, orderID string) error {
+	qeury, args, err := r.pg.Builder.Delete("stock_reservations").Where("order_id = ?", orderID).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	return err
+}
+
+func (r *ReservationRepo) GetList(ctx context.Context, req entity.GetListFilter) (entity.StockReservationList, error) {
+	response := entity.StockReservationList{}
+
+	qeuryBuilder := r.pg.Builder.Select(reservationColumns).From("stock_reservations")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			item      entity.StockReservation
+			expiresAt sql.NullTime
+			createdAt time.Time
+		)
+
+		err = rows.Scan(&item.ID, &item.OrderID, &item.LineID, &item.ProductID, &item.WarehouseID, &item.Quantity,
+			&expiresAt, &item.IsExpired, &createdAt)
+		if err != nil {
+			return response, err
+		}
+
+		item.ExpiresAt = formatNullTime(expiresAt)
+		item.CreatedAt = createdAt.Format(time.RFC3339)
+
+		response.Items, err = listItem(response.Items, item, req)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return response, err
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("stock_reservations").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+// availabilityQuery returns on hand and reserved quantities per product and
+// warehouse, bundles derived from their components.
+func (r *ReservationRepo) availabilityQuery() squirrel.SelectBuilder {
+	return r.pg.Builder.Select(`product_id, warehouse_id, on_hand, reserved`).
+		From("(" + stockAvailability + " UNION ALL " + bundleAvailability + ") AS atp_union")
+}
+
+// GetAvailability returns the available-to-promise quantities. Filters may use
+// product_id and warehouse_id.
+func (r *ReservationRepo) GetAvailability(ctx context.Context, req entity.GetListFilter) (entity.StockAvailabilityList, error) {
+	response := entity.StockAvailabilityList{}
+
+	qeuryBuilder := r.pg.Builder.Select(`product_id, warehouse_id, on_hand, reserved`).
+		FromSelect(r.availabilityQuery(), "atp").
+		Where("(on_hand <> 0 OR reserved <> 0)")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item entity.StockAvailability
+
+		err = rows.Scan(&item.ProductID, &item.WarehouseID, &item.OnHand, &item.Reserved)
+		if err != nil {
+			return response, err
+		}
+
+		item.Available = item.OnHand - item.Reserved
+
+		response.Items, err = listItem(response.Items, item, req)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return response, err
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").FromSelect(r.availabilityQuery(), "atp").
+		Where("(on_hand <> 0 OR reserved <> 0)").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
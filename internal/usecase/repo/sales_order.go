@@ -0,0 +1,424 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/logger"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/postgres"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+var (
+	ErrOrderNotPending      = entity.NewError(config.ErrorInvalidStatus, "Only pending orders can be changed or deleted")
+	ErrReturnExceedsShipped = entity.NewError(config.ErrorReturnExceeds, "Returned quantity exceeds the shipped quantity")
+)
+
+const (
+	orderTotalExpr    = `COALESCE((SELECT SUM((quantity - shorted - returned) * price) FROM sales_order_lines WHERE sales_order_lines.order_id = sales_orders.id), 0)`
+	orderReturnedExpr = `COALESCE((SELECT SUM(returned * price) FROM sales_order_lines WHERE sales_order_lines.order_id = sales_orders.id), 0)`
+	orderPaidExpr     = `COALESCE((SELECT SUM(amount) FROM customer_payments WHERE customer_payments.order_id = sales_orders.id), 0)`
+
+	salesOrderColumns = `id, number, customer_id, customer_name, warehouse_id, order_date, status, note, currency, exchange_rate, created_by, ` +
+		orderTotalExpr + `, ` + orderReturnedExpr + `, ` + orderPaidExpr + `, completed_at, created_at, updated_at`
+)
+
+type SalesOrderRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewSalesOrderRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *SalesOrderRepo {
+	return &SalesOrderRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+func (r *SalesOrderRepo) Create(ctx context.Context, req entity.SalesOrder) (entity.SalesOrder, error) {
+	req.ID = uuid.NewString()
+
+	err := r.pg.WithTx(ctx, func(ctx context.Context) error {
+		orderDate := squirrel.Expr("COALESCE(NULLIF(?, '')::DATE, CURRENT_DATE)", req.OrderDate)
+
+		rate, err := exchangeRate(ctx, r.pg, &req.Currency, orderDate)
+		if err != nil {
+			return err
+		}
+
+		qeury, args, err := r.pg.Builder.Insert("sales_orders").
+			Columns(`id, number, customer_id, customer_name, warehouse_id, order_date, note, currency, exchange_rate, created_by`).
+			Values(req.ID,
+				squirrel.Expr("COALESCE(NULLIF(?, ''), 'SO-' || LPAD(nextval('sales_order_number_seq')::TEXT, 6, '0'))", req.Number),
+				nullString(req.CustomerID), customerNameExpr(req), req.WarehouseID, orderDate,
+				req.Note, req.Currency, rate, nullString(req.CreatedBy)).ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		if err != nil {
+			return err
+		}
+
+		return r.insertLines(ctx, req.ID, req.Lines)
+	})
+	if err != nil {
+		return entity.SalesOrder{}, err
+	}
+
+	return r.GetSingle(ctx, entity.Id{ID: req.ID})
+}
+
+func (r *SalesOrderRepo) GetSingle(ctx context.Context, req entity.Id) (entity.SalesOrder, error) {
+	qeury, args, err := r.pg.Builder.Select(salesOrderColumns).From("sales_orders").Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return entity.SalesOrder{}, err
+	}
+
+	response, err := scanSalesOrder(r.pg.DB(ctx).QueryRow(ctx, qeury, args...))
+	if err != nil {
+		return entity.SalesOrder{}, err
+	}
+
+	qeury, args, err = r.pg.Builder.
+		Select(`id, product_id, bin_id, lot_id, serials, unit, unit_quantity, quantity, shipped, shorted, returned, price,
+			list_price, price_rules`).
+		From("sales_order_lines").Where("order_id = ?", req.ID).OrderBy("line_no").ToSql()
+	if err != nil {
+		return entity.SalesOrder{}, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return entity.SalesOrder{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			line  entity.SalesOrderLine
+			lotID sql.NullString
+		)
+
+		err = rows.Scan(&line.ID, &line.ProductID, &line.BinID, &lotID, &line.Serials, &line.Unit, &line.UnitQuantity,
+			&line.Quantity, &line.Shipped, &line.Shorted, &line.Returned, &line.Price, &line.ListPrice, &line.PriceRules)
+		if err != nil {
+			return entity.SalesOrder{}, err
+		}
+
+		line.LotID = lotID.String
+
+		response.Lines = append(response.Lines, line)
+	}
+
+	return response, rows.Err()
+}
+
+func (r *SalesOrderRepo) GetList(ctx context.Context, req entity.GetListFilter) (entity.SalesOrderList, error) {
+	response := entity.SalesOrderList{}
+
+	qeuryBuilder := r.pg.Builder.Select(salesOrderColumns).From("sales_orders")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanSalesOrder(rows)
+		if err != nil {
+			return response, err
+		}
+
+		response.Items, err = listItem(response.Items, item, req)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return response, err
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("sales_orders").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+// Update replaces the header fields and the lines of a pending order.
+func (r *SalesOrderRepo) Update(ctx context.Context, req entity.SalesOrder) (entity.SalesOrder, error) {
+	err := r.pg.WithTx(ctx, func(ctx context.Context) error {
+		status, err := lockStatus(ctx, r.pg, "sales_orders", req.ID)
+		if err != nil {
+			return err
+		}
+
+		if status != entity.OrderStatusPending {
+			return ErrOrderNotPending
+		}
+
+		rate, err := exchangeRate(ctx, r.pg, &req.Currency,
+			squirrel.Expr("COALESCE(NULLIF(?, '')::DATE, (SELECT order_date FROM sales_orders WHERE id = ?))", req.OrderDate, req.ID))
+		if err != nil {
+			return err
+		}
+
+		mp := map[string]interface{}{
+			"customer_id":   nullString(req.CustomerID),
+			"customer_name": customerNameExpr(req),
+			"warehouse_id":  req.WarehouseID,
+			"order_date":    squirrel.Expr("COALESCE(NULLIF(?, '')::DATE, order_date)", req.OrderDate),
+			"note":          req.Note,
+			"currency":      req.Currency,
+			"exchange_rate": rate,
+			"updated_at":    "now()",
+		}
+
+		if req.Number != "" {
+			mp["number"] = req.Number
+		}
+
+		qeury, args, err := r.pg.Builder.Update("sales_orders").SetMap(mp).Where("id = ?", req.ID).ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		if err != nil {
+			return err
+		}
+
+		qeury, args, err = r.pg.Builder.Delete("sales_order_lines").Where("order_id = ?", req.ID).ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		if err != nil {
+			return err
+		}
+
+		return r.insertLines(ctx, req.ID, req.Lines)
+	})
+	if err != nil {
+		return entity.SalesOrder{}, err
+	}
+
+	return r.GetSingle(ctx, entity.Id{ID: req.ID})
+}
+
+// Delete removes a pending order, anything further along must be cancelled instead.
+func (r *SalesOrderRepo) Delete(ctx context.Context, req entity.Id) error {
+	return r.pg.WithTx(ctx, func(ctx context.Context) error {
+		status, err := lockStatus(ctx, r.pg, "sales_orders", req.ID)
+		if err != nil {
+			return err
+		}
+
+		if status != entity.OrderStatusPending {
+			return ErrOrderNotPending
+		}
+
+		qeury, args, err := r.pg.Builder.Delete("sales_orders").Where("id = ?", req.ID).ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		return err
+	})
+}
+
+// LockStatus locks the order for the rest of the transaction and returns its status.
+func (r *SalesOrderRepo) LockStatus(ctx context.Context, req entity.Id) (string, error) {
+	return lockStatus(ctx, r.pg, "sales_orders", req.ID)
+}
+
+func (r *SalesOrderRepo) SetStatus(ctx context.Context, req entity.Id, status string) error {
+	mp := map[string]interface{}{
+		"status":     status,
+		"updated_at": "now()",
+	}
+
+	if status == entity.OrderStatusCompleted {
+		mp["completed_at"] = "now()"
+	}
+
+	qeury, args, err := r.pg.Builder.Update("sales_orders").SetMap(mp).Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// AddShipped records quantity as shipped on a line of the order.
+func (r *SalesOrderRepo) AddShipped(ctx context.Context, orderID, lineID string, quantity float64) error {
+	qeury, args, err := r.pg.Builder.Update("sales_order_lines").
+		Set("shipped", squirrel.Expr("shipped + ?", quantity)).
+		Where("id = ? AND order_id = ?", lineID, orderID).ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// AddShorted records quantity of a line as short picked, it is no longer
+// shipped or billed.
+func (r *SalesOrderRepo) AddShorted(ctx context.Context, orderID, lineID string, quantity float64) error {
+	qeury, args, err := r.pg.Builder.Update("sales_order_lines").
+		Set("shorted", squirrel.Expr("shorted + ?", quantity)).
+		Where("id = ? AND order_id = ?", lineID, orderID).ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// AddReturned records quantity as returned on a line of the order and returns
+// the updated line. Returning more than was shipped fails with ErrReturnExceedsShipped.
+func (r *SalesOrderRepo) AddReturned(ctx context.Context, orderID, lineID string, quantity float64) (entity.SalesOrderLine, error) {
+	var (
+		line  entity.SalesOrderLine
+		lotID sql.NullString
+	)
+
+	qeury, args, err := r.pg.Builder.Update("sales_order_lines").
+		Set("returned", squirrel.Expr("returned + ?", quantity)).
+		Where("id = ? AND order_id = ?", lineID, orderID).
+		Suffix("RETURNING id, product_id, bin_id, lot_id, serials, quantity, shipped, shorted, returned, price").ToSql()
+	if err != nil {
+		return line, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, qeury, args...).
+		Scan(&line.ID, &line.ProductID, &line.BinID, &lotID, &line.Serials, &line.Quantity, &line.Shipped, &line.Shorted,
+			&line.Returned, &line.Price)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.ConstraintName == "sales_order_lines_returned_check" {
+			return line, ErrReturnExceedsShipped
+		}
+		return line, err
+	}
+
+	line.LotID = lotID.String
+
+	return line, nil
+}
+
+func (r *SalesOrderRepo) insertLines(ctx context.Context, orderID string, lines []entity.SalesOrderLine) error {
+	if len(lines) == 0 {
+		return nil
+	}
+
+	insert := r.pg.Builder.Insert("sales_order_lines").
+		Columns(`id, order_id, line_no, product_id, bin_id, lot_id, serials, unit, unit_quantity, quantity, price,
+			list_price, price_rules`)
+	for i, line := range lines {
+		err := toBaseUnit(ctx, r.pg, line.ProductID, &line.Unit, &line.UnitQuantity, &line.Quantity)
+		if err != nil {
+			return err
+		}
+
+		insert = insert.Values(uuid.NewString(), orderID, i+1, line.ProductID, line.BinID, nullString(line.LotID),
+			textArray(line.Serials), line.Unit, line.UnitQuantity, line.Quantity, line.Price, line.ListPrice,
+			textArray(line.PriceRules))
+	}
+
+	qeury, args, err := insert.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	return err
+}
+
+// customerNameExpr keeps the name typed on the order, or copies it from the customer.
+func customerNameExpr(req entity.SalesOrder) squirrel.Sqlizer {
+	return squirrel.Expr("COALESCE(NULLIF(?, ''), (SELECT name FROM customers WHERE id = ?::UUID), '')",
+		req.CustomerName, nullString(req.CustomerID))
+}
+
+func scanSalesOrder(row pgx.Row) (entity.SalesOrder, error) {
+	var (
+		item                  entity.SalesOrder
+		customerID, createdBy sql.NullString
+		completedAt           sql.NullTime
+		orderDate             time.Time
+		createdAt, updatedAt  time.Time
+	)
+
+	err := row.Scan(&item.ID, &item.Number, &customerID, &item.CustomerName, &item.WarehouseID, &orderDate, &item.Status, &item.Note,
+		&item.Currency, &item.ExchangeRate, &createdBy, &item.TotalAmount, &item.ReturnedAmount, &item.PaidAmount, &completedAt, &createdAt, &updatedAt)
+	if err != nil {
+		return entity.SalesOrder{}, err
+	}
+
+	item.CustomerID = customerID.String
+	item.CreatedBy = createdBy.String
+	item.Debt = item.TotalAmount - item.PaidAmount
+	item.CompletedAt = formatNullTime(completedAt)
+	item.OrderDate = orderDate.Format("2006-01-02")
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+	item.UpdatedAt = updatedAt.Format(time.RFC3339)
+
+	return item, nil
+}
@@ -0,0 +1,291 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/logger"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/postgres"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+const serialColumns = `id, product_id, serial_number, status,
+	(SELECT warehouse_id FROM bins WHERE bins.id = serials.bin_id),
+	bin_id, lot_id, created_at, updated_at`
+
+type SerialRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewSerialRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *SerialRepo {
+	return &SerialRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+// Receive puts units into a bin. Serial numbers that are new are created, units
+// that left stock earlier (sold, removed, missing) come back. A serial number
+// that is in stock or in transit, or belongs to another product, fails the whole call.
+func (r *SerialRepo) Receive(ctx context.Context, productID, binID, lotID string, serials []string) error {
+	if len(serials) == 0 {
+		return nil
+	}
+
+	insert := r.pg.Builder.Insert("serials").
+		Columns(`id, product_id, serial_number, status, bin_id, lot_id`)
+	for _, serial := range serials {
+		insert = insert.Values(uuid.NewString(), productID, serial, entity.SerialStatusInStock, binID, nullString(lotID))
+	}
+
+	qeury, args, err := insert.Suffix(`ON CONFLICT (serial_number) DO UPDATE
+		SET status = EXCLUDED.status, bin_id = EXCLUDED.bin_id, lot_id = EXCLUDED.lot_id, updated_at = NOW()
+		WHERE serials.product_id = EXCLUDED.product_id AND serials.status NOT IN ('in_stock', 'in_transit')
+		RETURNING serial_number`).ToSql()
+	if err != nil {
+		return err
+	}
+
+	received, err := r.collectSerialNumbers(ctx, qeury, args)
+	if err != nil {
+		return err
+	}
+
+	if missing := missingSerials(serials, received); len(missing) > 0 {
+		return entity.NewError(config.ErrorSerialInStock,
+			"Serial numbers are already in stock or belong to another product: "+strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// Move changes the status and the bin of units. Every serial number has to match
+// the conditions of req, otherwise nothing is moved. The moved units are
+// returned so that callers know the lot of each.
+func (r *SerialRepo) Move(ctx context.Context, req entity.SerialMove) ([]entity.Serial, error) {
+	if len(req.Serials) == 0 {
+		return nil, nil
+	}
+
+	qeuryBuilder := r.pg.Builder.Update("serials").
+		Set("status", req.ToStatus).
+		Set("bin_id", nullString(req.ToBinID)).
+		Set("updated_at", "now()").
+		Where("product_id = ?", req.ProductID).
+		Where("serial_number = ANY(?)", req.Serials).
+		Where("status = ?", req.FromStatus)
+
+	if req.FromBinID != "" {
+		qeuryBuilder = qeuryBuilder.Where("bin_id = ?", req.FromBinID)
+	}
+
+	if req.LotID != "" {
+		qeuryBuilder = qeuryBuilder.Where("lot_id = ?", req.LotID)
+	}
+
+	if req.FromDocumentID != "" {
+		// the unit's last movement, in the order of GetHistory
+		qeuryBuilder = qeuryBuilder.Where(`(SELECT stock_movements.document_id FROM serial_movements
+			JOIN stock_movements ON stock_movements.id = serial_movements.movement_id
+			WHERE serial_movements.serial_id = serials.id
+			ORDER BY stock_movements.created_at DESC, stock_movements.quantity DESC LIMIT 1) = ?`, req.FromDocumentID)
+	}
+
+	qeury, args, err := qeuryBuilder.Suffix("RETURNING " + serialColumns).ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		response []entity.Serial
+		moved    []string
+	)
+
+	for rows.Next() {
+		item, err := scanSerial(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		response = append(response, item)
+		moved = append(moved, item.SerialNumber)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if missing := missingSerials(req.Serials, moved); len(missing) > 0 {
+		return nil, entity.NewError(config.ErrorSerialUnavailable,
+			fmt.Sprintf("Serial numbers are not %s here: %s", strings.ReplaceAll(req.FromStatus, "_", " "), strings.Join(missing, ", ")))
+	}
+
+	return response, nil
+}
+
+func (r *SerialRepo) GetSingle(ctx context.Context, serialNumber string) (entity.Serial, error) {
+	qeury, args, err := r.pg.Builder.Select(serialColumns).From("serials").Where("serial_number = ?", serialNumber).ToSql()
+	if err != nil {
+		return entity.Serial{}, err
+	}
+
+	return scanSerial(r.pg.DB(ctx).QueryRow(ctx, qeury, args...))
+}
+
+func (r *SerialRepo) GetList(ctx context.Context, req entity.GetListFilter) (entity.SerialList, error) {
+	response := entity.SerialList{}
+
+	qeuryBuilder := r.pg.Builder.Select(serialColumns).From("serials")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanSerial(rows)
+		if err != nil {
+			return response, err
+		}
+
+		response.Items, err = listItem(response.Items, item, req)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return response, err
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("serials").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+// GetHistory returns a unit together with every movement that carried it.
+// Movements of the same document share a timestamp, outbound ones are listed first.
+func (r *SerialRepo) GetHistory(ctx context.Context, serialNumber string) (entity.SerialHistory, error) {
+	response := entity.SerialHistory{}
+
+	serial, err := r.GetSingle(ctx, serialNumber)
+	if err != nil {
+		return response, err
+	}
+	response.Serial = serial
+
+	qeury, args, err := r.pg.Builder.Select(movementColumns).From("stock_movements").
+		Where("id IN (SELECT movement_id FROM serial_movements WHERE serial_id = ?)", serial.ID).
+		OrderBy("created_at", "quantity").ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanMovement(rows)
+		if err != nil {
+			return response, err
+		}
+
+		response.Movements = append(response.Movements, item)
+	}
+
+	return response, rows.Err()
+}
+
+func (r *SerialRepo) collectSerialNumbers(ctx context.Context, qeury string, args []interface{}) ([]string, error) {
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var response []string
+	for rows.Next() {
+		var serial string
+		if err = rows.Scan(&serial); err != nil {
+			return nil, err
+		}
+
+		response = append(response, serial)
+	}
+
+	return response, rows.Err()
+}
+
+// missingSerials returns the serial numbers of want that are not in got.
+func missingSerials(want, got []string) []string {
+	found := make(map[string]bool, len(got))
+	for _, serial := range got {
+		found[serial] = true
+	}
+
+	var missing []string
+	for _, serial := range want {
+		if !found[serial] {
+			missing = append(missing, serial)
+		}
+	}
+
+	return missing
+}
+
+func scanSerial(row pgx.Row) (entity.Serial, error) {
+	var (
+		item                      entity.Serial
+		warehouseID, binID, lotID sql.NullString
+		createdAt, updatedAt      time.Time
+	)
+
+	err := row.Scan(&item.ID, &item.ProductID, &item.SerialNumber, &item.Status, &warehouseID, &binID, &lotID,
+		&createdAt, &updatedAt)
+	if err != nil {
+		return entity.Serial{}, err
+	}
+
+	item.WarehouseID = warehouseID.String
+	item.BinID = binID.String
+	item.LotID = lotID.String
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+	item.UpdatedAt = updatedAt.Format(time.RFC3339)
+
+	return item, nil
+}
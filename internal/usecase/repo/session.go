@@ -44,7 +44,7 @@ func (r *SessionRepo) Create(ctx context.Context, req entity.Session) (entity.Se
 		return entity.Session{}, err
 	}
 
-	_, err = r.pg.Pool.Exec(ctx, qeury, args...)
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
 	if err != nil {
 		return entity.Session{}, err
 	}
@@ -67,7 +67,7 @@ func (r *SessionRepo) GetSingle(ctx context.Context, req entity.Id) (entity.Sess
 		return entity.Session{}, err
 	}
 
-	err = r.pg.Pool.QueryRow(ctx, qeury, args...).
+	err = r.pg.DB(ctx).QueryRow(ctx, qeury, args...).
 		Scan(&response.ID, &response.UserID, &response.IPAddress, &response.UserAgent,
 			&response.IsActive, &expiresAt, &lastActiveAt, &response.Platform, &createdAt, &updatedAt)
 	if err != nil {
@@ -116,11 +116,11 @@ func (r *SessionRepo) GetList(ctx context.Context, req entity.GetListFilter) (en
 		}
 	}
 
-	// Apply pagination (LIMIT and OFFSET)
-	if req.Limit > 0 {
+	// Apply pagination (LIMIT and OFFSET), exports get every session
+	if req.Limit > 0 && req.Export == nil {
 		queryBuilder = queryBuilder.Limit(uint64(req.Limit))
 	}
-	if req.Page > 0 {
+	if req.Page > 0 && req.Export == nil {
 		offset := (req.Page - 1) * req.Limit
 		queryBuilder = queryBuilder.Offset(uint64(offset))
 	}
@@ -131,7 +131,7 @@ func (r *SessionRepo) GetList(ctx context.Context, req entity.GetListFilter) (en
 		return response, fmt.Errorf("error building query: %w", err)
 	}
 
-	rows, err := r.pg.Pool.Query(ctx, query, args...)
+	rows, err := r.pg.DB(ctx).Query(ctx, query, args...)
 	if err != nil {
 		return response, fmt.Errorf("error executing query: %w", err)
 	}
@@ -155,7 +155,14 @@ func (r *SessionRepo) GetList(ctx context.Context, req entity.GetListFilter) (en
 			item.LastActiveAt = lastActiveAt.Time.Format(time.RFC3339)
 		}
 
-		response.Items = append(response.Items, item)
+		response.Items, err = listItem(response.Items, item, req)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return response, err
 	}
 
 	// Count query to get the total number of records
@@ -177,7 +184,7 @@ func (r *SessionRepo) GetList(ctx context.Context, req entity.GetListFilter) (en
 		return response, fmt.Errorf("error building count query: %w", err)
 	}
 
-	err = r.pg.Pool.QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	err = r.pg.DB(ctx).QueryRow(ctx, countQuery, args...).Scan(&response.Count)
 	if err != nil {
 		return response, fmt.Errorf("error executing count query: %w", err)
 	}
@@ -198,7 +205,7 @@ func (r *SessionRepo) Update(ctx context.Context, req entity.Session) (entity.Se
 		return entity.Session{}, err
 	}
 
-	_, err = r.pg.Pool.Exec(ctx, qeury, args...)
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
 	if err != nil {
 		return entity.Session{}, err
 	}
@@ -212,7 +219,7 @@ func (r *SessionRepo) Delete(ctx context.Context, req entity.Id) error {
 		return err
 	}
 
-	_, err = r.pg.Pool.Exec(ctx, qeury, args...)
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
 	if err != nil {
 		return err
 	}
@@ -233,7 +240,7 @@ func (r *SessionRepo) UpdateField(ctx context.Context, req entity.UpdateFieldReq
 		return response, err
 	}
 
-	n, err := r.pg.Pool.Exec(ctx, qeury, args...)
+	n, err := r.pg.DB(ctx).Exec(ctx, qeury, args...)
 	if err != nil {
 		return response, err
 	}
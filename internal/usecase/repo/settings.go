@@ -0,0 +1,79 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/logger"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/postgres"
+)
+
+type SettingsRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewSettingsRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *SettingsRepo {
+	return &SettingsRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+func (r *SettingsRepo) GetSingle(ctx context.Context) (entity.CompanySettings, error) {
+	var (
+		response  entity.CompanySettings
+		updatedAt time.Time
+	)
+
+	qeury, args, err := r.pg.Builder.Select(`costing_method, reservation_hours, company_name, company_address, company_tax_id,
+		company_phone, bank_name, bank_account, bank_code, director, accountant, updated_at`).From("company_settings").ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	company := &response.Company
+	err = r.pg.DB(ctx).QueryRow(ctx, qeury, args...).Scan(&response.CostingMethod, &response.ReservationHours, &company.Name, &company.Address,
+		&company.TaxID, &company.Phone, &company.BankName, &company.BankAccount, &company.BankCode, &company.Director, &company.Accountant, &updatedAt)
+	if err != nil {
+		return response, err
+	}
+
+	response.UpdatedAt = updatedAt.Format(time.RFC3339)
+
+	return response, nil
+}
+
+func (r *SettingsRepo) Update(ctx context.Context, req entity.CompanySettings) (entity.CompanySettings, error) {
+	mp := map[string]interface{}{
+		"costing_method":    req.CostingMethod,
+		"reservation_hours": req.ReservationHours,
+		"company_name":      req.Company.Name,
+		"company_address":   req.Company.Address,
+		"company_tax_id":    req.Company.TaxID,
+		"company_phone":     req.Company.Phone,
+		"bank_name":         req.Company.BankName,
+		"bank_account":      req.Company.BankAccount,
+		"bank_code":         req.Company.BankCode,
+		"director":          req.Company.Director,
+		"accountant":        req.Company.Accountant,
+		"updated_at":        "now()",
+	}
+
+	qeury, args, err := r.pg.Builder.Update("company_settings").SetMap(mp).ToSql()
+	if err != nil {
+		return entity.CompanySettings{}, err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil {
+		return entity.CompanySettings{}, err
+	}
+
+	return r.GetSingle(ctx)
+}
@@ -0,0 +1,256 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/logger"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/postgres"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	parcelColumns = `id, order_id, parcel_no, weight, note, shipment_id, created_at`
+
+	shipmentColumns = `id, number, order_id, (SELECT number FROM sales_orders WHERE sales_orders.id = shipments.order_id),
+	carrier, tracking_number, note,
+	COALESCE((SELECT SUM(weight) FROM parcels WHERE parcels.shipment_id = shipments.id), 0),
+	created_by, shipped_at, created_at`
+)
+
+type ShipmentRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewShipmentRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *ShipmentRepo {
+	return &ShipmentRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+// SetParcels replaces the parcels of an order that are not shipped yet.
+func (r *ShipmentRepo) SetParcels(ctx context.Context, orderID string, parcels []entity.Parcel) ([]entity.Parcel, error) {
+	err := r.pg.WithTx(ctx, func(ctx context.Context) error {
+		qeury, args, err := r.pg.Builder.Delete("parcels").Where("order_id = ? AND shipment_id IS NULL", orderID).ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		if err != nil {
+			return err
+		}
+
+		if len(parcels) == 0 {
+			return nil
+		}
+
+		insert := r.pg.Builder.Insert("parcels").Columns(`id, order_id, parcel_no, weight, note`)
+		for i, parcel := range parcels {
+			insert = insert.Values(uuid.NewString(), orderID, i+1, parcel.Weight, parcel.Note)
+		}
+
+		qeury, args, err = insert.ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetParcels(ctx, orderID)
+}
+
+func (r *ShipmentRepo) GetParcels(ctx context.Context, orderID string) ([]entity.Parcel, error) {
+	qeury, args, err := r.pg.Builder.Select(parcelColumns).From("parcels").Where("order_id = ?", orderID).
+		OrderBy("parcel_no").ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var response []entity.Parcel
+	for rows.Next() {
+		item, err := scanParcel(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		response = append(response, item)
+	}
+
+	return response, rows.Err()
+}
+
+// Create records the shipment of an order and puts its unshipped parcels on it.
+func (r *ShipmentRepo) Create(ctx context.Context, req entity.Shipment) (entity.Shipment, error) {
+	req.ID = uuid.NewString()
+
+	err := r.pg.WithTx(ctx, func(ctx context.Context) error {
+		qeury, args, err := r.pg.Builder.Insert("shipments").
+			Columns(`id, number, order_id, carrier, tracking_number, note, created_by`).
+			Values(req.ID, squirrel.Expr("'SH-' || LPAD(nextval('shipment_number_seq')::TEXT, 6, '0')"),
+				req.OrderID, req.Carrier, req.TrackingNumber, req.Note, nullString(req.CreatedBy)).ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		if err != nil {
+			return err
+		}
+
+		qeury, args, err = r.pg.Builder.Update("parcels").Set("shipment_id", req.ID).
+			Where("order_id = ? AND shipment_id IS NULL", req.OrderID).ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		return err
+	})
+	if err != nil {
+		return entity.Shipment{}, err
+	}
+
+	return r.GetSingle(ctx, entity.Id{ID: req.ID})
+}
+
+func (r *ShipmentRepo) GetSingle(ctx context.Context, req entity.Id) (entity.Shipment, error) {
+	qeury, args, err := r.pg.Builder.Select(shipmentColumns).From("shipments").Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return entity.Shipment{}, err
+	}
+
+	response, err := scanShipment(r.pg.DB(ctx).QueryRow(ctx, qeury, args...))
+	if err != nil {
+		return entity.Shipment{}, err
+	}
+
+	qeury, args, err = r.pg.Builder.Select(parcelColumns).From("parcels").Where("shipment_id = ?", req.ID).
+		OrderBy("parcel_no").ToSql()
+	if err != nil {
+		return entity.Shipment{}, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return entity.Shipment{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		parcel, err := scanParcel(rows)
+		if err != nil {
+			return entity.Shipment{}, err
+		}
+
+		response.Parcels = append(response.Parcels, parcel)
+	}
+
+	return response, rows.Err()
+}
+
+func (r *ShipmentRepo) GetList(ctx context.Context, req entity.GetListFilter) (entity.ShipmentList, error) {
+	response := entity.ShipmentList{}
+
+	qeuryBuilder := r.pg.Builder.Select(shipmentColumns).From("shipments")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanShipment(rows)
+		if err != nil {
+			return response, err
+		}
+
+		response.Items, err = listItem(response.Items, item, req)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return response, err
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("shipments").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+func scanParcel(row pgx.Row) (entity.Parcel, error) {
+	var (
+		item       entity.Parcel
+		shipmentID sql.NullString
+		createdAt  time.Time
+	)
+
+	err := row.Scan(&item.ID, &item.OrderID, &item.ParcelNo, &item.Weight, &item.Note, &shipmentID, &createdAt)
+	if err != nil {
+		return entity.Parcel{}, err
+	}
+
+	item.ShipmentID = shipmentID.String
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+
+	return item, nil
+}
+
+func scanShipment(row pgx.Row) (entity.Shipment, error) {
+	var (
+		item                 entity.Shipment
+		createdBy            sql.NullString
+		shippedAt, createdAt time.Time
+	)
+
+	err := row.Scan(&item.ID, &item.Number, &item.OrderID, &item.OrderNumber, &item.Carrier, &item.TrackingNumber, &item.Note,
+		&item.TotalWeight, &createdBy, &shippedAt, &createdAt)
+	if err != nil {
+		return entity.Shipment{}, err
+	}
+
+	item.CreatedBy = createdBy.String
+	item.ShippedAt = shippedAt.Format(time.RFC3339)
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+
+	return item, nil
+}
@@ -0,0 +1,436 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/logger"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/postgres"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+var ErrInsufficientStock = entity.NewError(config.ErrorInsufficientStock, "Not enough stock in the bin")
+
+// balanceLotKey matches the unique index of stock_balances, stock without a lot
+// is stored with a NULL lot_id.
+const balanceLotKey = `(product_id, bin_id, COALESCE(lot_id, '00000000-0000-0000-0000-000000000000'::UUID))`
+
+// lotPickOrder is first-expired-first-out, lots without an expiry date and
+// stock without a lot come last.
+const lotPickOrder = `lots.expiry_date NULLS LAST, stock_balances.lot_id NULLS LAST, bins.code`
+
+const movementColumns = `id, product_id, warehouse_id, bin_id, lot_id,
+	COALESCE((SELECT array_agg(serials.serial_number ORDER BY serials.serial_number) FROM serial_movements
+		JOIN serials ON serials.id = serial_movements.serial_id
+		WHERE serial_movements.movement_id = stock_movements.id), '{}'),
+	quantity,
+	COALESCE((SELECT unit_cost FROM movement_costs WHERE movement_costs.movement_id = stock_movements.id), 0),
+	COALESCE((SELECT cost FROM movement_costs WHERE movement_costs.movement_id = stock_movements.id), 0),
+	reason, document_type, document_id, note, user_id, created_at`
+
+type StockRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewStockRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *StockRepo {
+	return &StockRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+// CreateMovements appends movements to the ledger and applies them to the
+// balances in the same transaction. Either every movement is posted or none.
+func (r *StockRepo) CreateMovements(ctx context.Context, req []entity.StockMovement) ([]entity.StockMovement, error) {
+	// balances are always locked in the same order so that concurrent
+	// documents touching the same rows wait for each other instead of deadlocking
+	sorted := make([]int, len(req))
+	for i := range sorted {
+		sorted[i] = i
+	}
+	sort.SliceStable(sorted, func(a, b int) bool {
+		x, y := req[sorted[a]], req[sorted[b]]
+		if x.ProductID != y.ProductID {
+			return x.ProductID < y.ProductID
+		}
+		if x.BinID != y.BinID {
+			return x.BinID < y.BinID
+		}
+		return x.LotID < y.LotID
+	})
+
+	err := r.pg.WithTx(ctx, func(ctx context.Context) (err error) {
+		for _, i := range sorted {
+			req[i], err = r.createMovement(ctx, req[i])
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// LockBalance returns the quantity of a product lot in a bin, locked until the
+// end of the current transaction so that no document moves it meanwhile. A
+// bin without the product gets an empty balance to lock.
+func (r *StockRepo) LockBalance(ctx context.Context, productID, warehouseID, binID, lotID string) (float64, error) {
+	var quantity float64
+
+	qeury, args, err := r.pg.Builder.Insert("stock_balances").
+		Columns(`product_id, bin_id, warehouse_id, lot_id, quantity`).
+		Values(productID, binID, warehouseID, nullString(lotID), 0).
+		Suffix(`ON CONFLICT ` + balanceLotKey + ` DO UPDATE SET quantity = stock_balances.quantity
+			RETURNING quantity`).ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, qeury, args...).Scan(&quantity)
+	return quantity, err
+}
+
+func (r *StockRepo) createMovement(ctx context.Context, req entity.StockMovement) (entity.StockMovement, error) {
+	var createdAt time.Time
+	req.ID = uuid.NewString()
+
+	// warehouse_id is copied from the bin, a lot of another product finds no row
+	qeury, args, err := r.pg.Builder.Insert("stock_movements").
+		Columns(`id, product_id, warehouse_id, bin_id, lot_id, quantity, reason, document_type, document_id, note, user_id`).
+		Select(r.pg.Builder.Select().
+			Column("?::UUID", req.ID).
+			Column("?::UUID", req.ProductID).
+			Columns("warehouse_id", "id").
+			Column("?::UUID", nullString(req.LotID)).
+			Column("?::NUMERIC", req.Quantity).
+			Column("?::TEXT", req.Reason).
+			Column("?::TEXT", req.DocumentType).
+			Column("?::UUID", nullString(req.DocumentID)).
+			Column("?::TEXT", req.Note).
+			Column("?::UUID", nullString(req.UserID)).
+			From("bins").Where("id = ?", req.BinID).
+			Where("(?::UUID IS NULL OR EXISTS (SELECT 1 FROM lots WHERE lots.id = ? AND lots.product_id = ?))",
+				nullString(req.LotID), nullString(req.LotID), req.ProductID)).
+		Suffix("RETURNING warehouse_id, created_at").ToSql()
+	if err != nil {
+		return req, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, qeury, args...).Scan(&req.WarehouseID, &createdAt)
+	if err != nil {
+		return req, err
+	}
+
+	req.CreatedAt = createdAt.Format(time.RFC3339)
+
+	qeury, args, err = r.pg.Builder.Insert("stock_balances").
+		Columns(`product_id, bin_id, warehouse_id, lot_id, quantity`).
+		Values(req.ProductID, req.BinID, req.WarehouseID, nullString(req.LotID), req.Quantity).
+		Suffix(`ON CONFLICT ` + balanceLotKey + ` DO UPDATE
+			SET quantity = stock_balances.quantity + EXCLUDED.quantity, updated_at = NOW()`).ToSql()
+	if err != nil {
+		return req, err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.ConstraintName == "stock_balances_quantity_check" {
+			return req, ErrInsufficientStock
+		}
+		return req, err
+	}
+
+	if len(req.Serials) == 0 {
+		return req, nil
+	}
+
+	qeury, args, err = r.pg.Builder.Insert("serial_movements").
+		Columns("serial_id, movement_id").
+		Select(r.pg.Builder.Select("id").Column("?::UUID", req.ID).
+			From("serials").Where("product_id = ?", req.ProductID).Where("serial_number = ANY(?)", req.Serials)).ToSql()
+	if err != nil {
+		return req, err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil {
+		return req, err
+	}
+
+	return req, nil
+}
+
+func (r *StockRepo) GetMovements(ctx context.Context, req entity.GetListFilter) (entity.StockMovementList, error) {
+	response := entity.StockMovementList{}
+
+	qeuryBuilder := r.pg.Builder.
+		Select(movementColumns).
+		From("stock_movements")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanMovement(rows)
+		if err != nil {
+			return response, err
+		}
+
+		response.Items, err = listItem(response.Items, item, req)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return response, err
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("stock_movements").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+func (r *StockRepo) GetBalances(ctx context.Context, req entity.GetListFilter) (entity.StockBalanceList, error) {
+	var (
+		response   = entity.StockBalanceList{}
+		lotID      sql.NullString
+		expiryDate sql.NullTime
+		updatedAt  time.Time
+	)
+
+	qeuryBuilder := r.pg.Builder.
+		Select(`product_id, warehouse_id, bin_id, lot_id,
+			COALESCE((SELECT lot_number FROM lots WHERE lots.id = stock_balances.lot_id), ''),
+			(SELECT expiry_date FROM lots WHERE lots.id = stock_balances.lot_id),
+			quantity, updated_at`).
+		From("stock_balances")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item entity.StockBalance
+		err = rows.Scan(&item.ProductID, &item.WarehouseID, &item.BinID, &lotID, &item.LotNumber, &expiryDate,
+			&item.Quantity, &updatedAt)
+		if err != nil {
+			return response, err
+		}
+
+		item.LotID = lotID.String
+		item.ExpiryDate = formatNullDate(expiryDate)
+
+		item.UpdatedAt = updatedAt.Format(time.RFC3339)
+
+		response.Items, err = listItem(response.Items, item, req)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return response, err
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("stock_balances").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+// GetLotPicks lists the stock of a product that can be picked, in
+// first-expired-first-out order. Expired lots and virtual bins are left out.
+// binID narrows the list down to one bin when it is not empty, a virtual one
+// is then listed in full so that stock in transit can be taken out of it.
+func (r *StockRepo) GetLotPicks(ctx context.Context, productID, warehouseID, binID string) ([]entity.LotPick, error) {
+	var (
+		response   []entity.LotPick
+		lotID      sql.NullString
+		expiryDate sql.NullTime
+	)
+
+	qeuryBuilder := r.pg.Builder.
+		Select(`stock_balances.bin_id, bins.code, stock_balances.lot_id, COALESCE(lots.lot_number, ''), lots.expiry_date,
+			stock_balances.quantity`).
+		From("stock_balances").
+		Join("bins ON bins.id = stock_balances.bin_id").
+		LeftJoin("lots ON lots.id = stock_balances.lot_id").
+		Where("stock_balances.product_id = ? AND stock_balances.warehouse_id = ?", productID, warehouseID).
+		Where("stock_balances.quantity > 0").
+		// expired lots in transit still have to be taken out of it
+		Where("(bins.is_virtual OR lots.expiry_date IS NULL OR lots.expiry_date >= CURRENT_DATE)").
+		OrderBy(lotPickOrder)
+
+	// virtual bins are only picked from when they are asked for
+	if binID != "" {
+		qeuryBuilder = qeuryBuilder.Where("stock_balances.bin_id = ?", binID)
+	} else {
+		qeuryBuilder = qeuryBuilder.Where("NOT bins.is_virtual")
+	}
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item entity.LotPick
+		err = rows.Scan(&item.BinID, &item.BinCode, &lotID, &item.LotNumber, &expiryDate, &item.Available)
+		if err != nil {
+			return nil, err
+		}
+
+		item.LotID = lotID.String
+		item.ExpiryDate = formatNullDate(expiryDate)
+
+		response = append(response, item)
+	}
+
+	return response, rows.Err()
+}
+
+// GetExpiring lists lot balances that expire within days from today, already
+// expired lots included. Filters may use stock_balances.warehouse_id,
+// stock_balances.product_id and stock_balances.bin_id.
+func (r *StockRepo) GetExpiring(ctx context.Context, req entity.GetListFilter, days int) (entity.ExpiringLotList, error) {
+	var (
+		response   = entity.ExpiringLotList{}
+		expiryDate time.Time
+	)
+
+	from := func(builder squirrel.SelectBuilder) squirrel.SelectBuilder {
+		return builder.From("stock_balances").
+			Join("lots ON lots.id = stock_balances.lot_id").
+			Join("products ON products.id = stock_balances.product_id").
+			Join("bins ON bins.id = stock_balances.bin_id").
+			Where("stock_balances.quantity > 0 AND lots.expiry_date <= CURRENT_DATE + ?::INT", days)
+	}
+
+	qeuryBuilder := from(r.pg.Builder.Select(`stock_balances.product_id, products.name, products.sku,
+		stock_balances.warehouse_id, stock_balances.bin_id, bins.code, lots.id, lots.lot_number, lots.expiry_date,
+		lots.expiry_date - CURRENT_DATE, stock_balances.quantity`))
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item entity.ExpiringLot
+		err = rows.Scan(&item.ProductID, &item.ProductName, &item.SKU, &item.WarehouseID, &item.BinID, &item.BinCode,
+			&item.LotID, &item.LotNumber, &expiryDate, &item.DaysLeft, &item.Quantity)
+		if err != nil {
+			return response, err
+		}
+
+		item.ExpiryDate = expiryDate.Format("2006-01-02")
+
+		response.Items, err = listItem(response.Items, item, req)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return response, err
+	}
+
+	countQuery, args, err := from(r.pg.Builder.Select("COUNT(1)")).Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+func scanMovement(row pgx.Row) (entity.StockMovement, error) {
+	var (
+		item                      entity.StockMovement
+		createdAt                 time.Time
+		lotID, documentID, userID sql.NullString
+	)
+
+	err := row.Scan(&item.ID, &item.ProductID, &item.WarehouseID, &item.BinID, &lotID, &item.Serials, &item.Quantity,
+		&item.UnitCost, &item.Cost, &item.Reason, &item.DocumentType, &documentID, &item.Note, &userID, &createdAt)
+	if err != nil {
+		return entity.StockMovement{}, err
+	}
+
+	item.LotID = lotID.String
+	item.DocumentID = documentID.String
+	item.UserID = userID.String
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+
+	return item, nil
+}
@@ -0,0 +1,424 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/logger"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/postgres"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+var (
+	ErrStocktakeNotOpen    = entity.NewError(config.ErrorInvalidStatus, "Stocktake is not open for counting")
+	ErrZoneNotInWarehouse  = entity.NewError(config.ErrorBadRequest, "Zone does not belong to the stocktake warehouse")
+	ErrCountOutOfStocktake = entity.NewError(config.ErrorBadRequest, "Bin or product is outside of the stocktake scope")
+)
+
+const (
+	stocktakeColumns = `id, number, warehouse_id, zone_id, category_id, status, note, created_by, approved_by, approved_at,
+	created_at, updated_at`
+
+	// stocktakeSnapshotQuery copies the current balances of the session scope
+	// into stocktake_lines. $1 stocktake, $2 warehouse, $3 zone, $4 category.
+	stocktakeSnapshotQuery = ` INSERT INTO stocktake_lines (id, stocktake_id, product_id, bin_id, lot_id, expected)
+	SELECT gen_random_uuid(), $1, stock_balances.product_id, stock_balances.bin_id, stock_balances.lot_id, stock_balances.quantity
+	FROM stock_balances
+	JOIN bins ON bins.id = stock_balances.bin_id
+	JOIN products ON products.id = stock_balances.product_id
+	WHERE stock_balances.warehouse_id = $2 AND stock_balances.quantity > 0 AND NOT bins.is_virtual
+		AND ($3::UUID IS NULL OR bins.zone_id = $3)
+		AND ($4::UUID IS NULL OR products.category_id IN (SELECT id FROM tree))`
+
+	// stocktakeCountQuery inserts a count only if the bin and the product fall
+	// into the session scope and the lot belongs to the product.
+	// $1 stocktake, $2 id, $3 product, $4 bin, $5 lot, $6 quantity, $7 counter,
+	// $8 unit, $9 quantity in unit.
+	stocktakeCountQuery = ` INSERT INTO stocktake_counts (id, stocktake_id, product_id, bin_id, lot_id, quantity, counted_by,
+		unit, unit_quantity)
+	SELECT $2, stocktakes.id, $3, bins.id, $5, $6, $7, $8, $9
+	FROM stocktakes JOIN bins ON bins.warehouse_id = stocktakes.warehouse_id
+	WHERE stocktakes.id = $1 AND bins.id = $4 AND NOT bins.is_virtual
+		AND ($5::UUID IS NULL OR EXISTS (SELECT 1 FROM lots WHERE lots.id = $5 AND lots.product_id = $3))
+		AND (stocktakes.zone_id IS NULL OR bins.zone_id = stocktakes.zone_id)
+		AND (stocktakes.category_id IS NULL OR EXISTS (
+			SELECT 1 FROM products JOIN tree ON tree.id = products.category_id WHERE products.id = $3))`
+
+	// stocktakeLinesQuery joins the snapshot with the latest count of every
+	// product/bin/lot. Products counted in bins they weren't expected in come
+	// with a zero expected quantity, variance stays zero until a bin is counted.
+	stocktakeLinesQuery = `WITH counted AS (
+		SELECT DISTINCT ON (product_id, bin_id, lot_id) product_id, bin_id, lot_id, quantity,
+			COUNT(1) OVER (PARTITION BY product_id, bin_id, lot_id) AS counts
+		FROM stocktake_counts WHERE stocktake_id = $1
+		ORDER BY product_id, bin_id, lot_id, created_at DESC
+	), expected AS (
+		SELECT product_id, bin_id, lot_id, expected FROM stocktake_lines WHERE stocktake_id = $1
+	)
+	SELECT products.id, products.name, products.sku, bins.id, bins.code,
+		lots.id, COALESCE(lots.lot_number, ''), lots.expiry_date,
+		COALESCE(expected.expected, 0), COALESCE(counted.quantity, 0), COALESCE(counted.counts, 0),
+		COALESCE(counted.quantity - COALESCE(expected.expected, 0), 0)
+	FROM expected
+	FULL JOIN counted ON counted.product_id = expected.product_id AND counted.bin_id = expected.bin_id
+		AND counted.lot_id IS NOT DISTINCT FROM expected.lot_id
+	JOIN products ON products.id = COALESCE(expected.product_id, counted.product_id)
+	JOIN bins ON bins.id = COALESCE(expected.bin_id, counted.bin_id)
+	LEFT JOIN lots ON lots.id = COALESCE(expected.lot_id, counted.lot_id)
+	ORDER BY bins.code, products.name, lots.expiry_date NULLS LAST`
+)
+
+type StocktakeRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewStocktakeRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *StocktakeRepo {
+	return &StocktakeRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+// Create opens a count session and snapshots the expected quantities of its scope.
+func (r *StocktakeRepo) Create(ctx context.Context, req entity.Stocktake) (entity.Stocktake, error) {
+	req.ID = uuid.NewString()
+
+	err := r.pg.WithTx(ctx, func(ctx context.Context) error {
+		if req.ZoneID != "" {
+			var warehouseID string
+
+			qeury, args, err := r.pg.Builder.Select("warehouse_id").From("zones").Where("id = ?", req.ZoneID).ToSql()
+			if err != nil {
+				return err
+			}
+
+			err = r.pg.DB(ctx).QueryRow(ctx, qeury, args...).Scan(&warehouseID)
+			if err != nil {
+				return err
+			}
+
+			if warehouseID != req.WarehouseID {
+				return ErrZoneNotInWarehouse
+			}
+		}
+
+		qeury, args, err := r.pg.Builder.Insert("stocktakes").
+			Columns(`id, number, warehouse_id, zone_id, category_id, note, created_by`).
+			Values(req.ID,
+				squirrel.Expr("COALESCE(NULLIF(?, ''), 'ST-' || LPAD(nextval('stocktake_number_seq')::TEXT, 6, '0'))", req.Number),
+				req.WarehouseID, nullString(req.ZoneID), nullString(req.CategoryID), req.Note, nullString(req.CreatedBy)).ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, fmt.Sprintf(subtreeQuery, "id = $4")+stocktakeSnapshotQuery,
+			req.ID, req.WarehouseID, nullString(req.ZoneID), nullString(req.CategoryID))
+		return err
+	})
+	if err != nil {
+		return entity.Stocktake{}, err
+	}
+
+	return r.GetSingle(ctx, entity.Id{ID: req.ID})
+}
+
+// GetSingle returns the session with the variance of every line.
+func (r *StocktakeRepo) GetSingle(ctx context.Context, req entity.Id) (entity.Stocktake, error) {
+	qeury, args, err := r.pg.Builder.Select(stocktakeColumns).From("stocktakes").Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return entity.Stocktake{}, err
+	}
+
+	response, err := scanStocktake(r.pg.DB(ctx).QueryRow(ctx, qeury, args...))
+	if err != nil {
+		return entity.Stocktake{}, err
+	}
+
+	response.Lines, err = r.getLines(ctx, req.ID)
+	if err != nil {
+		return entity.Stocktake{}, err
+	}
+
+	return response, nil
+}
+
+func (r *StocktakeRepo) GetList(ctx context.Context, req entity.GetListFilter) (entity.StocktakeList, error) {
+	response := entity.StocktakeList{}
+
+	qeuryBuilder := r.pg.Builder.Select(stocktakeColumns).From("stocktakes")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanStocktake(rows)
+		if err != nil {
+			return response, err
+		}
+
+		response.Items, err = listItem(response.Items, item, req)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return response, err
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("stocktakes").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+// GetSheet returns the count sheet of a session without expected quantities.
+func (r *StocktakeRepo) GetSheet(ctx context.Context, req entity.Id) (entity.StocktakeSheet, error) {
+	stocktake, err := r.GetSingle(ctx, req)
+	if err != nil {
+		return entity.StocktakeSheet{}, err
+	}
+
+	response := entity.StocktakeSheet{
+		ID:          stocktake.ID,
+		Number:      stocktake.Number,
+		WarehouseID: stocktake.WarehouseID,
+		ZoneID:      stocktake.ZoneID,
+		CategoryID:  stocktake.CategoryID,
+		Status:      stocktake.Status,
+		Lines:       make([]entity.StocktakeSheetLine, 0, len(stocktake.Lines)),
+	}
+
+	for _, line := range stocktake.Lines {
+		response.Lines = append(response.Lines, entity.StocktakeSheetLine{
+			ProductID:   line.ProductID,
+			ProductName: line.ProductName,
+			SKU:         line.SKU,
+			BinID:       line.BinID,
+			BinCode:     line.BinCode,
+			LotID:       line.LotID,
+			LotNumber:   line.LotNumber,
+			ExpiryDate:  line.ExpiryDate,
+			Counted:     line.Counted,
+			Counts:      line.Counts,
+		})
+	}
+
+	return response, nil
+}
+
+// LockStatus locks the stocktake for the rest of the transaction and returns its status.
+func (r *StocktakeRepo) LockStatus(ctx context.Context, req entity.Id) (string, error) {
+	return lockStatus(ctx, r.pg, "stocktakes", req.ID)
+}
+
+// SetStatus moves the stocktake to status, approvals also record who approved it.
+func (r *StocktakeRepo) SetStatus(ctx context.Context, req entity.Id, status, userID string) error {
+	mp := map[string]interface{}{
+		"status":     status,
+		"updated_at": "now()",
+	}
+
+	if status == entity.StocktakeStatusApproved {
+		mp["approved_by"] = nullString(userID)
+		mp["approved_at"] = "now()"
+	}
+
+	qeury, args, err := r.pg.Builder.Update("stocktakes").SetMap(mp).Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// CreateCounts records counts of an open session. The whole request fails if
+// any count is outside of the session warehouse, zone or category.
+func (r *StocktakeRepo) CreateCounts(ctx context.Context, req entity.StocktakeCountRequest, userID string) error {
+	return r.pg.WithTx(ctx, func(ctx context.Context) error {
+		status, err := lockStatus(ctx, r.pg, "stocktakes", req.StocktakeID)
+		if err != nil {
+			return err
+		}
+
+		if status != entity.StocktakeStatusOpen {
+			return ErrStocktakeNotOpen
+		}
+
+		qeury := fmt.Sprintf(subtreeQuery, "id = (SELECT category_id FROM stocktakes WHERE id = $1)") + stocktakeCountQuery
+
+		for _, count := range req.Counts {
+			err := toBaseUnit(ctx, r.pg, count.ProductID, &count.Unit, &count.UnitQuantity, &count.Quantity)
+			if err != nil {
+				return err
+			}
+
+			tag, err := r.pg.DB(ctx).Exec(ctx, qeury, req.StocktakeID, uuid.NewString(), count.ProductID, count.BinID,
+				nullString(count.LotID), count.Quantity, nullString(userID), count.Unit, count.UnitQuantity)
+			if err != nil {
+				return err
+			}
+
+			if tag.RowsAffected() == 0 {
+				return ErrCountOutOfStocktake
+			}
+		}
+
+		return nil
+	})
+}
+
+// GetCounts returns every count of a session, newest first.
+func (r *StocktakeRepo) GetCounts(ctx context.Context, req entity.Id) ([]entity.StocktakeCount, error) {
+	var (
+		response         []entity.StocktakeCount
+		lotID, countedBy sql.NullString
+		createdAt        time.Time
+	)
+
+	qeury, args, err := r.pg.Builder.
+		Select(`id, stocktake_id, product_id, bin_id, lot_id, unit, unit_quantity, quantity, counted_by, created_at`).
+		From("stocktake_counts").Where("stocktake_id = ?", req.ID).OrderBy("created_at DESC").ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item entity.StocktakeCount
+		err = rows.Scan(&item.ID, &item.StocktakeID, &item.ProductID, &item.BinID, &lotID, &item.Unit, &item.UnitQuantity,
+			&item.Quantity, &countedBy, &createdAt)
+		if err != nil {
+			return nil, err
+		}
+
+		item.LotID = lotID.String
+
+		item.CountedBy = countedBy.String
+		item.CreatedAt = createdAt.Format(time.RFC3339)
+
+		response = append(response, item)
+	}
+
+	return response, rows.Err()
+}
+
+// GetMovedSinceCount sums the movements of a product lot in a bin posted after
+// its latest count in the session, i.e. what the count doesn't know about yet.
+func (r *StocktakeRepo) GetMovedSinceCount(ctx context.Context, stocktakeID, productID, binID, lotID string) (float64, error) {
+	var response float64
+
+	qeury, args, err := r.pg.Builder.Select("COALESCE(SUM(quantity), 0)").From("stock_movements").
+		Where("product_id = ? AND bin_id = ? AND lot_id IS NOT DISTINCT FROM ?", productID, binID, nullString(lotID)).
+		Where(`created_at > (SELECT MAX(created_at) FROM stocktake_counts
+			WHERE stocktake_id = ? AND product_id = ? AND bin_id = ? AND lot_id IS NOT DISTINCT FROM ?)`,
+			stocktakeID, productID, binID, nullString(lotID)).ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, qeury, args...).Scan(&response)
+	return response, err
+}
+
+func (r *StocktakeRepo) getLines(ctx context.Context, stocktakeID string) ([]entity.StocktakeLine, error) {
+	var (
+		response   []entity.StocktakeLine
+		lotID      sql.NullString
+		expiryDate sql.NullTime
+	)
+
+	rows, err := r.pg.DB(ctx).Query(ctx, stocktakeLinesQuery, stocktakeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var line entity.StocktakeLine
+		err = rows.Scan(&line.ProductID, &line.ProductName, &line.SKU, &line.BinID, &line.BinCode, &lotID, &line.LotNumber,
+			&expiryDate, &line.Expected, &line.Counted, &line.Counts, &line.Variance)
+		if err != nil {
+			return nil, err
+		}
+
+		line.LotID = lotID.String
+		line.ExpiryDate = formatNullDate(expiryDate)
+
+		response = append(response, line)
+	}
+
+	return response, rows.Err()
+}
+
+func scanStocktake(row pgx.Row) (entity.Stocktake, error) {
+	var (
+		item                  entity.Stocktake
+		zoneID, categoryID    sql.NullString
+		createdBy, approvedBy sql.NullString
+		approvedAt            sql.NullTime
+		createdAt, updatedAt  time.Time
+	)
+
+	err := row.Scan(&item.ID, &item.Number, &item.WarehouseID, &zoneID, &categoryID, &item.Status, &item.Note,
+		&createdBy, &approvedBy, &approvedAt, &createdAt, &updatedAt)
+	if err != nil {
+		return entity.Stocktake{}, err
+	}
+
+	item.ZoneID = zoneID.String
+	item.CategoryID = categoryID.String
+	item.CreatedBy = createdBy.String
+	item.ApprovedBy = approvedBy.String
+	item.ApprovedAt = formatNullTime(approvedAt)
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+	item.UpdatedAt = updatedAt.Format(time.RFC3339)
+
+	return item, nil
+}
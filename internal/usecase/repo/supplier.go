@@ -0,0 +1,314 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/logger"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/postgres"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+const supplierColumns = `id, name, contact_person, phone, email, address, tax_id, note, is_active,
+	COALESCE((SELECT SUM(l.quantity * l.price * c.exchange_rate) FROM supplier_credit_lines l
+		JOIN supplier_credits c ON c.id = l.credit_id WHERE c.supplier_id = suppliers.id), 0)
+	- COALESCE((SELECT SUM(p.amount * c.exchange_rate) FROM supplier_payments p
+		JOIN supplier_credits c ON c.id = p.credit_id WHERE p.supplier_id = suppliers.id), 0),
+	created_at, updated_at`
+
+// supplierStatementQuery lists every credit and payment of a supplier in the order they
+// happened. Amounts are in the base currency at the rate of the credit.
+const supplierStatementQuery = `SELECT date, type, document_id, number, note, debit, credit FROM (
+	SELECT c.credit_date AS date, 'credit' AS type, c.id AS document_id, c.number, c.note,
+		COALESCE(SUM(l.quantity * l.price), 0) * c.exchange_rate AS debit, 0 AS credit, c.created_at
+	FROM supplier_credits c LEFT JOIN supplier_credit_lines l ON l.credit_id = c.id
+	WHERE c.supplier_id = $1
+	GROUP BY c.id
+	UNION ALL
+	SELECT p.paid_date, 'payment', p.id, c.number, p.note, 0, p.amount * c.exchange_rate, p.created_at
+	FROM supplier_payments p JOIN supplier_credits c ON c.id = p.credit_id
+	WHERE p.supplier_id = $1
+) entries ORDER BY date, created_at`
+
+// creditDebtsQuery is the unpaid part of every credit in the base currency at
+// the rate of the credit, like the debt of the supplier and its statement,
+// together with its age in days.
+var creditDebtsQuery = `(SELECT supplier_id, CURRENT_DATE - credit_date AS age_days,
+		(` + creditTotalExpr + ` - ` + creditPaidExpr + `) * exchange_rate AS debt
+	FROM supplier_credits) debts`
+
+type SupplierRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewSupplierRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *SupplierRepo {
+	return &SupplierRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+func (r *SupplierRepo) Create(ctx context.Context, req entity.Supplier) (entity.Supplier, error) {
+	req.ID = uuid.NewString()
+
+	qeury, args, err := r.pg.Builder.Insert("suppliers").
+		Columns(`id, name, contact_person, phone, email, address, tax_id, note, is_active`).
+		Values(req.ID, req.Name, req.ContactPerson, req.Phone, req.Email, req.Address, req.TaxID, req.Note, req.IsActive).ToSql()
+	if err != nil {
+		return entity.Supplier{}, err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil {
+		return entity.Supplier{}, err
+	}
+
+	return r.GetSingle(ctx, entity.Id{ID: req.ID})
+}
+
+func (r *SupplierRepo) GetSingle(ctx context.Context, req entity.Id) (entity.Supplier, error) {
+	qeury, args, err := r.pg.Builder.Select(supplierColumns).From("suppliers").Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return entity.Supplier{}, err
+	}
+
+	return scanSupplier(r.pg.DB(ctx).QueryRow(ctx, qeury, args...))
+}
+
+func (r *SupplierRepo) GetList(ctx context.Context, req entity.GetListFilter) (entity.SupplierList, error) {
+	response := entity.SupplierList{}
+
+	qeuryBuilder := r.pg.Builder.Select(supplierColumns).From("suppliers")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanSupplier(rows)
+		if err != nil {
+			return response, err
+		}
+
+		response.Items, err = listItem(response.Items, item, req)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return response, err
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("suppliers").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+func (r *SupplierRepo) Update(ctx context.Context, req entity.Supplier) (entity.Supplier, error) {
+	mp := map[string]interface{}{
+		"name":           req.Name,
+		"contact_person": req.ContactPerson,
+		"phone":          req.Phone,
+		"email":          req.Email,
+		"address":        req.Address,
+		"tax_id":         req.TaxID,
+		"note":           req.Note,
+		"is_active":      req.IsActive,
+		"updated_at":     "now()",
+	}
+
+	qeury, args, err := r.pg.Builder.Update("suppliers").SetMap(mp).Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return entity.Supplier{}, err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil {
+		return entity.Supplier{}, err
+	}
+
+	return r.GetSingle(ctx, entity.Id{ID: req.ID})
+}
+
+func (r *SupplierRepo) Delete(ctx context.Context, req entity.Id) error {
+	qeury, args, err := r.pg.Builder.Delete("suppliers").Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetStatement returns the credits and payments of a supplier between from and to
+// (YYYY-MM-DD, both optional and inclusive) with the debt running after each entry.
+func (r *SupplierRepo) GetStatement(ctx context.Context, supplierID, from, to string) (entity.SupplierStatement, error) {
+	response := entity.SupplierStatement{
+		SupplierID: supplierID,
+		From:       from,
+		To:         to,
+		Entries:    []entity.SupplierStatementEntry{},
+	}
+
+	var exists bool
+	err := r.pg.DB(ctx).QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM suppliers WHERE id = $1)`, supplierID).Scan(&exists)
+	if err != nil {
+		return response, err
+	}
+
+	if !exists {
+		return response, pgx.ErrNoRows
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, supplierStatementQuery, supplierID)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	balance := 0.0
+	for rows.Next() {
+		var (
+			item entity.SupplierStatementEntry
+			date time.Time
+		)
+
+		err = rows.Scan(&date, &item.Type, &item.DocumentID, &item.Number, &item.Note, &item.Debit, &item.Credit)
+		if err != nil {
+			return response, err
+		}
+
+		item.Date = date.Format("2006-01-02")
+		balance += item.Debit - item.Credit
+		item.Balance = balance
+
+		switch {
+		case from != "" && item.Date < from:
+			response.OpeningBalance = balance
+		case to != "" && item.Date > to:
+			continue
+		default:
+			response.Entries = append(response.Entries, item)
+		}
+
+		response.ClosingBalance = balance
+	}
+
+	return response, rows.Err()
+}
+
+// GetDebtAging buckets the open debt to every supplier by the days passed since
+// the credits were taken, in the base currency. Filters may use supplier_id.
+func (r *SupplierRepo) GetDebtAging(ctx context.Context, req entity.GetListFilter) (entity.SupplierDebtAgingReport, error) {
+	response := entity.SupplierDebtAgingReport{}
+
+	qeuryBuilder := r.pg.Builder.
+		Select(`debts.supplier_id, suppliers.name, `+agingBuckets).
+		From(creditDebtsQuery).
+		Join("suppliers ON suppliers.id = debts.supplier_id").
+		Where("debt > 0").
+		GroupBy("debts.supplier_id", "suppliers.name")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item entity.SupplierDebtAging
+
+		err = rows.Scan(&item.SupplierID, &item.SupplierName, &item.Days0To30, &item.Days31To60, &item.Days61To90,
+			&item.Days90Plus, &item.Total)
+		if err != nil {
+			return response, err
+		}
+
+		response.Items, err = listItem(response.Items, item, req)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return response, err
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("supplier_id").From(creditDebtsQuery).
+		Where("debt > 0").Where(where).GroupBy("supplier_id").ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, `SELECT COUNT(1) FROM (`+countQuery+`) grouped`, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	totalsQuery, args, err := r.pg.Builder.Select(agingBuckets).From(creditDebtsQuery).Where("debt > 0").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, totalsQuery, args...).Scan(&response.Totals.Days0To30, &response.Totals.Days31To60,
+		&response.Totals.Days61To90, &response.Totals.Days90Plus, &response.Totals.Total)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+func scanSupplier(row pgx.Row) (entity.Supplier, error) {
+	var (
+		item                 entity.Supplier
+		createdAt, updatedAt time.Time
+	)
+
+	err := row.Scan(&item.ID, &item.Name, &item.ContactPerson, &item.Phone, &item.Email, &item.Address, &item.TaxID,
+		&item.Note, &item.IsActive, &item.RemainingDebt, &createdAt, &updatedAt)
+	if err != nil {
+		return entity.Supplier{}, err
+	}
+
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+	item.UpdatedAt = updatedAt.Format(time.RFC3339)
+
+	return item, nil
+}
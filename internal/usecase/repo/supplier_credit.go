@@ -0,0 +1,347 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/logger"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/postgres"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+var ErrCreditHasPayments = entity.NewError(config.ErrorConflict, "Credits with payments can't be changed or deleted")
+
+const (
+	creditTotalExpr = `COALESCE((SELECT SUM(quantity * price) FROM supplier_credit_lines WHERE supplier_credit_lines.credit_id = supplier_credits.id), 0)`
+	creditPaidExpr  = `COALESCE((SELECT SUM(amount) FROM supplier_payments WHERE supplier_payments.credit_id = supplier_credits.id), 0)`
+
+	supplierCreditColumns = `id, number, supplier_id, goods_receipt_id, credit_date, due_date, note, currency, exchange_rate, match_status, created_by, ` +
+		creditTotalExpr + `, ` + creditPaidExpr + `, created_at, updated_at`
+)
+
+type SupplierCreditRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewSupplierCreditRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *SupplierCreditRepo {
+	return &SupplierCreditRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+func (r *SupplierCreditRepo) Create(ctx context.Context, req entity.SupplierCredit) (entity.SupplierCredit, error) {
+	req.ID = uuid.NewString()
+
+	err := r.pg.WithTx(ctx, func(ctx context.Context) error {
+		creditDate := squirrel.Expr("COALESCE(NULLIF(?, '')::DATE, CURRENT_DATE)", req.CreditDate)
+
+		rate, err := exchangeRate(ctx, r.pg, &req.Currency, creditDate)
+		if err != nil {
+			return err
+		}
+
+		qeury, args, err := r.pg.Builder.Insert("supplier_credits").
+			Columns(`id, number, supplier_id, goods_receipt_id, credit_date, due_date, note, currency, exchange_rate, created_by`).
+			Values(req.ID,
+				squirrel.Expr("COALESCE(NULLIF(?, ''), 'CR-' || LPAD(nextval('supplier_credit_number_seq')::TEXT, 6, '0'))", req.Number),
+				req.SupplierID, nullString(req.GoodsReceiptID), creditDate,
+				squirrel.Expr("NULLIF(?, '')::DATE", req.DueDate),
+				req.Note, req.Currency, rate, nullString(req.CreatedBy)).ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		if err != nil {
+			return err
+		}
+
+		return r.insertLines(ctx, req.ID, req.Lines)
+	})
+	if err != nil {
+		return entity.SupplierCredit{}, err
+	}
+
+	return r.GetSingle(ctx, entity.Id{ID: req.ID})
+}
+
+func (r *SupplierCreditRepo) GetSingle(ctx context.Context, req entity.Id) (entity.SupplierCredit, error) {
+	qeury, args, err := r.pg.Builder.Select(supplierCreditColumns).From("supplier_credits").Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return entity.SupplierCredit{}, err
+	}
+
+	response, err := scanSupplierCredit(r.pg.DB(ctx).QueryRow(ctx, qeury, args...))
+	if err != nil {
+		return entity.SupplierCredit{}, err
+	}
+
+	qeury, args, err = r.pg.Builder.
+		Select(`id, product_id, unit, unit_quantity, quantity, price`).
+		From("supplier_credit_lines").Where("credit_id = ?", req.ID).OrderBy("line_no").ToSql()
+	if err != nil {
+		return entity.SupplierCredit{}, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return entity.SupplierCredit{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var line entity.SupplierCreditLine
+		err = rows.Scan(&line.ID, &line.ProductID, &line.Unit, &line.UnitQuantity, &line.Quantity, &line.Price)
+		if err != nil {
+			return entity.SupplierCredit{}, err
+		}
+
+		response.Lines = append(response.Lines, line)
+	}
+
+	return response, rows.Err()
+}
+
+func (r *SupplierCreditRepo) GetList(ctx context.Context, req entity.GetListFilter) (entity.SupplierCreditList, error) {
+	response := entity.SupplierCreditList{}
+
+	qeuryBuilder := r.pg.Builder.Select(supplierCreditColumns).From("supplier_credits")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanSupplierCredit(rows)
+		if err != nil {
+			return response, err
+		}
+
+		response.Items, err = listItem(response.Items, item, req)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return response, err
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("supplier_credits").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+// Update replaces the header fields and the lines of a credit that has no payments yet.
+func (r *SupplierCreditRepo) Update(ctx context.Context, req entity.SupplierCredit) (entity.SupplierCredit, error) {
+	err := r.pg.WithTx(ctx, func(ctx context.Context) error {
+		err := r.lockUnpaid(ctx, req.ID)
+		if err != nil {
+			return err
+		}
+
+		rate, err := exchangeRate(ctx, r.pg, &req.Currency,
+			squirrel.Expr("COALESCE(NULLIF(?, '')::DATE, (SELECT credit_date FROM supplier_credits WHERE id = ?))", req.CreditDate, req.ID))
+		if err != nil {
+			return err
+		}
+
+		mp := map[string]interface{}{
+			"supplier_id":      req.SupplierID,
+			"goods_receipt_id": nullString(req.GoodsReceiptID),
+			"credit_date":      squirrel.Expr("COALESCE(NULLIF(?, '')::DATE, credit_date)", req.CreditDate),
+			"due_date":         squirrel.Expr("NULLIF(?, '')::DATE", req.DueDate),
+			"note":             req.Note,
+			"currency":         req.Currency,
+			"exchange_rate":    rate,
+			"updated_at":       "now()",
+		}
+
+		if req.Number != "" {
+			mp["number"] = req.Number
+		}
+
+		qeury, args, err := r.pg.Builder.Update("supplier_credits").SetMap(mp).Where("id = ?", req.ID).ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		if err != nil {
+			return err
+		}
+
+		qeury, args, err = r.pg.Builder.Delete("supplier_credit_lines").Where("credit_id = ?", req.ID).ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		if err != nil {
+			return err
+		}
+
+		return r.insertLines(ctx, req.ID, req.Lines)
+	})
+	if err != nil {
+		return entity.SupplierCredit{}, err
+	}
+
+	return r.GetSingle(ctx, entity.Id{ID: req.ID})
+}
+
+func (r *SupplierCreditRepo) Delete(ctx context.Context, req entity.Id) error {
+	return r.pg.WithTx(ctx, func(ctx context.Context) error {
+		err := r.lockUnpaid(ctx, req.ID)
+		if err != nil {
+			return err
+		}
+
+		qeury, args, err := r.pg.Builder.Delete("supplier_credits").Where("id = ?", req.ID).ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		return err
+	})
+}
+
+func (r *SupplierCreditRepo) SetMatchStatus(ctx context.Context, req entity.Id, status string) error {
+	qeury, args, err := r.pg.Builder.Update("supplier_credits").Set("match_status", status).Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	return err
+}
+
+// GetIDsByGoodsReceipt returns the credits recorded against a goods receipt.
+func (r *SupplierCreditRepo) GetIDsByGoodsReceipt(ctx context.Context, goodsReceiptID string) ([]string, error) {
+	qeury, args, err := r.pg.Builder.Select("id").From("supplier_credits").Where("goods_receipt_id = ?", goodsReceiptID).ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var response []string
+	for rows.Next() {
+		var id string
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+
+		response = append(response, id)
+	}
+
+	return response, rows.Err()
+}
+
+// lockUnpaid locks the credit and fails with ErrCreditHasPayments if anything was paid on it.
+func (r *SupplierCreditRepo) lockUnpaid(ctx context.Context, id string) error {
+	var hasPayments bool
+
+	qeury, args, err := r.pg.Builder.
+		Select(`EXISTS (SELECT 1 FROM supplier_payments WHERE supplier_payments.credit_id = supplier_credits.id)`).
+		From("supplier_credits").Where("id = ?", id).Suffix("FOR UPDATE").ToSql()
+	if err != nil {
+		return err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, qeury, args...).Scan(&hasPayments)
+	if err != nil {
+		return err
+	}
+
+	if hasPayments {
+		return ErrCreditHasPayments
+	}
+
+	return nil
+}
+
+func (r *SupplierCreditRepo) insertLines(ctx context.Context, creditID string, lines []entity.SupplierCreditLine) error {
+	if len(lines) == 0 {
+		return nil
+	}
+
+	insert := r.pg.Builder.Insert("supplier_credit_lines").
+		Columns(`id, credit_id, line_no, product_id, unit, unit_quantity, quantity, price`)
+	for i, line := range lines {
+		err := toBaseUnit(ctx, r.pg, line.ProductID, &line.Unit, &line.UnitQuantity, &line.Quantity)
+		if err != nil {
+			return err
+		}
+
+		insert = insert.Values(uuid.NewString(), creditID, i+1, line.ProductID, line.Unit, line.UnitQuantity, line.Quantity, line.Price)
+	}
+
+	qeury, args, err := insert.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	return err
+}
+
+func scanSupplierCredit(row pgx.Row) (entity.SupplierCredit, error) {
+	var (
+		item                      entity.SupplierCredit
+		goodsReceiptID, createdBy sql.NullString
+		dueDate                   sql.NullTime
+		creditDate                time.Time
+		createdAt, updatedAt      time.Time
+	)
+
+	err := row.Scan(&item.ID, &item.Number, &item.SupplierID, &goodsReceiptID, &creditDate, &dueDate, &item.Note,
+		&item.Currency, &item.ExchangeRate, &item.MatchStatus, &createdBy, &item.TotalAmount, &item.PaidAmount, &createdAt, &updatedAt)
+	if err != nil {
+		return entity.SupplierCredit{}, err
+	}
+
+	item.GoodsReceiptID = goodsReceiptID.String
+	item.CreatedBy = createdBy.String
+	item.CreditDate = creditDate.Format("2006-01-02")
+	if dueDate.Valid {
+		item.DueDate = dueDate.Time.Format("2006-01-02")
+	}
+	item.RemainingDebt = item.TotalAmount - item.PaidAmount
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+	item.UpdatedAt = updatedAt.Format(time.RFC3339)
+
+	return item, nil
+}
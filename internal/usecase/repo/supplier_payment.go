@@ -0,0 +1,210 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/logger"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/postgres"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+var ErrPaymentExceedsDebt = entity.NewError(config.ErrorPaymentExceeds, "Payment is larger than the remaining debt of the credit")
+
+const supplierPaymentColumns = `id, supplier_id, credit_id, amount, currency, exchange_rate, cash_desk_id, payment_method, paid_date, note, created_by, created_at`
+
+type SupplierPaymentRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewSupplierPaymentRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *SupplierPaymentRepo {
+	return &SupplierPaymentRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+// Create records a payment against a credit. The credit row stays locked until the
+// payment is written so that concurrent payments can't overpay it.
+func (r *SupplierPaymentRepo) Create(ctx context.Context, req entity.SupplierPayment) (entity.SupplierPayment, error) {
+	req.ID = uuid.NewString()
+
+	err := r.pg.WithTx(ctx, func(ctx context.Context) error {
+		var remaining float64
+
+		qeury, args, err := r.pg.Builder.
+			Select(creditTotalExpr+` - `+creditPaidExpr, "currency").
+			From("supplier_credits").Where("id = ?", req.CreditID).Suffix("FOR UPDATE").ToSql()
+		if err != nil {
+			return err
+		}
+
+		err = r.pg.DB(ctx).QueryRow(ctx, qeury, args...).Scan(&remaining, &req.Currency)
+		if err != nil {
+			return err
+		}
+
+		// amounts are stored with two decimals, anything below a tiyin is rounding noise
+		if req.Amount-remaining > 0.005 {
+			return ErrPaymentExceedsDebt
+		}
+
+		paidDate := squirrel.Expr("COALESCE(NULLIF(?, '')::DATE, CURRENT_DATE)", req.PaidDate)
+
+		rate, err := exchangeRate(ctx, r.pg, &req.Currency, paidDate)
+		if err != nil {
+			return err
+		}
+
+		if req.CashDeskID != "" {
+			req.Method, err = paymentDesk(ctx, r.pg, req.CashDeskID, req.PaidDate, req.Currency, req.Method)
+			if err != nil {
+				return err
+			}
+		}
+
+		if req.Method == "" {
+			req.Method = entity.PaymentMethodCash
+		}
+
+		qeury, args, err = r.pg.Builder.Insert("supplier_payments").
+			Columns(`id, supplier_id, credit_id, amount, currency, exchange_rate, cash_desk_id, payment_method, paid_date, note, created_by`).
+			Select(r.pg.Builder.Select().
+				Column("?::UUID", req.ID).
+				Column("supplier_id").
+				Column("id").
+				Column("?::NUMERIC", req.Amount).
+				Column("currency").
+				Column("?::NUMERIC", rate).
+				Column("?::UUID", nullString(req.CashDeskID)).
+				Column("?::TEXT", req.Method).
+				Column(paidDate).
+				Column("?::TEXT", req.Note).
+				Column("?::UUID", nullString(req.CreatedBy)).
+				From("supplier_credits").Where("id = ?", req.CreditID)).ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		return err
+	})
+	if err != nil {
+		return entity.SupplierPayment{}, err
+	}
+
+	return r.GetSingle(ctx, entity.Id{ID: req.ID})
+}
+
+func (r *SupplierPaymentRepo) GetSingle(ctx context.Context, req entity.Id) (entity.SupplierPayment, error) {
+	qeury, args, err := r.pg.Builder.Select(supplierPaymentColumns).From("supplier_payments").Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return entity.SupplierPayment{}, err
+	}
+
+	return scanSupplierPayment(r.pg.DB(ctx).QueryRow(ctx, qeury, args...))
+}
+
+func (r *SupplierPaymentRepo) GetList(ctx context.Context, req entity.GetListFilter) (entity.SupplierPaymentList, error) {
+	response := entity.SupplierPaymentList{}
+
+	qeuryBuilder := r.pg.Builder.Select(supplierPaymentColumns).From("supplier_payments")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanSupplierPayment(rows)
+		if err != nil {
+			return response, err
+		}
+
+		response.Items, err = listItem(response.Items, item, req)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return response, err
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("supplier_payments").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+// Delete removes a payment unless the day it was booked to its cash desk on is closed.
+func (r *SupplierPaymentRepo) Delete(ctx context.Context, req entity.Id) error {
+	return r.pg.WithTx(ctx, func(ctx context.Context) error {
+		err := unbookPayment(ctx, r.pg, "supplier_payments", req.ID)
+		if err != nil {
+			return err
+		}
+
+		qeury, args, err := r.pg.Builder.Delete("supplier_payments").Where("id = ?", req.ID).ToSql()
+		if err != nil {
+			return err
+		}
+
+		tag, err := r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		if err != nil {
+			return err
+		}
+
+		if tag.RowsAffected() == 0 {
+			return pgx.ErrNoRows
+		}
+
+		return nil
+	})
+}
+
+func scanSupplierPayment(row pgx.Row) (entity.SupplierPayment, error) {
+	var (
+		item                  entity.SupplierPayment
+		cashDeskID, createdBy sql.NullString
+		paidDate              time.Time
+		createdAt             time.Time
+	)
+
+	err := row.Scan(&item.ID, &item.SupplierID, &item.CreditID, &item.Amount, &item.Currency, &item.ExchangeRate,
+		&cashDeskID, &item.Method, &paidDate, &item.Note, &createdBy, &createdAt)
+	if err != nil {
+		return entity.SupplierPayment{}, err
+	}
+
+	item.CashDeskID = cashDeskID.String
+	item.CreatedBy = createdBy.String
+	item.PaidDate = paidDate.Format("2006-01-02")
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+
+	return item, nil
+}
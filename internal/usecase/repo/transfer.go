@@ -0,0 +1,432 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/logger"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/postgres"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+const transferColumns = `id, number, source_warehouse_id, dest_warehouse_id, transit_bin_id, status, note,
+	created_by, dispatched_by, dispatched_at, received_by, received_at, cancelled_at, created_at, updated_at`
+
+type TransferRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewTransferRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *TransferRepo {
+	return &TransferRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+func (r *TransferRepo) Create(ctx context.Context, req entity.Transfer) (entity.Transfer, error) {
+	req.ID = uuid.NewString()
+
+	err := r.pg.WithTx(ctx, func(ctx context.Context) error {
+		qeury, args, err := r.pg.Builder.Insert("transfers").
+			Columns(`id, number, source_warehouse_id, dest_warehouse_id, note, created_by`).
+			Values(req.ID,
+				squirrel.Expr("COALESCE(NULLIF(?, ''), 'TR-' || LPAD(nextval('transfer_number_seq')::TEXT, 6, '0'))", req.Number),
+				req.SourceWarehouseID, req.DestWarehouseID, req.Note, nullString(req.CreatedBy)).ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		if err != nil {
+			return err
+		}
+
+		return r.insertLines(ctx, req.ID, req.Lines)
+	})
+	if err != nil {
+		return entity.Transfer{}, err
+	}
+
+	return r.GetSingle(ctx, entity.Id{ID: req.ID})
+}
+
+func (r *TransferRepo) GetSingle(ctx context.Context, req entity.Id) (entity.Transfer, error) {
+	qeury, args, err := r.pg.Builder.Select(transferColumns).From("transfers").Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return entity.Transfer{}, err
+	}
+
+	response, err := scanTransfer(r.pg.DB(ctx).QueryRow(ctx, qeury, args...))
+	if err != nil {
+		return entity.Transfer{}, err
+	}
+
+	response.Lines, err = r.getLines(ctx, req.ID)
+	if err != nil {
+		return entity.Transfer{}, err
+	}
+
+	response.Discrepancies, err = r.getDiscrepancies(ctx, req.ID)
+	if err != nil {
+		return entity.Transfer{}, err
+	}
+
+	return response, nil
+}
+
+func (r *TransferRepo) GetList(ctx context.Context, req entity.GetListFilter) (entity.TransferList, error) {
+	response := entity.TransferList{}
+
+	qeuryBuilder := r.pg.Builder.Select(transferColumns).From("transfers")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanTransfer(rows)
+		if err != nil {
+			return response, err
+		}
+
+		response.Items, err = listItem(response.Items, item, req)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return response, err
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("transfers").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+// Update replaces the header fields and the lines of a draft transfer.
+func (r *TransferRepo) Update(ctx context.Context, req entity.Transfer) (entity.Transfer, error) {
+	err := r.pg.WithTx(ctx, func(ctx context.Context) error {
+		status, err := lockStatus(ctx, r.pg, "transfers", req.ID)
+		if err != nil {
+			return err
+		}
+
+		if status != entity.TransferStatusDraft {
+			return ErrDocumentNotDraft
+		}
+
+		mp := map[string]interface{}{
+			"source_warehouse_id": req.SourceWarehouseID,
+			"dest_warehouse_id":   req.DestWarehouseID,
+			"note":                req.Note,
+			"updated_at":          "now()",
+		}
+
+		if req.Number != "" {
+			mp["number"] = req.Number
+		}
+
+		qeury, args, err := r.pg.Builder.Update("transfers").SetMap(mp).Where("id = ?", req.ID).ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		if err != nil {
+			return err
+		}
+
+		qeury, args, err = r.pg.Builder.Delete("transfer_lines").Where("transfer_id = ?", req.ID).ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		if err != nil {
+			return err
+		}
+
+		return r.insertLines(ctx, req.ID, req.Lines)
+	})
+	if err != nil {
+		return entity.Transfer{}, err
+	}
+
+	return r.GetSingle(ctx, entity.Id{ID: req.ID})
+}
+
+// Delete removes a draft transfer, dispatched ones must be cancelled instead.
+func (r *TransferRepo) Delete(ctx context.Context, req entity.Id) error {
+	return r.pg.WithTx(ctx, func(ctx context.Context) error {
+		status, err := lockStatus(ctx, r.pg, "transfers", req.ID)
+		if err != nil {
+			return err
+		}
+
+		if status != entity.TransferStatusDraft {
+			return ErrDocumentNotDraft
+		}
+
+		qeury, args, err := r.pg.Builder.Delete("transfers").Where("id = ?", req.ID).ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		return err
+	})
+}
+
+// LockStatus locks the transfer for the rest of the transaction and returns its status.
+func (r *TransferRepo) LockStatus(ctx context.Context, req entity.Id) (string, error) {
+	return lockStatus(ctx, r.pg, "transfers", req.ID)
+}
+
+// SetStatus moves the transfer to status and stamps who did it and when.
+func (r *TransferRepo) SetStatus(ctx context.Context, req entity.Id, status, userID string) error {
+	mp := map[string]interface{}{
+		"status":     status,
+		"updated_at": "now()",
+	}
+
+	switch status {
+	case entity.TransferStatusInTransit:
+		mp["dispatched_by"] = nullString(userID)
+		mp["dispatched_at"] = "now()"
+	case entity.TransferStatusReceived:
+		mp["received_by"] = nullString(userID)
+		mp["received_at"] = "now()"
+	case entity.TransferStatusCancelled:
+		mp["cancelled_at"] = "now()"
+	}
+
+	qeury, args, err := r.pg.Builder.Update("transfers").SetMap(mp).Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// ReplaceLines swaps the lines of a transfer for lines, e.g. once they are split by lot on dispatch.
+func (r *TransferRepo) ReplaceLines(ctx context.Context, transferID string, lines []entity.TransferLine) error {
+	return r.pg.WithTx(ctx, func(ctx context.Context) error {
+		qeury, args, err := r.pg.Builder.Delete("transfer_lines").Where("transfer_id = ?", transferID).ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+		if err != nil {
+			return err
+		}
+
+		return r.insertLines(ctx, transferID, lines)
+	})
+}
+
+func (r *TransferRepo) SetTransitBin(ctx context.Context, req entity.Id, binID string) error {
+	qeury, args, err := r.pg.Builder.Update("transfers").Set("transit_bin_id", binID).Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	return err
+}
+
+func (r *TransferRepo) SetReceived(ctx context.Context, transferID, lineID string, quantity float64) error {
+	qeury, args, err := r.pg.Builder.Update("transfer_lines").Set("received_quantity", quantity).
+		Where("id = ? AND transfer_id = ?", lineID, transferID).ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *TransferRepo) CreateDiscrepancy(ctx context.Context, req entity.TransferDiscrepancy) error {
+	qeury, args, err := r.pg.Builder.Insert("transfer_discrepancies").
+		Columns(`id, transfer_id, line_id, product_id, dispatched, received, difference, note, created_by`).
+		Values(uuid.NewString(), req.TransferID, req.LineID, req.ProductID, req.Dispatched, req.Received,
+			req.Received-req.Dispatched, req.Note, nullString(req.CreatedBy)).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	return err
+}
+
+func (r *TransferRepo) getLines(ctx context.Context, transferID string) ([]entity.TransferLine, error) {
+	var response []entity.TransferLine
+
+	qeury, args, err := r.pg.Builder.
+		Select(`id, product_id, source_bin_id, dest_bin_id, lot_id, serials, unit, unit_quantity, quantity,
+			COALESCE(received_quantity, 0)`).
+		From("transfer_lines").Where("transfer_id = ?", transferID).OrderBy("line_no").ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			line  entity.TransferLine
+			lotID sql.NullString
+		)
+
+		err = rows.Scan(&line.ID, &line.ProductID, &line.SourceBinID, &line.DestBinID, &lotID, &line.Serials, &line.Unit,
+			&line.UnitQuantity, &line.Quantity, &line.ReceivedQuantity)
+		if err != nil {
+			return nil, err
+		}
+
+		line.LotID = lotID.String
+
+		response = append(response, line)
+	}
+
+	return response, rows.Err()
+}
+
+func (r *TransferRepo) getDiscrepancies(ctx context.Context, transferID string) ([]entity.TransferDiscrepancy, error) {
+	var (
+		response  []entity.TransferDiscrepancy
+		createdBy sql.NullString
+		createdAt time.Time
+	)
+
+	qeury, args, err := r.pg.Builder.
+		Select(`id, transfer_id, line_id, product_id, dispatched, received, difference, note, created_by, created_at`).
+		From("transfer_discrepancies").Where("transfer_id = ?", transferID).OrderBy("created_at").ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item entity.TransferDiscrepancy
+		err = rows.Scan(&item.ID, &item.TransferID, &item.LineID, &item.ProductID, &item.Dispatched, &item.Received,
+			&item.Difference, &item.Note, &createdBy, &createdAt)
+		if err != nil {
+			return nil, err
+		}
+
+		item.CreatedBy = createdBy.String
+		item.CreatedAt = createdAt.Format(time.RFC3339)
+
+		response = append(response, item)
+	}
+
+	return response, rows.Err()
+}
+
+func (r *TransferRepo) insertLines(ctx context.Context, transferID string, lines []entity.TransferLine) error {
+	if len(lines) == 0 {
+		return nil
+	}
+
+	insert := r.pg.Builder.Insert("transfer_lines").
+		Columns(`id, transfer_id, line_no, product_id, source_bin_id, dest_bin_id, lot_id, serials, unit, unit_quantity, quantity`)
+	for i, line := range lines {
+		err := toBaseUnit(ctx, r.pg, line.ProductID, &line.Unit, &line.UnitQuantity, &line.Quantity)
+		if err != nil {
+			return err
+		}
+
+		insert = insert.Values(uuid.NewString(), transferID, i+1, line.ProductID, line.SourceBinID, line.DestBinID,
+			nullString(line.LotID), textArray(line.Serials), line.Unit, line.UnitQuantity, line.Quantity)
+	}
+
+	qeury, args, err := insert.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	return err
+}
+
+func scanTransfer(row pgx.Row) (entity.Transfer, error) {
+	var (
+		item                                  entity.Transfer
+		transitBinID, createdBy               sql.NullString
+		dispatchedBy, receivedBy              sql.NullString
+		dispatchedAt, receivedAt, cancelledAt sql.NullTime
+		createdAt, updatedAt                  time.Time
+	)
+
+	err := row.Scan(&item.ID, &item.Number, &item.SourceWarehouseID, &item.DestWarehouseID, &transitBinID, &item.Status,
+		&item.Note, &createdBy, &dispatchedBy, &dispatchedAt, &receivedBy, &receivedAt, &cancelledAt, &createdAt, &updatedAt)
+	if err != nil {
+		return entity.Transfer{}, err
+	}
+
+	item.TransitBinID = transitBinID.String
+	item.CreatedBy = createdBy.String
+	item.DispatchedBy = dispatchedBy.String
+	item.DispatchedAt = formatNullTime(dispatchedAt)
+	item.ReceivedBy = receivedBy.String
+	item.ReceivedAt = formatNullTime(receivedAt)
+	item.CancelledAt = formatNullTime(cancelledAt)
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+	item.UpdatedAt = updatedAt.Format(time.RFC3339)
+
+	return item, nil
+}
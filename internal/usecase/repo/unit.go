@@ -0,0 +1,50 @@
+package repo
+
+import (
+	"context"
+	"math"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/postgres"
+)
+
+var ErrUnknownUnit = entity.NewError(config.ErrorBadRequest, "The unit is not defined for the product")
+
+// toBaseUnit converts the quantity of a document line entered in any unit of
+// its product to the base unit. When unitQuantity is given the base quantity
+// is derived from it, else unitQuantity is derived from the base quantity. An
+// empty unit is the base unit.
+func toBaseUnit(ctx context.Context, pg *postgres.Postgres, productID string, unit *string, unitQuantity, quantity *float64) error {
+	var (
+		baseUnit string
+		factor   float64
+	)
+
+	qeury, args, err := pg.Builder.Select("base_unit").
+		Column("COALESCE((SELECT factor FROM product_units WHERE product_id = products.id AND unit = ?), 0)", *unit).
+		From("products").Where("id = ?", productID).ToSql()
+	if err != nil {
+		return err
+	}
+
+	err = pg.DB(ctx).QueryRow(ctx, qeury, args...).Scan(&baseUnit, &factor)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case *unit == "" || *unit == baseUnit:
+		*unit, factor = baseUnit, 1
+	case factor <= 0:
+		return ErrUnknownUnit
+	}
+
+	if *unitQuantity > 0 {
+		*quantity = math.Round(*unitQuantity*factor*1000) / 1000
+	} else {
+		*unitQuantity = math.Round(*quantity/factor*1000) / 1000
+	}
+
+	return nil
+}
@@ -39,7 +39,7 @@ func (r *UserRepo) Create(ctx context.Context, req entity.User) (entity.User, er
 		return entity.User{}, err
 	}
 
-	_, err = r.pg.Pool.Exec(ctx, qeury, args...)
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
 	if err != nil {
 		return entity.User{}, err
 	}
@@ -74,7 +74,7 @@ func (r *UserRepo) GetSingle(ctx context.Context, req entity.UserSingleRequest)
 		return entity.User{}, err
 	}
 
-	err = r.pg.Pool.QueryRow(ctx, qeury, args...).
+	err = r.pg.DB(ctx).QueryRow(ctx, qeury, args...).
 		Scan(&response.ID, &response.FullName, &response.Email, &response.Bio, &response.Username, &response.Password,
 			&response.UserType, &response.UserRole, &response.Status, &avatarID, &response.Gender, &createdAt, &updatedAt)
 	if err != nil {
@@ -110,7 +110,7 @@ func (r *UserRepo) GetList(ctx context.Context, req entity.GetListFilter) (entit
 		return response, err
 	}
 
-	rows, err := r.pg.Pool.Query(ctx, qeury, args...)
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
 	if err != nil {
 		return response, err
 	}
@@ -127,7 +127,14 @@ func (r *UserRepo) GetList(ctx context.Context, req entity.GetListFilter) (entit
 		item.CreatedAt = createdAt.Format(time.RFC3339)
 		item.UpdatedAt = updatedAt.Format(time.RFC3339)
 
-		response.Items = append(response.Items, item)
+		response.Items, err = listItem(response.Items, item, req)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return response, err
 	}
 
 	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("users").Where(where).ToSql()
@@ -135,7 +142,7 @@ func (r *UserRepo) GetList(ctx context.Context, req entity.GetListFilter) (entit
 		return response, err
 	}
 
-	err = r.pg.Pool.QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	err = r.pg.DB(ctx).QueryRow(ctx, countQuery, args...).Scan(&response.Count)
 	if err != nil {
 		return response, err
 	}
@@ -190,7 +197,7 @@ func (r *UserRepo) Update(ctx context.Context, req entity.User) (entity.User, er
 		return entity.User{}, err
 	}
 
-	_, err = r.pg.Pool.Exec(ctx, query, args...)
+	_, err = r.pg.DB(ctx).Exec(ctx, query, args...)
 	if err != nil {
 		return entity.User{}, err
 	}
@@ -208,7 +215,7 @@ func (r *UserRepo) Delete(ctx context.Context, req entity.Id) error {
 		return err
 	}
 
-	_, err = r.pg.Pool.Exec(ctx, qeury, args...)
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,160 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/logger"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/postgres"
+	"github.com/google/uuid"
+)
+
+type WarehouseRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewWarehouseRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *WarehouseRepo {
+	return &WarehouseRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+func (r *WarehouseRepo) Create(ctx context.Context, req entity.Warehouse) (entity.Warehouse, error) {
+	req.ID = uuid.NewString()
+
+	qeury, args, err := r.pg.Builder.Insert("warehouses").
+		Columns(`id, code, name, address, is_active`).
+		Values(req.ID, req.Code, req.Name, req.Address, req.IsActive).ToSql()
+	if err != nil {
+		return entity.Warehouse{}, err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil {
+		return entity.Warehouse{}, err
+	}
+
+	return r.GetSingle(ctx, entity.Id{ID: req.ID})
+}
+
+func (r *WarehouseRepo) GetSingle(ctx context.Context, req entity.Id) (entity.Warehouse, error) {
+	response := entity.Warehouse{}
+	var createdAt, updatedAt time.Time
+
+	qeury, args, err := r.pg.Builder.
+		Select(`id, code, name, address, is_active, created_at, updated_at`).
+		From("warehouses").Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return entity.Warehouse{}, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, qeury, args...).
+		Scan(&response.ID, &response.Code, &response.Name, &response.Address, &response.IsActive, &createdAt, &updatedAt)
+	if err != nil {
+		return entity.Warehouse{}, err
+	}
+
+	response.CreatedAt = createdAt.Format(time.RFC3339)
+	response.UpdatedAt = updatedAt.Format(time.RFC3339)
+
+	return response, nil
+}
+
+func (r *WarehouseRepo) GetList(ctx context.Context, req entity.GetListFilter) (entity.WarehouseList, error) {
+	var (
+		response             = entity.WarehouseList{}
+		createdAt, updatedAt time.Time
+	)
+
+	qeuryBuilder := r.pg.Builder.
+		Select(`id, code, name, address, is_active, created_at, updated_at`).
+		From("warehouses")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item entity.Warehouse
+		err = rows.Scan(&item.ID, &item.Code, &item.Name, &item.Address, &item.IsActive, &createdAt, &updatedAt)
+		if err != nil {
+			return response, err
+		}
+
+		item.CreatedAt = createdAt.Format(time.RFC3339)
+		item.UpdatedAt = updatedAt.Format(time.RFC3339)
+
+		response.Items, err = listItem(response.Items, item, req)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return response, err
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("warehouses").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+func (r *WarehouseRepo) Update(ctx context.Context, req entity.Warehouse) (entity.Warehouse, error) {
+	mp := map[string]interface{}{
+		"code":       req.Code,
+		"name":       req.Name,
+		"address":    req.Address,
+		"is_active":  req.IsActive,
+		"updated_at": "now()",
+	}
+
+	qeury, args, err := r.pg.Builder.Update("warehouses").SetMap(mp).Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return entity.Warehouse{}, err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil {
+		return entity.Warehouse{}, err
+	}
+
+	return r.GetSingle(ctx, entity.Id{ID: req.ID})
+}
+
+func (r *WarehouseRepo) Delete(ctx context.Context, req entity.Id) error {
+	qeury, args, err := r.pg.Builder.Delete("warehouses").Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
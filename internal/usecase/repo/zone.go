@@ -0,0 +1,175 @@
+package repo
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/config"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/logger"
+	"github.com/Avazbek-02/DE-Lider-Warehouse/pkg/postgres"
+	"github.com/google/uuid"
+)
+
+type ZoneRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewZoneRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *ZoneRepo {
+	return &ZoneRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+func (r *ZoneRepo) Create(ctx context.Context, req entity.Zone) (entity.Zone, error) {
+	if strings.EqualFold(req.Code, transitCode) {
+		return entity.Zone{}, ErrReservedCode
+	}
+
+	req.ID = uuid.NewString()
+
+	qeury, args, err := r.pg.Builder.Insert("zones").
+		Columns(`id, warehouse_id, code, name`).
+		Values(req.ID, req.WarehouseID, req.Code, req.Name).ToSql()
+	if err != nil {
+		return entity.Zone{}, err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil {
+		return entity.Zone{}, err
+	}
+
+	return r.GetSingle(ctx, entity.Id{ID: req.ID})
+}
+
+func (r *ZoneRepo) GetSingle(ctx context.Context, req entity.Id) (entity.Zone, error) {
+	response := entity.Zone{}
+	var createdAt, updatedAt time.Time
+
+	qeury, args, err := r.pg.Builder.
+		Select(`id, warehouse_id, code, name, created_at, updated_at`).
+		From("zones").Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return entity.Zone{}, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, qeury, args...).
+		Scan(&response.ID, &response.WarehouseID, &response.Code, &response.Name, &createdAt, &updatedAt)
+	if err != nil {
+		return entity.Zone{}, err
+	}
+
+	response.CreatedAt = createdAt.Format(time.RFC3339)
+	response.UpdatedAt = updatedAt.Format(time.RFC3339)
+
+	return response, nil
+}
+
+func (r *ZoneRepo) GetList(ctx context.Context, req entity.GetListFilter) (entity.ZoneList, error) {
+	var (
+		response             = entity.ZoneList{}
+		createdAt, updatedAt time.Time
+	)
+
+	qeuryBuilder := r.pg.Builder.
+		Select(`id, warehouse_id, code, name, created_at, updated_at`).
+		From("zones")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.DB(ctx).Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item entity.Zone
+		err = rows.Scan(&item.ID, &item.WarehouseID, &item.Code, &item.Name, &createdAt, &updatedAt)
+		if err != nil {
+			return response, err
+		}
+
+		item.CreatedAt = createdAt.Format(time.RFC3339)
+		item.UpdatedAt = updatedAt.Format(time.RFC3339)
+
+		response.Items, err = listItem(response.Items, item, req)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return response, err
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("zones").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.DB(ctx).QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+func (r *ZoneRepo) Update(ctx context.Context, req entity.Zone) (entity.Zone, error) {
+	mp := map[string]interface{}{
+		"code":       req.Code,
+		"name":       req.Name,
+		"updated_at": "now()",
+	}
+
+	qeuryBuilder := r.pg.Builder.Update("zones").SetMap(mp).Where("id = ?", req.ID)
+
+	reserved := strings.EqualFold(req.Code, transitCode)
+	if reserved {
+		// only the in-transit zone itself may keep the code
+		qeuryBuilder = qeuryBuilder.Where("code = ?", transitCode)
+	}
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return entity.Zone{}, err
+	}
+
+	tag, err := r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil {
+		return entity.Zone{}, err
+	}
+
+	if reserved && tag.RowsAffected() == 0 {
+		return entity.Zone{}, ErrReservedCode
+	}
+
+	return r.GetSingle(ctx, entity.Id{ID: req.ID})
+}
+
+func (r *ZoneRepo) Delete(ctx context.Context, req entity.Id) error {
+	qeury, args, err := r.pg.Builder.Delete("zones").Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pg.DB(ctx).Exec(ctx, qeury, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,399 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+)
+
+// salesOrderTransitions lists the statuses an order may move to from each
+// status. Picking, picked and packed are reached through the pick list, packing
+// and shipment of the order instead.
+var salesOrderTransitions = map[string][]string{
+	entity.OrderStatusPending:    {entity.OrderStatusProcessing, entity.OrderStatusCancelled},
+	entity.OrderStatusProcessing: {entity.OrderStatusPending, entity.OrderStatusCompleted, entity.OrderStatusCancelled},
+	entity.OrderStatusPicking:    {entity.OrderStatusCancelled},
+	entity.OrderStatusPicked:     {entity.OrderStatusCancelled},
+	entity.OrderStatusPacked:     {entity.OrderStatusCancelled},
+}
+
+// holdsReservation tells whether orders in status keep their stock reserved.
+func holdsReservation(status string) bool {
+	switch status {
+	case entity.OrderStatusProcessing, entity.OrderStatusPicking, entity.OrderStatusPicked, entity.OrderStatusPacked:
+		return true
+	}
+
+	return false
+}
+
+func canTransition(transitions map[string][]string, from, to string) bool {
+	for _, status := range transitions[from] {
+		if status == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CreateSalesOrder creates a pending order with its lines priced for the
+// customer in the order currency on the order date.
+func (uc *UseCase) CreateSalesOrder(ctx context.Context, req entity.SalesOrder) (entity.SalesOrder, error) {
+	err := uc.PriceLines(ctx, req.CustomerID, req.Currency, req.OrderDate, req.Lines)
+	if err != nil {
+		return entity.SalesOrder{}, err
+	}
+
+	return uc.SalesOrderRepo.Create(ctx, req)
+}
+
+// UpdateSalesOrder replaces a pending order and prices its lines again for
+// the customer in the order currency on the order date.
+func (uc *UseCase) UpdateSalesOrder(ctx context.Context, req entity.SalesOrder) (entity.SalesOrder, error) {
+	date := req.OrderDate
+	if date == "" {
+		order, err := uc.SalesOrderRepo.GetSingle(ctx, entity.Id{ID: req.ID})
+		if err != nil {
+			return entity.SalesOrder{}, err
+		}
+
+		date = order.OrderDate
+	}
+
+	err := uc.PriceLines(ctx, req.CustomerID, req.Currency, date, req.Lines)
+	if err != nil {
+		return entity.SalesOrder{}, err
+	}
+
+	return uc.SalesOrderRepo.Update(ctx, req)
+}
+
+// ChangeSalesOrderStatus moves the order to the requested status if the
+// transition is allowed. Orders being processed and fulfilled hold a
+// reservation of their quantities, completing a processing order ships every
+// line from its bin.
+func (uc *UseCase) ChangeSalesOrderStatus(ctx context.Context, req entity.SalesOrderStatusRequest, userID string) (entity.SalesOrder, error) {
+	err := uc.Tx.WithTx(ctx, func(ctx context.Context) error {
+		status, err := uc.SalesOrderRepo.LockStatus(ctx, entity.Id{ID: req.ID})
+		if err != nil {
+			return err
+		}
+
+		if !canTransition(salesOrderTransitions, status, req.Status) {
+			return ErrInvalidStatusTransition
+		}
+
+		// every move out of processing and fulfillment ends the reservation,
+		// shipping takes the stock itself
+		if holdsReservation(status) {
+			err = uc.ReservationRepo.Release(ctx, req.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		switch req.Status {
+		case entity.OrderStatusProcessing:
+			err = uc.reserveSalesOrder(ctx, req.ID)
+			if err != nil {
+				return err
+			}
+		case entity.OrderStatusCompleted:
+			err = uc.shipSalesOrder(ctx, req.ID, userID)
+			if err != nil {
+				return err
+			}
+		case entity.OrderStatusCancelled:
+			order, err := uc.SalesOrderRepo.GetSingle(ctx, entity.Id{ID: req.ID})
+			if err != nil {
+				return err
+			}
+
+			if order.PaidAmount > 0 {
+				return ErrOrderHasPayments
+			}
+
+			err = uc.PickListRepo.CancelOpen(ctx, req.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		return uc.SalesOrderRepo.SetStatus(ctx, entity.Id{ID: req.ID}, req.Status)
+	})
+	if err != nil {
+		return entity.SalesOrder{}, err
+	}
+
+	return uc.SalesOrderRepo.GetSingle(ctx, entity.Id{ID: req.ID})
+}
+
+// reserveSalesOrder reserves the unshipped quantities of the order in its
+// warehouse, failing if any product isn't available to promise.
+func (uc *UseCase) reserveSalesOrder(ctx context.Context, orderID string) error {
+	order, err := uc.SalesOrderRepo.GetSingle(ctx, entity.Id{ID: orderID})
+	if err != nil {
+		return err
+	}
+
+	if len(order.Lines) == 0 {
+		return ErrEmptyDocument
+	}
+
+	settings, err := uc.SettingsRepo.GetSingle(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = uc.lockAvailable(ctx, order.WarehouseID, unshipped(order))
+	if err != nil {
+		return err
+	}
+
+	reservations := make([]entity.StockReservation, 0, len(order.Lines))
+	for _, line := range order.Lines {
+		remaining := line.Quantity - line.Shipped - line.Shorted
+		if remaining <= 0 {
+			continue
+		}
+
+		reservations = append(reservations, entity.StockReservation{
+			OrderID:     order.ID,
+			LineID:      line.ID,
+			ProductID:   line.ProductID,
+			WarehouseID: order.WarehouseID,
+			Quantity:    remaining,
+		})
+	}
+
+	return uc.ReservationRepo.Create(ctx, reservations, settings.ReservationHours)
+}
+
+// unshipped sums the quantities of the order left to ship per product.
+func unshipped(order entity.SalesOrder) map[string]float64 {
+	needed := map[string]float64{}
+	for _, line := range order.Lines {
+		remaining := line.Quantity - line.Shipped - line.Shorted
+		if remaining <= 0 {
+			continue
+		}
+
+		needed[line.ProductID] += remaining
+	}
+
+	return needed
+}
+
+// lockAvailable locks the needed products in the warehouse and makes sure
+// their quantities are available to promise, bundles by their components.
+// Products are locked in a fixed order so that concurrent orders don't deadlock.
+func (uc *UseCase) lockAvailable(ctx context.Context, warehouseID string, needed map[string]float64) error {
+	needed, err := uc.componentDemand(ctx, needed)
+	if err != nil {
+		return err
+	}
+
+	products := make([]string, 0, len(needed))
+	for productID := range needed {
+		products = append(products, productID)
+	}
+
+	sort.Strings(products)
+
+	for _, productID := range products {
+		available, err := uc.ReservationRepo.LockAvailable(ctx, productID, warehouseID)
+		if err != nil {
+			return err
+		}
+
+		if roundTo(available-needed[productID], 3) < 0 {
+			return ErrNotAvailable
+		}
+	}
+
+	return nil
+}
+
+// componentDemand replaces the quantities of bundles by those of their components.
+func (uc *UseCase) componentDemand(ctx context.Context, needed map[string]float64) (map[string]float64, error) {
+	response := make(map[string]float64, len(needed))
+
+	for productID, quantity := range needed {
+		product, err := uc.ProductRepo.GetSingle(ctx, entity.ProductSingleRequest{ID: productID})
+		if err != nil {
+			return nil, err
+		}
+
+		if product.Kind != entity.ProductKindBundle {
+			response[productID] += quantity
+			continue
+		}
+
+		for _, component := range product.Components {
+			response[component.ComponentID] += quantity * component.Quantity
+		}
+	}
+
+	return response, nil
+}
+
+func (uc *UseCase) shipSalesOrder(ctx context.Context, orderID, userID string) error {
+	order, err := uc.SalesOrderRepo.GetSingle(ctx, entity.Id{ID: orderID})
+	if err != nil {
+		return err
+	}
+
+	if len(order.Lines) == 0 {
+		return ErrEmptyDocument
+	}
+
+	// the order's own reservation is released by now, stock reserved by
+	// other orders can't be shipped
+	err = uc.lockAvailable(ctx, order.WarehouseID, unshipped(order))
+	if err != nil {
+		return err
+	}
+
+	movements := make([]entity.StockMovement, 0, len(order.Lines))
+	for _, line := range order.Lines {
+		remaining := line.Quantity - line.Shipped - line.Shorted
+		if remaining <= 0 {
+			continue
+		}
+
+		serialized, err := uc.checkSerials(ctx, line.ProductID, line.Serials, remaining)
+		if err != nil {
+			return err
+		}
+
+		movement := entity.StockMovement{
+			ProductID:    line.ProductID,
+			BinID:        line.BinID,
+			LotID:        line.LotID,
+			Quantity:     -remaining,
+			Reason:       entity.MovementReasonSale,
+			DocumentType: entity.DocumentTypeSalesOrder,
+			DocumentID:   order.ID,
+			Note:         order.Number,
+			UserID:       userID,
+		}
+
+		// serialized lines name their units, so their lots are known
+		var picked []entity.StockMovement
+		if serialized {
+			serials, err := uc.SerialRepo.Move(ctx, entity.SerialMove{
+				ProductID:  line.ProductID,
+				Serials:    line.Serials,
+				FromStatus: entity.SerialStatusInStock,
+				FromBinID:  line.BinID,
+				LotID:      line.LotID,
+				ToStatus:   entity.SerialStatusSold,
+			})
+			if err != nil {
+				return err
+			}
+
+			picked = serialMovements(movement, serials)
+		} else {
+			picked, err = uc.pickLots(ctx, movement, order.WarehouseID)
+			if err != nil {
+				return err
+			}
+		}
+
+		movements = append(movements, picked...)
+
+		err = uc.SalesOrderRepo.AddShipped(ctx, order.ID, line.ID, remaining)
+		if err != nil {
+			return err
+		}
+	}
+
+	return uc.createDocumentMovements(ctx, movements, order.WarehouseID)
+}
+
+// ReturnSalesOrderLines records returned quantities on shipped lines and puts
+// the goods back into stock, by default into the bin they were shipped from.
+// Serialized products come back by serial number.
+func (uc *UseCase) ReturnSalesOrderLines(ctx context.Context, req entity.SalesOrderReturnRequest, userID string) (entity.SalesOrder, error) {
+	err := uc.Tx.WithTx(ctx, func(ctx context.Context) error {
+		status, err := uc.SalesOrderRepo.LockStatus(ctx, entity.Id{ID: req.OrderID})
+		if err != nil {
+			return err
+		}
+
+		if status != entity.OrderStatusCompleted {
+			return ErrInvalidStatusTransition
+		}
+
+		order, err := uc.SalesOrderRepo.GetSingle(ctx, entity.Id{ID: req.OrderID})
+		if err != nil {
+			return err
+		}
+
+		movements := make([]entity.StockMovement, 0, len(req.Lines))
+		for _, item := range req.Lines {
+			line, err := uc.SalesOrderRepo.AddReturned(ctx, order.ID, item.LineID, item.Quantity)
+			if err != nil {
+				return err
+			}
+
+			binID := item.BinID
+			if binID == "" {
+				binID = line.BinID
+			}
+
+			lotID := item.LotID
+			if lotID == "" {
+				lotID = line.LotID
+			}
+
+			movement := entity.StockMovement{
+				ProductID:    line.ProductID,
+				BinID:        binID,
+				LotID:        lotID,
+				Quantity:     item.Quantity,
+				Reason:       entity.MovementReasonSaleReturn,
+				DocumentType: entity.DocumentTypeSalesOrder,
+				DocumentID:   order.ID,
+				Note:         req.Note,
+				UserID:       userID,
+			}
+
+			serialized, err := uc.checkSerials(ctx, line.ProductID, item.Serials, item.Quantity)
+			if err != nil {
+				return err
+			}
+
+			if !serialized {
+				movements = append(movements, movement)
+				continue
+			}
+
+			// only units this order sold can come back through it
+			serials, err := uc.SerialRepo.Move(ctx, entity.SerialMove{
+				ProductID:      line.ProductID,
+				Serials:        item.Serials,
+				FromStatus:     entity.SerialStatusSold,
+				FromDocumentID: order.ID,
+				LotID:          lotID,
+				ToStatus:       entity.SerialStatusInStock,
+				ToBinID:        binID,
+			})
+			if err != nil {
+				return err
+			}
+
+			movements = append(movements, serialMovements(movement, serials)...)
+		}
+
+		return uc.createDocumentMovements(ctx, movements, order.WarehouseID)
+	})
+	if err != nil {
+		return entity.SalesOrder{}, err
+	}
+
+	return uc.SalesOrderRepo.GetSingle(ctx, entity.Id{ID: req.OrderID})
+}
@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+)
+
+// reservationRepo knows the stock still free to promise per product and
+// records the order products are locked in.
+type reservationRepo struct {
+	ReservationRepoI
+
+	available map[string]float64
+	locked    []string
+}
+
+func (r *reservationRepo) LockAvailable(ctx context.Context, productID, warehouseID string) (float64, error) {
+	r.locked = append(r.locked, productID)
+
+	return r.available[productID], nil
+}
+
+func TestUnshipped(t *testing.T) {
+	order := entity.SalesOrder{Lines: []entity.SalesOrderLine{
+		{ProductID: "soap", Quantity: 10, Shipped: 4},
+		{ProductID: "soap", Quantity: 2},
+		{ProductID: "towel", Quantity: 5, Shipped: 3, Shorted: 2},
+		{ProductID: "tea", Quantity: 1.5, Shorted: 0.5},
+		{ProductID: "gum", Quantity: 1, Shipped: 2},
+	}}
+
+	want := map[string]float64{"soap": 8, "tea": 1}
+	if got := unshipped(order); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestLockAvailable(t *testing.T) {
+	products := &productRepo{products: map[string]entity.Product{
+		"gift-set": {ID: "gift-set", Kind: entity.ProductKindBundle, Components: []entity.ProductComponent{
+			{ComponentID: "soap", Quantity: 2},
+			{ComponentID: "towel", Quantity: 1},
+		}},
+		"soap":  {ID: "soap"},
+		"towel": {ID: "towel"},
+		"tea":   {ID: "tea"},
+	}}
+
+	for _, tc := range []struct {
+		name      string
+		needed    map[string]float64
+		available map[string]float64
+		locked    []string
+		err       error
+	}{
+		{
+			name:      "enough stock",
+			needed:    map[string]float64{"towel": 3, "soap": 5},
+			available: map[string]float64{"soap": 5, "towel": 10},
+			locked:    []string{"soap", "towel"},
+		},
+		{
+			name:      "short of stock",
+			needed:    map[string]float64{"soap": 6},
+			available: map[string]float64{"soap": 5},
+			locked:    []string{"soap"},
+			err:       ErrNotAvailable,
+		},
+		{
+			name:      "bundle needs its components",
+			needed:    map[string]float64{"gift-set": 2, "soap": 1},
+			available: map[string]float64{"soap": 5, "towel": 2},
+			locked:    []string{"soap", "towel"},
+		},
+		{
+			name:      "bundle short of a component",
+			needed:    map[string]float64{"gift-set": 3},
+			available: map[string]float64{"soap": 5, "towel": 3},
+			locked:    []string{"soap"},
+			err:       ErrNotAvailable,
+		},
+		{
+			name:      "rounding error in the balance",
+			needed:    map[string]float64{"tea": 0.3},
+			available: map[string]float64{"tea": 0.2999999999},
+			locked:    []string{"tea"},
+		},
+		{
+			name:   "nothing needed",
+			needed: map[string]float64{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			reservations := &reservationRepo{available: tc.available}
+			uc := &UseCase{ProductRepo: products, ReservationRepo: reservations}
+
+			err := uc.lockAvailable(context.Background(), "main", tc.needed)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("got error %v, want %v", err, tc.err)
+			}
+
+			if !reflect.DeepEqual(reservations.locked, tc.locked) {
+				t.Errorf("locked %v, want %v", reservations.locked, tc.locked)
+			}
+		})
+	}
+}
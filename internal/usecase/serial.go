@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+)
+
+// checkSerials tells whether a product is serialized and makes sure a line of
+// quantity units carries exactly one serial number per unit. Lines of other
+// products can't carry serial numbers.
+func (uc *UseCase) checkSerials(ctx context.Context, productID string, serials []string, quantity float64) (bool, error) {
+	product, err := uc.ProductRepo.GetSingle(ctx, entity.ProductSingleRequest{ID: productID})
+	if err != nil {
+		return false, err
+	}
+
+	if !product.IsSerialized {
+		if len(serials) > 0 {
+			return false, ErrProductNotSerialized
+		}
+		return false, nil
+	}
+
+	if float64(len(serials)) != quantity {
+		return true, ErrSerialCount
+	}
+
+	return true, nil
+}
+
+// serialMovements splits movement by the lots of the units it carries, so that
+// every movement names the lot and the serial numbers it moves.
+func serialMovements(movement entity.StockMovement, serials []entity.Serial) []entity.StockMovement {
+	var (
+		response []entity.StockMovement
+		byLot    = map[string]int{}
+		sign     = 1.0
+	)
+
+	if movement.Quantity < 0 {
+		sign = -1
+	}
+
+	for _, serial := range serials {
+		i, ok := byLot[serial.LotID]
+		if !ok {
+			item := movement
+			item.LotID = serial.LotID
+			item.Quantity = 0
+			item.Serials = nil
+
+			i = len(response)
+			byLot[serial.LotID] = i
+			response = append(response, item)
+		}
+
+		response[i].Quantity += sign
+		response[i].Serials = append(response[i].Serials, serial.SerialNumber)
+	}
+
+	return response
+}
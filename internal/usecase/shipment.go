@@ -0,0 +1,173 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+)
+
+// PackSalesOrder packs a picked order into parcels and moves it to packed. A
+// packed order can be packed again until it is shipped, its parcels are replaced.
+func (uc *UseCase) PackSalesOrder(ctx context.Context, req entity.PackRequest) ([]entity.Parcel, error) {
+	var parcels []entity.Parcel
+
+	err := uc.Tx.WithTx(ctx, func(ctx context.Context) error {
+		status, err := uc.SalesOrderRepo.LockStatus(ctx, entity.Id{ID: req.OrderID})
+		if err != nil {
+			return err
+		}
+
+		if status != entity.OrderStatusPicked && status != entity.OrderStatusPacked {
+			return ErrInvalidStatusTransition
+		}
+
+		parcels, err = uc.ShipmentRepo.SetParcels(ctx, req.OrderID, req.Parcels)
+		if err != nil {
+			return err
+		}
+
+		return uc.SalesOrderRepo.SetStatus(ctx, entity.Id{ID: req.OrderID}, entity.OrderStatusPacked)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return parcels, nil
+}
+
+// CreateShipment hands a packed order over to the carrier. The picked goods
+// leave stock from the bins and lots they were picked in, the order is
+// completed and its reservation released.
+func (uc *UseCase) CreateShipment(ctx context.Context, req entity.Shipment, userID string) (entity.Shipment, error) {
+	var shipmentID string
+
+	err := uc.Tx.WithTx(ctx, func(ctx context.Context) error {
+		status, err := uc.SalesOrderRepo.LockStatus(ctx, entity.Id{ID: req.OrderID})
+		if err != nil {
+			return err
+		}
+
+		if status != entity.OrderStatusPacked {
+			return ErrInvalidStatusTransition
+		}
+
+		order, err := uc.SalesOrderRepo.GetSingle(ctx, entity.Id{ID: req.OrderID})
+		if err != nil {
+			return err
+		}
+
+		pickList, err := uc.PickListRepo.GetByOrder(ctx, order.ID)
+		if err != nil {
+			return err
+		}
+
+		err = uc.ReservationRepo.Release(ctx, order.ID)
+		if err != nil {
+			return err
+		}
+
+		err = uc.shipPicked(ctx, order, pickList, userID)
+		if err != nil {
+			return err
+		}
+
+		req.CreatedBy = userID
+
+		shipment, err := uc.ShipmentRepo.Create(ctx, req)
+		if err != nil {
+			return err
+		}
+		shipmentID = shipment.ID
+
+		return uc.SalesOrderRepo.SetStatus(ctx, entity.Id{ID: order.ID}, entity.OrderStatusCompleted)
+	})
+	if err != nil {
+		return entity.Shipment{}, err
+	}
+
+	return uc.ShipmentRepo.GetSingle(ctx, entity.Id{ID: shipmentID})
+}
+
+// shipPicked takes what the pick list picked out of stock and records it as
+// shipped on the order lines.
+func (uc *UseCase) shipPicked(ctx context.Context, order entity.SalesOrder, pickList entity.PickList, userID string) error {
+	needed := map[string]float64{}
+	for _, line := range pickList.Lines {
+		if line.Picked > 0 {
+			needed[line.ProductID] += line.Picked
+		}
+	}
+
+	// the order's own reservation is released by now, stock reserved by
+	// other orders can't be shipped
+	err := uc.lockAvailable(ctx, order.WarehouseID, needed)
+	if err != nil {
+		return err
+	}
+
+	var (
+		movements = make([]entity.StockMovement, 0, len(pickList.Lines))
+		shipped   = map[string]float64{}
+	)
+
+	for _, line := range pickList.Lines {
+		if line.Picked <= 0 {
+			continue
+		}
+
+		serialized, err := uc.checkSerials(ctx, line.ProductID, line.PickedSerials, line.Picked)
+		if err != nil {
+			return err
+		}
+
+		movement := entity.StockMovement{
+			ProductID:    line.ProductID,
+			BinID:        line.BinID,
+			LotID:        line.LotID,
+			Quantity:     -line.Picked,
+			Reason:       entity.MovementReasonSale,
+			DocumentType: entity.DocumentTypeSalesOrder,
+			DocumentID:   order.ID,
+			Note:         order.Number,
+			UserID:       userID,
+		}
+
+		var picked []entity.StockMovement
+		if serialized {
+			serials, err := uc.SerialRepo.Move(ctx, entity.SerialMove{
+				ProductID:  line.ProductID,
+				Serials:    line.PickedSerials,
+				FromStatus: entity.SerialStatusInStock,
+				FromBinID:  line.BinID,
+				LotID:      line.LotID,
+				ToStatus:   entity.SerialStatusSold,
+			})
+			if err != nil {
+				return err
+			}
+
+			picked = serialMovements(movement, serials)
+		} else {
+			picked, err = uc.pickLots(ctx, movement, order.WarehouseID)
+			if err != nil {
+				return err
+			}
+		}
+
+		movements = append(movements, picked...)
+		shipped[line.OrderLineID] += line.Picked
+	}
+
+	for _, line := range order.Lines {
+		if shipped[line.ID] <= 0 {
+			continue
+		}
+
+		err = uc.SalesOrderRepo.AddShipped(ctx, order.ID, line.ID, shipped[line.ID])
+		if err != nil {
+			return err
+		}
+	}
+
+	return uc.createDocumentMovements(ctx, movements, order.WarehouseID)
+}
@@ -0,0 +1,283 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+)
+
+// createDocumentMovements writes the movements of a document and makes sure
+// every bin they touch belongs to the document warehouse, then values them.
+func (uc *UseCase) createDocumentMovements(ctx context.Context, movements []entity.StockMovement, warehouseID string) error {
+	_, err := uc.postMovements(ctx, movements, warehouseID)
+	return err
+}
+
+// postMovements is createDocumentMovements returning the valued movements.
+// Bundles are replaced by their components first.
+func (uc *UseCase) postMovements(ctx context.Context, movements []entity.StockMovement, warehouseID string) ([]entity.StockMovement, error) {
+	movements, err := uc.expandBundles(ctx, movements, warehouseID)
+	if err != nil {
+		return nil, err
+	}
+
+	movements, err = uc.StockRepo.CreateMovements(ctx, movements)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, movement := range movements {
+		if movement.WarehouseID != warehouseID {
+			return nil, ErrBinWarehouseMismatch
+		}
+	}
+
+	return uc.costMovements(ctx, movements)
+}
+
+// expandBundles replaces the movements of bundles by movements of their
+// components. Outbound components are taken from the bin of the movement
+// first, then from the rest of the warehouse, inbound ones go into its bin.
+// Bundles can't be received at a cost of their own, their components are.
+func (uc *UseCase) expandBundles(ctx context.Context, movements []entity.StockMovement, warehouseID string) ([]entity.StockMovement, error) {
+	var (
+		response   = make([]entity.StockMovement, 0, len(movements))
+		components = map[string][]entity.ProductComponent{}
+	)
+
+	for _, movement := range movements {
+		items, ok := components[movement.ProductID]
+		if !ok {
+			product, err := uc.ProductRepo.GetSingle(ctx, entity.ProductSingleRequest{ID: movement.ProductID})
+			if err != nil {
+				return nil, err
+			}
+
+			if product.Kind == entity.ProductKindBundle {
+				items = product.Components
+			}
+			components[movement.ProductID] = items
+		}
+
+		if len(items) == 0 {
+			response = append(response, movement)
+			continue
+		}
+
+		if bringsOwnCost(movement) {
+			return nil, ErrBundleReceipt
+		}
+
+		for _, component := range items {
+			item := movement
+			item.ProductID = component.ComponentID
+			item.LotID = ""
+			item.Serials = nil
+			item.Quantity = roundTo(movement.Quantity*component.Quantity, 3)
+
+			if item.Quantity > 0 {
+				response = append(response, item)
+				continue
+			}
+
+			picked, err := uc.pickWarehouse(ctx, item, warehouseID)
+			if err != nil {
+				return nil, err
+			}
+
+			response = append(response, picked...)
+		}
+	}
+
+	return response, nil
+}
+
+// pickWarehouse splits an outbound movement across the lots of its bin and
+// then of the other bins of the warehouse, first expired first out. Stock in
+// transit is only taken from the in-transit bin. Whatever the warehouse can't
+// cover stays on the bin of the movement.
+func (uc *UseCase) pickWarehouse(ctx context.Context, movement entity.StockMovement, warehouseID string) ([]entity.StockMovement, error) {
+	bin, err := uc.BinRepo.GetSingle(ctx, entity.Id{ID: movement.BinID})
+	if err != nil {
+		return nil, err
+	}
+
+	var binID string
+	if bin.IsVirtual {
+		binID = bin.ID
+	}
+
+	picks, err := uc.StockRepo.GetLotPicks(ctx, movement.ProductID, warehouseID, binID)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(picks, func(a, b int) bool {
+		return picks[a].BinID == movement.BinID && picks[b].BinID != movement.BinID
+	})
+
+	var (
+		response  []entity.StockMovement
+		remaining = -movement.Quantity
+	)
+
+	for _, pick := range picks {
+		if remaining <= 0 {
+			break
+		}
+
+		quantity := math.Min(pick.Available, remaining)
+		remaining = roundTo(remaining-quantity, 3)
+
+		item := movement
+		item.BinID = pick.BinID
+		item.LotID = pick.LotID
+		item.Quantity = -quantity
+		response = append(response, item)
+	}
+
+	if remaining > 0 {
+		item := movement
+		item.Quantity = -remaining
+		response = append(response, item)
+	}
+
+	return response, nil
+}
+
+// CreateStockAdjustment posts manual corrections to the ledger and values them.
+// Bundles hold no stock, their components are adjusted instead. Serialized
+// products are adjusted by their serial numbers: the units added are received
+// into the bin, the units written off are removed from it.
+func (uc *UseCase) CreateStockAdjustment(ctx context.Context, movements []entity.StockMovement) ([]entity.StockMovement, error) {
+	err := uc.Tx.WithTx(ctx, func(ctx context.Context) (err error) {
+		var posted []entity.StockMovement
+		for _, movement := range movements {
+			product, err := uc.ProductRepo.GetSingle(ctx, entity.ProductSingleRequest{ID: movement.ProductID})
+			if err != nil {
+				return err
+			}
+
+			if product.Kind == entity.ProductKindBundle {
+				return ErrBundleNoStock
+			}
+
+			serialized, err := uc.checkSerials(ctx, movement.ProductID, movement.Serials, math.Abs(movement.Quantity))
+			if err != nil {
+				return err
+			}
+
+			switch {
+			case !serialized:
+				posted = append(posted, movement)
+			case movement.Quantity > 0:
+				err = uc.SerialRepo.Receive(ctx, movement.ProductID, movement.BinID, movement.LotID, movement.Serials)
+				if err != nil {
+					return err
+				}
+
+				posted = append(posted, movement)
+			default:
+				serials, err := uc.SerialRepo.Move(ctx, entity.SerialMove{
+					ProductID:  movement.ProductID,
+					Serials:    movement.Serials,
+					FromStatus: entity.SerialStatusInStock,
+					FromBinID:  movement.BinID,
+					LotID:      movement.LotID,
+					ToStatus:   entity.SerialStatusRemoved,
+				})
+				if err != nil {
+					return err
+				}
+
+				posted = append(posted, serialMovements(movement, serials)...)
+			}
+		}
+
+		movements = posted
+		movements, err = uc.StockRepo.CreateMovements(ctx, movements)
+		if err != nil {
+			return err
+		}
+
+		movements, err = uc.costMovements(ctx, movements)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return movements, nil
+}
+
+// pickLots splits an outbound movement that names no lot across the lots in
+// its bin, first expired first out. Whatever the lots can't cover stays on the
+// stock kept without a lot, where posting fails if it isn't there either.
+func (uc *UseCase) pickLots(ctx context.Context, movement entity.StockMovement, warehouseID string) ([]entity.StockMovement, error) {
+	if movement.LotID != "" || movement.Quantity >= 0 {
+		return []entity.StockMovement{movement}, nil
+	}
+
+	picks, err := uc.StockRepo.GetLotPicks(ctx, movement.ProductID, warehouseID, movement.BinID)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		response  []entity.StockMovement
+		remaining = -movement.Quantity
+	)
+
+	for _, pick := range picks {
+		if remaining <= 0 {
+			break
+		}
+
+		quantity := math.Min(pick.Available, remaining)
+		remaining = roundTo(remaining-quantity, 3)
+
+		item := movement
+		item.LotID = pick.LotID
+		item.Quantity = -quantity
+		response = append(response, item)
+	}
+
+	if remaining > 0 {
+		item := movement
+		item.Quantity = -remaining
+		response = append(response, item)
+	}
+
+	return response, nil
+}
+
+// SuggestLotPicks tells where to take quantity of a product from in a
+// warehouse, first expired first out.
+func (uc *UseCase) SuggestLotPicks(ctx context.Context, productID, warehouseID string, quantity float64) (entity.LotPickList, error) {
+	response := entity.LotPickList{
+		Requested: quantity,
+	}
+
+	picks, err := uc.StockRepo.GetLotPicks(ctx, productID, warehouseID, "")
+	if err != nil {
+		return response, err
+	}
+
+	remaining := quantity
+	for _, pick := range picks {
+		if remaining <= 0 {
+			break
+		}
+
+		pick.Quantity = math.Min(pick.Available, remaining)
+		remaining = roundTo(remaining-pick.Quantity, 3)
+
+		response.Items = append(response.Items, pick)
+	}
+
+	response.Shortage = math.Max(remaining, 0)
+
+	return response, nil
+}
@@ -0,0 +1,284 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+)
+
+// transitBin is the virtual bin of the fake warehouse.
+const transitBin = "transit"
+
+// stockRepo lists the lot picks of a single warehouse from memory, in the
+// first-expired-first-out order the database returns them in.
+type stockRepo struct {
+	StockRepoI
+
+	picks map[string][]entity.LotPick
+}
+
+func (r *stockRepo) GetLotPicks(ctx context.Context, productID, warehouseID, binID string) ([]entity.LotPick, error) {
+	var response []entity.LotPick
+	for _, pick := range r.picks[productID] {
+		if binID == "" && pick.BinID == transitBin || binID != "" && pick.BinID != binID {
+			continue
+		}
+		response = append(response, pick)
+	}
+
+	return response, nil
+}
+
+type productRepo struct {
+	ProductRepoI
+
+	products map[string]entity.Product
+}
+
+func (r *productRepo) GetSingle(ctx context.Context, req entity.ProductSingleRequest) (entity.Product, error) {
+	return r.products[req.ID], nil
+}
+
+type binRepo struct {
+	BinRepoI
+}
+
+func (r *binRepo) GetSingle(ctx context.Context, req entity.Id) (entity.Bin, error) {
+	return entity.Bin{ID: req.ID, IsVirtual: req.ID == transitBin}, nil
+}
+
+func movement(productID, binID, lotID string, quantity float64) entity.StockMovement {
+	return entity.StockMovement{ProductID: productID, BinID: binID, LotID: lotID, Quantity: quantity}
+}
+
+func TestExpandBundles(t *testing.T) {
+	var (
+		products = map[string]entity.Product{
+			"gift-set": {ID: "gift-set", Kind: entity.ProductKindBundle, Components: []entity.ProductComponent{
+				{ComponentID: "soap", Quantity: 2},
+				{ComponentID: "towel", Quantity: 1},
+			}},
+			"sampler": {ID: "sampler", Kind: entity.ProductKindBundle, Components: []entity.ProductComponent{
+				{ComponentID: "tea", Quantity: 0.1},
+			}},
+			"soap":  {ID: "soap"},
+			"towel": {ID: "towel"},
+			"tea":   {ID: "tea"},
+		}
+		picks = map[string][]entity.LotPick{
+			"soap": {
+				{BinID: "b", LotID: "soap-1", Available: 3},
+				{BinID: "a", LotID: "soap-2", Available: 2},
+				{BinID: "a", LotID: "soap-3", Available: 10},
+				{BinID: transitBin, LotID: "", Available: 4},
+			},
+			"towel": {
+				{BinID: "a", Available: 1},
+				{BinID: transitBin, Available: 2},
+			},
+			"tea": {
+				{BinID: "a", LotID: "tea-1", Available: 1},
+				{BinID: "a", LotID: "tea-2", Available: 0.1},
+			},
+		}
+	)
+
+	for _, tc := range []struct {
+		name string
+		in   entity.StockMovement
+		want []entity.StockMovement
+		err  error
+	}{
+		{
+			name: "plain product",
+			in:   movement("soap", "a", "soap-1", -1),
+			want: []entity.StockMovement{movement("soap", "a", "soap-1", -1)},
+		},
+		{
+			name: "inbound bundle",
+			in:   movement("gift-set", "a", "gift-lot", 3),
+			want: []entity.StockMovement{movement("soap", "a", "", 6), movement("towel", "a", "", 3)},
+		},
+		{
+			name: "own bin first then the warehouse",
+			in:   movement("gift-set", "a", "", -1),
+			want: []entity.StockMovement{movement("soap", "a", "soap-2", -2), movement("towel", "a", "", -1)},
+		},
+		{
+			name: "other bins in expiry order",
+			in:   movement("gift-set", "c", "", -1),
+			want: []entity.StockMovement{movement("soap", "b", "soap-1", -2), movement("towel", "a", "", -1)},
+		},
+		{
+			name: "shortage stays on the bin",
+			in:   movement("gift-set", "a", "", -3),
+			want: []entity.StockMovement{
+				movement("soap", "a", "soap-2", -2),
+				movement("soap", "a", "soap-3", -4),
+				movement("towel", "a", "", -1),
+				movement("towel", "a", "", -2),
+			},
+		},
+		{
+			name: "in transit only from the transit bin",
+			in:   movement("gift-set", transitBin, "", -2),
+			want: []entity.StockMovement{movement("soap", transitBin, "", -4), movement("towel", transitBin, "", -2)},
+		},
+		{
+			name: "fractions leave no remainder",
+			in:   movement("sampler", "a", "", -11),
+			want: []entity.StockMovement{movement("tea", "a", "tea-1", -1), movement("tea", "a", "tea-2", -0.1)},
+		},
+		{
+			name: "receipt",
+			in:   entity.StockMovement{ProductID: "gift-set", BinID: "a", Quantity: 1, UnitCost: 90, Reason: entity.MovementReasonReceipt},
+			err:  ErrBundleReceipt,
+		},
+		{
+			name: "opening balance",
+			in:   entity.StockMovement{ProductID: "gift-set", BinID: "a", Quantity: 1, Reason: entity.MovementReasonOpeningBalance},
+			err:  ErrBundleReceipt,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			uc := &UseCase{
+				ProductRepo: &productRepo{products: products},
+				StockRepo:   &stockRepo{picks: picks},
+				BinRepo:     &binRepo{},
+			}
+
+			got, err := uc.expandBundles(context.Background(), []entity.StockMovement{tc.in}, "warehouse")
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("got error %v, want %v", err, tc.err)
+			}
+
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPickLots(t *testing.T) {
+	picks := map[string][]entity.LotPick{
+		"milk": {
+			{BinID: "a", LotID: "milk-1", Available: 1},
+			{BinID: "b", LotID: "milk-2", Available: 5},
+			{BinID: "a", LotID: "milk-3", Available: 0.1},
+			{BinID: "a", LotID: "milk-4", Available: 5},
+			{BinID: "a", LotID: "", Available: 2},
+		},
+	}
+
+	for _, tc := range []struct {
+		name string
+		in   entity.StockMovement
+		want []entity.StockMovement
+	}{
+		{
+			name: "inbound",
+			in:   movement("milk", "a", "", 3),
+			want: []entity.StockMovement{movement("milk", "a", "", 3)},
+		},
+		{
+			name: "lot named",
+			in:   movement("milk", "a", "milk-4", -3),
+			want: []entity.StockMovement{movement("milk", "a", "milk-4", -3)},
+		},
+		{
+			name: "first expired first out",
+			in:   movement("milk", "a", "", -3),
+			want: []entity.StockMovement{
+				movement("milk", "a", "milk-1", -1),
+				movement("milk", "a", "milk-3", -0.1),
+				movement("milk", "a", "milk-4", -1.9),
+			},
+		},
+		{
+			name: "fractions leave no remainder",
+			in:   movement("milk", "a", "", -1.1),
+			want: []entity.StockMovement{movement("milk", "a", "milk-1", -1), movement("milk", "a", "milk-3", -0.1)},
+		},
+		{
+			name: "stock without a lot last",
+			in:   movement("milk", "a", "", -7.1),
+			want: []entity.StockMovement{
+				movement("milk", "a", "milk-1", -1),
+				movement("milk", "a", "milk-3", -0.1),
+				movement("milk", "a", "milk-4", -5),
+				movement("milk", "a", "", -1),
+			},
+		},
+		{
+			name: "shortage on the stock without a lot",
+			in:   movement("milk", "a", "", -9),
+			want: []entity.StockMovement{
+				movement("milk", "a", "milk-1", -1),
+				movement("milk", "a", "milk-3", -0.1),
+				movement("milk", "a", "milk-4", -5),
+				movement("milk", "a", "", -2),
+				movement("milk", "a", "", -0.9),
+			},
+		},
+		{
+			name: "nothing in the bin",
+			in:   movement("milk", "c", "", -2),
+			want: []entity.StockMovement{movement("milk", "c", "", -2)},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			uc := &UseCase{StockRepo: &stockRepo{picks: picks}}
+
+			got, err := uc.pickLots(context.Background(), tc.in, "warehouse")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSuggestLotPicks(t *testing.T) {
+	picks := map[string][]entity.LotPick{
+		"milk": {
+			{BinID: "a", LotID: "milk-1", Available: 1},
+			{BinID: "b", LotID: "milk-2", Available: 0.1},
+			{BinID: transitBin, LotID: "milk-3", Available: 5},
+		},
+	}
+
+	for _, tc := range []struct {
+		name     string
+		quantity float64
+		want     []float64
+		shortage float64
+	}{
+		{name: "covered by the first lot", quantity: 0.5, want: []float64{0.5}},
+		{name: "fractions leave no shortage", quantity: 1.1, want: []float64{1, 0.1}},
+		{name: "shortage", quantity: 2, want: []float64{1, 0.1}, shortage: 0.9},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			uc := &UseCase{StockRepo: &stockRepo{picks: picks}}
+
+			response, err := uc.SuggestLotPicks(context.Background(), "milk", "warehouse", tc.quantity)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []float64
+			for _, pick := range response.Items {
+				got = append(got, pick.Quantity)
+			}
+
+			if !reflect.DeepEqual(got, tc.want) || response.Shortage != tc.shortage {
+				t.Errorf("got %v short %v, want %v short %v", got, response.Shortage, tc.want, tc.shortage)
+			}
+		})
+	}
+}
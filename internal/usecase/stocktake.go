@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+)
+
+// ApproveStocktake closes an open count session and posts the difference
+// between every counted line and its balance at the time of its latest count.
+// Movements posted after the count stay on top of it, so stock moved since is
+// neither lost nor counted twice. Balances are locked meanwhile. Lines nobody
+// counted are left alone. Serialized products that are off are rejected, they
+// are adjusted by their serial numbers first.
+func (uc *UseCase) ApproveStocktake(ctx context.Context, id, userID string) (entity.Stocktake, error) {
+	err := uc.Tx.WithTx(ctx, func(ctx context.Context) error {
+		status, err := uc.StocktakeRepo.LockStatus(ctx, entity.Id{ID: id})
+		if err != nil {
+			return err
+		}
+
+		if status != entity.StocktakeStatusOpen {
+			return ErrInvalidStatusTransition
+		}
+
+		stocktake, err := uc.StocktakeRepo.GetSingle(ctx, entity.Id{ID: id})
+		if err != nil {
+			return err
+		}
+
+		// balances are locked in the order documents lock them in
+		lines := stocktake.Lines
+		sort.SliceStable(lines, func(a, b int) bool {
+			x, y := lines[a], lines[b]
+			if x.ProductID != y.ProductID {
+				return x.ProductID < y.ProductID
+			}
+			if x.BinID != y.BinID {
+				return x.BinID < y.BinID
+			}
+			return x.LotID < y.LotID
+		})
+
+		var movements []entity.StockMovement
+		for _, line := range lines {
+			if line.Counts == 0 {
+				continue
+			}
+
+			balance, err := uc.StockRepo.LockBalance(ctx, line.ProductID, stocktake.WarehouseID, line.BinID, line.LotID)
+			if err != nil {
+				return err
+			}
+
+			moved, err := uc.StocktakeRepo.GetMovedSinceCount(ctx, stocktake.ID, line.ProductID, line.BinID, line.LotID)
+			if err != nil {
+				return err
+			}
+
+			// the count is taken as of when it was made, what moved since stays moved
+			adjustment := roundTo(line.Counted+moved-balance, 3)
+			if adjustment == 0 {
+				continue
+			}
+
+			// counts carry no serial numbers to say which units moved
+			serialized, err := uc.checkSerials(ctx, line.ProductID, nil, 0)
+			if err != nil {
+				return err
+			}
+
+			if serialized {
+				return ErrStocktakeSerialized
+			}
+
+			movements = append(movements, entity.StockMovement{
+				ProductID:    line.ProductID,
+				BinID:        line.BinID,
+				LotID:        line.LotID,
+				Quantity:     adjustment,
+				Reason:       entity.MovementReasonAdjustment,
+				DocumentType: entity.DocumentTypeStocktake,
+				DocumentID:   stocktake.ID,
+				Note:         stocktake.Number,
+				UserID:       userID,
+			})
+		}
+
+		err = uc.createDocumentMovements(ctx, movements, stocktake.WarehouseID)
+		if err != nil {
+			return err
+		}
+
+		return uc.StocktakeRepo.SetStatus(ctx, entity.Id{ID: id}, entity.StocktakeStatusApproved, userID)
+	})
+	if err != nil {
+		return entity.Stocktake{}, err
+	}
+
+	return uc.StocktakeRepo.GetSingle(ctx, entity.Id{ID: id})
+}
+
+// CancelStocktake drops an open count session without touching stock.
+func (uc *UseCase) CancelStocktake(ctx context.Context, id string) (entity.Stocktake, error) {
+	err := uc.Tx.WithTx(ctx, func(ctx context.Context) error {
+		status, err := uc.StocktakeRepo.LockStatus(ctx, entity.Id{ID: id})
+		if err != nil {
+			return err
+		}
+
+		if status != entity.StocktakeStatusOpen {
+			return ErrInvalidStatusTransition
+		}
+
+		return uc.StocktakeRepo.SetStatus(ctx, entity.Id{ID: id}, entity.StocktakeStatusCancelled, "")
+	})
+	if err != nil {
+		return entity.Stocktake{}, err
+	}
+
+	return uc.StocktakeRepo.GetSingle(ctx, entity.Id{ID: id})
+}
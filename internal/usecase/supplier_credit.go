@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+)
+
+// CreateSupplierCredit records a supplier invoice and matches it against its
+// goods receipt and the purchase order of the receipt.
+func (uc *UseCase) CreateSupplierCredit(ctx context.Context, req entity.SupplierCredit) (entity.SupplierCredit, error) {
+	var id string
+
+	err := uc.Tx.WithTx(ctx, func(ctx context.Context) error {
+		credit, err := uc.SupplierCreditRepo.Create(ctx, req)
+		if err != nil {
+			return err
+		}
+		id = credit.ID
+
+		return uc.rematchCredit(ctx, id)
+	})
+	if err != nil {
+		return entity.SupplierCredit{}, err
+	}
+
+	return uc.SupplierCreditRepo.GetSingle(ctx, entity.Id{ID: id})
+}
+
+// UpdateSupplierCredit replaces an unpaid credit and matches it again.
+func (uc *UseCase) UpdateSupplierCredit(ctx context.Context, req entity.SupplierCredit) (entity.SupplierCredit, error) {
+	err := uc.Tx.WithTx(ctx, func(ctx context.Context) error {
+		_, err := uc.SupplierCreditRepo.Update(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		return uc.rematchCredit(ctx, req.ID)
+	})
+	if err != nil {
+		return entity.SupplierCredit{}, err
+	}
+
+	return uc.SupplierCreditRepo.GetSingle(ctx, entity.Id{ID: req.ID})
+}
+
+// GetSupplierCreditMatch compares a credit line by line with its goods receipt
+// and purchase order as they are now.
+func (uc *UseCase) GetSupplierCreditMatch(ctx context.Context, id string) (entity.ThreeWayMatch, error) {
+	credit, err := uc.SupplierCreditRepo.GetSingle(ctx, entity.Id{ID: id})
+	if err != nil {
+		return entity.ThreeWayMatch{}, err
+	}
+
+	return uc.matchSupplierCredit(ctx, credit)
+}
+
+func (uc *UseCase) rematchCredit(ctx context.Context, id string) error {
+	match, err := uc.GetSupplierCreditMatch(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return uc.SupplierCreditRepo.SetMatchStatus(ctx, entity.Id{ID: id}, match.Status)
+}
+
+// rematchReceiptCredits refreshes the match of the credits recorded against a
+// receipt once the receipt is posted or cancelled.
+func (uc *UseCase) rematchReceiptCredits(ctx context.Context, receiptID string) error {
+	ids, err := uc.SupplierCreditRepo.GetIDsByGoodsReceipt(ctx, receiptID)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		err = uc.rematchCredit(ctx, id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// matchTotals adds up one product of a three-way match. Values are kept to
+// average the prices of products spread over several lines.
+type matchTotals struct {
+	line                                       *entity.ThreeWayMatchLine
+	orderedValue, receivedValue, invoicedValue float64
+}
+
+// matchSupplierCredit compares per product what the purchase order asked for,
+// what the posted receipt brought in and what the credit charges for. The
+// invoiced quantity has to equal the received one and nothing may be received
+// beyond the order; invoice price and receipt cost have to equal the order
+// price. Without a purchase order the invoice is only matched to the receipt.
+func (uc *UseCase) matchSupplierCredit(ctx context.Context, credit entity.SupplierCredit) (entity.ThreeWayMatch, error) {
+	match := entity.ThreeWayMatch{
+		CreditID:       credit.ID,
+		GoodsReceiptID: credit.GoodsReceiptID,
+		Status:         entity.MatchStatusNone,
+	}
+
+	if credit.GoodsReceiptID == "" {
+		return match, nil
+	}
+
+	receipt, err := uc.GoodsReceiptRepo.GetSingle(ctx, entity.Id{ID: credit.GoodsReceiptID})
+	if err != nil {
+		return match, err
+	}
+	match.PurchaseOrderID = receipt.PurchaseOrderID
+
+	orderLines := map[string]entity.PurchaseOrderLine{}
+	if receipt.PurchaseOrderID != "" {
+		order, err := uc.PurchaseOrderRepo.GetSingle(ctx, entity.Id{ID: receipt.PurchaseOrderID})
+		if err != nil {
+			return match, err
+		}
+
+		for _, line := range order.Lines {
+			orderLines[line.ID] = line
+		}
+	}
+
+	var (
+		products []string
+		totals   = map[string]*matchTotals{}
+		ordered  = map[string]bool{}
+	)
+
+	product := func(productID string) *matchTotals {
+		if _, ok := totals[productID]; !ok {
+			products = append(products, productID)
+			totals[productID] = &matchTotals{line: &entity.ThreeWayMatchLine{ProductID: productID}}
+		}
+		return totals[productID]
+	}
+
+	// a receipt that isn't posted hasn't received anything yet
+	posted := receipt.Status == entity.DocumentStatusPosted
+
+	for _, line := range receipt.Lines {
+		item := product(line.ProductID)
+
+		if posted {
+			item.line.ReceivedQuantity += line.Quantity
+			item.receivedValue += line.Quantity * line.UnitCost
+		}
+
+		orderLine, ok := orderLines[line.PurchaseOrderLineID]
+		if ok && !ordered[orderLine.ID] {
+			ordered[orderLine.ID] = true
+			item.line.OrderedQuantity += orderLine.Quantity
+			item.orderedValue += orderLine.Quantity * orderLine.Price
+		}
+	}
+
+	for _, line := range credit.Lines {
+		item := product(line.ProductID)
+		item.line.InvoicedQuantity += line.Quantity
+		item.invoicedValue += line.Quantity * line.Price
+	}
+
+	match.Status = entity.MatchStatusMatched
+
+	for _, productID := range products {
+		item := totals[productID]
+		line := item.line
+
+		line.OrderPrice = averagePrice(item.orderedValue, line.OrderedQuantity)
+		line.ReceiptUnitCost = averagePrice(item.receivedValue, line.ReceivedQuantity)
+		line.InvoicePrice = averagePrice(item.invoicedValue, line.InvoicedQuantity)
+
+		line.QuantityMismatch = differs(line.InvoicedQuantity, line.ReceivedQuantity, 3)
+		if receipt.PurchaseOrderID != "" && roundTo(line.ReceivedQuantity-line.OrderedQuantity, 3) > 0 {
+			line.QuantityMismatch = true
+		}
+
+		switch {
+		case receipt.PurchaseOrderID == "":
+			line.PriceMismatch = line.InvoicedQuantity > 0 && line.ReceivedQuantity > 0 &&
+				differs(line.InvoicePrice, line.ReceiptUnitCost, 2)
+		case line.OrderedQuantity > 0:
+			line.PriceMismatch = (line.InvoicedQuantity > 0 && differs(line.InvoicePrice, line.OrderPrice, 2)) ||
+				(line.ReceivedQuantity > 0 && differs(line.ReceiptUnitCost, line.OrderPrice, 2))
+		}
+
+		if line.QuantityMismatch || line.PriceMismatch {
+			match.Status = entity.MatchStatusMismatch
+		}
+
+		match.Lines = append(match.Lines, *line)
+	}
+
+	return match, nil
+}
+
+func averagePrice(value, quantity float64) float64 {
+	if quantity == 0 {
+		return 0
+	}
+
+	return roundTo(value/quantity, 2)
+}
+
+// differs compares a and b rounded to decimals.
+func differs(a, b float64, decimals int) bool {
+	return roundTo(a, decimals) != roundTo(b, decimals)
+}
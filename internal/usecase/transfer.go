@@ -0,0 +1,359 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/Avazbek-02/DE-Lider-Warehouse/internal/entity"
+)
+
+// DispatchTransfer takes the lines out of their source bins and parks them in
+// the in-transit bin of the destination warehouse. Lines without a lot are
+// split by lot first, so that the same lots arrive at the destination.
+// Units of serialized products travel by serial number.
+func (uc *UseCase) DispatchTransfer(ctx context.Context, id, userID string) (entity.Transfer, error) {
+	err := uc.Tx.WithTx(ctx, func(ctx context.Context) error {
+		status, err := uc.TransferRepo.LockStatus(ctx, entity.Id{ID: id})
+		if err != nil {
+			return err
+		}
+
+		if status != entity.TransferStatusDraft {
+			return ErrInvalidStatusTransition
+		}
+
+		transfer, err := uc.TransferRepo.GetSingle(ctx, entity.Id{ID: id})
+		if err != nil {
+			return err
+		}
+
+		if len(transfer.Lines) == 0 {
+			return ErrEmptyDocument
+		}
+
+		transitBinID, err := uc.BinRepo.GetTransitBin(ctx, transfer.DestWarehouseID)
+		if err != nil {
+			return err
+		}
+
+		err = uc.TransferRepo.SetTransitBin(ctx, entity.Id{ID: id}, transitBinID)
+		if err != nil {
+			return err
+		}
+
+		lines, err := uc.pickTransferLots(ctx, transfer, transitBinID)
+		if err != nil {
+			return err
+		}
+
+		out := make([]entity.StockMovement, 0, len(lines))
+		in := make([]entity.StockMovement, 0, len(lines))
+		for _, line := range lines {
+			out = append(out, transferMovement(transfer, line.ProductID, line.SourceBinID, line.LotID, line.Serials,
+				-line.Quantity, entity.MovementReasonTransferDispatch, "", userID))
+			in = append(in, transferMovement(transfer, line.ProductID, transitBinID, line.LotID, line.Serials,
+				line.Quantity, entity.MovementReasonTransferDispatch, "", userID))
+		}
+
+		err = uc.createDocumentMovements(ctx, out, transfer.SourceWarehouseID)
+		if err != nil {
+			return err
+		}
+
+		err = uc.createDocumentMovements(ctx, in, transfer.DestWarehouseID)
+		if err != nil {
+			return err
+		}
+
+		return uc.TransferRepo.SetStatus(ctx, entity.Id{ID: id}, entity.TransferStatusInTransit, userID)
+	})
+	if err != nil {
+		return entity.Transfer{}, err
+	}
+
+	return uc.TransferRepo.GetSingle(ctx, entity.Id{ID: id})
+}
+
+// ReceiveTransfer empties the in-transit bin into the destination bins. Lines
+// that arrive with a different quantity than was dispatched get a discrepancy record.
+// Units of serialized products that didn't arrive are marked missing.
+func (uc *UseCase) ReceiveTransfer(ctx context.Context, req entity.TransferReceiveRequest, userID string) (entity.Transfer, error) {
+	err := uc.Tx.WithTx(ctx, func(ctx context.Context) error {
+		status, err := uc.TransferRepo.LockStatus(ctx, entity.Id{ID: req.TransferID})
+		if err != nil {
+			return err
+		}
+
+		if status != entity.TransferStatusInTransit {
+			return ErrInvalidStatusTransition
+		}
+
+		transfer, err := uc.TransferRepo.GetSingle(ctx, entity.Id{ID: req.TransferID})
+		if err != nil {
+			return err
+		}
+
+		received := make(map[string]entity.TransferReceiveLine, len(req.Lines))
+		for _, line := range req.Lines {
+			received[line.LineID] = line
+		}
+
+		movements := make([]entity.StockMovement, 0, 2*len(transfer.Lines))
+		for _, line := range transfer.Lines {
+			item, ok := received[line.ID]
+			if !ok {
+				item = entity.TransferReceiveLine{LineID: line.ID, Quantity: line.Quantity, Serials: line.Serials}
+			}
+			delete(received, line.ID)
+
+			quantity := item.Quantity
+
+			err = uc.receiveTransferSerials(ctx, transfer, line, item.Serials, quantity)
+			if err != nil {
+				return err
+			}
+
+			err = uc.TransferRepo.SetReceived(ctx, transfer.ID, line.ID, quantity)
+			if err != nil {
+				return err
+			}
+
+			if quantity != line.Quantity {
+				err = uc.TransferRepo.CreateDiscrepancy(ctx, entity.TransferDiscrepancy{
+					TransferID: transfer.ID,
+					LineID:     line.ID,
+					ProductID:  line.ProductID,
+					Dispatched: line.Quantity,
+					Received:   quantity,
+					Note:       req.Note,
+					CreatedBy:  userID,
+				})
+				if err != nil {
+					return err
+				}
+			}
+
+			movements = append(movements, transferMovement(transfer, line.ProductID, transfer.TransitBinID, line.LotID,
+				line.Serials, -line.Quantity, entity.MovementReasonTransferReceive, req.Note, userID))
+			if quantity > 0 {
+				movements = append(movements, transferMovement(transfer, line.ProductID, line.DestBinID, line.LotID,
+					item.Serials, quantity, entity.MovementReasonTransferReceive, req.Note, userID))
+			}
+		}
+
+		if len(received) > 0 {
+			return ErrUnknownLine
+		}
+
+		err = uc.createDocumentMovements(ctx, movements, transfer.DestWarehouseID)
+		if err != nil {
+			return err
+		}
+
+		return uc.TransferRepo.SetStatus(ctx, entity.Id{ID: transfer.ID}, entity.TransferStatusReceived, userID)
+	})
+	if err != nil {
+		return entity.Transfer{}, err
+	}
+
+	return uc.TransferRepo.GetSingle(ctx, entity.Id{ID: req.TransferID})
+}
+
+// CancelTransfer cancels a draft transfer, or sends dispatched goods back from
+// the in-transit bin to the bins they were taken from.
+func (uc *UseCase) CancelTransfer(ctx context.Context, id, userID string) (entity.Transfer, error) {
+	err := uc.Tx.WithTx(ctx, func(ctx context.Context) error {
+		status, err := uc.TransferRepo.LockStatus(ctx, entity.Id{ID: id})
+		if err != nil {
+			return err
+		}
+
+		switch status {
+		case entity.TransferStatusDraft:
+		case entity.TransferStatusInTransit:
+			transfer, err := uc.TransferRepo.GetSingle(ctx, entity.Id{ID: id})
+			if err != nil {
+				return err
+			}
+
+			out := make([]entity.StockMovement, 0, len(transfer.Lines))
+			back := make([]entity.StockMovement, 0, len(transfer.Lines))
+			for _, line := range transfer.Lines {
+				_, err = uc.SerialRepo.Move(ctx, entity.SerialMove{
+					ProductID:      line.ProductID,
+					Serials:        line.Serials,
+					FromStatus:     entity.SerialStatusInTransit,
+					FromBinID:      transfer.TransitBinID,
+					FromDocumentID: transfer.ID,
+					ToStatus:       entity.SerialStatusInStock,
+					ToBinID:        line.SourceBinID,
+				})
+				if err != nil {
+					return err
+				}
+
+				out = append(out, transferMovement(transfer, line.ProductID, transfer.TransitBinID, line.LotID,
+					line.Serials, -line.Quantity, entity.MovementReasonTransferCancel, "", userID))
+				back = append(back, transferMovement(transfer, line.ProductID, line.SourceBinID, line.LotID,
+					line.Serials, line.Quantity, entity.MovementReasonTransferCancel, "", userID))
+			}
+
+			err = uc.createDocumentMovements(ctx, out, transfer.DestWarehouseID)
+			if err != nil {
+				return err
+			}
+
+			err = uc.createDocumentMovements(ctx, back, transfer.SourceWarehouseID)
+			if err != nil {
+				return err
+			}
+		default:
+			return ErrInvalidStatusTransition
+		}
+
+		return uc.TransferRepo.SetStatus(ctx, entity.Id{ID: id}, entity.TransferStatusCancelled, userID)
+	})
+	if err != nil {
+		return entity.Transfer{}, err
+	}
+
+	return uc.TransferRepo.GetSingle(ctx, entity.Id{ID: id})
+}
+
+// pickTransferLots splits the lines that name no lot by the lots in their
+// source bin and stores the split lines on the transfer. Serialized lines are
+// split by the lots of their units, which are moved to the in-transit bin.
+func (uc *UseCase) pickTransferLots(ctx context.Context, transfer entity.Transfer, transitBinID string) ([]entity.TransferLine, error) {
+	var (
+		lines []entity.TransferLine
+		split bool
+	)
+
+	for _, line := range transfer.Lines {
+		serialized, err := uc.checkSerials(ctx, line.ProductID, line.Serials, line.Quantity)
+		if err != nil {
+			return nil, err
+		}
+
+		movement := entity.StockMovement{
+			ProductID: line.ProductID,
+			BinID:     line.SourceBinID,
+			LotID:     line.LotID,
+			Quantity:  -line.Quantity,
+		}
+
+		var picked []entity.StockMovement
+		if serialized {
+			serials, err := uc.SerialRepo.Move(ctx, entity.SerialMove{
+				ProductID:  line.ProductID,
+				Serials:    line.Serials,
+				FromStatus: entity.SerialStatusInStock,
+				FromBinID:  line.SourceBinID,
+				LotID:      line.LotID,
+				ToStatus:   entity.SerialStatusInTransit,
+				ToBinID:    transitBinID,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			picked = serialMovements(movement, serials)
+		} else {
+			picked, err = uc.pickLots(ctx, movement, transfer.SourceWarehouseID)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		for _, movement := range picked {
+			item := line
+			item.LotID = movement.LotID
+			item.Serials = movement.Serials
+			item.Quantity = -movement.Quantity
+			if item.Quantity != line.Quantity {
+				// the quantity in the line unit is derived again from the split
+				item.UnitQuantity = 0
+			}
+			lines = append(lines, item)
+
+			split = split || item.LotID != line.LotID || item.Quantity != line.Quantity
+		}
+	}
+
+	if !split {
+		return lines, nil
+	}
+
+	return lines, uc.TransferRepo.ReplaceLines(ctx, transfer.ID, lines)
+}
+
+// receiveTransferSerials puts the units of a serialized line that arrived into
+// its destination bin and marks the rest of the dispatched units missing.
+func (uc *UseCase) receiveTransferSerials(ctx context.Context, transfer entity.Transfer, line entity.TransferLine, serials []string, quantity float64) error {
+	serialized, err := uc.checkSerials(ctx, line.ProductID, serials, quantity)
+	if err != nil || !serialized {
+		return err
+	}
+
+	dispatched := make(map[string]bool, len(line.Serials))
+	for _, serial := range line.Serials {
+		dispatched[serial] = true
+	}
+
+	arrived := make(map[string]bool, len(serials))
+	for _, serial := range serials {
+		if !dispatched[serial] {
+			return ErrUnknownSerial
+		}
+		arrived[serial] = true
+	}
+
+	_, err = uc.SerialRepo.Move(ctx, entity.SerialMove{
+		ProductID:      line.ProductID,
+		Serials:        serials,
+		FromStatus:     entity.SerialStatusInTransit,
+		FromBinID:      transfer.TransitBinID,
+		FromDocumentID: transfer.ID,
+		ToStatus:       entity.SerialStatusInStock,
+		ToBinID:        line.DestBinID,
+	})
+	if err != nil {
+		return err
+	}
+
+	var missing []string
+	for _, serial := range line.Serials {
+		if !arrived[serial] {
+			missing = append(missing, serial)
+		}
+	}
+
+	_, err = uc.SerialRepo.Move(ctx, entity.SerialMove{
+		ProductID:      line.ProductID,
+		Serials:        missing,
+		FromStatus:     entity.SerialStatusInTransit,
+		FromBinID:      transfer.TransitBinID,
+		FromDocumentID: transfer.ID,
+		ToStatus:       entity.SerialStatusMissing,
+	})
+	return err
+}
+
+func transferMovement(transfer entity.Transfer, productID, binID, lotID string, serials []string, quantity float64,
+	reason, note, userID string) entity.StockMovement {
+	if note == "" {
+		note = transfer.Number
+	}
+
+	return entity.StockMovement{
+		ProductID:    productID,
+		BinID:        binID,
+		LotID:        lotID,
+		Serials:      serials,
+		Quantity:     quantity,
+		Reason:       reason,
+		DocumentType: entity.DocumentTypeTransfer,
+		DocumentID:   transfer.ID,
+		Note:         note,
+		UserID:       userID,
+	}
+}
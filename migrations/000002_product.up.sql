@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS products (
+    id          UUID PRIMARY KEY,
+    sku         VARCHAR(64)    NOT NULL UNIQUE,
+    name        VARCHAR(255)   NOT NULL,
+    description TEXT           NOT NULL DEFAULT '',
+    category    VARCHAR(255)   NOT NULL DEFAULT '',
+    base_unit   VARCHAR(16)    NOT NULL DEFAULT 'pcs',
+    sale_price  NUMERIC(18, 2) NOT NULL DEFAULT 0 CHECK (sale_price >= 0),
+    is_active   BOOLEAN        NOT NULL DEFAULT TRUE,
+    created_at  TIMESTAMP      NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMP      NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_products_category ON products (category);
+
+CREATE TABLE IF NOT EXISTS product_barcodes (
+    barcode    VARCHAR(64) PRIMARY KEY,
+    product_id UUID        NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    created_at TIMESTAMP   NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_barcodes_product_id ON product_barcodes (product_id);
@@ -0,0 +1,11 @@
+ALTER TABLE products ADD COLUMN IF NOT EXISTS category VARCHAR(255) NOT NULL DEFAULT '';
+
+UPDATE products SET category = categories.name
+FROM categories WHERE categories.id = products.category_id;
+
+DROP INDEX IF EXISTS idx_products_category_id;
+ALTER TABLE products DROP COLUMN IF EXISTS category_id;
+
+CREATE INDEX IF NOT EXISTS idx_products_category ON products (category);
+
+DROP TABLE IF EXISTS categories;
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS categories (
+    id         UUID PRIMARY KEY,
+    parent_id  UUID REFERENCES categories (id) ON DELETE RESTRICT,
+    name       VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP    NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP    NOT NULL DEFAULT NOW(),
+    CHECK (parent_id <> id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories (parent_id);
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS category_id UUID REFERENCES categories (id) ON DELETE SET NULL;
+
+-- free-text categories become root categories
+INSERT INTO categories (id, name)
+SELECT gen_random_uuid(), category FROM (SELECT DISTINCT category FROM products WHERE category <> '') c;
+
+UPDATE products SET category_id = categories.id
+FROM categories WHERE categories.name = products.category AND categories.parent_id IS NULL;
+
+DROP INDEX IF EXISTS idx_products_category;
+ALTER TABLE products DROP COLUMN IF EXISTS category;
+
+CREATE INDEX IF NOT EXISTS idx_products_category_id ON products (category_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS bins;
+DROP TABLE IF EXISTS zones;
+DROP TABLE IF EXISTS warehouses;
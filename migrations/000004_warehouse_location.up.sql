@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS warehouses (
+    id         UUID PRIMARY KEY,
+    code       VARCHAR(32)  NOT NULL UNIQUE,
+    name       VARCHAR(255) NOT NULL,
+    address    TEXT         NOT NULL DEFAULT '',
+    is_active  BOOLEAN      NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP    NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP    NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS zones (
+    id           UUID PRIMARY KEY,
+    warehouse_id UUID         NOT NULL REFERENCES warehouses (id) ON DELETE RESTRICT,
+    code         VARCHAR(32)  NOT NULL,
+    name         VARCHAR(255) NOT NULL DEFAULT '',
+    created_at   TIMESTAMP    NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMP    NOT NULL DEFAULT NOW(),
+    UNIQUE (warehouse_id, code)
+);
+
+CREATE TABLE IF NOT EXISTS bins (
+    id           UUID PRIMARY KEY,
+    warehouse_id UUID        NOT NULL REFERENCES warehouses (id) ON DELETE RESTRICT,
+    zone_id      UUID        NOT NULL REFERENCES zones (id) ON DELETE RESTRICT,
+    code         VARCHAR(64) NOT NULL,
+    description  TEXT        NOT NULL DEFAULT '',
+    is_active    BOOLEAN     NOT NULL DEFAULT TRUE,
+    created_at   TIMESTAMP   NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMP   NOT NULL DEFAULT NOW(),
+    UNIQUE (warehouse_id, code)
+);
+
+CREATE INDEX IF NOT EXISTS idx_bins_zone_id ON bins (zone_id);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS stock_balances;
+DROP TRIGGER IF EXISTS stock_movements_append_only ON stock_movements;
+DROP FUNCTION IF EXISTS stock_movements_append_only();
+DROP TABLE IF EXISTS stock_movements;
@@ -0,0 +1,40 @@
+CREATE TABLE IF NOT EXISTS stock_movements (
+    id            UUID PRIMARY KEY,
+    product_id    UUID           NOT NULL REFERENCES products (id) ON DELETE RESTRICT,
+    warehouse_id  UUID           NOT NULL REFERENCES warehouses (id) ON DELETE RESTRICT,
+    bin_id        UUID           NOT NULL REFERENCES bins (id) ON DELETE RESTRICT,
+    quantity      NUMERIC(18, 3) NOT NULL CHECK (quantity <> 0),
+    reason        VARCHAR(32)    NOT NULL,
+    document_type VARCHAR(32)    NOT NULL DEFAULT '',
+    document_id   UUID,
+    note          TEXT           NOT NULL DEFAULT '',
+    user_id       UUID,
+    created_at    TIMESTAMP      NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_movements_product_bin ON stock_movements (product_id, bin_id);
+CREATE INDEX IF NOT EXISTS idx_stock_movements_document ON stock_movements (document_id);
+CREATE INDEX IF NOT EXISTS idx_stock_movements_created_at ON stock_movements (created_at);
+
+-- the ledger is the audit trail, corrections are new movements
+CREATE OR REPLACE FUNCTION stock_movements_append_only() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'stock_movements is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER stock_movements_append_only
+    BEFORE UPDATE OR DELETE ON stock_movements
+    FOR EACH ROW EXECUTE FUNCTION stock_movements_append_only();
+
+CREATE TABLE IF NOT EXISTS stock_balances (
+    product_id   UUID           NOT NULL REFERENCES products (id) ON DELETE RESTRICT,
+    bin_id       UUID           NOT NULL REFERENCES bins (id) ON DELETE RESTRICT,
+    warehouse_id UUID           NOT NULL REFERENCES warehouses (id) ON DELETE RESTRICT,
+    quantity     NUMERIC(18, 3) NOT NULL DEFAULT 0,
+    updated_at   TIMESTAMP      NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (product_id, bin_id),
+    CONSTRAINT stock_balances_quantity_check CHECK (quantity >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_balances_warehouse_id ON stock_balances (warehouse_id);
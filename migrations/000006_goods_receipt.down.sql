@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS goods_receipt_lines;
+DROP TABLE IF EXISTS goods_receipts;
+DROP SEQUENCE IF EXISTS goods_receipt_number_seq;
@@ -0,0 +1,30 @@
+CREATE SEQUENCE IF NOT EXISTS goods_receipt_number_seq;
+
+CREATE TABLE IF NOT EXISTS goods_receipts (
+    id           UUID PRIMARY KEY,
+    number       VARCHAR(32) NOT NULL UNIQUE,
+    supplier_id  UUID,
+    warehouse_id UUID        NOT NULL REFERENCES warehouses (id) ON DELETE RESTRICT,
+    receipt_date DATE        NOT NULL DEFAULT CURRENT_DATE,
+    status       VARCHAR(16) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'posted', 'cancelled')),
+    note         TEXT        NOT NULL DEFAULT '',
+    created_by   UUID,
+    posted_at    TIMESTAMP,
+    cancelled_at TIMESTAMP,
+    created_at   TIMESTAMP   NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMP   NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_goods_receipts_supplier_id ON goods_receipts (supplier_id);
+CREATE INDEX IF NOT EXISTS idx_goods_receipts_warehouse_id ON goods_receipts (warehouse_id);
+
+CREATE TABLE IF NOT EXISTS goods_receipt_lines (
+    id         UUID PRIMARY KEY,
+    receipt_id UUID           NOT NULL REFERENCES goods_receipts (id) ON DELETE CASCADE,
+    line_no    INT            NOT NULL,
+    product_id UUID           NOT NULL REFERENCES products (id) ON DELETE RESTRICT,
+    bin_id     UUID           NOT NULL REFERENCES bins (id) ON DELETE RESTRICT,
+    quantity   NUMERIC(18, 3) NOT NULL CHECK (quantity > 0),
+    unit_cost  NUMERIC(18, 2) NOT NULL DEFAULT 0 CHECK (unit_cost >= 0),
+    UNIQUE (receipt_id, line_no)
+);
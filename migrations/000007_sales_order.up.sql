@@ -0,0 +1,32 @@
+CREATE SEQUENCE IF NOT EXISTS sales_order_number_seq;
+
+CREATE TABLE IF NOT EXISTS sales_orders (
+    id            UUID PRIMARY KEY,
+    number        VARCHAR(32)  NOT NULL UNIQUE,
+    customer_name VARCHAR(255) NOT NULL DEFAULT '',
+    warehouse_id  UUID         NOT NULL REFERENCES warehouses (id) ON DELETE RESTRICT,
+    order_date    DATE         NOT NULL DEFAULT CURRENT_DATE,
+    status        VARCHAR(16)  NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'processing', 'completed', 'cancelled')),
+    note          TEXT         NOT NULL DEFAULT '',
+    created_by    UUID,
+    created_at    TIMESTAMP    NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMP    NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_sales_orders_warehouse_id ON sales_orders (warehouse_id);
+CREATE INDEX IF NOT EXISTS idx_sales_orders_status ON sales_orders (status);
+
+CREATE TABLE IF NOT EXISTS sales_order_lines (
+    id         UUID PRIMARY KEY,
+    order_id   UUID           NOT NULL REFERENCES sales_orders (id) ON DELETE CASCADE,
+    line_no    INT            NOT NULL,
+    product_id UUID           NOT NULL REFERENCES products (id) ON DELETE RESTRICT,
+    bin_id     UUID           NOT NULL REFERENCES bins (id) ON DELETE RESTRICT,
+    quantity   NUMERIC(18, 3) NOT NULL CHECK (quantity > 0),
+    shipped    NUMERIC(18, 3) NOT NULL DEFAULT 0,
+    returned   NUMERIC(18, 3) NOT NULL DEFAULT 0,
+    price      NUMERIC(18, 2) NOT NULL DEFAULT 0 CHECK (price >= 0),
+    UNIQUE (order_id, line_no),
+    CONSTRAINT sales_order_lines_shipped_check CHECK (shipped >= 0 AND shipped <= quantity),
+    CONSTRAINT sales_order_lines_returned_check CHECK (returned >= 0 AND returned <= shipped)
+);
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS supplier_payments;
+DROP TABLE IF EXISTS supplier_credit_lines;
+DROP TABLE IF EXISTS supplier_credits;
+DROP SEQUENCE IF EXISTS supplier_credit_number_seq;
+ALTER TABLE goods_receipts DROP CONSTRAINT IF EXISTS goods_receipts_supplier_id_fkey;
+DROP TABLE IF EXISTS suppliers;
@@ -0,0 +1,57 @@
+CREATE TABLE IF NOT EXISTS suppliers (
+    id             UUID PRIMARY KEY,
+    name           VARCHAR(255) NOT NULL,
+    contact_person VARCHAR(255) NOT NULL DEFAULT '',
+    phone          VARCHAR(32)  NOT NULL DEFAULT '',
+    email          VARCHAR(255) NOT NULL DEFAULT '',
+    address        TEXT         NOT NULL DEFAULT '',
+    tax_id         VARCHAR(32)  NOT NULL DEFAULT '',
+    note           TEXT         NOT NULL DEFAULT '',
+    is_active      BOOLEAN      NOT NULL DEFAULT TRUE,
+    created_at     TIMESTAMP    NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMP    NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE goods_receipts
+    ADD CONSTRAINT goods_receipts_supplier_id_fkey FOREIGN KEY (supplier_id) REFERENCES suppliers (id) ON DELETE RESTRICT;
+
+CREATE SEQUENCE IF NOT EXISTS supplier_credit_number_seq;
+
+CREATE TABLE IF NOT EXISTS supplier_credits (
+    id               UUID PRIMARY KEY,
+    number           VARCHAR(32) NOT NULL UNIQUE,
+    supplier_id      UUID        NOT NULL REFERENCES suppliers (id) ON DELETE RESTRICT,
+    goods_receipt_id UUID REFERENCES goods_receipts (id) ON DELETE SET NULL,
+    credit_date      DATE        NOT NULL DEFAULT CURRENT_DATE,
+    due_date         DATE,
+    note             TEXT        NOT NULL DEFAULT '',
+    created_by       UUID,
+    created_at       TIMESTAMP   NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMP   NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_supplier_credits_supplier_id ON supplier_credits (supplier_id);
+
+CREATE TABLE IF NOT EXISTS supplier_credit_lines (
+    id         UUID PRIMARY KEY,
+    credit_id  UUID           NOT NULL REFERENCES supplier_credits (id) ON DELETE CASCADE,
+    line_no    INT            NOT NULL,
+    product_id UUID           NOT NULL REFERENCES products (id) ON DELETE RESTRICT,
+    quantity   NUMERIC(18, 3) NOT NULL CHECK (quantity > 0),
+    price      NUMERIC(18, 2) NOT NULL CHECK (price >= 0),
+    UNIQUE (credit_id, line_no)
+);
+
+CREATE TABLE IF NOT EXISTS supplier_payments (
+    id          UUID PRIMARY KEY,
+    supplier_id UUID           NOT NULL REFERENCES suppliers (id) ON DELETE RESTRICT,
+    credit_id   UUID           NOT NULL REFERENCES supplier_credits (id) ON DELETE RESTRICT,
+    amount      NUMERIC(18, 2) NOT NULL CHECK (amount > 0),
+    paid_date   DATE           NOT NULL DEFAULT CURRENT_DATE,
+    note        TEXT           NOT NULL DEFAULT '',
+    created_by  UUID,
+    created_at  TIMESTAMP      NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_supplier_payments_supplier_id ON supplier_payments (supplier_id);
+CREATE INDEX IF NOT EXISTS idx_supplier_payments_credit_id ON supplier_payments (credit_id);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS customer_payments;
+ALTER TABLE sales_orders
+    DROP COLUMN IF EXISTS completed_at,
+    DROP COLUMN IF EXISTS customer_id;
+DROP TABLE IF EXISTS customers;
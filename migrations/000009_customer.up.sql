@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS customers (
+    id         UUID PRIMARY KEY,
+    name       VARCHAR(255) NOT NULL,
+    phone      VARCHAR(32)  NOT NULL DEFAULT '',
+    email      VARCHAR(255) NOT NULL DEFAULT '',
+    address    TEXT         NOT NULL DEFAULT '',
+    tax_id     VARCHAR(32)  NOT NULL DEFAULT '',
+    note       TEXT         NOT NULL DEFAULT '',
+    is_active  BOOLEAN      NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP    NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP    NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE sales_orders
+    ADD COLUMN IF NOT EXISTS customer_id  UUID REFERENCES customers (id) ON DELETE RESTRICT,
+    ADD COLUMN IF NOT EXISTS completed_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_sales_orders_customer_id ON sales_orders (customer_id);
+
+CREATE TABLE IF NOT EXISTS customer_payments (
+    id          UUID PRIMARY KEY,
+    customer_id UUID REFERENCES customers (id) ON DELETE RESTRICT,
+    order_id    UUID           NOT NULL REFERENCES sales_orders (id) ON DELETE RESTRICT,
+    amount      NUMERIC(18, 2) NOT NULL CHECK (amount > 0),
+    paid_date   DATE           NOT NULL DEFAULT CURRENT_DATE,
+    note        TEXT           NOT NULL DEFAULT '',
+    created_by  UUID,
+    created_at  TIMESTAMP      NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_customer_payments_customer_id ON customer_payments (customer_id);
+CREATE INDEX IF NOT EXISTS idx_customer_payments_order_id ON customer_payments (order_id);
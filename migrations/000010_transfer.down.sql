@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS transfer_discrepancies;
+DROP TABLE IF EXISTS transfer_lines;
+DROP TABLE IF EXISTS transfers;
+DROP SEQUENCE IF EXISTS transfer_number_seq;
+DROP INDEX IF EXISTS bins_virtual_warehouse_key;
+ALTER TABLE bins DROP COLUMN IF EXISTS is_virtual;
@@ -0,0 +1,54 @@
+ALTER TABLE bins ADD COLUMN IF NOT EXISTS is_virtual BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- one in-transit bin per warehouse
+CREATE UNIQUE INDEX IF NOT EXISTS bins_virtual_warehouse_key ON bins (warehouse_id) WHERE is_virtual;
+
+CREATE SEQUENCE IF NOT EXISTS transfer_number_seq;
+
+CREATE TABLE IF NOT EXISTS transfers (
+    id                  UUID PRIMARY KEY,
+    number              VARCHAR(32) NOT NULL UNIQUE,
+    source_warehouse_id UUID        NOT NULL REFERENCES warehouses (id) ON DELETE RESTRICT,
+    dest_warehouse_id   UUID        NOT NULL REFERENCES warehouses (id) ON DELETE RESTRICT,
+    transit_bin_id      UUID REFERENCES bins (id) ON DELETE RESTRICT,
+    status              VARCHAR(16) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'in_transit', 'received', 'cancelled')),
+    note                TEXT        NOT NULL DEFAULT '',
+    created_by          UUID,
+    dispatched_by       UUID,
+    dispatched_at       TIMESTAMP,
+    received_by         UUID,
+    received_at         TIMESTAMP,
+    cancelled_at        TIMESTAMP,
+    created_at          TIMESTAMP   NOT NULL DEFAULT NOW(),
+    updated_at          TIMESTAMP   NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_transfers_source_warehouse_id ON transfers (source_warehouse_id);
+CREATE INDEX IF NOT EXISTS idx_transfers_dest_warehouse_id ON transfers (dest_warehouse_id);
+
+CREATE TABLE IF NOT EXISTS transfer_lines (
+    id                UUID PRIMARY KEY,
+    transfer_id       UUID           NOT NULL REFERENCES transfers (id) ON DELETE CASCADE,
+    line_no           INT            NOT NULL,
+    product_id        UUID           NOT NULL REFERENCES products (id) ON DELETE RESTRICT,
+    source_bin_id     UUID           NOT NULL REFERENCES bins (id) ON DELETE RESTRICT,
+    dest_bin_id       UUID           NOT NULL REFERENCES bins (id) ON DELETE RESTRICT,
+    quantity          NUMERIC(18, 3) NOT NULL CHECK (quantity > 0),
+    received_quantity NUMERIC(18, 3) CHECK (received_quantity >= 0),
+    UNIQUE (transfer_id, line_no)
+);
+
+CREATE TABLE IF NOT EXISTS transfer_discrepancies (
+    id          UUID PRIMARY KEY,
+    transfer_id UUID           NOT NULL REFERENCES transfers (id) ON DELETE CASCADE,
+    line_id     UUID           NOT NULL REFERENCES transfer_lines (id) ON DELETE CASCADE,
+    product_id  UUID           NOT NULL REFERENCES products (id) ON DELETE RESTRICT,
+    dispatched  NUMERIC(18, 3) NOT NULL,
+    received    NUMERIC(18, 3) NOT NULL,
+    difference  NUMERIC(18, 3) NOT NULL, -- received - dispatched, negative when goods went missing
+    note        TEXT           NOT NULL DEFAULT '',
+    created_by  UUID,
+    created_at  TIMESTAMP      NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_transfer_discrepancies_transfer_id ON transfer_discrepancies (transfer_id);
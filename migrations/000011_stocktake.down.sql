@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS stocktake_counts;
+DROP TABLE IF EXISTS stocktake_lines;
+DROP TABLE IF EXISTS stocktakes;
+DROP SEQUENCE IF EXISTS stocktake_number_seq;
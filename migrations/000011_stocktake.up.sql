@@ -0,0 +1,41 @@
+CREATE SEQUENCE IF NOT EXISTS stocktake_number_seq;
+
+CREATE TABLE IF NOT EXISTS stocktakes (
+    id           UUID PRIMARY KEY,
+    number       VARCHAR(32) NOT NULL UNIQUE,
+    warehouse_id UUID        NOT NULL REFERENCES warehouses (id) ON DELETE RESTRICT,
+    zone_id      UUID REFERENCES zones (id) ON DELETE RESTRICT,
+    category_id  UUID REFERENCES categories (id) ON DELETE RESTRICT,
+    status       VARCHAR(16) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'approved', 'cancelled')),
+    note         TEXT        NOT NULL DEFAULT '',
+    created_by   UUID,
+    approved_by  UUID,
+    approved_at  TIMESTAMP,
+    created_at   TIMESTAMP   NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMP   NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_stocktakes_warehouse_id ON stocktakes (warehouse_id);
+
+-- expected quantities as they were when the session was opened
+CREATE TABLE IF NOT EXISTS stocktake_lines (
+    id           UUID PRIMARY KEY,
+    stocktake_id UUID           NOT NULL REFERENCES stocktakes (id) ON DELETE CASCADE,
+    product_id   UUID           NOT NULL REFERENCES products (id) ON DELETE RESTRICT,
+    bin_id       UUID           NOT NULL REFERENCES bins (id) ON DELETE RESTRICT,
+    expected     NUMERIC(18, 3) NOT NULL,
+    UNIQUE (stocktake_id, product_id, bin_id)
+);
+
+CREATE TABLE IF NOT EXISTS stocktake_counts (
+    id           UUID PRIMARY KEY,
+    stocktake_id UUID           NOT NULL REFERENCES stocktakes (id) ON DELETE CASCADE,
+    product_id   UUID           NOT NULL REFERENCES products (id) ON DELETE RESTRICT,
+    bin_id       UUID           NOT NULL REFERENCES bins (id) ON DELETE RESTRICT,
+    quantity     NUMERIC(18, 3) NOT NULL CHECK (quantity >= 0),
+    counted_by   UUID,
+    -- clock_timestamp keeps counts submitted in one request in order
+    created_at   TIMESTAMP      NOT NULL DEFAULT clock_timestamp()
+);
+
+CREATE INDEX IF NOT EXISTS idx_stocktake_counts_stocktake_id ON stocktake_counts (stocktake_id, product_id, bin_id);
@@ -0,0 +1,29 @@
+ALTER TABLE stocktake_counts DROP COLUMN IF EXISTS lot_id;
+
+DROP INDEX IF EXISTS stocktake_lines_product_bin_lot_key;
+ALTER TABLE stocktake_lines DROP COLUMN IF EXISTS lot_id;
+ALTER TABLE stocktake_lines ADD CONSTRAINT stocktake_lines_stocktake_id_product_id_bin_id_key UNIQUE (stocktake_id, product_id, bin_id);
+
+ALTER TABLE transfer_lines DROP COLUMN IF EXISTS lot_id;
+
+ALTER TABLE sales_order_lines DROP COLUMN IF EXISTS lot_id;
+
+ALTER TABLE goods_receipt_lines DROP COLUMN IF EXISTS expiry_date;
+ALTER TABLE goods_receipt_lines DROP COLUMN IF EXISTS lot_number;
+
+-- lot balances are merged back into one row per product and bin
+DROP INDEX IF EXISTS idx_stock_balances_lot_id;
+DROP INDEX IF EXISTS stock_balances_product_bin_lot_key;
+CREATE TEMPORARY TABLE merged_balances AS
+SELECT product_id, bin_id, warehouse_id, SUM(quantity) AS quantity, MAX(updated_at) AS updated_at
+FROM stock_balances GROUP BY product_id, bin_id, warehouse_id;
+DELETE FROM stock_balances;
+ALTER TABLE stock_balances DROP COLUMN IF EXISTS lot_id;
+INSERT INTO stock_balances (product_id, bin_id, warehouse_id, quantity, updated_at)
+SELECT product_id, bin_id, warehouse_id, quantity, updated_at FROM merged_balances;
+DROP TABLE merged_balances;
+ALTER TABLE stock_balances ADD PRIMARY KEY (product_id, bin_id);
+
+ALTER TABLE stock_movements DROP COLUMN IF EXISTS lot_id;
+
+DROP TABLE IF EXISTS lots;
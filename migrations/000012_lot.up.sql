@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS lots (
+    id          UUID PRIMARY KEY,
+    product_id  UUID        NOT NULL REFERENCES products (id) ON DELETE RESTRICT,
+    lot_number  VARCHAR(64) NOT NULL,
+    expiry_date DATE,
+    created_at  TIMESTAMP   NOT NULL DEFAULT NOW(),
+    UNIQUE (product_id, lot_number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_lots_expiry_date ON lots (expiry_date);
+
+ALTER TABLE stock_movements ADD COLUMN IF NOT EXISTS lot_id UUID REFERENCES lots (id) ON DELETE RESTRICT;
+
+-- balances are kept per product, bin and lot; stock without a lot has a NULL
+-- lot_id, which the unique index folds into the nil UUID
+ALTER TABLE stock_balances ADD COLUMN IF NOT EXISTS lot_id UUID REFERENCES lots (id) ON DELETE RESTRICT;
+ALTER TABLE stock_balances DROP CONSTRAINT IF EXISTS stock_balances_pkey;
+CREATE UNIQUE INDEX IF NOT EXISTS stock_balances_product_bin_lot_key
+    ON stock_balances (product_id, bin_id, COALESCE(lot_id, '00000000-0000-0000-0000-000000000000'::UUID));
+CREATE INDEX IF NOT EXISTS idx_stock_balances_lot_id ON stock_balances (lot_id);
+
+ALTER TABLE goods_receipt_lines ADD COLUMN IF NOT EXISTS lot_number VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE goods_receipt_lines ADD COLUMN IF NOT EXISTS expiry_date DATE;
+
+ALTER TABLE sales_order_lines ADD COLUMN IF NOT EXISTS lot_id UUID REFERENCES lots (id) ON DELETE RESTRICT;
+
+ALTER TABLE transfer_lines ADD COLUMN IF NOT EXISTS lot_id UUID REFERENCES lots (id) ON DELETE RESTRICT;
+
+ALTER TABLE stocktake_lines ADD COLUMN IF NOT EXISTS lot_id UUID REFERENCES lots (id) ON DELETE RESTRICT;
+ALTER TABLE stocktake_lines DROP CONSTRAINT IF EXISTS stocktake_lines_stocktake_id_product_id_bin_id_key;
+CREATE UNIQUE INDEX IF NOT EXISTS stocktake_lines_product_bin_lot_key
+    ON stocktake_lines (stocktake_id, product_id, bin_id, COALESCE(lot_id, '00000000-0000-0000-0000-000000000000'::UUID));
+
+ALTER TABLE stocktake_counts ADD COLUMN IF NOT EXISTS lot_id UUID REFERENCES lots (id) ON DELETE RESTRICT;
@@ -0,0 +1,8 @@
+ALTER TABLE transfer_lines DROP COLUMN IF EXISTS serials;
+ALTER TABLE sales_order_lines DROP COLUMN IF EXISTS serials;
+ALTER TABLE goods_receipt_lines DROP COLUMN IF EXISTS serials;
+
+DROP TABLE IF EXISTS serial_movements;
+DROP TABLE IF EXISTS serials;
+
+ALTER TABLE products DROP COLUMN IF EXISTS is_serialized;
@@ -0,0 +1,29 @@
+ALTER TABLE products ADD COLUMN IF NOT EXISTS is_serialized BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- one row per unit, bin_id is where the unit is now and NULL once it left stock
+CREATE TABLE IF NOT EXISTS serials (
+    id            UUID PRIMARY KEY,
+    product_id    UUID         NOT NULL REFERENCES products (id) ON DELETE RESTRICT,
+    serial_number VARCHAR(128) NOT NULL UNIQUE,
+    status        VARCHAR(16)  NOT NULL CHECK (status IN ('in_stock', 'in_transit', 'sold', 'removed', 'missing')),
+    bin_id        UUID REFERENCES bins (id) ON DELETE RESTRICT,
+    lot_id        UUID REFERENCES lots (id) ON DELETE RESTRICT,
+    created_at    TIMESTAMP    NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMP    NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_serials_product_id ON serials (product_id);
+CREATE INDEX IF NOT EXISTS idx_serials_bin_id ON serials (bin_id);
+
+-- links every unit to the ledger movements that carried it
+CREATE TABLE IF NOT EXISTS serial_movements (
+    serial_id   UUID NOT NULL REFERENCES serials (id) ON DELETE RESTRICT,
+    movement_id UUID NOT NULL REFERENCES stock_movements (id) ON DELETE RESTRICT,
+    PRIMARY KEY (serial_id, movement_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_serial_movements_movement_id ON serial_movements (movement_id);
+
+ALTER TABLE goods_receipt_lines ADD COLUMN IF NOT EXISTS serials TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE sales_order_lines ADD COLUMN IF NOT EXISTS serials TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE transfer_lines ADD COLUMN IF NOT EXISTS serials TEXT[] NOT NULL DEFAULT '{}';
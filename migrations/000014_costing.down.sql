@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS movement_costs;
+DROP TABLE IF EXISTS cost_layers;
+DROP TABLE IF EXISTS company_settings;
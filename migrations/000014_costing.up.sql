@@ -0,0 +1,55 @@
+-- single row holding company wide settings
+CREATE TABLE IF NOT EXISTS company_settings (
+    id             BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    costing_method VARCHAR(16) NOT NULL DEFAULT 'fifo' CHECK (costing_method IN ('fifo', 'average')),
+    updated_at     TIMESTAMP   NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO company_settings (id) VALUES (TRUE) ON CONFLICT DO NOTHING;
+
+-- open stock value per product and warehouse, FIFO keeps one layer per inbound
+-- movement, the weighted average keeps a single layer
+CREATE TABLE IF NOT EXISTS cost_layers (
+    id           UUID PRIMARY KEY,
+    product_id   UUID           NOT NULL REFERENCES products (id) ON DELETE RESTRICT,
+    warehouse_id UUID           NOT NULL REFERENCES warehouses (id) ON DELETE RESTRICT,
+    movement_id  UUID REFERENCES stock_movements (id) ON DELETE RESTRICT,
+    quantity     NUMERIC(18, 3) NOT NULL,
+    remaining    NUMERIC(18, 3) NOT NULL CHECK (remaining >= 0),
+    unit_cost    NUMERIC(18, 4) NOT NULL,
+    created_at   TIMESTAMP      NOT NULL DEFAULT clock_timestamp()
+);
+
+CREATE INDEX IF NOT EXISTS idx_cost_layers_open ON cost_layers (product_id, warehouse_id, created_at) WHERE remaining > 0;
+
+-- the ledger is append-only, so the value of every movement is kept beside it;
+-- cost is signed like the quantity
+CREATE TABLE IF NOT EXISTS movement_costs (
+    movement_id UUID PRIMARY KEY REFERENCES stock_movements (id) ON DELETE RESTRICT,
+    unit_cost   NUMERIC(18, 4) NOT NULL,
+    cost        NUMERIC(18, 2) NOT NULL
+);
+
+-- existing stock is valued at the average cost of its posted receipts
+CREATE TEMPORARY TABLE opening_costs AS
+SELECT goods_receipt_lines.product_id,
+    SUM(goods_receipt_lines.quantity * goods_receipt_lines.unit_cost) / SUM(goods_receipt_lines.quantity) AS unit_cost
+FROM goods_receipt_lines
+JOIN goods_receipts ON goods_receipts.id = goods_receipt_lines.receipt_id
+WHERE goods_receipts.status = 'posted'
+GROUP BY goods_receipt_lines.product_id;
+
+INSERT INTO movement_costs (movement_id, unit_cost, cost)
+SELECT stock_movements.id, COALESCE(opening_costs.unit_cost, 0),
+    ROUND(stock_movements.quantity * COALESCE(opening_costs.unit_cost, 0), 2)
+FROM stock_movements
+LEFT JOIN opening_costs ON opening_costs.product_id = stock_movements.product_id;
+
+INSERT INTO cost_layers (id, product_id, warehouse_id, quantity, remaining, unit_cost)
+SELECT gen_random_uuid(), balances.product_id, balances.warehouse_id, balances.quantity, balances.quantity,
+    COALESCE(opening_costs.unit_cost, 0)
+FROM (SELECT product_id, warehouse_id, SUM(quantity) AS quantity FROM stock_balances GROUP BY product_id, warehouse_id) balances
+LEFT JOIN opening_costs ON opening_costs.product_id = balances.product_id
+WHERE balances.quantity > 0;
+
+DROP TABLE opening_costs;
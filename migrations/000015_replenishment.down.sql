@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS purchase_order_lines;
+DROP TABLE IF EXISTS purchase_orders;
+DROP SEQUENCE IF EXISTS purchase_order_number_seq;
+DROP TABLE IF EXISTS stock_levels;
+
+ALTER TABLE products DROP COLUMN IF EXISTS preferred_supplier_id;
@@ -0,0 +1,46 @@
+ALTER TABLE products ADD COLUMN IF NOT EXISTS preferred_supplier_id UUID REFERENCES suppliers (id) ON DELETE SET NULL;
+
+CREATE TABLE IF NOT EXISTS stock_levels (
+    product_id    UUID           NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    warehouse_id  UUID           NOT NULL REFERENCES warehouses (id) ON DELETE CASCADE,
+    min_quantity  NUMERIC(18, 3) NOT NULL DEFAULT 0 CHECK (min_quantity >= 0),
+    max_quantity  NUMERIC(18, 3) NOT NULL DEFAULT 0,
+    reorder_point NUMERIC(18, 3) NOT NULL DEFAULT 0 CHECK (reorder_point >= 0),
+    updated_at    TIMESTAMP      NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (product_id, warehouse_id),
+    CHECK (max_quantity >= min_quantity AND max_quantity >= reorder_point)
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_levels_warehouse_id ON stock_levels (warehouse_id);
+
+CREATE SEQUENCE IF NOT EXISTS purchase_order_number_seq;
+
+CREATE TABLE IF NOT EXISTS purchase_orders (
+    id            UUID PRIMARY KEY,
+    number        VARCHAR(32) NOT NULL UNIQUE,
+    supplier_id   UUID        NOT NULL REFERENCES suppliers (id) ON DELETE RESTRICT,
+    warehouse_id  UUID        NOT NULL REFERENCES warehouses (id) ON DELETE RESTRICT,
+    order_date    DATE        NOT NULL DEFAULT CURRENT_DATE,
+    expected_date DATE,
+    status        VARCHAR(24) NOT NULL DEFAULT 'draft',
+    note          TEXT        NOT NULL DEFAULT '',
+    created_by    UUID,
+    created_at    TIMESTAMP   NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMP   NOT NULL DEFAULT NOW(),
+    CONSTRAINT purchase_orders_status_check CHECK (status IN ('draft'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_purchase_orders_supplier_id ON purchase_orders (supplier_id);
+CREATE INDEX IF NOT EXISTS idx_purchase_orders_warehouse_id ON purchase_orders (warehouse_id);
+
+CREATE TABLE IF NOT EXISTS purchase_order_lines (
+    id         UUID PRIMARY KEY,
+    order_id   UUID           NOT NULL REFERENCES purchase_orders (id) ON DELETE CASCADE,
+    line_no    INT            NOT NULL,
+    product_id UUID           NOT NULL REFERENCES products (id) ON DELETE RESTRICT,
+    quantity   NUMERIC(18, 3) NOT NULL CHECK (quantity > 0),
+    price      NUMERIC(18, 2) NOT NULL DEFAULT 0 CHECK (price >= 0),
+    UNIQUE (order_id, line_no)
+);
+
+CREATE INDEX IF NOT EXISTS idx_purchase_order_lines_product_id ON purchase_order_lines (product_id);
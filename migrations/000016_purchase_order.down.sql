@@ -0,0 +1,14 @@
+ALTER TABLE supplier_credits DROP COLUMN IF EXISTS match_status;
+
+ALTER TABLE goods_receipt_lines DROP COLUMN IF EXISTS purchase_order_line_id;
+ALTER TABLE goods_receipts DROP COLUMN IF EXISTS purchase_order_id;
+
+ALTER TABLE purchase_order_lines DROP COLUMN IF EXISTS received;
+
+DROP INDEX IF EXISTS idx_purchase_orders_status;
+
+ALTER TABLE purchase_orders DROP COLUMN IF EXISTS closed_at;
+ALTER TABLE purchase_orders DROP COLUMN IF EXISTS sent_at;
+ALTER TABLE purchase_orders DROP CONSTRAINT IF EXISTS purchase_orders_status_check;
+UPDATE purchase_orders SET status = 'draft' WHERE status <> 'draft';
+ALTER TABLE purchase_orders ADD CONSTRAINT purchase_orders_status_check CHECK (status IN ('draft'));
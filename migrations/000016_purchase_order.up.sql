@@ -0,0 +1,18 @@
+ALTER TABLE purchase_orders DROP CONSTRAINT IF EXISTS purchase_orders_status_check;
+ALTER TABLE purchase_orders ADD CONSTRAINT purchase_orders_status_check
+    CHECK (status IN ('draft', 'sent', 'partially_received', 'received', 'closed'));
+ALTER TABLE purchase_orders ADD COLUMN IF NOT EXISTS sent_at TIMESTAMP;
+ALTER TABLE purchase_orders ADD COLUMN IF NOT EXISTS closed_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_purchase_orders_status ON purchase_orders (status);
+
+ALTER TABLE purchase_order_lines ADD COLUMN IF NOT EXISTS received NUMERIC(18, 3) NOT NULL DEFAULT 0 CHECK (received >= 0);
+
+ALTER TABLE goods_receipts ADD COLUMN IF NOT EXISTS purchase_order_id UUID REFERENCES purchase_orders (id) ON DELETE RESTRICT;
+ALTER TABLE goods_receipt_lines ADD COLUMN IF NOT EXISTS purchase_order_line_id UUID REFERENCES purchase_order_lines (id) ON DELETE RESTRICT;
+
+CREATE INDEX IF NOT EXISTS idx_goods_receipts_purchase_order_id ON goods_receipts (purchase_order_id);
+
+-- outcome of matching the credit against its receipt and purchase order
+ALTER TABLE supplier_credits ADD COLUMN IF NOT EXISTS match_status VARCHAR(16) NOT NULL DEFAULT 'none'
+    CHECK (match_status IN ('none', 'matched', 'mismatch'));
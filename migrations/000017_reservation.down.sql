@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS stock_reservations;
+
+ALTER TABLE company_settings DROP COLUMN IF EXISTS reservation_hours;
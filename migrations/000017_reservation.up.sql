@@ -0,0 +1,26 @@
+-- hours after which reservations lapse, 0 keeps them until the order is done
+ALTER TABLE company_settings ADD COLUMN IF NOT EXISTS reservation_hours INT NOT NULL DEFAULT 0 CHECK (reservation_hours >= 0);
+
+-- stock promised to processing sales orders, one row per order line
+CREATE TABLE IF NOT EXISTS stock_reservations (
+    id           UUID PRIMARY KEY,
+    order_id     UUID           NOT NULL REFERENCES sales_orders (id) ON DELETE CASCADE,
+    line_id      UUID           NOT NULL UNIQUE REFERENCES sales_order_lines (id) ON DELETE CASCADE,
+    product_id   UUID           NOT NULL REFERENCES products (id) ON DELETE RESTRICT,
+    warehouse_id UUID           NOT NULL REFERENCES warehouses (id) ON DELETE RESTRICT,
+    quantity     NUMERIC(18, 3) NOT NULL CHECK (quantity > 0),
+    expires_at   TIMESTAMP,
+    created_at   TIMESTAMP      NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_reservations_order_id ON stock_reservations (order_id);
+CREATE INDEX IF NOT EXISTS idx_stock_reservations_product_warehouse ON stock_reservations (product_id, warehouse_id);
+
+-- orders already processing hold their unshipped quantities
+INSERT INTO stock_reservations (id, order_id, line_id, product_id, warehouse_id, quantity)
+SELECT gen_random_uuid(), sales_orders.id, sales_order_lines.id, sales_order_lines.product_id, sales_orders.warehouse_id,
+       sales_order_lines.quantity - sales_order_lines.shipped
+FROM sales_order_lines
+JOIN sales_orders ON sales_orders.id = sales_order_lines.order_id
+WHERE sales_orders.status = 'processing' AND sales_order_lines.quantity > sales_order_lines.shipped
+ON CONFLICT (line_id) DO NOTHING;
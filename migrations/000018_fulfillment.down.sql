@@ -0,0 +1,15 @@
+DROP TABLE IF EXISTS parcels;
+DROP TABLE IF EXISTS shipments;
+DROP SEQUENCE IF EXISTS shipment_number_seq;
+DROP TABLE IF EXISTS pick_list_lines;
+DROP TABLE IF EXISTS pick_lists;
+DROP SEQUENCE IF EXISTS pick_list_number_seq;
+
+ALTER TABLE sales_order_lines DROP CONSTRAINT IF EXISTS sales_order_lines_shipped_check;
+ALTER TABLE sales_order_lines DROP COLUMN IF EXISTS shorted;
+ALTER TABLE sales_order_lines ADD CONSTRAINT sales_order_lines_shipped_check CHECK (shipped >= 0 AND shipped <= quantity);
+
+ALTER TABLE sales_orders DROP CONSTRAINT IF EXISTS sales_orders_status_check;
+UPDATE sales_orders SET status = 'processing' WHERE status IN ('picking', 'picked', 'packed');
+ALTER TABLE sales_orders ADD CONSTRAINT sales_orders_status_check
+    CHECK (status IN ('pending', 'processing', 'completed', 'cancelled'));
@@ -0,0 +1,74 @@
+ALTER TABLE sales_orders DROP CONSTRAINT IF EXISTS sales_orders_status_check;
+ALTER TABLE sales_orders ADD CONSTRAINT sales_orders_status_check
+    CHECK (status IN ('pending', 'processing', 'picking', 'picked', 'packed', 'completed', 'cancelled'));
+
+-- quantities the warehouse could not pick, they are neither shipped nor billed
+ALTER TABLE sales_order_lines ADD COLUMN IF NOT EXISTS shorted NUMERIC(18, 3) NOT NULL DEFAULT 0;
+ALTER TABLE sales_order_lines DROP CONSTRAINT IF EXISTS sales_order_lines_shipped_check;
+ALTER TABLE sales_order_lines ADD CONSTRAINT sales_order_lines_shipped_check
+    CHECK (shipped >= 0 AND shorted >= 0 AND shipped + shorted <= quantity);
+
+CREATE SEQUENCE IF NOT EXISTS pick_list_number_seq;
+
+CREATE TABLE IF NOT EXISTS pick_lists (
+    id           UUID PRIMARY KEY,
+    number       VARCHAR(32) NOT NULL UNIQUE,
+    order_id     UUID        NOT NULL REFERENCES sales_orders (id) ON DELETE CASCADE,
+    warehouse_id UUID        NOT NULL REFERENCES warehouses (id) ON DELETE RESTRICT,
+    status       VARCHAR(16) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'completed', 'cancelled')),
+    created_by   UUID,
+    completed_at TIMESTAMP,
+    created_at   TIMESTAMP   NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMP   NOT NULL DEFAULT NOW()
+);
+
+-- an order has at most one pick list that isn't cancelled
+CREATE UNIQUE INDEX IF NOT EXISTS uq_pick_lists_order_id ON pick_lists (order_id) WHERE status <> 'cancelled';
+CREATE INDEX IF NOT EXISTS idx_pick_lists_status ON pick_lists (status);
+
+CREATE TABLE IF NOT EXISTS pick_list_lines (
+    id             UUID PRIMARY KEY,
+    pick_list_id   UUID           NOT NULL REFERENCES pick_lists (id) ON DELETE CASCADE,
+    line_no        INT            NOT NULL,
+    order_line_id  UUID           NOT NULL REFERENCES sales_order_lines (id) ON DELETE CASCADE,
+    product_id     UUID           NOT NULL REFERENCES products (id) ON DELETE RESTRICT,
+    bin_id         UUID           NOT NULL REFERENCES bins (id) ON DELETE RESTRICT,
+    lot_id         UUID REFERENCES lots (id) ON DELETE RESTRICT,
+    serials        TEXT[]         NOT NULL DEFAULT '{}',
+    quantity       NUMERIC(18, 3) NOT NULL CHECK (quantity > 0),
+    picked         NUMERIC(18, 3) NOT NULL DEFAULT 0,
+    picked_serials TEXT[]         NOT NULL DEFAULT '{}',
+    shorted        NUMERIC(18, 3) NOT NULL DEFAULT 0,
+    short_reason   TEXT           NOT NULL DEFAULT '',
+    UNIQUE (pick_list_id, line_no),
+    CONSTRAINT pick_list_lines_picked_check CHECK (picked >= 0 AND shorted >= 0 AND picked + shorted <= quantity)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pick_list_lines_order_line_id ON pick_list_lines (order_line_id);
+
+CREATE SEQUENCE IF NOT EXISTS shipment_number_seq;
+
+CREATE TABLE IF NOT EXISTS shipments (
+    id              UUID PRIMARY KEY,
+    number          VARCHAR(32)  NOT NULL UNIQUE,
+    order_id        UUID         NOT NULL UNIQUE REFERENCES sales_orders (id) ON DELETE CASCADE,
+    carrier         VARCHAR(128) NOT NULL,
+    tracking_number VARCHAR(128) NOT NULL DEFAULT '',
+    note            TEXT         NOT NULL DEFAULT '',
+    created_by      UUID,
+    shipped_at      TIMESTAMP    NOT NULL DEFAULT NOW(),
+    created_at      TIMESTAMP    NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_shipments_carrier ON shipments (carrier);
+
+CREATE TABLE IF NOT EXISTS parcels (
+    id          UUID PRIMARY KEY,
+    order_id    UUID           NOT NULL REFERENCES sales_orders (id) ON DELETE CASCADE,
+    parcel_no   INT            NOT NULL,
+    weight      NUMERIC(12, 3) NOT NULL CHECK (weight > 0),
+    note        TEXT           NOT NULL DEFAULT '',
+    shipment_id UUID REFERENCES shipments (id) ON DELETE SET NULL,
+    created_at  TIMESTAMP      NOT NULL DEFAULT NOW(),
+    UNIQUE (order_id, parcel_no)
+);
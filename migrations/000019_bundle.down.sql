@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS assembly_lines;
+DROP TABLE IF EXISTS assemblies;
+DROP SEQUENCE IF EXISTS assembly_number_seq;
+
+DROP TABLE IF EXISTS product_components;
+
+ALTER TABLE products DROP COLUMN IF EXISTS kind;
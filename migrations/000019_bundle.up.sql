@@ -0,0 +1,45 @@
+-- bundles hold no stock and are sold out of their components, kits are
+-- assembled from their components ahead and stocked like other products
+ALTER TABLE products ADD COLUMN IF NOT EXISTS kind VARCHAR(16) NOT NULL DEFAULT 'simple'
+    CHECK (kind IN ('simple', 'bundle', 'kit'));
+
+CREATE TABLE IF NOT EXISTS product_components (
+    product_id   UUID           NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    component_id UUID           NOT NULL REFERENCES products (id) ON DELETE RESTRICT,
+    quantity     NUMERIC(18, 3) NOT NULL CHECK (quantity > 0),
+    PRIMARY KEY (product_id, component_id),
+    CHECK (product_id <> component_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_components_component_id ON product_components (component_id);
+
+CREATE SEQUENCE IF NOT EXISTS assembly_number_seq;
+
+CREATE TABLE IF NOT EXISTS assemblies (
+    id           UUID PRIMARY KEY,
+    number       VARCHAR(32)    NOT NULL UNIQUE,
+    type         VARCHAR(16)    NOT NULL CHECK (type IN ('assemble', 'disassemble')),
+    warehouse_id UUID           NOT NULL REFERENCES warehouses (id) ON DELETE RESTRICT,
+    bin_id       UUID           NOT NULL REFERENCES bins (id) ON DELETE RESTRICT,
+    product_id   UUID           NOT NULL REFERENCES products (id) ON DELETE RESTRICT,
+    quantity     NUMERIC(18, 3) NOT NULL CHECK (quantity > 0),
+    status       VARCHAR(16)    NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'posted')),
+    note         TEXT           NOT NULL DEFAULT '',
+    created_by   UUID,
+    posted_at    TIMESTAMP,
+    created_at   TIMESTAMP      NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMP      NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_assemblies_product_id ON assemblies (product_id);
+CREATE INDEX IF NOT EXISTS idx_assemblies_warehouse_id ON assemblies (warehouse_id);
+
+-- components a document takes or gives back, fixed when it is created
+CREATE TABLE IF NOT EXISTS assembly_lines (
+    id           UUID PRIMARY KEY,
+    assembly_id  UUID           NOT NULL REFERENCES assemblies (id) ON DELETE CASCADE,
+    line_no      INT            NOT NULL,
+    component_id UUID           NOT NULL REFERENCES products (id) ON DELETE RESTRICT,
+    quantity     NUMERIC(18, 3) NOT NULL CHECK (quantity > 0),
+    UNIQUE (assembly_id, line_no)
+);
@@ -0,0 +1,20 @@
+ALTER TABLE stocktake_counts DROP COLUMN IF EXISTS unit_quantity;
+ALTER TABLE stocktake_counts DROP COLUMN IF EXISTS unit;
+ALTER TABLE supplier_credit_lines DROP COLUMN IF EXISTS unit_quantity;
+ALTER TABLE supplier_credit_lines DROP COLUMN IF EXISTS unit;
+ALTER TABLE transfer_lines DROP COLUMN IF EXISTS unit_quantity;
+ALTER TABLE transfer_lines DROP COLUMN IF EXISTS unit;
+ALTER TABLE sales_order_lines DROP COLUMN IF EXISTS unit_quantity;
+ALTER TABLE sales_order_lines DROP COLUMN IF EXISTS unit;
+ALTER TABLE purchase_order_lines DROP COLUMN IF EXISTS unit_quantity;
+ALTER TABLE purchase_order_lines DROP COLUMN IF EXISTS unit;
+ALTER TABLE goods_receipt_lines DROP COLUMN IF EXISTS unit_quantity;
+ALTER TABLE goods_receipt_lines DROP COLUMN IF EXISTS unit;
+
+-- unit barcodes go with their units
+DELETE FROM product_barcodes WHERE unit IS NOT NULL;
+DROP INDEX IF EXISTS uq_product_barcodes_unit;
+ALTER TABLE product_barcodes DROP CONSTRAINT IF EXISTS product_barcodes_unit_fkey;
+ALTER TABLE product_barcodes DROP COLUMN IF EXISTS unit;
+
+DROP TABLE IF EXISTS product_units;
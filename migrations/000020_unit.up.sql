@@ -0,0 +1,46 @@
+-- alternate units of a product, factor is how many base units one of them holds
+CREATE TABLE IF NOT EXISTS product_units (
+    product_id UUID           NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    unit       VARCHAR(16)    NOT NULL,
+    factor     NUMERIC(18, 6) NOT NULL CHECK (factor > 0),
+    PRIMARY KEY (product_id, unit)
+);
+
+-- a barcode with a unit is scanned as one of that unit, without one as one base unit
+ALTER TABLE product_barcodes ADD COLUMN IF NOT EXISTS unit VARCHAR(16);
+ALTER TABLE product_barcodes DROP CONSTRAINT IF EXISTS product_barcodes_unit_fkey;
+ALTER TABLE product_barcodes ADD CONSTRAINT product_barcodes_unit_fkey
+    FOREIGN KEY (product_id, unit) REFERENCES product_units (product_id, unit) ON DELETE CASCADE;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_product_barcodes_unit ON product_barcodes (product_id, unit) WHERE unit IS NOT NULL;
+
+-- document lines keep the unit and quantity they were entered in, quantity
+-- itself stays in the base unit
+ALTER TABLE goods_receipt_lines ADD COLUMN IF NOT EXISTS unit VARCHAR(16) NOT NULL DEFAULT '';
+ALTER TABLE goods_receipt_lines ADD COLUMN IF NOT EXISTS unit_quantity NUMERIC(18, 3) NOT NULL DEFAULT 0;
+UPDATE goods_receipt_lines SET unit = products.base_unit, unit_quantity = goods_receipt_lines.quantity
+FROM products WHERE products.id = goods_receipt_lines.product_id;
+
+ALTER TABLE purchase_order_lines ADD COLUMN IF NOT EXISTS unit VARCHAR(16) NOT NULL DEFAULT '';
+ALTER TABLE purchase_order_lines ADD COLUMN IF NOT EXISTS unit_quantity NUMERIC(18, 3) NOT NULL DEFAULT 0;
+UPDATE purchase_order_lines SET unit = products.base_unit, unit_quantity = purchase_order_lines.quantity
+FROM products WHERE products.id = purchase_order_lines.product_id;
+
+ALTER TABLE sales_order_lines ADD COLUMN IF NOT EXISTS unit VARCHAR(16) NOT NULL DEFAULT '';
+ALTER TABLE sales_order_lines ADD COLUMN IF NOT EXISTS unit_quantity NUMERIC(18, 3) NOT NULL DEFAULT 0;
+UPDATE sales_order_lines SET unit = products.base_unit, unit_quantity = sales_order_lines.quantity
+FROM products WHERE products.id = sales_order_lines.product_id;
+
+ALTER TABLE transfer_lines ADD COLUMN IF NOT EXISTS unit VARCHAR(16) NOT NULL DEFAULT '';
+ALTER TABLE transfer_lines ADD COLUMN IF NOT EXISTS unit_quantity NUMERIC(18, 3) NOT NULL DEFAULT 0;
+UPDATE transfer_lines SET unit = products.base_unit, unit_quantity = transfer_lines.quantity
+FROM products WHERE products.id = transfer_lines.product_id;
+
+ALTER TABLE supplier_credit_lines ADD COLUMN IF NOT EXISTS unit VARCHAR(16) NOT NULL DEFAULT '';
+ALTER TABLE supplier_credit_lines ADD COLUMN IF NOT EXISTS unit_quantity NUMERIC(18, 3) NOT NULL DEFAULT 0;
+UPDATE supplier_credit_lines SET unit = products.base_unit, unit_quantity = supplier_credit_lines.quantity
+FROM products WHERE products.id = supplier_credit_lines.product_id;
+
+ALTER TABLE stocktake_counts ADD COLUMN IF NOT EXISTS unit VARCHAR(16) NOT NULL DEFAULT '';
+ALTER TABLE stocktake_counts ADD COLUMN IF NOT EXISTS unit_quantity NUMERIC(18, 3) NOT NULL DEFAULT 0;
+UPDATE stocktake_counts SET unit = products.base_unit, unit_quantity = stocktake_counts.quantity
+FROM products WHERE products.id = stocktake_counts.product_id;
@@ -0,0 +1,9 @@
+ALTER TABLE sales_order_lines DROP COLUMN IF EXISTS price_rules;
+ALTER TABLE sales_order_lines DROP COLUMN IF EXISTS list_price;
+
+DROP TABLE IF EXISTS promotions;
+
+ALTER TABLE customers DROP COLUMN IF EXISTS price_list_id;
+
+DROP TABLE IF EXISTS price_list_items;
+DROP TABLE IF EXISTS price_lists;
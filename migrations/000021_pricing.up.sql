@@ -0,0 +1,51 @@
+-- named price lists, a customer buys at the prices of its list while it is valid
+CREATE TABLE IF NOT EXISTS price_lists (
+    id         UUID PRIMARY KEY,
+    name       VARCHAR(255) NOT NULL UNIQUE,
+    type       VARCHAR(16)  NOT NULL CHECK (type IN ('retail', 'wholesale', 'dealer')),
+    valid_from DATE,
+    valid_to   DATE,
+    is_active  BOOLEAN      NOT NULL DEFAULT TRUE,
+    note       TEXT         NOT NULL DEFAULT '',
+    created_at TIMESTAMP    NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP    NOT NULL DEFAULT NOW(),
+    CHECK (valid_to IS NULL OR valid_from IS NULL OR valid_to >= valid_from)
+);
+
+-- prices per base unit, the item with the largest min_quantity not above the
+-- line quantity applies
+CREATE TABLE IF NOT EXISTS price_list_items (
+    price_list_id UUID           NOT NULL REFERENCES price_lists (id) ON DELETE CASCADE,
+    product_id    UUID           NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    min_quantity  NUMERIC(18, 3) NOT NULL DEFAULT 0 CHECK (min_quantity >= 0),
+    price         NUMERIC(18, 2) NOT NULL CHECK (price >= 0),
+    PRIMARY KEY (price_list_id, product_id, min_quantity)
+);
+
+ALTER TABLE customers ADD COLUMN IF NOT EXISTS price_list_id UUID REFERENCES price_lists (id) ON DELETE SET NULL;
+
+-- discounts on a product or on every product of a category and its subcategories
+CREATE TABLE IF NOT EXISTS promotions (
+    id          UUID PRIMARY KEY,
+    name        VARCHAR(255)   NOT NULL,
+    type        VARCHAR(16)    NOT NULL CHECK (type IN ('percent', 'fixed')),
+    value       NUMERIC(18, 2) NOT NULL CHECK (value > 0),
+    product_id  UUID REFERENCES products (id) ON DELETE CASCADE,
+    category_id UUID REFERENCES categories (id) ON DELETE CASCADE,
+    valid_from  DATE,
+    valid_to    DATE,
+    is_active   BOOLEAN        NOT NULL DEFAULT TRUE,
+    created_at  TIMESTAMP      NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMP      NOT NULL DEFAULT NOW(),
+    CHECK ((product_id IS NULL) <> (category_id IS NULL)),
+    CHECK (type <> 'percent' OR value <= 100),
+    CHECK (valid_to IS NULL OR valid_from IS NULL OR valid_to >= valid_from)
+);
+
+CREATE INDEX IF NOT EXISTS idx_promotions_product_id ON promotions (product_id);
+CREATE INDEX IF NOT EXISTS idx_promotions_category_id ON promotions (category_id);
+
+-- order lines keep the price before discounts and how the price was arrived at
+ALTER TABLE sales_order_lines ADD COLUMN IF NOT EXISTS list_price NUMERIC(18, 2) NOT NULL DEFAULT 0;
+ALTER TABLE sales_order_lines ADD COLUMN IF NOT EXISTS price_rules TEXT[] NOT NULL DEFAULT '{}';
+UPDATE sales_order_lines SET list_price = price;
@@ -0,0 +1,15 @@
+ALTER TABLE supplier_payments DROP COLUMN IF EXISTS exchange_rate;
+ALTER TABLE supplier_payments DROP COLUMN IF EXISTS currency;
+ALTER TABLE supplier_credits DROP COLUMN IF EXISTS exchange_rate;
+ALTER TABLE supplier_credits DROP COLUMN IF EXISTS currency;
+ALTER TABLE goods_receipts DROP COLUMN IF EXISTS exchange_rate;
+ALTER TABLE goods_receipts DROP COLUMN IF EXISTS currency;
+ALTER TABLE purchase_orders DROP COLUMN IF EXISTS exchange_rate;
+ALTER TABLE purchase_orders DROP COLUMN IF EXISTS currency;
+ALTER TABLE customer_payments DROP COLUMN IF EXISTS exchange_rate;
+ALTER TABLE customer_payments DROP COLUMN IF EXISTS currency;
+ALTER TABLE sales_orders DROP COLUMN IF EXISTS exchange_rate;
+ALTER TABLE sales_orders DROP COLUMN IF EXISTS currency;
+
+DROP TABLE IF EXISTS exchange_rates;
+DROP TABLE IF EXISTS currencies;
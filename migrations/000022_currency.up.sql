@@ -0,0 +1,47 @@
+-- currencies documents may be kept in, amounts in the base one need no rate
+CREATE TABLE IF NOT EXISTS currencies (
+    code       VARCHAR(3) PRIMARY KEY,
+    name       VARCHAR(64) NOT NULL,
+    is_base    BOOLEAN     NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP   NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP   NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_currencies_base ON currencies (is_base) WHERE is_base;
+
+INSERT INTO currencies (code, name, is_base) VALUES
+    ('UZS', 'Uzbek sum', TRUE),
+    ('USD', 'US dollar', FALSE),
+    ('EUR', 'Euro', FALSE),
+    ('RUB', 'Russian ruble', FALSE)
+ON CONFLICT (code) DO NOTHING;
+
+-- base currency units one unit of the currency is worth from rate_date on
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    currency   VARCHAR(3)     NOT NULL REFERENCES currencies (code) ON DELETE CASCADE,
+    rate_date  DATE           NOT NULL,
+    rate       NUMERIC(18, 6) NOT NULL CHECK (rate > 0),
+    source     VARCHAR(16)    NOT NULL DEFAULT 'manual' CHECK (source IN ('manual', 'cbu')),
+    created_at TIMESTAMP      NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (currency, rate_date)
+);
+
+-- monetary documents keep their currency and the rate on their date, payments
+-- are in the currency of the document they pay
+ALTER TABLE sales_orders ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT 'UZS' REFERENCES currencies (code);
+ALTER TABLE sales_orders ADD COLUMN IF NOT EXISTS exchange_rate NUMERIC(18, 6) NOT NULL DEFAULT 1 CHECK (exchange_rate > 0);
+
+ALTER TABLE customer_payments ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT 'UZS' REFERENCES currencies (code);
+ALTER TABLE customer_payments ADD COLUMN IF NOT EXISTS exchange_rate NUMERIC(18, 6) NOT NULL DEFAULT 1 CHECK (exchange_rate > 0);
+
+ALTER TABLE purchase_orders ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT 'UZS' REFERENCES currencies (code);
+ALTER TABLE purchase_orders ADD COLUMN IF NOT EXISTS exchange_rate NUMERIC(18, 6) NOT NULL DEFAULT 1 CHECK (exchange_rate > 0);
+
+ALTER TABLE goods_receipts ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT 'UZS' REFERENCES currencies (code);
+ALTER TABLE goods_receipts ADD COLUMN IF NOT EXISTS exchange_rate NUMERIC(18, 6) NOT NULL DEFAULT 1 CHECK (exchange_rate > 0);
+
+ALTER TABLE supplier_credits ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT 'UZS' REFERENCES currencies (code);
+ALTER TABLE supplier_credits ADD COLUMN IF NOT EXISTS exchange_rate NUMERIC(18, 6) NOT NULL DEFAULT 1 CHECK (exchange_rate > 0);
+
+ALTER TABLE supplier_payments ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT 'UZS' REFERENCES currencies (code);
+ALTER TABLE supplier_payments ADD COLUMN IF NOT EXISTS exchange_rate NUMERIC(18, 6) NOT NULL DEFAULT 1 CHECK (exchange_rate > 0);
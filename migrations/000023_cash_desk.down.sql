@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_supplier_payments_desk;
+DROP INDEX IF EXISTS idx_customer_payments_desk;
+
+ALTER TABLE supplier_payments DROP COLUMN IF EXISTS payment_method;
+ALTER TABLE supplier_payments DROP COLUMN IF EXISTS cash_desk_id;
+ALTER TABLE customer_payments DROP COLUMN IF EXISTS payment_method;
+ALTER TABLE customer_payments DROP COLUMN IF EXISTS cash_desk_id;
+
+DROP TABLE IF EXISTS cash_operations;
+DROP TABLE IF EXISTS cash_day_closes;
+DROP TABLE IF EXISTS cash_desks;
@@ -0,0 +1,53 @@
+-- cash desks, card terminals and bank accounts payments are made through
+CREATE TABLE IF NOT EXISTS cash_desks (
+    id         UUID PRIMARY KEY,
+    name       VARCHAR(255) NOT NULL UNIQUE,
+    type       VARCHAR(16)  NOT NULL CHECK (type IN ('cash', 'card', 'bank')),
+    currency   VARCHAR(3)   NOT NULL DEFAULT 'UZS' REFERENCES currencies (code),
+    is_active  BOOLEAN      NOT NULL DEFAULT TRUE,
+    note       TEXT         NOT NULL DEFAULT '',
+    created_at TIMESTAMP    NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP    NOT NULL DEFAULT NOW()
+);
+
+-- counted balances of a desk at the end of a day, nothing dated on or before
+-- the last close of a desk can be added or removed
+CREATE TABLE IF NOT EXISTS cash_day_closes (
+    id           UUID PRIMARY KEY,
+    cash_desk_id UUID           NOT NULL REFERENCES cash_desks (id),
+    close_date   DATE           NOT NULL,
+    expected     NUMERIC(18, 2) NOT NULL,
+    counted      NUMERIC(18, 2) NOT NULL,
+    difference   NUMERIC(18, 2) NOT NULL, -- counted - expected, over when positive, short when negative
+    note         TEXT           NOT NULL DEFAULT '',
+    closed_by    UUID,
+    created_at   TIMESTAMP      NOT NULL DEFAULT NOW(),
+    UNIQUE (cash_desk_id, close_date)
+);
+
+-- money put into or taken out of a desk other than payments, over and short
+-- are written by the day close
+CREATE TABLE IF NOT EXISTS cash_operations (
+    id             UUID PRIMARY KEY,
+    cash_desk_id   UUID           NOT NULL REFERENCES cash_desks (id),
+    type           VARCHAR(16)    NOT NULL CHECK (type IN ('in', 'out', 'over', 'short')),
+    amount         NUMERIC(18, 2) NOT NULL CHECK (amount > 0),
+    operation_date DATE           NOT NULL DEFAULT CURRENT_DATE,
+    day_close_id   UUID REFERENCES cash_day_closes (id) ON DELETE CASCADE,
+    note           TEXT           NOT NULL DEFAULT '',
+    created_by     UUID,
+    created_at     TIMESTAMP      NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_cash_operations_desk ON cash_operations (cash_desk_id, operation_date);
+
+ALTER TABLE customer_payments ADD COLUMN IF NOT EXISTS cash_desk_id UUID REFERENCES cash_desks (id);
+ALTER TABLE customer_payments ADD COLUMN IF NOT EXISTS payment_method VARCHAR(16) NOT NULL DEFAULT 'cash'
+    CHECK (payment_method IN ('cash', 'card', 'bank_transfer'));
+
+ALTER TABLE supplier_payments ADD COLUMN IF NOT EXISTS cash_desk_id UUID REFERENCES cash_desks (id);
+ALTER TABLE supplier_payments ADD COLUMN IF NOT EXISTS payment_method VARCHAR(16) NOT NULL DEFAULT 'cash'
+    CHECK (payment_method IN ('cash', 'card', 'bank_transfer'));
+
+CREATE INDEX IF NOT EXISTS idx_customer_payments_desk ON customer_payments (cash_desk_id, paid_date);
+CREATE INDEX IF NOT EXISTS idx_supplier_payments_desk ON supplier_payments (cash_desk_id, paid_date);
@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS document_templates;
+
+ALTER TABLE company_settings DROP COLUMN IF EXISTS accountant;
+ALTER TABLE company_settings DROP COLUMN IF EXISTS director;
+ALTER TABLE company_settings DROP COLUMN IF EXISTS bank_code;
+ALTER TABLE company_settings DROP COLUMN IF EXISTS bank_account;
+ALTER TABLE company_settings DROP COLUMN IF EXISTS bank_name;
+ALTER TABLE company_settings DROP COLUMN IF EXISTS company_phone;
+ALTER TABLE company_settings DROP COLUMN IF EXISTS company_tax_id;
+ALTER TABLE company_settings DROP COLUMN IF EXISTS company_address;
+ALTER TABLE company_settings DROP COLUMN IF EXISTS company_name;
@@ -0,0 +1,25 @@
+-- company details printed on invoices and waybills
+ALTER TABLE company_settings ADD COLUMN IF NOT EXISTS company_name    VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE company_settings ADD COLUMN IF NOT EXISTS company_address TEXT         NOT NULL DEFAULT '';
+ALTER TABLE company_settings ADD COLUMN IF NOT EXISTS company_tax_id  VARCHAR(32)  NOT NULL DEFAULT '';
+ALTER TABLE company_settings ADD COLUMN IF NOT EXISTS company_phone   VARCHAR(64)  NOT NULL DEFAULT '';
+ALTER TABLE company_settings ADD COLUMN IF NOT EXISTS bank_name       VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE company_settings ADD COLUMN IF NOT EXISTS bank_account    VARCHAR(64)  NOT NULL DEFAULT '';
+ALTER TABLE company_settings ADD COLUMN IF NOT EXISTS bank_code       VARCHAR(16)  NOT NULL DEFAULT ''; -- MFO
+ALTER TABLE company_settings ADD COLUMN IF NOT EXISTS director        VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE company_settings ADD COLUMN IF NOT EXISTS accountant      VARCHAR(255) NOT NULL DEFAULT '';
+
+-- layout of the printed forms, one row per form, empty values fall back to
+-- the defaults of the language a document is printed in
+CREATE TABLE IF NOT EXISTS document_templates (
+    form       VARCHAR(16)   PRIMARY KEY CHECK (form IN ('invoice', 'waybill')),
+    title      VARCHAR(255)  NOT NULL DEFAULT '',
+    header     TEXT          NOT NULL DEFAULT '',
+    footer     TEXT          NOT NULL DEFAULT '',
+    font_size  NUMERIC(4, 1) NOT NULL DEFAULT 9 CHECK (font_size BETWEEN 6 AND 14),
+    columns    TEXT[]        NOT NULL DEFAULT '{}',
+    signatures TEXT[]        NOT NULL DEFAULT '{}',
+    updated_at TIMESTAMP     NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO document_templates (form) VALUES ('invoice'), ('waybill') ON CONFLICT DO NOTHING;
@@ -0,0 +1,35 @@
+-- uploaded CSV and XLSX files of products or opening balances, the worker
+-- validates or imports their rows in the background
+CREATE TABLE IF NOT EXISTS import_jobs (
+    id          UUID PRIMARY KEY,
+    kind        VARCHAR(32)  NOT NULL CHECK (kind IN ('products', 'opening_balances')),
+    file_name   VARCHAR(255) NOT NULL DEFAULT '',
+    status      VARCHAR(16)  NOT NULL DEFAULT 'uploaded' CHECK (status IN ('uploaded', 'queued', 'running', 'validated', 'completed', 'failed')),
+    columns     TEXT[]       NOT NULL DEFAULT '{}', -- header row of the file
+    mapping     JSONB        NOT NULL DEFAULT '{}', -- field -> column
+    dry_run     BOOLEAN      NOT NULL DEFAULT FALSE,
+    total_rows  INT          NOT NULL DEFAULT 0,
+    processed   INT          NOT NULL DEFAULT 0,
+    failed_rows INT          NOT NULL DEFAULT 0,
+    created     INT          NOT NULL DEFAULT 0,
+    updated     INT          NOT NULL DEFAULT 0,
+    error       TEXT         NOT NULL DEFAULT '',
+    created_by  UUID,
+    started_at  TIMESTAMP,
+    finished_at TIMESTAMP,
+    created_at  TIMESTAMP    NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMP    NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_import_jobs_status ON import_jobs (status, created_at);
+
+-- rows of an import file, imported rows are skipped when a job is run again
+CREATE TABLE IF NOT EXISTS import_rows (
+    job_id     UUID        NOT NULL REFERENCES import_jobs (id) ON DELETE CASCADE,
+    row_number INT         NOT NULL, -- line of the file, the header is line 1
+    cells      TEXT[]      NOT NULL DEFAULT '{}',
+    status     VARCHAR(16) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'valid', 'imported', 'error')),
+    error      TEXT        NOT NULL DEFAULT '',
+    record_id  UUID, -- product or movement written for the row
+    PRIMARY KEY (job_id, row_number)
+);
@@ -1,8 +1,10 @@
 package minio
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"log"
 	"log/slog"
 	"path/filepath"
@@ -26,6 +28,7 @@ var ContentType = map[string]string{
 	".bmp":  "image/bmp",
 	".webp": "image/webp",
 	".tiff": "image/tiff",
+	".pdf":  "application/pdf",
 }
 
 func MinIOConnect(cf *config.Config) (*MinIO, error) {
@@ -72,6 +75,22 @@ func MinIOConnect(cf *config.Config) (*MinIO, error) {
 		return nil, err
 	}
 
+	// the document bucket is private, an empty policy removes any left over
+	err = minioClient.MakeBucket(context.Background(), cf.MinIODocumentBucket, minio.MakeBucketOptions{})
+	if err != nil {
+		exists, errBucketExists := minioClient.BucketExists(context.Background(), cf.MinIODocumentBucket)
+		if errBucketExists != nil || !exists {
+			log.Println(err)
+			return nil, err
+		}
+	}
+
+	err = minioClient.SetBucketPolicy(context.Background(), cf.MinIODocumentBucket, "")
+	if err != nil {
+		log.Println("error while setting document bucket policy : ", err)
+		return nil, err
+	}
+
 	return &MinIO{
 		client: minioClient,
 		Cf:     cf,
@@ -101,3 +120,21 @@ func (m *MinIO) Upload(fileName, filePath string) (string, error) {
 
 	return minioURL, nil
 }
+
+// UploadDocument stores a printed document in the private document bucket.
+func (m *MinIO) UploadDocument(fileName string, content []byte) error {
+	_, err := m.client.PutObject(context.Background(), m.Cf.MinIODocumentBucket, fileName, bytes.NewReader(content),
+		int64(len(content)), minio.PutObjectOptions{ContentType: ContentType[filepath.Ext(fileName)]})
+	return err
+}
+
+// DownloadDocument returns the content of a document stored before.
+func (m *MinIO) DownloadDocument(fileName string) ([]byte, error) {
+	object, err := m.client.GetObject(context.Background(), m.Cf.MinIODocumentBucket, fileName, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer object.Close()
+
+	return io.ReadAll(object)
+}
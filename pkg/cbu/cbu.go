@@ -0,0 +1,138 @@
+// Package cbu reads the official exchange rates published by the Central Bank
+// of Uzbekistan as JSON, either from its web service or from a saved copy.
+package cbu
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultURL is the archive of rates on the site of the bank.
+const DefaultURL = "https://cbu.uz/uz/arkhiv-kursov-valyut/json/"
+
+// Rate is how many sums Nominal units of a currency cost on Date.
+type Rate struct {
+	Currency string  // ISO code, e.g. USD
+	Name     string  // English name
+	Nominal  float64 // units of the currency the rate is for
+	Rate     float64
+	Date     string // YYYY-MM-DD
+}
+
+// Source returns the rates of every currency on date (YYYY-MM-DD, the latest
+// published when empty).
+type Source interface {
+	Rates(ctx context.Context, date string) ([]Rate, error)
+}
+
+// NewSource reads rates from an http(s) URL laid out like DefaultURL, or from
+// a file otherwise.
+func NewSource(location string) Source {
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		return &HTTPSource{BaseURL: location, Client: &http.Client{Timeout: 30 * time.Second}}
+	}
+
+	return FileSource(location)
+}
+
+// HTTPSource fetches rates from BaseURL, the rates of a date are served at
+// BaseURL + "all/YYYY-MM-DD/" and the latest ones at BaseURL itself.
+type HTTPSource struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+func (s *HTTPSource) Rates(ctx context.Context, date string) ([]Rate, error) {
+	url := strings.TrimSuffix(s.BaseURL, "/") + "/"
+	if date != "" {
+		url += "all/" + date + "/"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("cbu: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("cbu: %s returned %s", url, resp.Status)
+	}
+
+	return Parse(resp.Body)
+}
+
+// FileSource reads rates saved from the web service to a file. The file holds
+// the rates of a single day, so the date asked for is not checked.
+type FileSource string
+
+func (s FileSource) Rates(ctx context.Context, date string) ([]Rate, error) {
+	f, err := os.Open(string(s))
+	if err != nil {
+		return nil, fmt.Errorf("cbu: %w", err)
+	}
+	defer f.Close()
+
+	return Parse(f)
+}
+
+// Parse decodes rates in the format of the web service, where every field is a
+// string and dates are DD.MM.YYYY.
+func Parse(r io.Reader) ([]Rate, error) {
+	var items []struct {
+		Ccy     string `json:"Ccy"`
+		CcyNmEN string `json:"CcyNm_EN"`
+		Nominal string `json:"Nominal"`
+		Rate    string `json:"Rate"`
+		Date    string `json:"Date"`
+	}
+
+	err := json.NewDecoder(r).Decode(&items)
+	if err != nil {
+		return nil, fmt.Errorf("cbu: decode rates: %w", err)
+	}
+
+	rates := make([]Rate, 0, len(items))
+	for _, item := range items {
+		rate := Rate{Currency: item.Ccy, Name: item.CcyNmEN, Nominal: 1}
+
+		if item.Nominal != "" {
+			rate.Nominal, err = strconv.ParseFloat(item.Nominal, 64)
+			if err != nil {
+				return nil, fmt.Errorf("cbu: nominal of %s: %w", item.Ccy, err)
+			}
+		}
+
+		rate.Rate, err = strconv.ParseFloat(item.Rate, 64)
+		if err != nil {
+			return nil, fmt.Errorf("cbu: rate of %s: %w", item.Ccy, err)
+		}
+
+		date, err := time.Parse("02.01.2006", item.Date)
+		if err != nil {
+			return nil, fmt.Errorf("cbu: date of %s: %w", item.Ccy, err)
+		}
+
+		rate.Date = date.Format("2006-01-02")
+
+		rates = append(rates, rate)
+	}
+
+	return rates, nil
+}
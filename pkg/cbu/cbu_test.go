@@ -0,0 +1,164 @@
+package cbu
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// payload is a trimmed response of the web service.
+const payload = `[
+	{"id": 69, "Code": "840", "Ccy": "USD", "CcyNm_EN": "US Dollar", "Nominal": "1", "Rate": "12850.35", "Diff": "-12.4", "Date": "17.10.2026"},
+	{"id": 21, "Code": "643", "Ccy": "RUB", "CcyNm_EN": "Russian Ruble", "Nominal": "1", "Rate": "158.6", "Diff": "0.3", "Date": "17.10.2026"},
+	{"id": 57, "Code": "392", "Ccy": "JPY", "CcyNm_EN": "Japan Yen", "Nominal": "10", "Rate": "861.2", "Diff": "1.05", "Date": "17.10.2026"}
+]`
+
+var payloadRates = []Rate{
+	{Currency: "USD", Name: "US Dollar", Nominal: 1, Rate: 12850.35, Date: "2026-10-17"},
+	{Currency: "RUB", Name: "Russian Ruble", Nominal: 1, Rate: 158.6, Date: "2026-10-17"},
+	{Currency: "JPY", Name: "Japan Yen", Nominal: 10, Rate: 861.2, Date: "2026-10-17"},
+}
+
+func TestParse(t *testing.T) {
+	rates, err := Parse(strings.NewReader(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(rates, payloadRates) {
+		t.Errorf("got %+v, want %+v", rates, payloadRates)
+	}
+}
+
+func TestParseDefaultsNominal(t *testing.T) {
+	rates, err := Parse(strings.NewReader(`[{"Ccy": "EUR", "Rate": "14010.5", "Date": "17.10.2026"}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rates) != 1 || rates[0].Nominal != 1 {
+		t.Errorf("got %+v, want a nominal of 1", rates)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for name, body := range map[string]string{
+		"malformed json": `[{"Ccy": "USD", "Rate": "12850.35"`,
+		"not a list":     `{"Ccy": "USD"}`,
+		"bad rate":       `[{"Ccy": "USD", "Nominal": "1", "Rate": "12 850,35", "Date": "17.10.2026"}]`,
+		"empty rate":     `[{"Ccy": "USD", "Nominal": "1", "Rate": "", "Date": "17.10.2026"}]`,
+		"bad nominal":    `[{"Ccy": "USD", "Nominal": "one", "Rate": "12850.35", "Date": "17.10.2026"}]`,
+		"iso date":       `[{"Ccy": "USD", "Nominal": "1", "Rate": "12850.35", "Date": "2026-10-17"}]`,
+		"bad date":       `[{"Ccy": "USD", "Nominal": "1", "Rate": "12850.35", "Date": "32.13.2026"}]`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(body))
+			if err == nil || !strings.HasPrefix(err.Error(), "cbu: ") {
+				t.Errorf("got %v, want a cbu error", err)
+			}
+		})
+	}
+}
+
+func TestFileSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	if err := os.WriteFile(path, []byte(payload), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	source := NewSource(path)
+	if _, ok := source.(FileSource); !ok {
+		t.Fatalf("got %T for a path, want FileSource", source)
+	}
+
+	// the file holds a single day, any date gets it
+	rates, err := source.Rates(context.Background(), "2026-10-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(rates, payloadRates) {
+		t.Errorf("got %+v, want %+v", rates, payloadRates)
+	}
+
+	_, err = FileSource(filepath.Join(t.TempDir(), "missing.json")).Rates(context.Background(), "")
+	if err == nil {
+		t.Error("got no error for a missing file")
+	}
+}
+
+func TestHTTPSource(t *testing.T) {
+	var paths []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(payload))
+	}))
+	defer server.Close()
+
+	source := NewSource(server.URL + "/json")
+	if _, ok := source.(*HTTPSource); !ok {
+		t.Fatalf("got %T for a URL, want *HTTPSource", source)
+	}
+
+	for _, date := range []string{"", "2026-10-17"} {
+		rates, err := source.Rates(context.Background(), date)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(rates, payloadRates) {
+			t.Errorf("got %+v, want %+v", rates, payloadRates)
+		}
+	}
+
+	want := []string{"/json/", "/json/all/2026-10-17/"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("requested %v, want %v", paths, want)
+	}
+}
+
+func TestHTTPSourceErrors(t *testing.T) {
+	for name, handler := range map[string]http.HandlerFunc{
+		"not found": func(w http.ResponseWriter, r *http.Request) {
+			http.NotFound(w, r)
+		},
+		"server error": func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "maintenance", http.StatusServiceUnavailable)
+		},
+		"html page": func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("<html><body>Sahifa topilmadi</body></html>"))
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(handler)
+			defer server.Close()
+
+			rates, err := (&HTTPSource{BaseURL: server.URL}).Rates(context.Background(), "2026-10-17")
+			if err == nil {
+				t.Errorf("got %+v, want an error", rates)
+			}
+		})
+	}
+}
+
+func TestHTTPSourceCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(payload))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := (&HTTPSource{BaseURL: server.URL}).Rates(ctx, "")
+	if err == nil {
+		t.Error("got no error for a cancelled context")
+	}
+}
@@ -0,0 +1,201 @@
+// Package numwords spells numbers and money amounts out in Uzbek (Latin
+// script) and Russian, as printed on invoices and waybills.
+package numwords
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Languages.
+const (
+	Uzbek   = "uz"
+	Russian = "ru"
+)
+
+// Spell returns n in words in lang, Russian for "ru" and Uzbek otherwise.
+func Spell(n int64, lang string) string {
+	if lang == Russian {
+		return russian(n)
+	}
+
+	return uzbek(n)
+}
+
+// Amount returns amount in words followed by the name of currency (an ISO
+// code) and the fractional part in digits, e.g. "Bir ming ikki yuz so'm 50
+// tiyin". Currencies without a known name are written as their code.
+func Amount(amount float64, currency, lang string) string {
+	cents := int64(math.Round(math.Abs(amount) * 100))
+	whole, fraction := cents/100, cents%100
+
+	name, known := currencies[currency]
+	if !known {
+		name = currencyName{
+			uz: [2]string{currency, ""},
+			ru: [6]string{currency, currency, currency, "", "", ""},
+		}
+	}
+
+	var words string
+	if lang == Russian {
+		words = fmt.Sprintf("%s %s", russian(whole), name.ru[plural(whole)])
+		if name.ru[3] != "" {
+			words += fmt.Sprintf(" %02d %s", fraction, name.ru[3+plural(fraction)])
+		} else if fraction != 0 {
+			words += fmt.Sprintf(" %02d", fraction)
+		}
+	} else {
+		words = fmt.Sprintf("%s %s", uzbek(whole), name.uz[0])
+		if name.uz[1] != "" {
+			words += fmt.Sprintf(" %02d %s", fraction, name.uz[1])
+		} else if fraction != 0 {
+			words += fmt.Sprintf(" %02d", fraction)
+		}
+	}
+
+	if amount < 0 {
+		words = minus[lang] + " " + words
+	}
+
+	return capitalize(words)
+}
+
+type currencyName struct {
+	uz [2]string // major and minor unit, Uzbek nouns don't change with numbers
+	ru [6]string // one, few and many of the major and the minor unit
+}
+
+var currencies = map[string]currencyName{
+	"UZS": {uz: [2]string{"so'm", "tiyin"}, ru: [6]string{"сум", "сума", "сумов", "тийин", "тийина", "тийинов"}},
+	"USD": {uz: [2]string{"AQSh dollari", "sent"}, ru: [6]string{"доллар США", "доллара США", "долларов США", "цент", "цента", "центов"}},
+	"EUR": {uz: [2]string{"yevro", "yevrosent"}, ru: [6]string{"евро", "евро", "евро", "евроцент", "евроцента", "евроцентов"}},
+	"RUB": {uz: [2]string{"rubl", "kopeyka"}, ru: [6]string{"рубль", "рубля", "рублей", "копейка", "копейки", "копеек"}},
+	"KZT": {uz: [2]string{"tenge", "tiyin"}, ru: [6]string{"тенге", "тенге", "тенге", "тиын", "тиына", "тиынов"}},
+	"CNY": {uz: [2]string{"yuan", "fen"}, ru: [6]string{"юань", "юаня", "юаней", "фэнь", "фэня", "фэней"}},
+}
+
+var minus = map[string]string{Uzbek: "minus", Russian: "минус"}
+
+var (
+	uzOnes  = []string{"", "bir", "ikki", "uch", "to'rt", "besh", "olti", "yetti", "sakkiz", "to'qqiz"}
+	uzTens  = []string{"", "o'n", "yigirma", "o'ttiz", "qirq", "ellik", "oltmish", "yetmish", "sakson", "to'qson"}
+	uzScale = []string{"", "ming", "million", "milliard", "trillion", "kvadrillion", "kvintillion"}
+)
+
+func uzbek(n int64) string {
+	if n == 0 {
+		return "nol"
+	}
+
+	var words []string
+	if n < 0 {
+		words = append(words, minus[Uzbek])
+		n = -n
+	}
+
+	groups := triples(n)
+	for i := len(groups) - 1; i >= 0; i-- {
+		group := groups[i]
+		if group == 0 {
+			continue
+		}
+
+		if group >= 100 {
+			words = append(words, uzOnes[group/100], "yuz")
+		}
+		if tens := group % 100 / 10; tens > 0 {
+			words = append(words, uzTens[tens])
+		}
+		if ones := group % 10; ones > 0 {
+			words = append(words, uzOnes[ones])
+		}
+		if i > 0 {
+			words = append(words, uzScale[i])
+		}
+	}
+
+	return strings.Join(words, " ")
+}
+
+var (
+	ruOnes     = []string{"", "один", "два", "три", "четыре", "пять", "шесть", "семь", "восемь", "девять"}
+	ruOnesFem  = []string{"", "одна", "две"}
+	ruTeens    = []string{"десять", "одиннадцать", "двенадцать", "тринадцать", "четырнадцать", "пятнадцать", "шестнадцать", "семнадцать", "восемнадцать", "девятнадцать"}
+	ruTens     = []string{"", "", "двадцать", "тридцать", "сорок", "пятьдесят", "шестьдесят", "семьдесят", "восемьдесят", "девяносто"}
+	ruHundreds = []string{"", "сто", "двести", "триста", "четыреста", "пятьсот", "шестьсот", "семьсот", "восемьсот", "девятьсот"}
+	ruScale    = [][3]string{{}, {"тысяча", "тысячи", "тысяч"}, {"миллион", "миллиона", "миллионов"}, {"миллиард", "миллиарда", "миллиардов"}, {"триллион", "триллиона", "триллионов"}, {"квадриллион", "квадриллиона", "квадриллионов"}, {"квинтиллион", "квинтиллиона", "квинтиллионов"}}
+)
+
+func russian(n int64) string {
+	if n == 0 {
+		return "ноль"
+	}
+
+	var words []string
+	if n < 0 {
+		words = append(words, minus[Russian])
+		n = -n
+	}
+
+	groups := triples(n)
+	for i := len(groups) - 1; i >= 0; i-- {
+		group := groups[i]
+		if group == 0 {
+			continue
+		}
+
+		words = append(words, ruHundreds[group/100])
+
+		switch tens, ones := group%100/10, group%10; {
+		case tens == 1:
+			words = append(words, ruTeens[ones])
+		default:
+			words = append(words, ruTens[tens])
+			if (ones == 1 || ones == 2) && i == 1 { // thousands are feminine
+				words = append(words, ruOnesFem[ones])
+			} else {
+				words = append(words, ruOnes[ones])
+			}
+		}
+
+		if i > 0 {
+			words = append(words, ruScale[i][plural(group)])
+		}
+	}
+
+	return strings.Join(strings.Fields(strings.Join(words, " ")), " ")
+}
+
+// plural returns the Russian plural form of a noun counted by n: 0 for one,
+// 1 for few and 2 for many.
+func plural(n int64) int {
+	switch n %= 100; {
+	case n >= 11 && n <= 14:
+		return 2
+	case n%10 == 1:
+		return 0
+	case n%10 >= 2 && n%10 <= 4:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// triples splits n into groups of three digits, the lowest first.
+func triples(n int64) []int64 {
+	var groups []int64
+	for ; n > 0; n /= 1000 {
+		groups = append(groups, n%1000)
+	}
+
+	return groups
+}
+
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[size:]
+}
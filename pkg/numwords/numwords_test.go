@@ -0,0 +1,72 @@
+package numwords
+
+import "testing"
+
+func TestSpell(t *testing.T) {
+	for _, tc := range []struct {
+		n    int64
+		lang string
+		want string
+	}{
+		{0, Uzbek, "nol"},
+		{5, Uzbek, "besh"},
+		{11, Uzbek, "o'n bir"},
+		{100, Uzbek, "bir yuz"},
+		{1000, Uzbek, "bir ming"},
+		{1234, Uzbek, "bir ming ikki yuz o'ttiz to'rt"},
+		{2005003, Uzbek, "ikki million besh ming uch"},
+		{3000000000, Uzbek, "uch milliard"},
+		{-42, Uzbek, "minus qirq ikki"},
+		{0, Russian, "ноль"},
+		{1, Russian, "один"},
+		{12, Russian, "двенадцать"},
+		{21, Russian, "двадцать один"},
+		{512, Russian, "пятьсот двенадцать"},
+		{1000, Russian, "одна тысяча"},
+		{2000, Russian, "две тысячи"},
+		{5000, Russian, "пять тысяч"},
+		{11000, Russian, "одиннадцать тысяч"},
+		{21001, Russian, "двадцать одна тысяча один"},
+		{1002000, Russian, "один миллион две тысячи"},
+		{2000000, Russian, "два миллиона"},
+		{-3, Russian, "минус три"},
+		{7, "en", "yetti"},
+	} {
+		if got := Spell(tc.n, tc.lang); got != tc.want {
+			t.Errorf("Spell(%d, %q) = %q, want %q", tc.n, tc.lang, got, tc.want)
+		}
+	}
+}
+
+func TestAmount(t *testing.T) {
+	for _, tc := range []struct {
+		amount   float64
+		currency string
+		lang     string
+		want     string
+	}{
+		{1200.5, "UZS", Uzbek, "Bir ming ikki yuz so'm 50 tiyin"},
+		{1200.5, "UZS", Russian, "Одна тысяча двести сумов 50 тийинов"},
+		{1, "USD", Russian, "Один доллар США 00 центов"},
+		{21.01, "RUB", Russian, "Двадцать один рубль 01 копейка"},
+		{3.02, "RUB", Russian, "Три рубля 02 копейки"},
+		{3, "EUR", Uzbek, "Uch yevro 00 yevrosent"},
+		{19.999, "UZS", Uzbek, "Yigirma so'm 00 tiyin"},
+		{-15.5, "UZS", Uzbek, "Minus o'n besh so'm 50 tiyin"},
+		{-15.5, "UZS", Russian, "Минус пятнадцать сумов 50 тийинов"},
+		{2.3, "GBP", Russian, "Два GBP 30"},
+		{7, "GBP", Uzbek, "Yetti GBP"},
+	} {
+		if got := Amount(tc.amount, tc.currency, tc.lang); got != tc.want {
+			t.Errorf("Amount(%v, %q, %q) = %q, want %q", tc.amount, tc.currency, tc.lang, got, tc.want)
+		}
+	}
+}
+
+func TestPlural(t *testing.T) {
+	for n, want := range map[int64]int{0: 2, 1: 0, 2: 1, 4: 1, 5: 2, 11: 2, 14: 2, 21: 0, 22: 1, 111: 2, 101: 0} {
+		if got := plural(n); got != want {
+			t.Errorf("plural(%d) = %d, want %d", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,381 @@
+package pdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var errBadFont = errors.New("pdf: unsupported or corrupt TrueType font")
+
+// Font is a parsed TrueType font. Only what is needed to lay out text and to
+// embed a subset of the glyphs is read.
+type Font struct {
+	tables     map[string][]byte
+	unitsPerEm float64
+	bbox       [4]int
+	ascent     int
+	descent    int
+	longLoca   bool
+	numGlyphs  int
+	advances   []uint16
+	glyphs     map[rune]uint16
+}
+
+// ParseFont reads a TrueType (glyf outlines) font file.
+func ParseFont(data []byte) (*Font, error) {
+	if len(data) < 12 {
+		return nil, errBadFont
+	}
+
+	f := &Font{tables: map[string][]byte{}}
+
+	numTables := int(u16(data, 4))
+	for i := 0; i < numTables; i++ {
+		record := 12 + 16*i
+		if record+16 > len(data) {
+			return nil, errBadFont
+		}
+
+		offset, length := int(u32(data, record+8)), int(u32(data, record+12))
+		if offset+length > len(data) {
+			return nil, errBadFont
+		}
+
+		f.tables[string(data[record:record+4])] = data[offset : offset+length]
+	}
+
+	for _, tag := range []string{"head", "hhea", "hmtx", "maxp", "cmap", "loca", "glyf"} {
+		if _, ok := f.tables[tag]; !ok {
+			return nil, fmt.Errorf("pdf: font has no %s table", tag)
+		}
+	}
+
+	head, hhea, maxp := f.tables["head"], f.tables["hhea"], f.tables["maxp"]
+	if len(head) < 54 || len(hhea) < 36 || len(maxp) < 6 {
+		return nil, errBadFont
+	}
+
+	f.unitsPerEm = float64(u16(head, 18))
+	f.bbox = [4]int{int(i16(head, 36)), int(i16(head, 38)), int(i16(head, 40)), int(i16(head, 42))}
+	f.longLoca = i16(head, 50) == 1
+	f.ascent = int(i16(hhea, 4))
+	f.descent = int(i16(hhea, 6))
+	f.numGlyphs = int(u16(maxp, 4))
+
+	hmtx := f.tables["hmtx"]
+	metrics := int(u16(hhea, 34))
+	if metrics == 0 || len(hmtx) < 4*metrics {
+		return nil, errBadFont
+	}
+
+	f.advances = make([]uint16, metrics)
+	for i := range f.advances {
+		f.advances[i] = u16(hmtx, 4*i)
+	}
+
+	glyphs, err := parseCmap(f.tables["cmap"])
+	if err != nil {
+		return nil, err
+	}
+	f.glyphs = glyphs
+
+	return f, nil
+}
+
+// Glyph returns the glyph of r, 0 (the missing glyph) when the font has none.
+func (f *Font) Glyph(r rune) uint16 {
+	return f.glyphs[r]
+}
+
+// Advance returns the width of glyph in thousandths of the font size.
+func (f *Font) Advance(glyph uint16) float64 {
+	i := int(glyph)
+	if i >= len(f.advances) {
+		i = len(f.advances) - 1
+	}
+
+	return float64(f.advances[i]) * 1000 / f.unitsPerEm
+}
+
+// Width returns the width of s set in size points.
+func (f *Font) Width(s string, size float64) float64 {
+	var width float64
+	for _, r := range s {
+		width += f.Advance(f.Glyph(r))
+	}
+
+	return width * size / 1000
+}
+
+// scale converts font units to thousandths of the font size.
+func (f *Font) scale(v int) int {
+	return int(float64(v) * 1000 / f.unitsPerEm)
+}
+
+// parseCmap reads the unicode mapping of the font, from a format 12 subtable
+// when there is one and from a format 4 one otherwise.
+func parseCmap(cmap []byte) (map[rune]uint16, error) {
+	if len(cmap) < 4 {
+		return nil, errBadFont
+	}
+
+	var format4, format12 []byte
+	for i := 0; i < int(u16(cmap, 2)); i++ {
+		record := 4 + 8*i
+		if record+8 > len(cmap) {
+			return nil, errBadFont
+		}
+
+		platform, encoding, offset := u16(cmap, record), u16(cmap, record+2), int(u32(cmap, record+4))
+		if offset+4 > len(cmap) || (platform != 0 && platform != 3) || (platform == 3 && encoding != 1 && encoding != 10) {
+			continue
+		}
+
+		switch u16(cmap, offset) {
+		case 4:
+			format4 = cmap[offset:]
+		case 12:
+			format12 = cmap[offset:]
+		}
+	}
+
+	glyphs := map[rune]uint16{}
+
+	switch {
+	case len(format12) >= 16:
+		groups := int(u32(format12, 12))
+		if len(format12) < 16+12*groups {
+			return nil, errBadFont
+		}
+
+		for i := 0; i < groups; i++ {
+			group := 16 + 12*i
+			start, end, glyph := u32(format12, group), u32(format12, group+4), u32(format12, group+8)
+			for c := start; c <= end && c <= 0x10FFFF; c++ {
+				glyphs[rune(c)] = uint16(glyph + c - start)
+			}
+		}
+	case len(format4) >= 14:
+		segments := int(u16(format4, 6)) / 2
+		ends := 14
+		starts := ends + 2*segments + 2
+		deltas := starts + 2*segments
+		ranges := deltas + 2*segments
+		if len(format4) < ranges+2*segments {
+			return nil, errBadFont
+		}
+
+		for i := 0; i < segments; i++ {
+			start, end := int(u16(format4, starts+2*i)), int(u16(format4, ends+2*i))
+			delta, rangeOffset := int(u16(format4, deltas+2*i)), int(u16(format4, ranges+2*i))
+
+			for c := start; c <= end && c != 0xFFFF; c++ {
+				glyph := (c + delta) & 0xFFFF
+				if rangeOffset != 0 {
+					at := ranges + 2*i + rangeOffset + 2*(c-start)
+					if at+2 > len(format4) {
+						continue
+					}
+
+					glyph = int(u16(format4, at))
+					if glyph != 0 {
+						glyph = (glyph + delta) & 0xFFFF
+					}
+				}
+
+				if glyph != 0 {
+					glyphs[rune(c)] = uint16(glyph)
+				}
+			}
+		}
+	default:
+		return nil, errors.New("pdf: font has no unicode cmap")
+	}
+
+	return glyphs, nil
+}
+
+// glyph returns the outline of glyph from the glyf table.
+func (f *Font) glyph(glyph int) []byte {
+	loca, glyf := f.tables["loca"], f.tables["glyf"]
+
+	var start, end int
+	if f.longLoca {
+		if 4*glyph+8 > len(loca) {
+			return nil
+		}
+		start, end = int(u32(loca, 4*glyph)), int(u32(loca, 4*glyph+4))
+	} else {
+		if 2*glyph+4 > len(loca) {
+			return nil
+		}
+		start, end = 2*int(u16(loca, 2*glyph)), 2*int(u16(loca, 2*glyph+2))
+	}
+
+	if start >= end || end > len(glyf) {
+		return nil
+	}
+
+	return glyf[start:end]
+}
+
+// Composite glyph flags.
+const (
+	argsAreWords    = 0x0001
+	haveScale       = 0x0008
+	moreComponents  = 0x0020
+	haveXYScale     = 0x0040
+	haveTwoByTwo    = 0x0080
+	compositeHeader = 10
+)
+
+// components returns the glyphs a composite glyph is built from.
+func components(outline []byte) []uint16 {
+	if len(outline) < compositeHeader || i16(outline, 0) >= 0 {
+		return nil
+	}
+
+	var glyphs []uint16
+	for at := compositeHeader; at+4 <= len(outline); {
+		flags := u16(outline, at)
+		glyphs = append(glyphs, u16(outline, at+2))
+
+		at += 4
+		if flags&argsAreWords != 0 {
+			at += 4
+		} else {
+			at += 2
+		}
+
+		switch {
+		case flags&haveScale != 0:
+			at += 2
+		case flags&haveXYScale != 0:
+			at += 4
+		case flags&haveTwoByTwo != 0:
+			at += 8
+		}
+
+		if flags&moreComponents == 0 {
+			break
+		}
+	}
+
+	return glyphs
+}
+
+// subset returns a font file that keeps the glyph numbering of f but only the
+// outlines of used and the glyphs they are composed of, the rest are left
+// empty. The cmap is dropped, PDF maps characters to glyphs itself.
+func (f *Font) subset(used map[uint16]rune) []byte {
+	keep := map[uint16]bool{}
+	pending := []uint16{0}
+	for glyph := range used {
+		pending = append(pending, glyph)
+	}
+
+	for len(pending) > 0 {
+		glyph := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if keep[glyph] {
+			continue
+		}
+		keep[glyph] = true
+		pending = append(pending, components(f.glyph(int(glyph)))...)
+	}
+
+	var glyf bytes.Buffer
+	loca := make([]byte, 4*(f.numGlyphs+1))
+	for glyph := 0; glyph < f.numGlyphs; glyph++ {
+		binary.BigEndian.PutUint32(loca[4*glyph:], uint32(glyf.Len()))
+		if keep[uint16(glyph)] {
+			glyf.Write(f.glyph(glyph))
+			for glyf.Len()%4 != 0 {
+				glyf.WriteByte(0)
+			}
+		}
+	}
+	binary.BigEndian.PutUint32(loca[4*f.numGlyphs:], uint32(glyf.Len()))
+
+	head := append([]byte(nil), f.tables["head"]...)
+	binary.BigEndian.PutUint32(head[8:], 0)  // checkSumAdjustment
+	binary.BigEndian.PutUint16(head[50:], 1) // long loca offsets
+
+	tables := map[string][]byte{
+		"head": head,
+		"hhea": f.tables["hhea"],
+		"hmtx": f.tables["hmtx"],
+		"maxp": f.tables["maxp"],
+		"loca": loca,
+		"glyf": glyf.Bytes(),
+	}
+	for _, tag := range []string{"cvt ", "fpgm", "prep"} {
+		if table, ok := f.tables[tag]; ok {
+			tables[tag] = table
+		}
+	}
+
+	return writeFont(tables)
+}
+
+// writeFont lays tables out as a TrueType font file.
+func writeFont(tables map[string][]byte) []byte {
+	tags := make([]string, 0, len(tables))
+	for tag := range tables {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	entrySelector := 0
+	for 1<<(entrySelector+1) <= len(tags) {
+		entrySelector++
+	}
+	searchRange := 16 << entrySelector
+
+	var out bytes.Buffer
+	binary.Write(&out, binary.BigEndian, []uint32{0x00010000})
+	binary.Write(&out, binary.BigEndian, []uint16{uint16(len(tags)), uint16(searchRange), uint16(entrySelector), uint16(16*len(tags) - searchRange)})
+
+	offset := 12 + 16*len(tags)
+	for _, tag := range tags {
+		table := tables[tag]
+		out.WriteString(tag)
+		binary.Write(&out, binary.BigEndian, []uint32{checksum(table), uint32(offset), uint32(len(table))})
+		offset += (len(table) + 3) &^ 3
+	}
+
+	for _, tag := range tags {
+		out.Write(tables[tag])
+		for out.Len()%4 != 0 {
+			out.WriteByte(0)
+		}
+	}
+
+	return out.Bytes()
+}
+
+func checksum(table []byte) uint32 {
+	var sum uint32
+	for i := 0; i < len(table); i += 4 {
+		var word [4]byte
+		copy(word[:], table[i:])
+		sum += binary.BigEndian.Uint32(word[:])
+	}
+
+	return sum
+}
+
+func u16(b []byte, at int) uint16 {
+	return binary.BigEndian.Uint16(b[at:])
+}
+
+func i16(b []byte, at int) int16 {
+	return int16(binary.BigEndian.Uint16(b[at:]))
+}
+
+func u32(b []byte, at int) uint32 {
+	return binary.BigEndian.Uint32(b[at:])
+}
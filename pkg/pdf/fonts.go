@@ -0,0 +1,12 @@
+package pdf
+
+import _ "embed"
+
+// DejaVu Sans covers Latin and Cyrillic, see fonts/LICENSE.
+var (
+	//go:embed fonts/DejaVuSans.ttf
+	DejaVuSans []byte
+
+	//go:embed fonts/DejaVuSans-Bold.ttf
+	DejaVuSansBold []byte
+)
@@ -0,0 +1,435 @@
+// Package pdf writes simple PDF documents: pages of text, lines and boxes set
+// in embedded TrueType fonts, enough to print business documents in any
+// language the fonts cover.
+//
+// Positions are in points (1/72 inch) measured from the top left corner of
+// the page.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"crypto/sha1"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// A4 page size in points.
+const (
+	A4Width  = 595.28
+	A4Height = 841.89
+)
+
+// Alignments of text relative to the position it is drawn at.
+const (
+	AlignLeft = iota
+	AlignCenter
+	AlignRight
+)
+
+// Document is a PDF being built. Fonts have to be added before they are used
+// on a page.
+type Document struct {
+	Title string // shown by viewers instead of the file name
+
+	width, height float64
+	fonts         []*docFont
+	pages         []*Page
+}
+
+type docFont struct {
+	name string
+	font *Font
+	used map[uint16]rune
+}
+
+// New returns an empty document with pages of width x height points.
+func New(width, height float64) *Document {
+	return &Document{width: width, height: height}
+}
+
+// AddFont registers the TrueType font in data under name.
+func (d *Document) AddFont(name string, data []byte) error {
+	font, err := ParseFont(data)
+	if err != nil {
+		return err
+	}
+
+	d.fonts = append(d.fonts, &docFont{name: name, font: font, used: map[uint16]rune{}})
+
+	return nil
+}
+
+// Font returns the font registered under name, nil when there is none.
+func (d *Document) Font(name string) *Font {
+	if font := d.font(name); font != nil {
+		return font.font
+	}
+
+	return nil
+}
+
+func (d *Document) font(name string) *docFont {
+	for _, font := range d.fonts {
+		if font.name == name {
+			return font
+		}
+	}
+
+	return nil
+}
+
+// AddPage appends a blank page and returns it.
+func (d *Document) AddPage() *Page {
+	page := &Page{doc: d}
+	d.pages = append(d.pages, page)
+
+	return page
+}
+
+// Pages returns the pages added so far.
+func (d *Document) Pages() []*Page {
+	return d.pages
+}
+
+// Page is a page of a document. Drawing on it appends to its content.
+type Page struct {
+	doc     *Document
+	content bytes.Buffer
+	font    *docFont
+	size    float64
+}
+
+// Width returns the width of the page.
+func (p *Page) Width() float64 {
+	return p.doc.width
+}
+
+// Height returns the height of the page.
+func (p *Page) Height() float64 {
+	return p.doc.height
+}
+
+// SetFont selects the font text is drawn in.
+func (p *Page) SetFont(name string, size float64) error {
+	font := p.doc.font(name)
+	if font == nil {
+		return fmt.Errorf("pdf: font %s is not added", name)
+	}
+
+	p.font, p.size = font, size
+
+	return nil
+}
+
+// TextWidth returns the width of s in the current font.
+func (p *Page) TextWidth(s string) float64 {
+	if p.font == nil {
+		return 0
+	}
+
+	return p.font.font.Width(s, p.size)
+}
+
+// Text draws s on the baseline y, starting, centered or ending at x
+// depending on align.
+func (p *Page) Text(x, y float64, s string, align int) {
+	if p.font == nil || s == "" {
+		return
+	}
+
+	switch align {
+	case AlignCenter:
+		x -= p.TextWidth(s) / 2
+	case AlignRight:
+		x -= p.TextWidth(s)
+	}
+
+	var glyphs strings.Builder
+	for _, r := range s {
+		glyph := p.font.font.Glyph(r)
+		if _, ok := p.font.used[glyph]; !ok && glyph != 0 {
+			p.font.used[glyph] = r
+		}
+		fmt.Fprintf(&glyphs, "%04X", glyph)
+	}
+
+	fmt.Fprintf(&p.content, "BT /%s %s Tf %s %s Td <%s> Tj ET\n",
+		fontResource(p.doc.fontIndex(p.font)), num(p.size), num(x), num(p.doc.height-y), glyphs.String())
+}
+
+// Wrap splits s into lines that fit in width in the current font, breaking
+// at spaces where possible. Line breaks in s are kept.
+func (p *Page) Wrap(s string, width float64) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(s, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+
+			if p.TextWidth(candidate) <= width {
+				line = candidate
+				continue
+			}
+
+			if line != "" {
+				lines = append(lines, line)
+			}
+
+			// words longer than the width are broken anywhere
+			line = ""
+			for _, r := range word {
+				if line != "" && p.TextWidth(line+string(r)) > width {
+					lines = append(lines, line)
+					line = ""
+				}
+				line += string(r)
+			}
+		}
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+// SetLineWidth sets the width of the lines drawn after it.
+func (p *Page) SetLineWidth(width float64) {
+	fmt.Fprintf(&p.content, "%s w\n", num(width))
+}
+
+// SetGray sets the color text and fills are drawn in, 0 is black and 1 white.
+func (p *Page) SetGray(gray float64) {
+	fmt.Fprintf(&p.content, "%s g\n", num(gray))
+}
+
+// Line draws a line from x1, y1 to x2, y2.
+func (p *Page) Line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(&p.content, "%s %s m %s %s l S\n", num(x1), num(p.doc.height-y1), num(x2), num(p.doc.height-y2))
+}
+
+// Rect draws a box with its top left corner at x, y, filled in the current
+// gray when fill is set and outlined otherwise.
+func (p *Page) Rect(x, y, width, height float64, fill bool) {
+	op := "S"
+	if fill {
+		op = "f"
+	}
+
+	fmt.Fprintf(&p.content, "%s %s %s %s re %s\n", num(x), num(p.doc.height-y-height), num(width), num(height), op)
+}
+
+func (d *Document) fontIndex(font *docFont) int {
+	for i := range d.fonts {
+		if d.fonts[i] == font {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func fontResource(i int) string {
+	return "F" + strconv.Itoa(i+1)
+}
+
+func num(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
+
+// Bytes returns the document as a PDF file. Fonts are embedded with the glyphs
+// used only.
+func (d *Document) Bytes() ([]byte, error) {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	w := &writer{}
+
+	const (
+		catalog = 1
+		pages   = 2
+		info    = 3
+	)
+
+	// every page takes two objects, the page and its content, and every font five
+	firstPage := info + 1
+	firstFont := firstPage + 2*len(d.pages)
+	w.offsets = make([]int, firstFont+5*len(d.fonts))
+
+	w.object(catalog, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pages))
+
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+	w.object(pages, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+
+	w.object(info, fmt.Sprintf("<< /Title %s /Producer (DE-Lider Warehouse) >>", textString(d.Title)))
+
+	var fonts strings.Builder
+	for i := range d.fonts {
+		fmt.Fprintf(&fonts, "/%s %d 0 R ", fontResource(i), firstFont+5*i)
+	}
+
+	for i, page := range d.pages {
+		w.object(firstPage+2*i, fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %s %s] /Resources << /Font << %s>> >> /Contents %d 0 R >>",
+			pages, num(d.width), num(d.height), fonts.String(), firstPage+2*i+1))
+
+		if err := w.stream(firstPage+2*i+1, "", page.content.Bytes()); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, font := range d.fonts {
+		if err := w.font(firstFont+5*i, font); err != nil {
+			return nil, err
+		}
+	}
+
+	return w.finish(catalog, info), nil
+}
+
+type writer struct {
+	buf     bytes.Buffer
+	offsets []int
+}
+
+func (w *writer) object(n int, body string) {
+	if w.buf.Len() == 0 {
+		w.buf.WriteString("%PDF-1.7\n%\xE2\xE3\xCF\xD3\n")
+	}
+
+	w.offsets[n] = w.buf.Len()
+	fmt.Fprintf(&w.buf, "%d 0 obj\n%s\nendobj\n", n, body)
+}
+
+// stream writes data compressed as a stream object, dict holds the entries of
+// its dictionary besides the length and filter.
+func (w *writer) stream(n int, dict string, data []byte) error {
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	if _, err := zw.Write(data); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	w.object(n, fmt.Sprintf("<< /Length %d /Filter /FlateDecode %s>>\nstream\n%s\nendstream", compressed.Len(), dict, compressed.Bytes()))
+
+	return nil
+}
+
+// font writes font as a Type0 font with Identity-H encoding, so text is
+// drawn as glyph numbers, followed by its CID font, descriptor, font file and
+// the map back to unicode that makes the text searchable and copyable.
+func (w *writer) font(n int, font *docFont) error {
+	var (
+		cidFont    = n + 1
+		descriptor = n + 2
+		file       = n + 3
+		toUnicode  = n + 4
+		f          = font.font
+	)
+
+	glyphs := make([]uint16, 0, len(font.used))
+	for glyph := range font.used {
+		glyphs = append(glyphs, glyph)
+	}
+	sort.Slice(glyphs, func(i, j int) bool { return glyphs[i] < glyphs[j] })
+
+	// subsets are tagged with six capital letters that tell them apart
+	hash := sha1.New()
+	fmt.Fprint(hash, font.name, glyphs)
+	var tag [6]byte
+	for i, b := range hash.Sum(nil)[:6] {
+		tag[i] = 'A' + b%26
+	}
+	baseFont := string(tag[:]) + "+" + font.name
+
+	var widths strings.Builder
+	for _, glyph := range glyphs {
+		fmt.Fprintf(&widths, "%d [%d] ", glyph, int(f.Advance(glyph)))
+	}
+
+	w.object(n, fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H /DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>",
+		baseFont, cidFont, toUnicode))
+
+	w.object(cidFont, fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> /FontDescriptor %d 0 R /DW %d /W [%s] /CIDToGIDMap /Identity >>",
+		baseFont, descriptor, int(f.Advance(0)), widths.String()))
+
+	w.object(descriptor, fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags 32 /FontBBox [%d %d %d %d] /ItalicAngle 0 /Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 %d 0 R >>",
+		baseFont, f.scale(f.bbox[0]), f.scale(f.bbox[1]), f.scale(f.bbox[2]), f.scale(f.bbox[3]), f.scale(f.ascent), f.scale(f.descent), f.scale(f.ascent), file))
+
+	subset := f.subset(font.used)
+	if err := w.stream(file, fmt.Sprintf("/Length1 %d ", len(subset)), subset); err != nil {
+		return err
+	}
+
+	return w.stream(toUnicode, "", toUnicodeCMap(glyphs, font.used))
+}
+
+func (w *writer) finish(root, info int) []byte {
+	xref := w.buf.Len()
+	fmt.Fprintf(&w.buf, "xref\n0 %d\n0000000000 65535 f \n", len(w.offsets))
+	for _, offset := range w.offsets[1:] {
+		fmt.Fprintf(&w.buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&w.buf, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(w.offsets), root, info, xref)
+
+	return w.buf.Bytes()
+}
+
+// toUnicodeCMap maps the glyphs text was drawn with back to the characters.
+func toUnicodeCMap(glyphs []uint16, used map[uint16]rune) []byte {
+	var cmap bytes.Buffer
+	cmap.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n" +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n" +
+		"/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n" +
+		"1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+
+	// at most 100 mappings are allowed in a block
+	for start := 0; start < len(glyphs); start += 100 {
+		end := start + 100
+		if end > len(glyphs) {
+			end = len(glyphs)
+		}
+
+		fmt.Fprintf(&cmap, "%d beginbfchar\n", end-start)
+		for _, glyph := range glyphs[start:end] {
+			fmt.Fprintf(&cmap, "<%04X> <%s>\n", glyph, utf16Hex(used[glyph]))
+		}
+		cmap.WriteString("endbfchar\n")
+	}
+
+	cmap.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n")
+
+	return cmap.Bytes()
+}
+
+func utf16Hex(r rune) string {
+	if r >= 0x10000 {
+		r -= 0x10000
+		return fmt.Sprintf("%04X%04X", 0xD800+(r>>10), 0xDC00+(r&0x3FF))
+	}
+
+	return fmt.Sprintf("%04X", r)
+}
+
+// textString encodes s as a UTF-16 PDF string.
+func textString(s string) string {
+	var out strings.Builder
+	out.WriteString("<FEFF")
+	for _, r := range s {
+		out.WriteString(utf16Hex(r))
+	}
+	out.WriteString(">")
+
+	return out.String()
+}
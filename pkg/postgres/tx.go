@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+type txKey struct{}
+
+// Querier -.
+type Querier interface {
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+// DB returns the transaction started by WithTx for this ctx, or the pool outside of one.
+func (p *Postgres) DB(ctx context.Context) Querier {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+
+	return p.Pool
+}
+
+// WithTx runs fn in a transaction. Queries made through DB with the ctx passed to fn
+// take part in it, and nested WithTx calls join the outer transaction instead of
+// starting a new one. The transaction is committed when fn returns nil.
+func (p *Postgres) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := p.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("postgres - WithTx - Begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = fn(context.WithValue(ctx, txKey{}, tx))
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,258 @@
+// Package xlsx reads and writes the cell values of Office Open XML
+// spreadsheets (.xlsx), the format Excel saves in by default. Formatting,
+// formulas and every sheet but the first are ignored on reading.
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+var ErrNoSheet = errors.New("xlsx: workbook has no sheets")
+
+// Read returns the rows of the first sheet of the workbook in data. Rows are
+// indexed by their number minus one, rows and cells left out of the file are
+// empty.
+func Read(data []byte) ([][]string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("xlsx: %w", err)
+	}
+
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+
+	sheet, err := firstSheet(files)
+	if err != nil {
+		return nil, err
+	}
+
+	var shared []string
+	if file, ok := files["xl/sharedStrings.xml"]; ok {
+		shared, err = sharedStrings(file)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return sheetRows(sheet, shared)
+}
+
+// firstSheet finds the part of the first sheet through the workbook and its
+// relationships.
+func firstSheet(files map[string]*zip.File) (*zip.File, error) {
+	var workbook struct {
+		Sheets []struct {
+			ID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := decodeFile(files["xl/workbook.xml"], &workbook); err != nil {
+		return nil, err
+	}
+	if len(workbook.Sheets) == 0 {
+		return nil, ErrNoSheet
+	}
+
+	var rels struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := decodeFile(files["xl/_rels/workbook.xml.rels"], &rels); err != nil {
+		return nil, err
+	}
+
+	for _, rel := range rels.Relationships {
+		if rel.ID != workbook.Sheets[0].ID {
+			continue
+		}
+
+		name := path.Join("xl", rel.Target)
+		if strings.HasPrefix(rel.Target, "/") {
+			name = strings.TrimPrefix(rel.Target, "/")
+		}
+
+		if file, ok := files[name]; ok {
+			return file, nil
+		}
+	}
+
+	return nil, ErrNoSheet
+}
+
+// sharedStrings reads the table of strings cells refer to by index. Rich text
+// strings are joined from their runs.
+func sharedStrings(file *zip.File) ([]string, error) {
+	var table struct {
+		Items []struct {
+			Text string `xml:"t"`
+			Runs []struct {
+				Text string `xml:"t"`
+			} `xml:"r"`
+		} `xml:"si"`
+	}
+	if err := decodeFile(file, &table); err != nil {
+		return nil, err
+	}
+
+	shared := make([]string, len(table.Items))
+	for i, item := range table.Items {
+		text := item.Text
+		for _, run := range item.Runs {
+			text += run.Text
+		}
+		shared[i] = text
+	}
+
+	return shared, nil
+}
+
+type cell struct {
+	Ref    string `xml:"r,attr"`
+	Type   string `xml:"t,attr"`
+	Value  string `xml:"v"`
+	Inline struct {
+		Text string `xml:"t"`
+		Runs []struct {
+			Text string `xml:"t"`
+		} `xml:"r"`
+	} `xml:"is"`
+}
+
+// sheetRows streams the rows of a sheet, large sheets aren't held as a tree.
+func sheetRows(file *zip.File, shared []string) ([][]string, error) {
+	reader, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("xlsx: %w", err)
+	}
+	defer reader.Close()
+
+	var (
+		rows    [][]string
+		decoder = xml.NewDecoder(reader)
+		row     = -1
+	)
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("xlsx: %w", err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch start.Name.Local {
+		case "row":
+			row++
+			for _, attr := range start.Attr {
+				if attr.Name.Local == "r" {
+					if n, err := strconv.Atoi(attr.Value); err == nil && n > 0 {
+						row = n - 1
+					}
+				}
+			}
+		case "c":
+			var c cell
+			if err := decoder.DecodeElement(&c, &start); err != nil {
+				return nil, fmt.Errorf("xlsx: %w", err)
+			}
+
+			column, cellRow, ok := parseRef(c.Ref)
+			if !ok {
+				// cells without a reference follow the previous one
+				cellRow = row
+				if cellRow >= 0 && cellRow < len(rows) {
+					column = len(rows[cellRow])
+				}
+			}
+			if cellRow < 0 {
+				continue
+			}
+
+			for len(rows) <= cellRow {
+				rows = append(rows, nil)
+			}
+			for len(rows[cellRow]) <= column {
+				rows[cellRow] = append(rows[cellRow], "")
+			}
+
+			rows[cellRow][column] = c.text(shared)
+		}
+	}
+
+	return rows, nil
+}
+
+func (c cell) text(shared []string) string {
+	switch c.Type {
+	case "s":
+		i, err := strconv.Atoi(strings.TrimSpace(c.Value))
+		if err != nil || i < 0 || i >= len(shared) {
+			return ""
+		}
+		return shared[i]
+	case "inlineStr":
+		text := c.Inline.Text
+		for _, run := range c.Inline.Runs {
+			text += run.Text
+		}
+		return text
+	case "b":
+		if c.Value == "1" {
+			return "TRUE"
+		}
+		return "FALSE"
+	}
+
+	return c.Value
+}
+
+// parseRef splits a cell reference like "AB12" into a column and a row, both
+// counted from 0.
+func parseRef(ref string) (int, int, bool) {
+	i, column := 0, 0
+	for ; i < len(ref) && ref[i] >= 'A' && ref[i] <= 'Z'; i++ {
+		column = column*26 + int(ref[i]-'A'+1)
+	}
+
+	row, err := strconv.Atoi(ref[i:])
+	if i == 0 || err != nil || row < 1 {
+		return 0, 0, false
+	}
+
+	return column - 1, row - 1, true
+}
+
+func decodeFile(file *zip.File, v interface{}) error {
+	if file == nil {
+		return ErrNoSheet
+	}
+
+	reader, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("xlsx: %w", err)
+	}
+	defer reader.Close()
+
+	if err := xml.NewDecoder(reader).Decode(v); err != nil {
+		return fmt.Errorf("xlsx: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const (
+	testWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Products" sheetId="1" r:id="rId2"/><sheet name="Notes" sheetId="2" r:id="rId1"/></sheets></workbook>`
+
+	testRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="/xl/worksheets/sheet2.xml"/>
+</Relationships>`
+
+	testShared = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" count="4" uniqueCount="4">
+<si><t>sku</t></si><si><t>name</t></si><si><r><t>Choy </t></r><r><rPr><b/></rPr><t>qora</t></r></si><si><t>AB-1</t></si>
+</sst>`
+
+	// the sheet of the first tab, with gaps, a row and cells without
+	// references, an inline string, a boolean and a formula
+	testSheet = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="D1" t="inlineStr"><is><t>price</t></is></c></row>
+<row r="2"><c r="A2" t="s"><v>3</v></c><c r="B2" t="s"><v>2</v></c><c r="C2" t="b"><v>1</v></c><c r="D2"><f>2*6</f><v>12.5</v></c></row>
+<row r="4"><c r="B4"><v>7</v></c></row>
+<row><c t="s"><v>9</v></c><c t="b"><v>0</v></c></row>
+</sheetData></worksheet>`
+
+	testOtherSheet = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+<row r="1"><c r="A1" t="inlineStr"><is><t>not read</t></is></c></row>
+</sheetData></worksheet>`
+)
+
+// workbook zips parts into a workbook.
+func workbook(t *testing.T, parts map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for name, content := range parts {
+		file, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = file.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestRead(t *testing.T) {
+	rows, err := Read(workbook(t, map[string]string{
+		"xl/workbook.xml":            testWorkbook,
+		"xl/_rels/workbook.xml.rels": testRels,
+		"xl/sharedStrings.xml":       testShared,
+		"xl/worksheets/sheet1.xml":   testOtherSheet,
+		"xl/worksheets/sheet2.xml":   testSheet,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]string{
+		{"sku", "name", "", "price"},
+		{"AB-1", "Choy qora", "TRUE", "12.5"},
+		nil,
+		{"", "7"},
+		{"", "FALSE"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("got %q, want %q", rows, want)
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		data    []byte
+		noSheet bool
+	}{
+		{name: "not a zip", data: []byte("sku,name\nAB-1,Choy\n")},
+		{name: "no workbook", data: workbook(t, map[string]string{"xl/worksheets/sheet1.xml": testSheet}), noSheet: true},
+		{name: "no sheets", data: workbook(t, map[string]string{
+			"xl/workbook.xml":            `<workbook><sheets></sheets></workbook>`,
+			"xl/_rels/workbook.xml.rels": testRels,
+		}), noSheet: true},
+		{name: "sheet part missing", data: workbook(t, map[string]string{
+			"xl/workbook.xml":            testWorkbook,
+			"xl/_rels/workbook.xml.rels": testRels,
+			"xl/worksheets/sheet1.xml":   testOtherSheet,
+		}), noSheet: true},
+		{name: "broken sheet", data: workbook(t, map[string]string{
+			"xl/workbook.xml":            testWorkbook,
+			"xl/_rels/workbook.xml.rels": testRels,
+			"xl/worksheets/sheet2.xml":   `<worksheet><sheetData><row><c r="A1"><v>1</c>`,
+		})},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rows, err := Read(tc.data)
+			if err == nil {
+				t.Fatalf("got %q, want an error", rows)
+			}
+
+			if errors.Is(err, ErrNoSheet) != tc.noSheet {
+				t.Errorf("got %v, want ErrNoSheet %v", err, tc.noSheet)
+			}
+		})
+	}
+}
+
+func TestParseRef(t *testing.T) {
+	for _, tc := range []struct {
+		ref         string
+		column, row int
+		ok          bool
+	}{
+		{"A1", 0, 0, true},
+		{"Z9", 25, 8, true},
+		{"AA10", 26, 9, true},
+		{"AB12", 27, 11, true},
+		{"XFD1048576", 16383, 1048575, true},
+		{"", 0, 0, false},
+		{"12", 0, 0, false},
+		{"A", 0, 0, false},
+		{"A0", 0, 0, false},
+		{"a1", 0, 0, false},
+	} {
+		column, row, ok := parseRef(tc.ref)
+		if column != tc.column || row != tc.row || ok != tc.ok {
+			t.Errorf("parseRef(%q) = %d, %d, %v, want %d, %d, %v", tc.ref, column, row, ok, tc.column, tc.row, tc.ok)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strconv"
+)
+
+// MaxRows is the number of rows a sheet can hold.
+const MaxRows = 1048576
+
+// maxCellText is the number of characters a cell can hold.
+const maxCellText = 32767
+
+var ErrTooManyRows = errors.New("xlsx: sheet is full")
+
+// parts of the workbook written before the sheet, which is streamed last
+var workbookParts = []struct{ name, content string }{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+		`</Relationships>`},
+	// style 1 is the bold header
+	{"xl/styles.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+		`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+		`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+		`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+		`<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+		`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs>` +
+		`</styleSheet>`},
+}
+
+// Writer streams a workbook of a single sheet to an io.Writer row by row, so
+// that sheets of any length are written without being held in memory.
+type Writer struct {
+	zip   *zip.Writer
+	sheet *bufio.Writer
+	rows  int
+}
+
+// NewWriter starts a workbook whose sheet begins with a bold header row that
+// stays in view while scrolling.
+func NewWriter(w io.Writer, header []string) (*Writer, error) {
+	archive := zip.NewWriter(w)
+
+	for _, part := range workbookParts {
+		file, err := archive.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err = io.WriteString(file, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	file, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	writer := &Writer{zip: archive, sheet: bufio.NewWriter(file)}
+
+	writer.sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<sheetViews><sheetView workbookViewId="0">` +
+		`<pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/>` +
+		`</sheetView></sheetViews><sheetData>`)
+
+	values := make([]interface{}, len(header))
+	for i, name := range header {
+		values[i] = name
+	}
+
+	return writer, writer.writeRow(values, ` s="1"`)
+}
+
+// Write adds a row. Strings are written as text, numbers and booleans as
+// values Excel can calculate with, nil leaves a cell empty.
+func (w *Writer) Write(values []interface{}) error {
+	return w.writeRow(values, "")
+}
+
+func (w *Writer) writeRow(values []interface{}, style string) error {
+	if w.rows == MaxRows {
+		return ErrTooManyRows
+	}
+	w.rows++
+
+	row := strconv.Itoa(w.rows)
+
+	w.sheet.WriteString(`<row r="` + row + `">`)
+	for i, value := range values {
+		ref := ` r="` + columnName(i) + row + `"` + style
+
+		switch v := value.(type) {
+		case nil:
+			continue
+		case string:
+			if v == "" {
+				continue
+			}
+			w.sheet.WriteString(`<c` + ref + ` t="inlineStr"><is><t xml:space="preserve">`)
+			xml.EscapeText(w.sheet, []byte(truncate(v)))
+			w.sheet.WriteString(`</t></is></c>`)
+		case bool:
+			b := "0"
+			if v {
+				b = "1"
+			}
+			w.sheet.WriteString(`<c` + ref + ` t="b"><v>` + b + `</v></c>`)
+		case int:
+			w.sheet.WriteString(`<c` + ref + `><v>` + strconv.Itoa(v) + `</v></c>`)
+		case int64:
+			w.sheet.WriteString(`<c` + ref + `><v>` + strconv.FormatInt(v, 10) + `</v></c>`)
+		case float64:
+			w.sheet.WriteString(`<c` + ref + `><v>` + strconv.FormatFloat(v, 'f', -1, 64) + `</v></c>`)
+		default:
+			return errors.New("xlsx: unsupported cell value")
+		}
+	}
+
+	_, err := w.sheet.WriteString(`</row>`)
+	return err
+}
+
+// Close ends the sheet and the workbook. It doesn't close the underlying writer.
+func (w *Writer) Close() error {
+	w.sheet.WriteString(`</sheetData></worksheet>`)
+
+	if err := w.sheet.Flush(); err != nil {
+		return err
+	}
+
+	return w.zip.Close()
+}
+
+// columnName returns the letters of a column counted from 0, e.g. AB for 27.
+func columnName(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+
+	return name
+}
+
+// truncate cuts text to what a cell can hold.
+func truncate(text string) string {
+	n := 0
+	for i := range text {
+		if n == maxCellText {
+			return text[:i]
+		}
+		n++
+	}
+
+	return text
+}
@@ -0,0 +1,98 @@
+package xlsx
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWriterRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+
+	w, err := NewWriter(&buf, []string{"SKU", "Nomi", "Миқдор", "Active"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, row := range [][]interface{}{
+		{"AB-1", "Choy <qora> & ko'k", 12.5, true},
+		{"AB-2", "  padded  ", 3, false},
+		{nil, "", int64(-7), nil},
+		{},
+		{"=1+1", "Сок 1 л", 0.001, false},
+	} {
+		if err = w.Write(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := Read(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]string{
+		{"SKU", "Nomi", "Миқдор", "Active"},
+		{"AB-1", "Choy <qora> & ko'k", "12.5", "TRUE"},
+		{"AB-2", "  padded  ", "3", "FALSE"},
+		{"", "", "-7"},
+		nil,
+		{"=1+1", "Сок 1 л", "0.001", "FALSE"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("got %q, want %q", rows, want)
+	}
+}
+
+func TestWriterErrors(t *testing.T) {
+	var buf bytes.Buffer
+
+	w, err := NewWriter(&buf, []string{"SKU"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = w.Write([]interface{}{struct{}{}}); err == nil {
+		t.Error("got no error for an unsupported value")
+	}
+
+	w.rows = MaxRows
+	if err = w.Write([]interface{}{"AB-1"}); !errors.Is(err, ErrTooManyRows) {
+		t.Errorf("got %v for a full sheet, want ErrTooManyRows", err)
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	for i, want := range map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 51: "AZ", 52: "BA", 701: "ZZ", 702: "AAA", 16383: "XFD"} {
+		if got := columnName(i); got != want {
+			t.Errorf("columnName(%d) = %q, want %q", i, got, want)
+		}
+
+		if column, _, _ := parseRef(want + "1"); column != i {
+			t.Errorf("parseRef(%q) column = %d, want %d", want+"1", column, i)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	for _, tc := range []struct {
+		text string
+		want int
+	}{
+		{"", 0},
+		{"short", 5},
+		{strings.Repeat("a", maxCellText), maxCellText},
+		{strings.Repeat("a", maxCellText+1), maxCellText},
+		{strings.Repeat("ў", maxCellText+10), maxCellText},
+	} {
+		if got := len([]rune(truncate(tc.text))); got != tc.want {
+			t.Errorf("truncate kept %d characters of %d, want %d", got, len([]rune(tc.text)), tc.want)
+		}
+	}
+}